@@ -0,0 +1,205 @@
+// Command audit exports hash-chained audit trail as signed NDJSON and verifies
+// exports offline.
+//
+//	audit keygen -out audit_key
+//	audit export -from 2024-10-01 -to 2024-11-01 -key audit_key -out trail.ndjson
+//	audit verify -in trail.ndjson -pubkey audit_key.pub
+package main
+
+import (
+	"app/internal/auditchain"
+	"app/internal/repo/pgdb"
+	"app/pkg/postgres"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func init() {
+	if err := godotenv.Load(); err != nil {
+		return
+	}
+}
+
+const usage = "usage: audit keygen|export|verify [flags]"
+
+func main() {
+	if len(os.Args) < 2 {
+		fail(errors.New(usage))
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen(os.Args[2:])
+	case "export":
+		err = export(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	default:
+		err = errors.New(usage)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// keygen writes Ed25519 private key to out and public key to out.pub
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "audit_key", "private key file, public key is written next to it with .pub suffix")
+	_ = fs.Parse(args)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("keygen - ed25519.GenerateKey: %w", err)
+	}
+	if err := os.WriteFile(*out, []byte(base64.StdEncoding.EncodeToString(priv)+"\n"), 0600); err != nil {
+		return fmt.Errorf("keygen - os.WriteFile: %w", err)
+	}
+	if err := os.WriteFile(*out+".pub", []byte(base64.StdEncoding.EncodeToString(pub)+"\n"), 0644); err != nil {
+		return fmt.Errorf("keygen - os.WriteFile: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "keys written to %s and %s.pub\n", *out, *out)
+	return nil
+}
+
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fromFlag := fs.String("from", "", "range start, inclusive (2006-01-02 or RFC 3339)")
+	toFlag := fs.String("to", "", "range end, exclusive (2006-01-02 or RFC 3339)")
+	keyFile := fs.String("key", "audit_key", "Ed25519 private key file")
+	out := fs.String("out", "", "output file, stdout if empty")
+	_ = fs.Parse(args)
+
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return fmt.Errorf("export - -from: %w", err)
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return fmt.Errorf("export - -to: %w", err)
+	}
+	if !from.Before(to) {
+		return errors.New("export - -from must be before -to")
+	}
+
+	key, err := readKey(*keyFile, ed25519.PrivateKeySize)
+	if err != nil {
+		return fmt.Errorf("export - %w", err)
+	}
+
+	pgUrl, ok := os.LookupEnv("POSTGRES_CONN")
+	if !ok || len(pgUrl) == 0 {
+		return errors.New("export - os.LookupEnv: POSTGRES_CONN not specified")
+	}
+	pg, err := postgres.New(pgUrl)
+	if err != nil {
+		return fmt.Errorf("export - postgres.New: %w", err)
+	}
+	defer pg.Close()
+
+	// Entries written before chaining get hashed first
+	ctx := context.Background()
+	auditRepo := pgdb.NewAuditRepo(pg)
+	if err := auditRepo.Seal(ctx); err != nil {
+		return fmt.Errorf("export - auditRepo.Seal: %w", err)
+	}
+	entries, err := auditRepo.GetRange(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("export - auditRepo.GetRange: %w", err)
+	}
+
+	records := make([]auditchain.Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, auditchain.FromEntry(entry))
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("export - os.Create: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := auditchain.Export(w, records, from, to, ed25519.PrivateKey(key)); err != nil {
+		return fmt.Errorf("export - %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d records\n", len(records))
+	return nil
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "", "export file, stdin if empty")
+	pubFile := fs.String("pubkey", "audit_key.pub", "Ed25519 public key file")
+	_ = fs.Parse(args)
+
+	pub, err := readKey(*pubFile, ed25519.PublicKeySize)
+	if err != nil {
+		return fmt.Errorf("verify - %w", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("verify - os.Open: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	summary, err := auditchain.Verify(r, ed25519.PublicKey(pub))
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
+	if summary.Records == 0 {
+		fmt.Printf("OK: no records in [%s, %s), anchor %s\n", summary.Header.From, summary.Header.To, summary.Header.Anchor)
+		return nil
+	}
+	fmt.Printf("OK: %d records, seq %d..%d, last hash %s\n", summary.Records, summary.FirstSeq, summary.LastSeq, summary.LastHash)
+	return nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func readKey(path string, size int) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("readKey - os.ReadFile: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("readKey - base64 decode: %w", err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("readKey - %s: expected %d byte key, got %d", path, size, len(key))
+	}
+	return key, nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -11,7 +12,17 @@ type (
 		App
 		HTTP
 		Log
+		Repo
 		PG
+		Auth
+		Password
+		Outbox
+		Webhook
+		Stream
+		Mail
+		Inbox
+		Idempotency
+		RateLimit
 	}
 
 	App struct {
@@ -21,15 +32,125 @@ type (
 
 	HTTP struct {
 		Address string `env-required:"true" env:"SERVER_ADDRESS"`
+		// Reject requests not matching OpenAPI document served at /api/openapi.json
+		ValidateRequests bool `env-default:"false" env:"HTTP_VALIDATE_REQUESTS"`
+		// CIDRs of reverse proxies allowed to pass client IP in X-Forwarded-For
+		TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 	}
 
 	Log struct {
 		Level string `env-required:"true" env:"LOG_LEVEL"`
 	}
 
+	Repo struct {
+		// memory keeps data in process and needs no database, for frontend development
+		Backend string `env:"REPO_BACKEND" env-default:"postgres"`
+	}
+
 	PG struct {
-		MaxPoolSize int    `env-required:"true" env:"MAX_POOL_SIZE"`
-		URL         string `env-required:"true" env:"POSTGRES_CONN"`
+		MaxPoolSize  int           `env-required:"true" env:"MAX_POOL_SIZE"`
+		URL          string        `env:"POSTGRES_CONN"`
+		TxIsolation  string        `env:"PG_TX_ISOLATION" env-default:"read committed"`
+		TxAttempts   int           `env:"PG_TX_ATTEMPTS" env-default:"3"`
+		TxRetryDelay time.Duration `env:"PG_TX_RETRY_DELAY" env-default:"20ms"`
+	}
+
+	Auth struct {
+		SignKey  string        `env-required:"true" env:"JWT_SIGN_KEY"`
+		TokenTTL time.Duration `env-default:"24h" env:"JWT_TOKEN_TTL"`
+		// Still accept username/authorId request parameters if they match token subject
+		LegacyIdentity bool `env-default:"false" env:"AUTH_LEGACY_IDENTITY"`
+		// Development only: employees without password log in with empty one.
+		// Password is then set with reset token issued by administrator
+		DevAllowPasswordless bool `env-default:"false" env:"AUTH_DEV_ALLOW_PASSWORDLESS"`
+		// Employees without password set first one presenting this token,
+		// e.g. first administrator of fresh deployment. Empty disables
+		BootstrapToken string `env:"AUTH_BOOTSTRAP_TOKEN"`
+	}
+
+	Password struct {
+		MinLength       int           `env-default:"8" env:"PASSWORD_MIN_LENGTH"`
+		RequireLetter   bool          `env-default:"true" env:"PASSWORD_REQUIRE_LETTER"`
+		RequireDigit    bool          `env-default:"true" env:"PASSWORD_REQUIRE_DIGIT"`
+		RequireSpecial  bool          `env-default:"false" env:"PASSWORD_REQUIRE_SPECIAL"`
+		BcryptCost      int           `env-default:"10" env:"PASSWORD_BCRYPT_COST"`
+		ResetTokenTTL   time.Duration `env-default:"1h" env:"PASSWORD_RESET_TOKEN_TTL"`
+		MaxFailedLogins int           `env-default:"5" env:"LOGIN_MAX_FAILED_ATTEMPTS"`
+		LockoutDuration time.Duration `env-default:"15m" env:"LOGIN_LOCKOUT_DURATION"`
+	}
+
+	Outbox struct {
+		PollInterval    time.Duration `env-default:"1s" env:"OUTBOX_POLL_INTERVAL"`
+		BatchSize       int           `env-default:"100" env:"OUTBOX_BATCH_SIZE"`
+		MaxBackoff      time.Duration `env-default:"10m" env:"OUTBOX_MAX_BACKOFF"`
+		DeliveryTimeout time.Duration `env-default:"10s" env:"OUTBOX_DELIVERY_TIMEOUT"`
+		// Claimed events are redelivered after Lease if dispatcher stops
+		Lease time.Duration `env-default:"5m" env:"OUTBOX_LEASE"`
+	}
+
+	Webhook struct {
+		PollInterval   time.Duration `env-default:"1s" env:"WEBHOOK_POLL_INTERVAL"`
+		BatchSize      int           `env-default:"20" env:"WEBHOOK_BATCH_SIZE"`
+		MaxAttempts    int           `env-default:"8" env:"WEBHOOK_MAX_ATTEMPTS"`
+		InitialBackoff time.Duration `env-default:"10s" env:"WEBHOOK_INITIAL_BACKOFF"`
+		MaxBackoff     time.Duration `env-default:"1h" env:"WEBHOOK_MAX_BACKOFF"`
+		RequestTimeout time.Duration `env-default:"10s" env:"WEBHOOK_REQUEST_TIMEOUT"`
+	}
+
+	Stream struct {
+		HistorySize int           `env-default:"1000" env:"STREAM_HISTORY_SIZE"`
+		BufferSize  int           `env-default:"64" env:"STREAM_BUFFER_SIZE"`
+		Heartbeat   time.Duration `env-default:"15s" env:"STREAM_HEARTBEAT"`
+		// Delay before listening to outbox again after connection fails
+		ListenRetry time.Duration `env-default:"5s" env:"STREAM_LISTEN_RETRY"`
+	}
+
+	Mail struct {
+		// file writes .eml files to MAIL_DROP_DIR instead of sending them
+		Transport    string        `env-default:"file" env:"MAIL_TRANSPORT"`
+		From         string        `env-default:"Tenders <noreply@localhost>" env:"MAIL_FROM"`
+		DropDir      string        `env-default:"./mail" env:"MAIL_DROP_DIR"`
+		SMTPHost     string        `env-default:"localhost" env:"SMTP_HOST"`
+		SMTPPort     int           `env-default:"25" env:"SMTP_PORT"`
+		SMTPUsername string        `env:"SMTP_USERNAME"`
+		SMTPPassword string        `env:"SMTP_PASSWORD"`
+		PollInterval time.Duration `env-default:"5s" env:"MAIL_POLL_INTERVAL"`
+		MaxAttempts  int           `env-default:"5" env:"MAIL_MAX_ATTEMPTS"`
+		// Reminder is sent when tender deadline is closer than ReminderWindow
+		ReminderWindow time.Duration `env-default:"24h" env:"DEADLINE_REMINDER_WINDOW"`
+		CheckInterval  time.Duration `env-default:"5m" env:"DEADLINE_CHECK_INTERVAL"`
+	}
+
+	Inbox struct {
+		// Notifications older than Retention are deleted, read or not
+		Retention     time.Duration `env-default:"720h" env:"INBOX_RETENTION"`
+		PruneInterval time.Duration `env-default:"1h" env:"INBOX_PRUNE_INTERVAL"`
+	}
+
+	Idempotency struct {
+		// Responses are replayed for TTL. Request repeated while first one is
+		// in progress waits up to Wait. First request holds key for Lease,
+		// lease is extended while it runs, so Lease bounds how long key of
+		// crashed instance stays taken
+		TTL           time.Duration `env-default:"24h" env:"IDEMPOTENCY_TTL"`
+		Lease         time.Duration `env-default:"1m" env:"IDEMPOTENCY_LEASE"`
+		Wait          time.Duration `env-default:"10s" env:"IDEMPOTENCY_WAIT"`
+		PollInterval  time.Duration `env-default:"100ms" env:"IDEMPOTENCY_POLL_INTERVAL"`
+		PruneInterval time.Duration `env-default:"1h" env:"IDEMPOTENCY_PRUNE_INTERVAL"`
+	}
+
+	RateLimit struct {
+		// memory limits each instance on its own, postgres shares limits
+		// between instances. Limits are requests per Period, 0 disables limit
+		Enabled            bool          `env-default:"true" env:"RATE_LIMIT_ENABLED"`
+		Store              string        `env-default:"memory" env:"RATE_LIMIT_STORE"`
+		Period             time.Duration `env-default:"1m" env:"RATE_LIMIT_PERIOD"`
+		EmployeeReads      int           `env-default:"600" env:"RATE_LIMIT_EMPLOYEE_READS"`
+		EmployeeWrites     int           `env-default:"120" env:"RATE_LIMIT_EMPLOYEE_WRITES"`
+		OrganizationReads  int           `env-default:"3000" env:"RATE_LIMIT_ORGANIZATION_READS"`
+		OrganizationWrites int           `env-default:"600" env:"RATE_LIMIT_ORGANIZATION_WRITES"`
+		IPReads            int           `env-default:"1200" env:"RATE_LIMIT_IP_READS"`
+		IPWrites           int           `env-default:"240" env:"RATE_LIMIT_IP_WRITES"`
 	}
 )
 
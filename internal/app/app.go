@@ -3,14 +3,24 @@ package app
 import (
 	"app/config"
 	httpapi "app/internal/controller/http/v1"
+	"app/internal/idempotency"
+	"app/internal/inbox"
+	"app/internal/notify"
+	"app/internal/outbox"
+	"app/internal/ratelimit"
 	"app/internal/repo"
+	"app/internal/repo/memdb"
 	"app/internal/service"
+	"app/internal/stream"
+	"app/internal/webhook"
 	"app/pkg/httpserver"
 	"app/pkg/postgres"
 	"app/pkg/validator"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/labstack/echo/v4"
@@ -27,25 +37,117 @@ func Run() {
 	// Logger
 	setLogrus(cfg.Log.Level)
 	log.Info("Config read successfully...")
-
-	// Postgres
-	log.Info("Initializing postgres...")
-	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.MaxPoolSize))
-	if err != nil {
-		log.Fatal(fmt.Errorf("app - postgres.New: %w", err))
+	if cfg.Auth.DevAllowPasswordless {
+		log.Warn("Employees without password log in with empty one, don't use in production...")
 	}
-	defer pg.Close()
 
-	// Services and repos
-	log.Info("Initializing services and repos...")
+	// Repos
+	repos, closeRepos := newRepos(cfg)
+	defer closeRepos()
+
+	// Services
+	log.Info("Initializing services...")
+	broker := stream.NewBroker(stream.HistorySize(cfg.Stream.HistorySize), stream.BufferSize(cfg.Stream.BufferSize))
 	services := service.NewServices(service.ServicesDependencies{
-		Repos: repo.NewPostgresRepo(pg),
+		Repos:  repos,
+		Broker: broker,
+		Auth: service.AuthConfig{
+			SignKey:              cfg.Auth.SignKey,
+			TokenTTL:             cfg.Auth.TokenTTL,
+			DevAllowPasswordless: cfg.Auth.DevAllowPasswordless,
+			BootstrapToken:       cfg.Auth.BootstrapToken,
+			PasswordPolicy: service.PasswordPolicy{
+				MinLength:      cfg.Password.MinLength,
+				RequireLetter:  cfg.Password.RequireLetter,
+				RequireDigit:   cfg.Password.RequireDigit,
+				RequireSpecial: cfg.Password.RequireSpecial,
+			},
+			BcryptCost:      cfg.Password.BcryptCost,
+			ResetTokenTTL:   cfg.Password.ResetTokenTTL,
+			MaxFailedLogins: cfg.Password.MaxFailedLogins,
+			LockoutDuration: cfg.Password.LockoutDuration,
+		},
+		Idempotency: service.IdempotencyConfig{
+			TTL:          cfg.Idempotency.TTL,
+			Lease:        cfg.Idempotency.Lease,
+			Wait:         cfg.Idempotency.Wait,
+			PollInterval: cfg.Idempotency.PollInterval,
+		},
 	})
 
+	// Outbox dispatcher
+	log.Info("Starting outbox dispatcher...")
+	renderer, err := notify.NewRenderer()
+	if err != nil {
+		log.Fatal(fmt.Errorf("app - notify.NewRenderer: %w", err))
+	}
+	sinks := []outbox.Sink{
+		outbox.NewLogSink(),
+		webhook.NewSink(repos.Webhook),
+		notify.NewSink(repos.Email, repos.Bid, renderer),
+		inbox.NewSink(repos.Notification, repos.Bid),
+	}
+	// Dispatcher delivers event on one instance only, so streams of every
+	// instance follow outbox themselves
+	var follower *stream.Follower
+	if repos.OutboxFeed != nil {
+		follower = stream.NewFollower(broker, repos.OutboxFeed, cfg.Stream.ListenRetry)
+		follower.Start()
+	} else {
+		sinks = append(sinks, broker)
+	}
+	dispatcher := outbox.New(repos.Outbox, repos.Transactor, sinks,
+		outbox.PollInterval(cfg.Outbox.PollInterval),
+		outbox.BatchSize(cfg.Outbox.BatchSize),
+		outbox.MaxBackoff(cfg.Outbox.MaxBackoff),
+		outbox.DeliveryTimeout(cfg.Outbox.DeliveryTimeout),
+		outbox.Lease(cfg.Outbox.Lease),
+	)
+	dispatcher.Start()
+
+	// Webhook worker
+	log.Info("Starting webhook worker...")
+	webhookWorker := webhook.NewWorker(repos.Webhook, repos.Transactor,
+		webhook.PollInterval(cfg.Webhook.PollInterval),
+		webhook.BatchSize(cfg.Webhook.BatchSize),
+		webhook.MaxAttempts(cfg.Webhook.MaxAttempts),
+		webhook.InitialBackoff(cfg.Webhook.InitialBackoff),
+		webhook.MaxBackoff(cfg.Webhook.MaxBackoff),
+		webhook.RequestTimeout(cfg.Webhook.RequestTimeout),
+	)
+	webhookWorker.Start()
+
+	// Mailer and deadline reminders
+	log.Info("Starting mailer...")
+	mailer := notify.NewMailer(repos.Email, repos.Transactor, newMailTransport(cfg.Mail), cfg.Mail.From,
+		notify.PollInterval(cfg.Mail.PollInterval),
+		notify.MaxAttempts(cfg.Mail.MaxAttempts),
+	)
+	mailer.Start()
+	scheduler := notify.NewDeadlineScheduler(services.Notification, cfg.Mail.CheckInterval, cfg.Mail.ReminderWindow)
+	scheduler.Start()
+
+	// Inbox retention
+	pruner := inbox.NewPruner(services.Inbox, cfg.Inbox.PruneInterval, cfg.Inbox.Retention)
+	pruner.Start()
+
+	// Idempotency keys retention
+	keyPruner := idempotency.NewPruner(services.Idempotency, cfg.Idempotency.PruneInterval, cfg.Idempotency.TTL)
+	keyPruner.Start()
+
 	// Echo handler
 	log.Info("Initializing handlers and routes...")
 	handler := echo.New()
-	httpapi.ConfigureRouter(handler, services)
+	routerOpts := []httpapi.Option{
+		httpapi.LegacyIdentity(cfg.Auth.LegacyIdentity),
+		httpapi.StreamHeartbeat(cfg.Stream.Heartbeat),
+		httpapi.ValidateRequests(cfg.HTTP.ValidateRequests),
+		httpapi.TrustedProxies(parseTrustedProxies(cfg.HTTP.TrustedProxies)),
+	}
+	if cfg.RateLimit.Enabled {
+		routerOpts = append(routerOpts, httpapi.RateLimiter(newRateLimiter(cfg.RateLimit, repos)))
+	}
+	httpapi.ConfigureRouter(handler, services, routerOpts...)
 	handler.Validator = validator.NewCustomValidator()
 
 	// HttpServer
@@ -67,7 +169,100 @@ func Run() {
 
 	// Graceful shutdown
 	log.Info("Graceful shutdown...")
+	// Event streams never become idle, end them before server waits for connections
+	if follower != nil {
+		follower.Stop()
+	}
+	broker.Close()
 	if err := httpServer.Shutdown(); err != nil {
 		log.Error(fmt.Errorf("app - Run - httpSever.Shutdown: %w", err))
 	}
+	scheduler.Stop()
+	pruner.Stop()
+	keyPruner.Stop()
+	dispatcher.Stop()
+	webhookWorker.Stop()
+	mailer.Stop()
+}
+
+// newRepos returns repositories of REPO_BACKEND and function releasing them
+func newRepos(cfg *config.Config) (*repo.Repositories, func()) {
+	switch cfg.Repo.Backend {
+	case "postgres":
+		if cfg.PG.URL == "" {
+			log.Fatal("app - newRepos: POSTGRES_CONN not specified")
+		}
+
+		log.Info("Initializing postgres...")
+		pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.MaxPoolSize))
+		if err != nil {
+			log.Fatal(fmt.Errorf("app - postgres.New: %w", err))
+		}
+
+		txManager, err := postgres.NewTxManager(pg,
+			postgres.TxIsolation(cfg.PG.TxIsolation),
+			postgres.TxAttempts(cfg.PG.TxAttempts),
+			postgres.TxBackoff(cfg.PG.TxRetryDelay),
+		)
+		if err != nil {
+			log.Fatal(fmt.Errorf("app - postgres.NewTxManager: %w", err))
+		}
+
+		return repo.NewPostgresRepo(pg, txManager), pg.Close
+	case "memory":
+		log.Warn("Using in-memory repos, data is lost on restart...")
+		store := memdb.NewStore()
+		store.Seed()
+
+		return repo.NewMemoryRepo(store), func() {}
+	}
+	log.Fatalf("app - newRepos: unknown REPO_BACKEND %q", cfg.Repo.Backend)
+	return nil, nil
+}
+
+func newMailTransport(cfg config.Mail) notify.Transport {
+	switch cfg.Transport {
+	case "smtp":
+		return notify.NewSMTPTransport(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword)
+	case "file":
+		return notify.NewFileTransport(cfg.DropDir)
+	}
+	log.Fatalf("app - newMailTransport: unknown MAIL_TRANSPORT %q", cfg.Transport)
+	return nil
+}
+
+func newRateLimiter(cfg config.RateLimit, repos *repo.Repositories) *ratelimit.Limiter {
+	rule := func(limit int) ratelimit.Rule {
+		return ratelimit.Rule{Limit: limit, Period: cfg.Period}
+	}
+	policy := ratelimit.Policy{
+		Employee:     ratelimit.Limits{Read: rule(cfg.EmployeeReads), Write: rule(cfg.EmployeeWrites)},
+		Organization: ratelimit.Limits{Read: rule(cfg.OrganizationReads), Write: rule(cfg.OrganizationWrites)},
+		IP:           ratelimit.Limits{Read: rule(cfg.IPReads), Write: rule(cfg.IPWrites)},
+	}
+
+	switch cfg.Store {
+	case "memory":
+		return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policy)
+	case "postgres":
+		return ratelimit.NewLimiter(ratelimit.NewPostgresStore(repos.RateLimit), policy)
+	}
+	log.Fatalf("app - newRateLimiter: unknown RATE_LIMIT_STORE %q", cfg.Store)
+	return nil
+}
+
+func parseTrustedProxies(cidrs []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatal(fmt.Errorf("app - parseTrustedProxies - net.ParseCIDR: %w", err))
+		}
+		nets = append(nets, n)
+	}
+	return nets
 }
@@ -18,6 +18,11 @@ const (
 )
 
 func Migrations() {
+	if os.Getenv("REPO_BACKEND") == "memory" {
+		log.Info("Migrations skipped for memory backend...")
+		return
+	}
+
 	log.Info("Configuring migrations...")
 	pgUrl, ok := os.LookupEnv("POSTGRES_CONN")
 	if !ok || len(pgUrl) == 0 {
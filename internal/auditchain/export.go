@@ -0,0 +1,95 @@
+package auditchain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	lineHeader    = "header"
+	lineRecord    = "record"
+	lineSignature = "signature"
+
+	formatVersion = "audit-chain/v1"
+)
+
+// Header opens export. Anchor is previous hash of first record, it links export
+// to the one before it or equals GenesisHash
+type Header struct {
+	Type        string `json:"type"`
+	Format      string `json:"format"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Anchor      string `json:"anchor"`
+	GeneratedAt string `json:"generatedAt"`
+}
+
+type recordLine struct {
+	Type string `json:"type"`
+	Record
+}
+
+// Signature closes export, it signs SHA-256 digest of all preceding lines
+type Signature struct {
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	Records   int    `json:"records"`
+	LastHash  string `json:"lastHash"`
+	Digest    string `json:"digest"`
+	Signature string `json:"signature"`
+}
+
+// Export writes records of [from, to) ordered by seq as NDJSON signed with key
+func Export(w io.Writer, records []Record, from, to time.Time, key ed25519.PrivateKey) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	anchor := GenesisHash
+	if len(records) > 0 {
+		anchor = records[0].PrevHash
+	}
+	err := enc.Encode(Header{
+		Type:        lineHeader,
+		Format:      formatVersion,
+		From:        from.UTC().Format(time.RFC3339),
+		To:          to.UTC().Format(time.RFC3339),
+		Anchor:      anchor,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("auditchain - Export - Encode header: %w", err)
+	}
+
+	lastHash := anchor
+	for _, r := range records {
+		if err := enc.Encode(recordLine{Type: lineRecord, Record: r}); err != nil {
+			return fmt.Errorf("auditchain - Export - Encode record %d: %w", r.Seq, err)
+		}
+		lastHash = r.Hash
+	}
+
+	digest := sha256.Sum256(buf.Bytes())
+	err = enc.Encode(Signature{
+		Type:      lineSignature,
+		Algorithm: "ed25519",
+		Records:   len(records),
+		LastHash:  lastHash,
+		Digest:    hex.EncodeToString(digest[:]),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest[:])),
+	})
+	if err != nil {
+		return fmt.Errorf("auditchain - Export - Encode signature: %w", err)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("auditchain - Export - Write: %w", err)
+	}
+	return nil
+}
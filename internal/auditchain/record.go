@@ -0,0 +1,120 @@
+// Package auditchain links audit log entries into SHA-256 hash chain and exports
+// it as signed NDJSON that can be verified without database
+package auditchain
+
+import (
+	e "app/internal/entity"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// GenesisHash is previous hash of the very first entry
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Record is audit entry as it is hashed and exported
+type Record struct {
+	Seq            int64           `json:"seq"`
+	Id             string          `json:"id"`
+	OrganizationId string          `json:"organizationId"`
+	ActorType      string          `json:"actorType"`
+	ActorId        string          `json:"actorId"`
+	ActorName      string          `json:"actorName"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entityType"`
+	EntityId       string          `json:"entityId"`
+	EntityVersion  int             `json:"entityVersion"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	RequestId      string          `json:"requestId"`
+	ClientIP       string          `json:"clientIp"`
+	CreatedAt      string          `json:"createdAt"`
+	PrevHash       string          `json:"prevHash"`
+	Hash           string          `json:"hash"`
+}
+
+func FromEntry(entry e.AuditEntry) Record {
+	r := Record{
+		Seq:           entry.Seq,
+		Id:            entry.Id.String(),
+		ActorType:     entry.ActorType,
+		ActorId:       entry.ActorId.String(),
+		ActorName:     entry.ActorName,
+		Action:        entry.Action,
+		EntityType:    entry.EntityType,
+		EntityId:      entry.EntityId.String(),
+		EntityVersion: entry.EntityVersion,
+		Before:        rawOrNull(entry.Before),
+		After:         rawOrNull(entry.After),
+		RequestId:     entry.RequestId,
+		ClientIP:      entry.ClientIP,
+		CreatedAt:     entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:      entry.PrevHash,
+		Hash:          entry.Hash,
+	}
+	if entry.OrganizationId.Valid {
+		r.OrganizationId = entry.OrganizationId.UUID.String()
+	}
+	return r
+}
+
+func rawOrNull(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(b)
+}
+
+// Canonical is compact JSON of all record fields except hash in fixed order
+func (r Record) Canonical() ([]byte, error) {
+	type canonical struct {
+		Seq            int64           `json:"seq"`
+		Id             string          `json:"id"`
+		OrganizationId string          `json:"organizationId"`
+		ActorType      string          `json:"actorType"`
+		ActorId        string          `json:"actorId"`
+		ActorName      string          `json:"actorName"`
+		Action         string          `json:"action"`
+		EntityType     string          `json:"entityType"`
+		EntityId       string          `json:"entityId"`
+		EntityVersion  int             `json:"entityVersion"`
+		Before         json.RawMessage `json:"before"`
+		After          json.RawMessage `json:"after"`
+		RequestId      string          `json:"requestId"`
+		ClientIP       string          `json:"clientIp"`
+		CreatedAt      string          `json:"createdAt"`
+		PrevHash       string          `json:"prevHash"`
+	}
+	return json.Marshal(canonical{
+		Seq:            r.Seq,
+		Id:             r.Id,
+		OrganizationId: r.OrganizationId,
+		ActorType:      r.ActorType,
+		ActorId:        r.ActorId,
+		ActorName:      r.ActorName,
+		Action:         r.Action,
+		EntityType:     r.EntityType,
+		EntityId:       r.EntityId,
+		EntityVersion:  r.EntityVersion,
+		Before:         r.Before,
+		After:          r.After,
+		RequestId:      r.RequestId,
+		ClientIP:       r.ClientIP,
+		CreatedAt:      r.CreatedAt,
+		PrevHash:       r.PrevHash,
+	})
+}
+
+// ComputeHash is hex SHA-256 over previous hash followed by canonical record
+func (r Record) ComputeHash() (string, error) {
+	canonical, err := r.Canonical()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(r.PrevHash))
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
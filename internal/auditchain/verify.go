@@ -0,0 +1,149 @@
+package auditchain
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+var (
+	ErrMalformed = errors.New("export is malformed")
+	ErrGap       = errors.New("chain has gap or records are reordered")
+	ErrModified  = errors.New("record was modified")
+	ErrSignature = errors.New("signature is invalid")
+)
+
+// Summary describes successfully verified export
+type Summary struct {
+	Header   Header
+	Records  int
+	FirstSeq int64
+	LastSeq  int64
+	LastHash string
+}
+
+// Verify checks export offline: header, hash of every record, links between them,
+// sequence continuity and signature over everything before signature line
+func Verify(r io.Reader, pub ed25519.PublicKey) (Summary, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	digest := sha256.New()
+	var summary Summary
+
+	// Header
+	if !scanner.Scan() {
+		return Summary{}, fmt.Errorf("%w: missing header", ErrMalformed)
+	}
+	if err := decodeLine(scanner.Bytes(), &summary.Header, lineHeader); err != nil {
+		return Summary{}, err
+	}
+	if summary.Header.Format != formatVersion {
+		return Summary{}, fmt.Errorf("%w: unsupported format %q", ErrMalformed, summary.Header.Format)
+	}
+	writeLine(digest, scanner.Bytes())
+
+	// Records up to signature
+	expectedPrev := summary.Header.Anchor
+	summary.LastHash = expectedPrev
+	var signature *Signature
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var typed struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(line, &typed); err != nil {
+			return Summary{}, fmt.Errorf("%w: line %d: %v", ErrMalformed, summary.Records+2, err)
+		}
+		if typed.Type == lineSignature {
+			signature = &Signature{}
+			if err := decodeLine(line, signature, lineSignature); err != nil {
+				return Summary{}, err
+			}
+			break
+		}
+
+		var rec recordLine
+		if err := decodeLine(line, &rec, lineRecord); err != nil {
+			return Summary{}, err
+		}
+		if err := checkRecord(rec.Record, expectedPrev, summary); err != nil {
+			return Summary{}, err
+		}
+		if summary.Records == 0 {
+			summary.FirstSeq = rec.Seq
+		}
+		summary.Records++
+		summary.LastSeq = rec.Seq
+		summary.LastHash = rec.Hash
+		expectedPrev = rec.Hash
+		writeLine(digest, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return Summary{}, fmt.Errorf("auditchain - Verify - scanner.Err: %w", err)
+	}
+
+	// Signature
+	if signature == nil {
+		return Summary{}, fmt.Errorf("%w: missing signature, export may be truncated", ErrMalformed)
+	}
+	if scanner.Scan() {
+		return Summary{}, fmt.Errorf("%w: data after signature", ErrMalformed)
+	}
+	if signature.Records != summary.Records || signature.LastHash != summary.LastHash {
+		return Summary{}, fmt.Errorf("%w: signature covers %d records ending with %s", ErrGap, signature.Records, signature.LastHash)
+	}
+	sum := digest.Sum(nil)
+	if signature.Digest != hex.EncodeToString(sum) {
+		return Summary{}, fmt.Errorf("%w: digest mismatch", ErrSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil || !ed25519.Verify(pub, sum, sig) {
+		return Summary{}, ErrSignature
+	}
+
+	return summary, nil
+}
+
+func checkRecord(rec Record, expectedPrev string, summary Summary) error {
+	if summary.Records > 0 && rec.Seq != summary.LastSeq+1 {
+		return fmt.Errorf("%w: seq %d follows %d", ErrGap, rec.Seq, summary.LastSeq)
+	}
+	if rec.PrevHash != expectedPrev {
+		return fmt.Errorf("%w: seq %d doesn't link to previous record", ErrGap, rec.Seq)
+	}
+	computed, err := rec.ComputeHash()
+	if err != nil {
+		return fmt.Errorf("%w: seq %d: %v", ErrMalformed, rec.Seq, err)
+	}
+	if computed != rec.Hash {
+		return fmt.Errorf("%w: seq %d", ErrModified, rec.Seq)
+	}
+	return nil
+}
+
+func decodeLine(line []byte, v any, lineType string) error {
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrMalformed, lineType, err)
+	}
+	var typed struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(line, &typed)
+	if typed.Type != lineType {
+		return fmt.Errorf("%w: expected %s line, got %q", ErrMalformed, lineType, typed.Type)
+	}
+	return nil
+}
+
+func writeLine(h hash.Hash, line []byte) {
+	h.Write(line)
+	h.Write([]byte("\n"))
+}
@@ -0,0 +1,194 @@
+package auditchain
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testFrom = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+)
+
+// chain links n records after anchor the way repos seal them
+func chain(t *testing.T, anchor string, firstSeq int64, n int) []Record {
+	t.Helper()
+
+	records := make([]Record, 0, n)
+	prev := anchor
+	for i := 0; i < n; i++ {
+		seq := firstSeq + int64(i)
+		r := Record{
+			Seq:           seq,
+			Id:            fmt.Sprintf("00000000-0000-0000-0000-%012d", seq),
+			ActorType:     "employee",
+			ActorId:       "00000000-0000-0000-0000-000000000001",
+			ActorName:     "alice",
+			Action:        "tender.create",
+			EntityType:    "tender",
+			EntityId:      "00000000-0000-0000-0000-0000000000f1",
+			EntityVersion: int(seq),
+			Before:        json.RawMessage("null"),
+			After:         json.RawMessage(`{"name":"Build"}`),
+			RequestId:     "req",
+			ClientIP:      "192.0.2.1",
+			CreatedAt:     testFrom.Add(time.Duration(seq) * time.Minute).Format(time.RFC3339Nano),
+			PrevHash:      prev,
+		}
+		hash, err := r.ComputeHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Hash = hash
+		prev = hash
+		records = append(records, r)
+	}
+	return records
+}
+
+// export returns lines of signed export, first is header and last is signature
+func export(t *testing.T, records []Record, key ed25519.PrivateKey) []string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := Export(&buf, records, testFrom, testTo, key); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestVerify(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full := chain(t, GenesisHash, 1, 6)
+	// Export of later range is anchored to hash of record before it
+	later := full[3:]
+
+	tests := []struct {
+		name    string
+		records []Record
+		pub     ed25519.PublicKey
+		tamper  func(lines []string) []string
+		wantErr error
+		want    Summary
+	}{
+		{
+			name:    "untouched",
+			records: full[:3],
+			want:    Summary{Records: 3, FirstSeq: 1, LastSeq: 3, LastHash: full[2].Hash},
+		},
+		{
+			name:    "untouched continuation",
+			records: later,
+			want:    Summary{Records: 3, FirstSeq: 4, LastSeq: 6, LastHash: full[5].Hash},
+		},
+		{
+			name: "empty",
+			want: Summary{LastHash: GenesisHash},
+		},
+		{
+			name:    "modified",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				lines[2] = strings.Replace(lines[2], `"actorName":"alice"`, `"actorName":"mallory"`, 1)
+				return lines
+			},
+			wantErr: ErrModified,
+		},
+		{
+			name:    "modified with recomputed hash",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				forged := full[1]
+				forged.ActorName = "mallory"
+				forged.Hash, _ = forged.ComputeHash()
+				line, _ := json.Marshal(recordLine{Type: lineRecord, Record: forged})
+				lines[2] = string(line)
+				return lines
+			},
+			wantErr: ErrGap,
+		},
+		{
+			name:    "reordered",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			wantErr: ErrGap,
+		},
+		{
+			name:    "gap",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				return append(lines[:2:2], lines[3:]...)
+			},
+			wantErr: ErrGap,
+		},
+		{
+			name:    "gap in source",
+			records: append(full[:2:2], full[3:]...),
+			wantErr: ErrGap,
+		},
+		{
+			name:    "truncated",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				return lines[:len(lines)-1]
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "truncated with signature kept",
+			records: full[:3],
+			tamper: func(lines []string) []string {
+				return append(lines[:3:3], lines[len(lines)-1])
+			},
+			wantErr: ErrGap,
+		},
+		{
+			name:    "other key",
+			records: full[:3],
+			pub:     otherPub,
+			wantErr: ErrSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := export(t, tt.records, key)
+			if tt.tamper != nil {
+				lines = tt.tamper(lines)
+			}
+			verifyKey := pub
+			if tt.pub != nil {
+				verifyKey = tt.pub
+			}
+
+			got, err := Verify(strings.NewReader(strings.Join(lines, "\n")+"\n"), verifyKey)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Records != tt.want.Records || got.FirstSeq != tt.want.FirstSeq ||
+				got.LastSeq != tt.want.LastSeq || got.LastHash != tt.want.LastHash {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package authz
+
+import (
+	e "app/internal/entity"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+var (
+	testOrg      = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	testOtherOrg = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	testAlice    = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	testBob      = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	testKeyId    = uuid.MustParse("00000000-0000-0000-0000-0000000000c1")
+
+	errFacts = errors.New("facts are unavailable")
+)
+
+// fakeFacts holds roles of employees in organizations, err fails every lookup
+type fakeFacts struct {
+	roles map[uuid.UUID]map[uuid.UUID][]string
+	err   error
+}
+
+func (f fakeFacts) GetRoles(ctx context.Context, orgId, userId uuid.UUID) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.roles[orgId][userId], nil
+}
+
+// aliceWith gives alice roles in testOrg
+func aliceWith(roles ...string) fakeFacts {
+	return fakeFacts{roles: map[uuid.UUID]map[uuid.UUID][]string{testOrg: {testAlice: roles}}}
+}
+
+var (
+	alice = EmployeeActor(testAlice, "alice")
+	bob   = EmployeeActor(testBob, "bob")
+)
+
+func apiKey(orgId uuid.UUID, scopes ...string) Actor {
+	return APIKeyActor(testKeyId, orgId, "ci", scopes)
+}
+
+// policy is Authorizer method checked against testOrg or its tender
+type policy struct {
+	name  string
+	check func(a *Authorizer, actor Actor) (Decision, error)
+}
+
+var (
+	testTender = e.Tender{Id: uuid.New(), OrganizationId: testOrg}
+	testOrgBid = e.Bid{
+		Id:             uuid.New(),
+		AuthorType:     "Organization",
+		AuthorId:       testOrg,
+		OrganizationId: uuid.NullUUID{UUID: testOrg, Valid: true},
+	}
+	ctx = context.Background()
+)
+
+var policies = []policy{
+	{"CanViewOrganization", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanViewOrganization(ctx, actor, testOrg)
+	}},
+	{"CanManageOrganization", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanManageOrganization(ctx, actor, testOrg)
+	}},
+	{"CanViewAuditLog", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanViewAuditLog(ctx, actor, testOrg)
+	}},
+	{"CanCreateTender", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanCreateTender(ctx, actor, testOrg)
+	}},
+	{"CanViewTender", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanViewTender(ctx, actor, testTender)
+	}},
+	{"CanEditTender", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanEditTender(ctx, actor, testTender)
+	}},
+	{"CanPublishTender", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanPublishTender(ctx, actor, testTender)
+	}},
+	{"CanCreateOrganizationBid", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanCreateOrganizationBid(ctx, actor, testOrg)
+	}},
+	{"CanViewBid", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanViewBid(ctx, actor, testOrgBid, testTender)
+	}},
+	{"CanEditBid", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanEditBid(ctx, actor, testOrgBid)
+	}},
+	{"CanDecideOnBid", func(a *Authorizer, actor Actor) (Decision, error) {
+		return a.CanDecideOnBid(ctx, actor, testOrgBid, testTender)
+	}},
+}
+
+// checkPolicies checks that actor is allowed exactly policies in allowed
+func checkPolicies(t *testing.T, a *Authorizer, actor Actor, allowed ...string) {
+	t.Helper()
+	want := map[string]bool{}
+	for _, name := range allowed {
+		want[name] = true
+	}
+	for _, p := range policies {
+		d, err := p.check(a, actor)
+		if err != nil {
+			t.Errorf("%s: %v", p.name, err)
+			continue
+		}
+		if d.Allowed != want[p.name] {
+			t.Errorf("%s allowed = %t, want %t (%s)", p.name, d.Allowed, want[p.name], d.Reason)
+		}
+		if d.Reason == "" {
+			t.Errorf("%s decision has no reason", p.name)
+		}
+	}
+}
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []string
+		allowed []string
+	}{
+		{name: "not responsible"},
+		{
+			name:    RoleViewer,
+			roles:   []string{RoleViewer},
+			allowed: []string{"CanViewOrganization", "CanViewTender", "CanViewBid"},
+		},
+		{
+			name:  RoleEditor,
+			roles: []string{RoleEditor},
+			allowed: []string{"CanViewOrganization", "CanCreateTender", "CanViewTender", "CanEditTender",
+				"CanCreateOrganizationBid", "CanViewBid", "CanEditBid"},
+		},
+		{
+			name:    RolePublisher,
+			roles:   []string{RolePublisher},
+			allowed: []string{"CanViewOrganization", "CanViewTender", "CanPublishTender", "CanViewBid"},
+		},
+		{
+			name:    RoleApprover,
+			roles:   []string{RoleApprover},
+			allowed: []string{"CanViewOrganization", "CanViewTender", "CanViewBid", "CanDecideOnBid"},
+		},
+		{
+			name:  RoleAdmin,
+			roles: []string{RoleAdmin},
+			allowed: []string{"CanViewOrganization", "CanManageOrganization", "CanViewAuditLog",
+				"CanCreateTender", "CanViewTender", "CanEditTender", "CanPublishTender",
+				"CanCreateOrganizationBid", "CanViewBid", "CanEditBid", "CanDecideOnBid"},
+		},
+		{
+			name:  "roles combined",
+			roles: []string{RolePublisher, RoleApprover},
+			allowed: []string{"CanViewOrganization", "CanViewTender", "CanPublishTender",
+				"CanViewBid", "CanDecideOnBid"},
+		},
+		{
+			name:    "unknown role",
+			roles:   []string{"owner"},
+			allowed: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(aliceWith(tt.roles...))
+			checkPolicies(t, a, alice, tt.allowed...)
+			// Roles are per employee
+			checkPolicies(t, a, bob)
+		})
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   Actor
+		allowed []string
+	}{
+		{name: "no scopes", actor: apiKey(testOrg)},
+		{
+			name:    ScopeTendersRead,
+			actor:   apiKey(testOrg, ScopeTendersRead),
+			allowed: []string{"CanViewTender"},
+		},
+		{
+			name:    ScopeTendersWrite,
+			actor:   apiKey(testOrg, ScopeTendersWrite),
+			allowed: []string{"CanCreateTender", "CanEditTender", "CanPublishTender"},
+		},
+		{
+			name:    ScopeBidsRead,
+			actor:   apiKey(testOrg, ScopeBidsRead),
+			allowed: []string{"CanViewBid"},
+		},
+		{
+			name:  "all scopes",
+			actor: apiKey(testOrg, ScopeTendersRead, ScopeTendersWrite, ScopeBidsRead),
+			// Keys never manage organization, read audit, author or decide on bids
+			allowed: []string{"CanCreateTender", "CanViewTender", "CanEditTender", "CanPublishTender", "CanViewBid"},
+		},
+		{
+			name:  "other organization",
+			actor: apiKey(testOtherOrg, ScopeTendersRead, ScopeTendersWrite, ScopeBidsRead),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keys don't depend on roles, facts fail if they are asked
+			checkPolicies(t, New(fakeFacts{err: errFacts}), tt.actor, tt.allowed...)
+		})
+	}
+}
+
+func TestBidAuthor(t *testing.T) {
+	userBid := e.Bid{Id: uuid.New(), AuthorType: "User", AuthorId: testAlice}
+	orphanBid := e.Bid{Id: uuid.New(), AuthorType: "Organization", AuthorId: testOrg}
+	a := New(aliceWith(RoleViewer))
+
+	tests := []struct {
+		name  string
+		actor Actor
+		bid   e.Bid
+		view  bool
+		edit  bool
+	}{
+		{name: "author", actor: alice, bid: userBid, view: true, edit: true},
+		{name: "other user", actor: bob, bid: userBid},
+		{name: "viewer of organization", actor: alice, bid: testOrgBid, view: true},
+		{name: "not responsible", actor: bob, bid: testOrgBid},
+		{name: "organization bid without organization", actor: alice, bid: orphanBid},
+		{name: "unknown author type", actor: alice, bid: e.Bid{Id: uuid.New(), AuthorType: "Robot", AuthorId: testAlice}},
+		{name: "api key of user bid", actor: apiKey(testOrg, ScopeBidsRead), bid: userBid, view: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := a.CanViewBid(ctx, tt.actor, tt.bid, testTender)
+			if err != nil || d.Allowed != tt.view {
+				t.Errorf("CanViewBid = %+v, %v, want allowed %t", d, err, tt.view)
+			}
+			d, err = a.CanEditBid(ctx, tt.actor, tt.bid)
+			if err != nil || d.Allowed != tt.edit {
+				t.Errorf("CanEditBid = %+v, %v, want allowed %t", d, err, tt.edit)
+			}
+		})
+	}
+}
+
+func TestFactsFailure(t *testing.T) {
+	a := New(fakeFacts{err: errFacts})
+	for _, p := range policies {
+		if d, err := p.check(a, alice); !errors.Is(err, errFacts) || d.Allowed {
+			t.Errorf("%s = %+v, %v, want error %v", p.name, d, err, errFacts)
+		}
+	}
+}
+
+func TestDenialLogLevel(t *testing.T) {
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.DebugLevel)
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	if _, err := New(aliceWith()).CanEditTender(ctx, alice, testTender); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(fakeFacts{err: errFacts}).CanEditTender(ctx, alice, testTender); err == nil {
+		t.Fatal("want error")
+	}
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	if entries[0].Level != log.DebugLevel {
+		t.Errorf("denial logged at %s, want debug", entries[0].Level)
+	}
+	if entries[1].Level != log.ErrorLevel {
+		t.Errorf("failure logged at %s, want error", entries[1].Level)
+	}
+}
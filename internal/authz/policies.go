@@ -0,0 +1,111 @@
+package authz
+
+import (
+	e "app/internal/entity"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (a *Authorizer) CanViewOrganization(ctx context.Context, actor Actor, orgId uuid.UUID) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, orgId, PermViewOrganization)
+	logDecision("CanViewOrganization", actor, orgId, d, err)
+	return d, err
+}
+
+// CanManageOrganization covers organization settings, responsibles, roles and API keys
+func (a *Authorizer) CanManageOrganization(ctx context.Context, actor Actor, orgId uuid.UUID) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, orgId, PermManageOrganization)
+	logDecision("CanManageOrganization", actor, orgId, d, err)
+	return d, err
+}
+
+// CanViewAuditLog allows organization admins only
+func (a *Authorizer) CanViewAuditLog(ctx context.Context, actor Actor, orgId uuid.UUID) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, orgId, PermViewAudit)
+	logDecision("CanViewAuditLog", actor, orgId, d, err)
+	return d, err
+}
+
+func (a *Authorizer) CanCreateTender(ctx context.Context, actor Actor, orgId uuid.UUID) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, orgId, PermEditTender)
+	logDecision("CanCreateTender", actor, orgId, d, err)
+	return d, err
+}
+
+func (a *Authorizer) CanViewTender(ctx context.Context, actor Actor, tender e.Tender) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, tender.OrganizationId, PermViewTender)
+	logDecision("CanViewTender", actor, tender.Id, d, err)
+	return d, err
+}
+
+// CanEditTender covers editing, rollback and invitations
+func (a *Authorizer) CanEditTender(ctx context.Context, actor Actor, tender e.Tender) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, tender.OrganizationId, PermEditTender)
+	logDecision("CanEditTender", actor, tender.Id, d, err)
+	return d, err
+}
+
+// CanPublishTender covers status and visibility changes
+func (a *Authorizer) CanPublishTender(ctx context.Context, actor Actor, tender e.Tender) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, tender.OrganizationId, PermPublishTender)
+	logDecision("CanPublishTender", actor, tender.Id, d, err)
+	return d, err
+}
+
+// CanCreateOrganizationBid allows bid editors of organization to bid on its behalf
+func (a *Authorizer) CanCreateOrganizationBid(ctx context.Context, actor Actor, orgId uuid.UUID) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, orgId, PermEditBid)
+	logDecision("CanCreateOrganizationBid", actor, orgId, d, err)
+	return d, err
+}
+
+// CanViewBid allows bid author side and API keys of tender organization
+func (a *Authorizer) CanViewBid(ctx context.Context, actor Actor, bid e.Bid, tender e.Tender) (Decision, error) {
+	var d Decision
+	var err error
+	if actor.IsAPIKey() {
+		d, err = a.orgPermission(ctx, actor, tender.OrganizationId, PermViewBid)
+	} else {
+		d, err = a.bidAuthorPermission(ctx, actor, bid, PermViewBid)
+	}
+	logDecision("CanViewBid", actor, bid.Id, d, err)
+	return d, err
+}
+
+// CanEditBid covers editing, status changes and rollback by bid author side
+func (a *Authorizer) CanEditBid(ctx context.Context, actor Actor, bid e.Bid) (Decision, error) {
+	d, err := a.bidAuthorPermission(ctx, actor, bid, PermEditBid)
+	logDecision("CanEditBid", actor, bid.Id, d, err)
+	return d, err
+}
+
+// CanDecideOnBid allows approvers of tender organization
+func (a *Authorizer) CanDecideOnBid(ctx context.Context, actor Actor, bid e.Bid, tender e.Tender) (Decision, error) {
+	d, err := a.orgPermission(ctx, actor, tender.OrganizationId, PermDecideBid)
+	logDecision("CanDecideOnBid", actor, bid.Id, d, err)
+	return d, err
+}
+
+// bidAuthorPermission: bid of user belongs to its author, bid of organization
+// to its responsibles having permission
+func (a *Authorizer) bidAuthorPermission(ctx context.Context, actor Actor, bid e.Bid, perm Permission) (Decision, error) {
+	if actor.IsAPIKey() {
+		return deny("API keys can't act as bid author"), nil
+	}
+
+	switch bid.AuthorType {
+	case "User":
+		if bid.AuthorId != actor.Id {
+			return deny("bid belongs to another user"), nil
+		}
+		return allow("actor is bid author"), nil
+	case "Organization":
+		if !bid.OrganizationId.Valid {
+			return deny("organization bid has no organization"), nil
+		}
+		return a.orgPermission(ctx, actor, bid.OrganizationId.UUID, perm)
+	}
+
+	return deny("unknown bid author type %q", bid.AuthorType), nil
+}
@@ -0,0 +1,66 @@
+package authz
+
+import "slices"
+
+// Organization roles
+const (
+	RoleViewer    = "viewer"
+	RoleEditor    = "editor"
+	RolePublisher = "publisher"
+	RoleApprover  = "approver"
+	RoleAdmin     = "admin"
+)
+
+// API key scopes
+const (
+	ScopeTendersRead  = "tenders:read"
+	ScopeTendersWrite = "tenders:write"
+	ScopeBidsRead     = "bids:read"
+)
+
+type Permission string
+
+const (
+	PermViewOrganization   Permission = "organization:view"
+	PermManageOrganization Permission = "organization:manage"
+	PermViewTender         Permission = "tender:view"
+	PermEditTender         Permission = "tender:edit"
+	PermPublishTender      Permission = "tender:publish"
+	PermViewBid            Permission = "bid:view"
+	PermEditBid            Permission = "bid:edit"
+	PermDecideBid          Permission = "bid:decide"
+	PermViewAudit          Permission = "audit:view"
+)
+
+// rolePermissions is permission matrix of organization roles
+var rolePermissions = map[string][]Permission{
+	RoleViewer:    {PermViewOrganization, PermViewTender, PermViewBid},
+	RoleEditor:    {PermViewOrganization, PermViewTender, PermEditTender, PermViewBid, PermEditBid},
+	RolePublisher: {PermViewOrganization, PermViewTender, PermPublishTender, PermViewBid},
+	RoleApprover:  {PermViewOrganization, PermViewTender, PermViewBid, PermDecideBid},
+	RoleAdmin: {
+		PermViewOrganization, PermManageOrganization,
+		PermViewTender, PermEditTender, PermPublishTender,
+		PermViewBid, PermEditBid, PermDecideBid,
+		PermViewAudit,
+	},
+}
+
+// permissionScopes maps permissions to API key scopes granting them. Permissions
+// missing here are not available to API keys
+var permissionScopes = map[Permission]string{
+	PermViewTender:    ScopeTendersRead,
+	PermEditTender:    ScopeTendersWrite,
+	PermPublishTender: ScopeTendersWrite,
+	PermViewBid:       ScopeBidsRead,
+}
+
+// RolesAllow reports if any of roles grants permission
+func RolesAllow(roles []string, perm Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(rolePermissions[role], perm) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"app/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Server WriteTimeout would cut stream, so every write gets its own deadline
+	streamWriteTimeout = 10 * time.Second
+	// Clients reconnect after this delay, sent in retry field
+	streamRetry = 3 * time.Second
+)
+
+type activityRoutes struct {
+	activityService service.Activity
+	identity        *identityResolver
+	heartbeat       time.Duration
+}
+
+func newActivityRoutes(s service.Activity, ir *identityResolver, heartbeat time.Duration) *activityRoutes {
+	return &activityRoutes{s, ir, heartbeat}
+}
+
+type ActivityDTO struct {
+	Username    string `query:"username" validate:"max=50"`
+	LastEventId string `query:"lastEventId" validate:"omitempty,uuid"`
+}
+
+// activity streams tender activity as server-sent events. Last-Event-ID header
+// (or lastEventId parameter for clients which can't set headers) resumes stream.
+// Every instance streams all events, but keeps own history since it started
+func (r *activityRoutes) activity(c echo.Context) error {
+	// Binding and validation
+	var input ActivityDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	if header := c.Request().Header.Get("Last-Event-ID"); header != "" {
+		input.LastEventId = header
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Subscribe
+	ctx := c.Request().Context()
+	activity, err := r.activityService.SubscribeActivity(ctx, username, input.LastEventId)
+	if err != nil {
+		return activityErrorResponse(c, err)
+	}
+	defer activity.Close()
+
+	// Stream events until client leaves or server shuts down
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	write := func(frame string) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(w, frame); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	frame := fmt.Sprintf("retry: %d\n\n", streamRetry.Milliseconds())
+	if activity.Resync {
+		frame += "event: resync\ndata: {}\n\n"
+	}
+	for {
+		if err := write(frame); err != nil {
+			log.Debugf("httpapi - activityRoutes.activity - write: %v", err)
+			return nil
+		}
+
+		event, ok, err := activity.Next(ctx, r.heartbeat)
+		switch {
+		case errors.Is(err, context.Canceled), errors.Is(err, service.ErrStreamClosed):
+			return nil
+		case err != nil:
+			log.Errorf("httpapi - activityRoutes.activity - Next: %v", err)
+			return nil
+		case !ok:
+			frame = ": heartbeat\n\n"
+		default:
+			frame = fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, event.Payload)
+		}
+	}
+}
+
+func activityErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrStreamClosed):
+		return newErrReasonJSON(c, http.StatusServiceUnavailable, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
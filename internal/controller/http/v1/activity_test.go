@@ -0,0 +1,168 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"app/internal/stream"
+	"app/pkg/validator"
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testActivityPath = "/api/tenders/activity"
+
+type stubActivity struct {
+	err error
+}
+
+func (s *stubActivity) SubscribeActivity(ctx context.Context, username, lastEventId string) (*service.ActivityStream, error) {
+	return nil, s.err
+}
+
+func TestActivityErrors(t *testing.T) {
+	fail := func(s *stubs, err error) { s.activity = &stubActivity{err: err} }
+
+	closed := stream.NewBroker()
+	closed.Close()
+
+	cases := []routeCase{
+		{
+			name: "anonymous", method: http.MethodGet, target: testActivityPath, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		validationCase("malformed last event id", http.MethodGet, testActivityPath+"?lastEventId=42", ""),
+		{
+			name: "malformed last event id header", method: http.MethodGet, target: testActivityPath,
+			header: with(asAlice, "Last-Event-ID", "42"),
+			code:   http.StatusBadRequest, want: reason(validator.ErrValidation.Error()),
+		},
+		{
+			name: "broker closed", method: http.MethodGet, target: testActivityPath, header: asAPIKey,
+			setup: func(s *stubs) { s.activity = service.NewActivityService(closed, nil, nil, nil) },
+			code:  http.StatusServiceUnavailable, want: reason(service.ErrStreamClosed.Error()),
+		},
+	}
+	cases = append(cases, errorCases(http.MethodGet, testActivityPath, "", fail, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrStreamClosed:        http.StatusServiceUnavailable,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestActivityStream(t *testing.T) {
+	broker := stream.NewBroker()
+	defer broker.Close()
+
+	s := newStubs()
+	s.activity = service.NewActivityService(broker, nil, nil, nil)
+	server := httptest.NewServer(newRouter(s, StreamHeartbeat(time.Hour)))
+	defer server.Close()
+
+	// Unknown resume point asks client to reload
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp := openStream(t, ctx, server.URL, with(asAPIKey, "Last-Event-ID", uuid.NewString()))
+	defer resp.Body.Close()
+
+	for header, want := range map[string]string{
+		"Content-Type":      "text/event-stream",
+		"Cache-Control":     "no-cache",
+		"X-Accel-Buffering": "no",
+	} {
+		if got := resp.Header.Get(header); got != want {
+			t.Errorf("%s %q, want %q", header, got, want)
+		}
+	}
+
+	frames := bufio.NewReader(resp.Body)
+	wantFrame(t, frames, "retry: 3000")
+	wantFrame(t, frames, "event: resync\ndata: {}")
+
+	// Subscription exists once first frame is written
+	event := e.OutboxEvent{
+		Id:      uuid.MustParse("00000000-0000-0000-0000-000000000031"),
+		Type:    service.EventTenderStatusChanged,
+		Payload: []byte(`{"tenderId":"` + testTenderId.String() + `","status":"Published","visibility":"Public"}`),
+	}
+	broker.Publish(e.OutboxEvent{
+		Id:      uuid.MustParse("00000000-0000-0000-0000-000000000032"),
+		Type:    "organization.created",
+		Payload: []byte(`{}`),
+	})
+	broker.Publish(event)
+	wantFrame(t, frames, "id: "+event.Id.String()+"\nevent: "+event.Type+"\ndata: "+string(event.Payload))
+}
+
+func TestActivityHeartbeat(t *testing.T) {
+	broker := stream.NewBroker()
+	defer broker.Close()
+
+	s := newStubs()
+	s.activity = service.NewActivityService(broker, nil, nil, nil)
+	server := httptest.NewServer(newRouter(s, StreamHeartbeat(10*time.Millisecond)))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp := openStream(t, ctx, server.URL, asAPIKey)
+	defer resp.Body.Close()
+
+	frames := bufio.NewReader(resp.Body)
+	wantFrame(t, frames, "retry: 3000")
+	wantFrame(t, frames, ": heartbeat")
+	wantFrame(t, frames, ": heartbeat")
+}
+
+func openStream(t *testing.T, ctx context.Context, url string, h http.Header) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+testActivityPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = h.Clone()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		t.Fatalf("code %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	return resp
+}
+
+// wantFrame reads one event stream frame, frames end with blank line
+func wantFrame(t *testing.T, r *bufio.Reader, want string) {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("no frame, want %q", want)
+			}
+			t.Fatalf("read frame: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Fatalf("frame %q, want %q", got, want)
+	}
+}
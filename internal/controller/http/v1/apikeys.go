@@ -0,0 +1,188 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type apiKeyRoutes struct {
+	apiKeyService service.APIKey
+	identity      *identityResolver
+}
+
+func newAPIKeyRoutes(s service.APIKey, ir *identityResolver) *apiKeyRoutes {
+	return &apiKeyRoutes{s, ir}
+}
+
+type apiKeyResponse struct {
+	Id             uuid.UUID `json:"id"`
+	OrganizationId uuid.UUID `json:"organizationId"`
+	Name           string    `json:"name"`
+	Prefix         string    `json:"prefix"`
+	Scopes         []string  `json:"scopes"`
+	ExpiresAt      *string   `json:"expiresAt"`
+	LastUsedAt     *string   `json:"lastUsedAt"`
+	RevokedAt      *string   `json:"revokedAt"`
+	CreatedAt      string    `json:"createdAt"`
+}
+
+func newAPIKeyResponse(key e.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		Id:             key.Id,
+		OrganizationId: key.OrganizationId,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		Scopes:         key.Scopes,
+		ExpiresAt:      formatOptionalTime(key.ExpiresAt),
+		LastUsedAt:     formatOptionalTime(key.LastUsedAt),
+		RevokedAt:      formatOptionalTime(key.RevokedAt),
+		CreatedAt:      key.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
+
+type NewAPIKeyDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Name           string    `json:"name" validate:"required,max=100"`
+	Scopes         []string  `json:"scopes" validate:"required,min=1,dive,oneof=tenders:read tenders:write bids:read"`
+	ExpiresInDays  int       `json:"expiresInDays" validate:"min=0,max=3650"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *apiKeyRoutes) newAPIKey(c echo.Context) error {
+	// Binding and validation
+	var input NewAPIKeyDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Create key
+	key, secret, err := r.apiKeyService.Create(c.Request().Context(), service.CreateAPIKeyInput{
+		OrganizationId: input.OrganizationId,
+		Username:       username,
+		Name:           input.Name,
+		Scopes:         input.Scopes,
+		ExpiresInDays:  input.ExpiresInDays,
+	})
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	// Create response, secret is shown only once
+	type response struct {
+		apiKeyResponse
+		Key string `json:"key"`
+	}
+
+	return c.JSON(http.StatusOK, response{
+		apiKeyResponse: newAPIKeyResponse(key),
+		Key:            secret,
+	})
+}
+
+type APIKeysDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *apiKeyRoutes) apiKeys(c echo.Context) error {
+	// Binding and validation
+	var input APIKeysDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get keys
+	keys, err := r.apiKeyService.GetByOrganization(c.Request().Context(), input.OrganizationId, username)
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	// Create response
+	responseBatch := []apiKeyResponse{}
+	for _, key := range keys {
+		responseBatch = append(responseBatch, newAPIKeyResponse(key))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type RevokeAPIKeyDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	KeyId          uuid.UUID `param:"keyId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *apiKeyRoutes) revokeAPIKey(c echo.Context) error {
+	// Binding and validation
+	var input RevokeAPIKeyDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Revoke key
+	if err := r.apiKeyService.Revoke(c.Request().Context(), input.OrganizationId, input.KeyId, username); err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func apiKeyErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFoundOrganization), errors.Is(err, service.ErrNotFoundAPIKey):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
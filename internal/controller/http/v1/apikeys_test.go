@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testAPIKey() e.APIKey {
+	return e.APIKey{
+		Id:             testKey.KeyId,
+		OrganizationId: testOrgId,
+		Name:           "ci",
+		Prefix:         "tk_ci",
+		Scopes:         []string{"tenders:read"},
+		ExpiresAt:      &testDeadline,
+		CreatedAt:      testTime,
+	}
+}
+
+const testAPIKeyJSON = `{"id":"00000000-0000-0000-0000-0000000000c1","organizationId":"00000000-0000-0000-0000-00000000000a",
+	"name":"ci","prefix":"tk_ci","scopes":["tenders:read"],"expiresAt":"2024-01-05T03:04:05Z",
+	"lastUsedAt":null,"revokedAt":null,"createdAt":"2024-01-02T03:04:05Z"`
+
+func failAPIKey(s *stubs, err error) { s.apiKey.err = err }
+
+func apiKeyErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:             http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:  http.StatusUnauthorized,
+		service.ErrForbidden:            http.StatusForbidden,
+		service.ErrNotFoundOrganization: http.StatusNotFound,
+		service.ErrNotFoundAPIKey:       http.StatusNotFound,
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	target := testOrgPath + "/api-keys"
+	body := `{"name":"ci","scopes":["tenders:read"],"expiresInDays":3}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body,
+			setup: func(s *stubs) {
+				s.apiKey.key = testAPIKey()
+				s.apiKey.secret = "tk_ci.secret"
+			},
+			code: http.StatusOK, want: testAPIKeyJSON + `,"key":"tk_ci.secret"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.apiKey.in, service.CreateAPIKeyInput{
+					OrganizationId: testOrgId,
+					Username:       "alice",
+					Name:           "ci",
+					Scopes:         []string{"tenders:read"},
+					ExpiresInDays:  3,
+				})
+			},
+		},
+		validationCase("no name", http.MethodPost, target, `{"scopes":["tenders:read"]}`),
+		validationCase("no scopes", http.MethodPost, target, `{"name":"ci","scopes":[]}`),
+		validationCase("unknown scope", http.MethodPost, target, `{"name":"ci","scopes":["bids:write"]}`),
+		validationCase("negative expiry", http.MethodPost, target, `{"name":"ci","scopes":["tenders:read"],"expiresInDays":-1}`),
+		validationCase("long expiry", http.MethodPost, target, `{"name":"ci","scopes":["tenders:read"],"expiresInDays":3651}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failAPIKey, apiKeyErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestAPIKeys(t *testing.T) {
+	target := testOrgPath + "/api-keys"
+	revoked := testTime.Add(time.Hour)
+
+	cases := []routeCase{
+		{
+			name: "keys", method: http.MethodGet, target: target,
+			setup: func(s *stubs) { s.apiKey.key = testAPIKey() },
+			code:  http.StatusOK, want: "[" + testAPIKeyJSON + "}]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.apiKey.in, []any{testOrgId, "alice"})
+			},
+		},
+		{
+			name: "revoked key", method: http.MethodGet, target: target,
+			setup: func(s *stubs) {
+				s.apiKey.key = testAPIKey()
+				s.apiKey.key.ExpiresAt = nil
+				s.apiKey.key.LastUsedAt = &testTime
+				s.apiKey.key.RevokedAt = &revoked
+			},
+			code: http.StatusOK,
+			want: `[{"id":"00000000-0000-0000-0000-0000000000c1","organizationId":"00000000-0000-0000-0000-00000000000a",
+				"name":"ci","prefix":"tk_ci","scopes":["tenders:read"],"expiresAt":null,
+				"lastUsedAt":"2024-01-02T03:04:05Z","revokedAt":"2024-01-02T04:04:05Z","createdAt":"2024-01-02T03:04:05Z"}]`,
+		},
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failAPIKey, apiKeyErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	keyId := uuid.MustParse("00000000-0000-0000-0000-0000000000c2")
+	target := testOrgPath + "/api-keys/" + keyId.String()
+
+	cases := []routeCase{
+		{
+			name: "revoked", method: http.MethodDelete, target: target,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.apiKey.in, []any{testOrgId, keyId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodDelete, target, "")...)
+	cases = append(cases, errorCases(http.MethodDelete, target, "", failAPIKey, apiKeyErrors())...)
+	runRouteCases(t, cases)
+}
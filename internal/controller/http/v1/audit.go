@@ -0,0 +1,125 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type auditRoutes struct {
+	auditService service.Audit
+	identity     *identityResolver
+}
+
+func newAuditRoutes(s service.Audit, ir *identityResolver) *auditRoutes {
+	return &auditRoutes{s, ir}
+}
+
+type auditEntryResponse struct {
+	Id            uuid.UUID       `json:"id"`
+	ActorType     string          `json:"actorType"`
+	ActorId       uuid.UUID       `json:"actorId"`
+	ActorName     string          `json:"actorName"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entityType"`
+	EntityId      uuid.UUID       `json:"entityId"`
+	EntityVersion int             `json:"entityVersion"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	RequestId     string          `json:"requestId"`
+	ClientIP      string          `json:"clientIp"`
+	CreatedAt     string          `json:"createdAt"`
+}
+
+func newAuditEntryResponse(entry e.AuditEntry) auditEntryResponse {
+	return auditEntryResponse{
+		Id:            entry.Id,
+		ActorType:     entry.ActorType,
+		ActorId:       entry.ActorId,
+		ActorName:     entry.ActorName,
+		Action:        entry.Action,
+		EntityType:    entry.EntityType,
+		EntityId:      entry.EntityId,
+		EntityVersion: entry.EntityVersion,
+		Before:        entry.Before,
+		After:         entry.After,
+		RequestId:     entry.RequestId,
+		ClientIP:      entry.ClientIP,
+		CreatedAt:     entry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+type AuditLogDTO struct {
+	LimitAndOffset
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	EntityType     string    `query:"entityType" validate:"omitempty,oneof=tender bid"`
+	EntityId       string    `query:"entityId" validate:"omitempty,uuid"`
+	Action         string    `query:"action" validate:"max=50"`
+	Actor          string    `query:"actor" validate:"max=100"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *auditRoutes) auditLog(c echo.Context) error {
+	// Binding and validation
+	var input AuditLogDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	var entityId uuid.UUID
+	if input.EntityId != "" {
+		id, err := uuid.Parse(input.EntityId)
+		if err != nil {
+			return newErrReasonJSON(c, http.StatusBadRequest, ErrInvalidParameters.Error())
+		}
+		entityId = id
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get audit log
+	entries, err := r.auditService.GetOrganizationLog(c.Request().Context(), service.GetAuditLogInput{
+		Limit:          int(input.Limit.Int32),
+		Offset:         int(input.Offset.Int32),
+		OrganizationId: input.OrganizationId,
+		Username:       username,
+		EntityType:     input.EntityType,
+		EntityId:       entityId,
+		Action:         input.Action,
+		ActorName:      input.Actor,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, service.ErrForbidden):
+			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrNotFoundOrganization):
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Create response
+	responseBatch := []auditEntryResponse{}
+	for _, entry := range entries {
+		responseBatch = append(responseBatch, newAuditEntryResponse(entry))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
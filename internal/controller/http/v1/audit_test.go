@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditLog(t *testing.T) {
+	target := testOrgPath + "/audit"
+	entry := e.AuditEntry{
+		Id:            uuid.MustParse("00000000-0000-0000-0000-0000000000a1"),
+		Seq:           7,
+		ActorType:     "employee",
+		ActorId:       alice.Id,
+		ActorName:     "alice",
+		Action:        "tender.status_changed",
+		EntityType:    "tender",
+		EntityId:      testTenderId,
+		EntityVersion: 1,
+		Before:        []byte(`{"status":"Created"}`),
+		After:         []byte(`{"status":"Published"}`),
+		RequestId:     "req-1",
+		ClientIP:      "192.0.2.1",
+		CreatedAt:     testTime,
+		Hash:          "not exposed",
+	}
+	fail := func(s *stubs, err error) { s.audit.err = err }
+
+	cases := []routeCase{
+		{
+			name: "entries", method: http.MethodGet,
+			target: target + "?entityType=tender&entityId=" + testTenderId.String() + "&action=tender.status_changed&actor=alice&limit=10",
+			setup:  func(s *stubs) { s.audit.entries = []e.AuditEntry{entry} },
+			code:   http.StatusOK,
+			want: `[{"id":"00000000-0000-0000-0000-0000000000a1","actorType":"employee",
+				"actorId":"00000000-0000-0000-0000-000000000001","actorName":"alice","action":"tender.status_changed",
+				"entityType":"tender","entityId":"00000000-0000-0000-0000-0000000000f1","entityVersion":1,
+				"before":{"status":"Created"},"after":{"status":"Published"},"requestId":"req-1",
+				"clientIp":"192.0.2.1","createdAt":"2024-01-02T03:04:05Z"}]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.audit.in, service.GetAuditLogInput{
+					Limit:          10,
+					OrganizationId: testOrgId,
+					Username:       "alice",
+					EntityType:     "tender",
+					EntityId:       testTenderId,
+					Action:         "tender.status_changed",
+					ActorName:      "alice",
+				})
+			},
+		},
+		{
+			name: "entry without snapshots", method: http.MethodGet, target: target,
+			setup: func(s *stubs) {
+				entry := entry
+				entry.Before, entry.After = nil, nil
+				s.audit.entries = []e.AuditEntry{entry}
+			},
+			code: http.StatusOK,
+			check: func(t *testing.T, _ *stubs, rec *httptest.ResponseRecorder) {
+				wantJSON(t, rec.Body.Bytes(), `[{"id":"00000000-0000-0000-0000-0000000000a1","actorType":"employee",
+					"actorId":"00000000-0000-0000-0000-000000000001","actorName":"alice","action":"tender.status_changed",
+					"entityType":"tender","entityId":"00000000-0000-0000-0000-0000000000f1","entityVersion":1,
+					"before":null,"after":null,"requestId":"req-1","clientIp":"192.0.2.1","createdAt":"2024-01-02T03:04:05Z"}]`)
+			},
+		},
+		{name: "empty", method: http.MethodGet, target: target, code: http.StatusOK, want: `[]`},
+		validationCase("unknown entity type", http.MethodGet, target+"?entityType=organization", ""),
+		validationCase("malformed entity id", http.MethodGet, target+"?entityId=42", ""),
+		validationCase("long action", http.MethodGet, target+"?action="+longString(51), ""),
+		validationCase("long actor", http.MethodGet, target+"?actor="+longString(101), ""),
+		invalidCase("negative offset", http.MethodGet, target+"?offset=-1", ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", fail, map[error]int{
+		service.ErrUsername:             http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:  http.StatusUnauthorized,
+		service.ErrForbidden:            http.StatusForbidden,
+		service.ErrNotFoundOrganization: http.StatusNotFound,
+	})...)
+	runRouteCases(t, cases)
+}
@@ -0,0 +1,300 @@
+package httpapi
+
+import (
+	"app/internal/identity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type authRoutes struct {
+	authService service.Auth
+	identity    *identityResolver
+}
+
+func newAuthRoutes(s service.Auth, ir *identityResolver) *authRoutes {
+	return &authRoutes{s, ir}
+}
+
+type LoginDTO struct {
+	Username string `json:"username" validate:"required,max=50"`
+	Password string `json:"password" validate:"max=72"`
+}
+
+func (r *authRoutes) login(c echo.Context) error {
+	// Binding and validation
+	var input LoginDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Issue token
+	token, err := r.authService.Login(c.Request().Context(), service.LoginInput{
+		Username: input.Username,
+		Password: input.Password,
+		ClientIP: c.RealIP(),
+	})
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	// Create response
+	type response struct {
+		AccessToken string `json:"accessToken"`
+		TokenType   string `json:"tokenType"`
+		ExpiresAt   string `json:"expiresAt"`
+	}
+
+	return c.JSON(http.StatusOK, response{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   token.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+func (r *authRoutes) me(c echo.Context) error {
+	employee, ok := identity.EmployeeFromContext(c.Request().Context())
+	if !ok {
+		return newErrReasonJSON(c, http.StatusUnauthorized, ErrUnauthenticated.Error())
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(employee))
+}
+
+type SetPasswordDTO struct {
+	Username       string `json:"username" validate:"required,max=50"`
+	BootstrapToken string `json:"bootstrapToken" validate:"required"`
+	NewPassword    string `json:"newPassword" validate:"required"`
+}
+
+// setPassword needs no access token, employee has no password to get one
+func (r *authRoutes) setPassword(c echo.Context) error {
+	// Binding and validation
+	var input SetPasswordDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Set password
+	err := r.authService.SetPassword(c.Request().Context(), service.SetPasswordInput{
+		Username:       input.Username,
+		BootstrapToken: input.BootstrapToken,
+		NewPassword:    input.NewPassword,
+	})
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type ChangePasswordDTO struct {
+	OldPassword string `json:"oldPassword" validate:"required,max=72"`
+	NewPassword string `json:"newPassword" validate:"required"`
+	Username    string `query:"username" validate:"max=50"`
+}
+
+func (r *authRoutes) changePassword(c echo.Context) error {
+	// Binding and validation
+	var input ChangePasswordDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Change password
+	err = r.authService.ChangePassword(c.Request().Context(), service.ChangePasswordInput{
+		Username:    username,
+		OldPassword: input.OldPassword,
+		NewPassword: input.NewPassword,
+	})
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type NewResetTokenDTO struct {
+	EmployeeId uuid.UUID `json:"employeeId" validate:"required"`
+	Username   string    `query:"username" validate:"max=50"`
+}
+
+func (r *authRoutes) newResetToken(c echo.Context) error {
+	// Binding and validation
+	var input NewResetTokenDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Create reset token
+	token, err := r.authService.CreateResetToken(c.Request().Context(), input.EmployeeId, username)
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	// Create response
+	type response struct {
+		ResetToken string `json:"resetToken"`
+		ExpiresAt  string `json:"expiresAt"`
+	}
+
+	return c.JSON(http.StatusOK, response{
+		ResetToken: token.Token,
+		ExpiresAt:  token.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+type ResetPasswordDTO struct {
+	ResetToken  string `json:"resetToken" validate:"required,max=100"`
+	NewPassword string `json:"newPassword" validate:"required"`
+}
+
+func (r *authRoutes) resetPassword(c echo.Context) error {
+	// Binding and validation
+	var input ResetPasswordDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Reset password
+	err := r.authService.ResetPassword(c.Request().Context(), service.ResetPasswordInput{
+		ResetToken:  input.ResetToken,
+		NewPassword: input.NewPassword,
+	})
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type LoginAttemptsDTO struct {
+	LimitAndOffset
+	EmployeeUsername string `query:"employeeUsername" validate:"max=50"`
+	Username         string `query:"username" validate:"max=50"`
+}
+
+func (r *authRoutes) loginAttempts(c echo.Context) error {
+	// Binding and validation
+	var input LoginAttemptsDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get login attempts
+	attempts, err := r.authService.GetLoginAttempts(c.Request().Context(), service.GetLoginAttemptsInput{
+		Limit:          int(input.Limit.Int32),
+		Offset:         int(input.Offset.Int32),
+		Username:       username,
+		TargetUsername: input.EmployeeUsername,
+	})
+	if err != nil {
+		return authErrorResponse(c, err)
+	}
+
+	// Create response
+	type response struct {
+		Id         uuid.UUID  `json:"id"`
+		EmployeeId *uuid.UUID `json:"employeeId"`
+		Username   string     `json:"username"`
+		ClientIP   string     `json:"clientIp"`
+		Success    bool       `json:"success"`
+		Reason     string     `json:"reason"`
+		CreatedAt  string     `json:"createdAt"`
+	}
+	responseBatch := []response{}
+	for _, a := range attempts {
+		var employeeId *uuid.UUID
+		if a.EmployeeId.Valid {
+			employeeId = &a.EmployeeId.UUID
+		}
+		responseBatch = append(responseBatch, response{
+			Id:         a.Id,
+			EmployeeId: employeeId,
+			Username:   a.Username,
+			ClientIP:   a.ClientIP,
+			Success:    a.Success,
+			Reason:     a.Reason,
+			CreatedAt:  a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+func authErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername),
+		errors.Is(err, service.ErrEmployeeDeactivated),
+		errors.Is(err, service.ErrInvalidCredentials),
+		errors.Is(err, service.ErrInvalidBootstrapToken):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFoundEmployee):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrPasswordAlreadySet):
+		return newErrReasonJSON(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrAccountLocked):
+		return newErrReasonJSON(c, http.StatusLocked, err.Error())
+	case errors.Is(err, service.ErrWeakPassword), errors.Is(err, service.ErrInvalidResetToken):
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
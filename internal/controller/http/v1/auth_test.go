@@ -0,0 +1,216 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func failAuth(s *stubs, err error) { s.auth.err = err }
+
+// authErrors is mapping of authErrorResponse
+func authErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:              http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:   http.StatusUnauthorized,
+		service.ErrInvalidCredentials:    http.StatusUnauthorized,
+		service.ErrInvalidBootstrapToken: http.StatusUnauthorized,
+		service.ErrForbidden:             http.StatusForbidden,
+		service.ErrNotFoundEmployee:      http.StatusNotFound,
+		service.ErrPasswordAlreadySet:    http.StatusConflict,
+		service.ErrAccountLocked:         http.StatusLocked,
+		service.ErrWeakPassword:          http.StatusBadRequest,
+		service.ErrInvalidResetToken:     http.StatusBadRequest,
+	}
+}
+
+func TestLogin(t *testing.T) {
+	const target = "/api/auth/login"
+	body := `{"username":"alice","password":"secret"}`
+
+	cases := []routeCase{
+		{
+			name: "logged in", method: http.MethodPost, target: target, body: body, header: asAnonymous,
+			setup: func(s *stubs) { s.auth.token = service.Token{AccessToken: "alice-token", ExpiresAt: testTime} },
+			code:  http.StatusOK,
+			want:  `{"accessToken":"alice-token","tokenType":"Bearer","expiresAt":"2024-01-02T03:04:05Z"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, service.LoginInput{Username: "alice", Password: "secret", ClientIP: "192.0.2.1"})
+			},
+		},
+		{
+			name: "forwarded for ignored", method: http.MethodPost, target: target, body: body,
+			header: header(echo.HeaderXForwardedFor, "203.0.113.1", echo.HeaderXRealIP, "203.0.113.2"),
+			code:   http.StatusOK,
+			check:  wantClientIP("192.0.2.1"),
+		},
+		{
+			name: "forwarded for from trusted proxy", method: http.MethodPost, target: target, body: body,
+			header: header(echo.HeaderXForwardedFor, "203.0.113.1"),
+			opts:   []Option{TrustedProxies([]*net.IPNet{{IP: net.IPv4(192, 0, 2, 0), Mask: net.CIDRMask(24, 32)}})},
+			code:   http.StatusOK,
+			check:  wantClientIP("203.0.113.1"),
+		},
+		validationCase("no username", http.MethodPost, target, `{"password":"secret"}`),
+		validationCase("long password", http.MethodPost, target, `{"username":"alice","password":"`+longString(73)+`"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPost, target, body, failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
+
+// wantClientIP checks client IP login attempt and its audit entries are
+// recorded with
+func wantClientIP(ip string) func(*testing.T, *stubs, *httptest.ResponseRecorder) {
+	return func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+		in, _ := s.auth.in.(service.LoginInput)
+		if in.ClientIP != ip || s.auth.request.ClientIP != ip {
+			t.Errorf("login client IP %q, request client IP %q, want %q", in.ClientIP, s.auth.request.ClientIP, ip)
+		}
+	}
+}
+
+func TestMe(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{
+			name: "anonymous", method: http.MethodGet, target: "/api/auth/me", header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+	})
+}
+
+func TestSetPassword(t *testing.T) {
+	const target = "/api/auth/password"
+	body := `{"username":"alice","bootstrapToken":"bootstrap","newPassword":"correct horse"}`
+
+	cases := []routeCase{
+		{
+			name: "set", method: http.MethodPut, target: target, body: body, header: asAnonymous,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, service.SetPasswordInput{
+					Username:       "alice",
+					BootstrapToken: "bootstrap",
+					NewPassword:    "correct horse",
+				})
+			},
+		},
+		validationCase("no username", http.MethodPut, target, `{"bootstrapToken":"bootstrap","newPassword":"correct horse"}`),
+		validationCase("no token", http.MethodPut, target, `{"username":"alice","newPassword":"correct horse"}`),
+		validationCase("no password", http.MethodPut, target, `{"username":"alice","bootstrapToken":"bootstrap"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPut, target, body, failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestChangePassword(t *testing.T) {
+	const target = "/api/auth/password/change"
+	body := `{"oldPassword":"secret","newPassword":"correct horse"}`
+
+	cases := []routeCase{
+		{
+			name: "changed", method: http.MethodPost, target: target, body: body,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, service.ChangePasswordInput{
+					Username:    "alice",
+					OldPassword: "secret",
+					NewPassword: "correct horse",
+				})
+			},
+		},
+		validationCase("no old password", http.MethodPost, target, `{"newPassword":"correct horse"}`),
+		validationCase("no new password", http.MethodPost, target, `{"oldPassword":"secret"}`),
+		validationCase("long old password", http.MethodPost, target, `{"oldPassword":"`+longString(73)+`","newPassword":"x"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestNewResetToken(t *testing.T) {
+	const target = "/api/auth/password/reset-tokens"
+	body := `{"employeeId":"` + testEmployeeId.String() + `"}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body,
+			setup: func(s *stubs) { s.auth.resetToken = service.ResetToken{Token: "reset", ExpiresAt: testTime} },
+			code:  http.StatusOK, want: `{"resetToken":"reset","expiresAt":"2024-01-02T03:04:05Z"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, []any{testEmployeeId, "alice"})
+			},
+		},
+		validationCase("no employee", http.MethodPost, target, `{}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestResetPassword(t *testing.T) {
+	const target = "/api/auth/password/reset"
+	body := `{"resetToken":"reset","newPassword":"correct horse"}`
+
+	cases := []routeCase{
+		{
+			name: "reset", method: http.MethodPost, target: target, body: body, header: asAnonymous,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, service.ResetPasswordInput{ResetToken: "reset", NewPassword: "correct horse"})
+			},
+		},
+		validationCase("no token", http.MethodPost, target, `{"newPassword":"correct horse"}`),
+		validationCase("long token", http.MethodPost, target, `{"resetToken":"`+longString(101)+`","newPassword":"x"}`),
+		validationCase("no password", http.MethodPost, target, `{"resetToken":"reset"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPost, target, body, failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestLoginAttempts(t *testing.T) {
+	const target = "/api/auth/login-attempts"
+	attempts := []e.LoginAttempt{
+		{
+			Id:         uuid.MustParse("00000000-0000-0000-0000-0000000000d1"),
+			EmployeeId: uuid.NullUUID{UUID: alice.Id, Valid: true},
+			Username:   "alice",
+			ClientIP:   "192.0.2.1",
+			Success:    true,
+			CreatedAt:  testTime,
+		},
+		{
+			Id:        uuid.MustParse("00000000-0000-0000-0000-0000000000d2"),
+			Username:  "mallory",
+			ClientIP:  "192.0.2.2",
+			Reason:    "unknown_username",
+			CreatedAt: testTime,
+		},
+	}
+
+	cases := []routeCase{
+		{
+			name: "attempts", method: http.MethodGet, target: target + "?employeeUsername=carol&limit=2",
+			setup: func(s *stubs) { s.auth.attempts = attempts },
+			code:  http.StatusOK,
+			want: `[{"id":"00000000-0000-0000-0000-0000000000d1","employeeId":"00000000-0000-0000-0000-000000000001",
+				"username":"alice","clientIp":"192.0.2.1","success":true,"reason":"","createdAt":"2024-01-02T03:04:05Z"},
+				{"id":"00000000-0000-0000-0000-0000000000d2","employeeId":null,"username":"mallory",
+				"clientIp":"192.0.2.2","success":false,"reason":"unknown_username","createdAt":"2024-01-02T03:04:05Z"}]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.auth.in, service.GetLoginAttemptsInput{Limit: 2, Username: "alice", TargetUsername: "carol"})
+			},
+		},
+		{name: "empty", method: http.MethodGet, target: target, code: http.StatusOK, want: `[]`},
+		validationCase("long employee username", http.MethodGet, target+"?employeeUsername="+longString(51), ""),
+		invalidCase("negative limit", http.MethodGet, target+"?limit=-1", ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failAuth, authErrors())...)
+	runRouteCases(t, cases)
+}
@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	e "app/internal/entity"
 	"app/internal/service"
 	"errors"
 	"net/http"
@@ -11,10 +12,11 @@ import (
 
 type bidRoutes struct {
 	bidService service.Bid
+	identity   *identityResolver
 }
 
-func newBidRoutes(s service.Bid) *bidRoutes {
-	return &bidRoutes{s}
+func newBidRoutes(s service.Bid, ir *identityResolver) *bidRoutes {
+	return &bidRoutes{s, ir}
 }
 
 type NewBidDTO struct {
@@ -22,7 +24,9 @@ type NewBidDTO struct {
 	Description string    `json:"description" validate:"required,max=500"`
 	TenderId    uuid.UUID `json:"tenderId" validate:"required"`
 	AuthorType  string    `json:"authorType" validate:"required,oneof=User Organization"`
-	AuthorId    uuid.UUID `json:"authorId" validate:"required"`
+	AuthorId    uuid.UUID `json:"authorId"`
+	// Required for organization bids
+	OrganizationId uuid.UUID `json:"organizationId"`
 }
 
 func (r *bidRoutes) newBid(c echo.Context) error {
@@ -36,21 +40,31 @@ func (r *bidRoutes) newBid(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	authorId, err := r.identity.employeeId(c, input.AuthorId)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Create bid
 	bid, err := r.bidService.CreateBid(c.Request().Context(), service.CreateBidInput{
-		Name:        input.Name,
-		Description: input.Description,
-		TenderId:    input.TenderId,
-		AuthorType:  input.AuthorType,
-		AuthorId:    input.AuthorId,
+		Name:           input.Name,
+		Description:    input.Description,
+		TenderId:       input.TenderId,
+		AuthorType:     input.AuthorType,
+		AuthorId:       authorId,
+		OrganizationId: input.OrganizationId,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundTender) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrBidOrganization) {
+			return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
@@ -58,31 +72,13 @@ func (r *bidRoutes) newBid(c echo.Context) error {
 	}
 
 	// Create response
-	type response struct {
-		Id         uuid.UUID `json:"id"`
-		Name       string    `json:"name"`
-		Status     string    `json:"status"`
-		AuthorType string    `json:"authorType"`
-		AuthorId   uuid.UUID `json:"authorId"`
-		Version    int       `json:"version"`
-		CreatedAt  string    `json:"createdAt"`
-	}
-
-	return c.JSON(http.StatusOK, response{
-		Id:         bid.Id,
-		Name:       bid.Name,
-		Status:     bid.Status,
-		AuthorType: bid.AuthorType,
-		AuthorId:   bid.AuthorId,
-		Version:    bid.Version,
-		CreatedAt:  bid.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	return c.JSON(http.StatusOK, newBidResponse(bid))
 }
 
 type SubmitDecisionDTO struct {
 	BidId    uuid.UUID `param:"bidId" validate:"required"`
 	Decision string    `query:"decision" validate:"required,oneof=Approved Rejected"`
-	Username string    `query:"username" validate:"required,max=50"`
+	Username string    `query:"username" validate:"max=50"`
 }
 
 func (r *bidRoutes) submitDecision(c echo.Context) error {
@@ -100,10 +96,16 @@ func (r *bidRoutes) submitDecision(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Make decision
-	bid, err := r.bidService.SubmitDecision(c.Request().Context(), input.BidId, input.Username, input.Decision)
+	bid, err := r.bidService.SubmitDecision(c.Request().Context(), input.BidId, username, input.Decision)
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundTender) {
@@ -112,6 +114,9 @@ func (r *bidRoutes) submitDecision(c echo.Context) error {
 		if errors.Is(err, service.ErrNotFoundBid) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
@@ -119,31 +124,14 @@ func (r *bidRoutes) submitDecision(c echo.Context) error {
 	}
 
 	// Create response
-	type response struct {
-		Id         uuid.UUID `json:"id"`
-		Name       string    `json:"name"`
-		Status     string    `json:"status"`
-		AuthorType string    `json:"authorType"`
-		AuthorId   uuid.UUID `json:"authorId"`
-		Version    int       `json:"version"`
-		CreatedAt  string    `json:"createdAt"`
-	}
-
-	return c.JSON(http.StatusOK, response{
-		Id:         bid.Id,
-		Name:       bid.Name,
-		Status:     bid.Status,
-		AuthorType: bid.AuthorType,
-		AuthorId:   bid.AuthorId,
-		Version:    bid.Version,
-		CreatedAt:  bid.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	return c.JSON(http.StatusOK, newBidResponse(bid))
 }
 
 type PutBidStatusDTO struct {
-	BidId    uuid.UUID `param:"bidId" validate:"required"`
-	Status   string    `query:"status" validate:"required,oneof=Created Published Canceled"`
-	Username string    `query:"username" validate:"required,max=50"`
+	BidId           uuid.UUID `param:"bidId" validate:"required"`
+	Status          string    `query:"status" validate:"required,oneof=Created Published Canceled"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 }
 
 func (r *bidRoutes) putStatus(c echo.Context) error {
@@ -161,46 +149,53 @@ func (r *bidRoutes) putStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Change status
-	bid, err := r.bidService.ChangeStatus(c.Request().Context(), input.BidId, input.Status, input.Username)
+	bid, err := r.bidService.ChangeStatus(c.Request().Context(), service.ChangeBidStatusInput{
+		BidId:        input.BidId,
+		Status:       input.Status,
+		Username:     username,
+		Precondition: pre,
+	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundBid) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
-	// Create response
-	type response struct {
-		Id         uuid.UUID `json:"id"`
-		Name       string    `json:"name"`
-		Status     string    `json:"status"`
-		AuthorType string    `json:"authorType"`
-		AuthorId   uuid.UUID `json:"authorId"`
-		Version    int       `json:"version"`
-		CreatedAt  string    `json:"createdAt"`
-	}
+	setETag(c, bid.Version, bid.UpdatedAt)
 
-	return c.JSON(http.StatusOK, response{
-		Id:         bid.Id,
-		Name:       bid.Name,
-		Status:     bid.Status,
-		AuthorType: bid.AuthorType,
-		AuthorId:   bid.AuthorId,
-		Version:    bid.Version,
-		CreatedAt:  bid.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	// Create response
+	return c.JSON(http.StatusOK, newBidResponse(bid))
 }
 
 type GetBidStatusDTO struct {
 	BidId    uuid.UUID `param:"bidId" validate:"required"`
-	Username string    `query:"username" validate:"required,max=50"`
+	Username string    `query:"username" validate:"max=50"`
 }
 
 func (r *bidRoutes) getStatus(c echo.Context) error {
@@ -214,10 +209,16 @@ func (r *bidRoutes) getStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Get status
-	bid, err := r.bidService.Get(c.Request().Context(), input.BidId, input.Username)
+	bid, err := r.bidService.Get(c.Request().Context(), input.BidId, username)
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundBid) {
@@ -229,13 +230,16 @@ func (r *bidRoutes) getStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, bid.Version, bid.UpdatedAt)
+
 	// Create respone
 	return c.JSON(http.StatusOK, bid.Status)
 }
 
 type EditBidDTO struct {
-	BidId    uuid.UUID `param:"bidId" validate:"required"`
-	Username string    `query:"username" validate:"required,max=50"`
+	BidId           uuid.UUID `param:"bidId" validate:"required"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 	EditBidBody
 }
 
@@ -257,52 +261,56 @@ func (r *bidRoutes) editBid(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Edit bid
 	bid, err := r.bidService.Edit(c.Request().Context(), service.EditBidInput{
-		BidId:       input.BidId,
-		Username:    input.Username,
-		Name:        input.Name.String,
-		Description: input.Description.String,
+		BidId:        input.BidId,
+		Username:     username,
+		Name:         input.Name.String,
+		Description:  input.Description.String,
+		Precondition: pre,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundBid) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
-	// Create response
-	type response struct {
-		Id         uuid.UUID `json:"id"`
-		Name       string    `json:"name"`
-		Status     string    `json:"status"`
-		AuthorType string    `json:"authorType"`
-		AuthorId   uuid.UUID `json:"authorId"`
-		Version    int       `json:"version"`
-		CreatedAt  string    `json:"createdAt"`
-	}
+	setETag(c, bid.Version, bid.UpdatedAt)
 
-	return c.JSON(http.StatusOK, response{
-		Id:         bid.Id,
-		Name:       bid.Name,
-		Status:     bid.Status,
-		AuthorType: bid.AuthorType,
-		AuthorId:   bid.AuthorId,
-		Version:    bid.Version,
-		CreatedAt:  bid.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	// Create response
+	return c.JSON(http.StatusOK, newBidResponse(bid))
 }
 
 type RollbackBidDTO struct {
-	BidId    uuid.UUID `param:"bidId" validate:"required"`
-	Version  int       `param:"version" validate:"required,gt=0"`
-	Username string    `query:"username" validate:"required,max=50"`
+	BidId           uuid.UUID `param:"bidId" validate:"required"`
+	Version         int       `param:"version" validate:"required,gt=0"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 }
 
 func (r *bidRoutes) rollbackBid(c echo.Context) error {
@@ -320,33 +328,63 @@ func (r *bidRoutes) rollbackBid(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Rollback bid
-	bid, err := r.bidService.Rollback(c.Request().Context(), input.BidId, input.Version, input.Username)
+	bid, err := r.bidService.Rollback(c.Request().Context(), service.RollbackBidInput{
+		BidId:        input.BidId,
+		Version:      input.Version,
+		Username:     username,
+		Precondition: pre,
+	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundBid) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, bid.Version, bid.UpdatedAt)
+
 	// Create response
-	type response struct {
-		Id         uuid.UUID `json:"id"`
-		Name       string    `json:"name"`
-		Status     string    `json:"status"`
-		AuthorType string    `json:"authorType"`
-		AuthorId   uuid.UUID `json:"authorId"`
-		Version    int       `json:"version"`
-		CreatedAt  string    `json:"createdAt"`
-	}
+	return c.JSON(http.StatusOK, newBidResponse(bid))
+}
 
-	return c.JSON(http.StatusOK, response{
+type bidResponse struct {
+	Id             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	AuthorType     string     `json:"authorType"`
+	AuthorId       uuid.UUID  `json:"authorId"`
+	OrganizationId *uuid.UUID `json:"organizationId,omitempty"`
+	Version        int        `json:"version"`
+	CreatedAt      string     `json:"createdAt"`
+}
+
+func newBidResponse(bid e.Bid) bidResponse {
+	resp := bidResponse{
 		Id:         bid.Id,
 		Name:       bid.Name,
 		Status:     bid.Status,
@@ -354,5 +392,9 @@ func (r *bidRoutes) rollbackBid(c echo.Context) error {
 		AuthorId:   bid.AuthorId,
 		Version:    bid.Version,
 		CreatedAt:  bid.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	})
+	}
+	if bid.OrganizationId.Valid {
+		resp.OrganizationId = &bid.OrganizationId.UUID
+	}
+	return resp
 }
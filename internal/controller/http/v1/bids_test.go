@@ -0,0 +1,275 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const testBidPath = "/api/bids/00000000-0000-0000-0000-0000000000b1"
+
+var testBidId = uuid.MustParse("00000000-0000-0000-0000-0000000000b1")
+
+func testBid() e.Bid {
+	return e.Bid{
+		Id:          testBidId,
+		Name:        "Offer",
+		Description: "Cheap",
+		AuthorType:  "User",
+		AuthorId:    alice.Id,
+		Status:      "Created",
+		Version:     1,
+		TenderId:    testTenderId,
+		CreatedAt:   testTime,
+		UpdatedAt:   testTime,
+	}
+}
+
+const testBidJSON = `{"id":"00000000-0000-0000-0000-0000000000b1","name":"Offer","status":"Created",
+	"authorType":"User","authorId":"00000000-0000-0000-0000-000000000001","version":1,
+	"createdAt":"2024-01-02T03:04:05Z"}`
+
+func withBid(s *stubs) { s.bid.bid = testBid() }
+
+func failBid(s *stubs, err error) { s.bid.err = err }
+
+func TestNewBid(t *testing.T) {
+	const target = "/api/bids/new"
+	body := `{"name":"Offer","description":"Cheap","tenderId":"` + testTenderId.String() + `","authorType":"User"}`
+
+	cases := []routeCase{
+		{
+			name: "user bid", method: http.MethodPost, target: target, body: body, setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, service.CreateBidInput{
+					Name:        "Offer",
+					Description: "Cheap",
+					TenderId:    testTenderId,
+					AuthorType:  "User",
+					AuthorId:    alice.Id,
+				})
+			},
+		},
+		{
+			name: "organization bid", method: http.MethodPost, target: target,
+			body: `{"name":"Offer","description":"Cheap","tenderId":"` + testTenderId.String() + `",
+				"authorType":"Organization","organizationId":"` + testOrgId.String() + `"}`,
+			setup: func(s *stubs) {
+				s.bid.bid = testBid()
+				s.bid.bid.AuthorType = "Organization"
+				s.bid.bid.OrganizationId = uuid.NullUUID{UUID: testOrgId, Valid: true}
+			},
+			code: http.StatusOK,
+			want: `{"id":"00000000-0000-0000-0000-0000000000b1","name":"Offer","status":"Created",
+				"authorType":"Organization","authorId":"00000000-0000-0000-0000-000000000001",
+				"organizationId":"00000000-0000-0000-0000-00000000000a","version":1,"createdAt":"2024-01-02T03:04:05Z"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if in := s.bid.in.(service.CreateBidInput); in.OrganizationId != testOrgId {
+					t.Errorf("organization %s", in.OrganizationId)
+				}
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodPost, target: target, body: body, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "api key", method: http.MethodPost, target: target, body: body, header: asAPIKey,
+			code: http.StatusForbidden, want: reason(ErrAPIKeyNotAllowed.Error()),
+		},
+		{
+			name: "legacy author id", method: http.MethodPost, target: target,
+			body: `{"name":"Offer","description":"Cheap","tenderId":"` + testTenderId.String() + `","authorType":"User",
+				"authorId":"` + alice.Id.String() + `"}`,
+			code: http.StatusBadRequest, want: reason(ErrLegacyIdentity.Error()),
+		},
+		{
+			name: "matching legacy author id", method: http.MethodPost, target: target,
+			body: `{"name":"Offer","description":"Cheap","tenderId":"` + testTenderId.String() + `","authorType":"User",
+				"authorId":"` + alice.Id.String() + `"}`,
+			opts: []Option{LegacyIdentity(true)}, setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+		},
+		validationCase("no name", http.MethodPost, target,
+			`{"description":"Cheap","tenderId":"`+testTenderId.String()+`","authorType":"User"}`),
+		validationCase("long name", http.MethodPost, target,
+			`{"name":"`+longString(101)+`","description":"Cheap","tenderId":"`+testTenderId.String()+`","authorType":"User"}`),
+		validationCase("long description", http.MethodPost, target,
+			`{"name":"Offer","description":"`+longString(501)+`","tenderId":"`+testTenderId.String()+`","authorType":"User"}`),
+		validationCase("no tender", http.MethodPost, target, `{"name":"Offer","description":"Cheap","authorType":"User"}`),
+		validationCase("unknown author type", http.MethodPost, target,
+			`{"name":"Offer","description":"Cheap","tenderId":"`+testTenderId.String()+`","authorType":"Robot"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPost, target, body, failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrBidOrganization:     http.StatusBadRequest,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestSubmitBidDecision(t *testing.T) {
+	target := testBidPath + "/submit_decision?decision=Approved"
+
+	cases := []routeCase{
+		{
+			name: "approved", method: http.MethodPut, target: target, setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, []any{testBidId, "alice", "Approved"})
+			},
+		},
+		validationCase("no decision", http.MethodPut, testBidPath+"/submit_decision", ""),
+		validationCase("unknown decision", http.MethodPut, testBidPath+"/submit_decision?decision=Maybe", ""),
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusBadRequest,
+		service.ErrNotFoundBid:         http.StatusNotFound,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestPutBidStatus(t *testing.T) {
+	target := testBidPath + "/status?status=Published"
+
+	cases := []routeCase{
+		{
+			name: "changed", method: http.MethodPut, target: target + "&expectedVersion=1", setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, service.ChangeBidStatusInput{
+					BidId:        testBidId,
+					Status:       "Published",
+					Username:     "alice",
+					Precondition: service.Precondition{Version: 1},
+				})
+				if got, want := rec.Header().Get("ETag"), formatETag(1, testTime); got != want {
+					t.Errorf("ETag %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name: "malformed If-Match", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", "1"),
+			code: http.StatusBadRequest, want: reason(errIfMatch.Error()),
+		},
+		validationCase("no status", http.MethodPut, testBidPath+"/status", ""),
+		validationCase("unknown status", http.MethodPut, testBidPath+"/status?status=Closed", ""),
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundBid:         http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestGetBidStatus(t *testing.T) {
+	target := testBidPath + "/status"
+
+	cases := []routeCase{
+		{
+			name: "status", method: http.MethodGet, target: target, setup: withBid,
+			code: http.StatusOK, want: `"Created"`,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, []any{testBidId, "alice"})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		{
+			name: "by api key", method: http.MethodGet, target: target, header: asAPIKey, setup: withBid,
+			code: http.StatusOK, want: `"Created"`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, []any{testBidId, "api-key:tk_ci"})
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodGet, target: target, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+	}
+	cases = append(cases, errorCases(http.MethodGet, target, "", failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundBid:         http.StatusNotFound,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestEditBid(t *testing.T) {
+	target := testBidPath + "/edit"
+	body := `{"description":"Cheaper"}`
+
+	cases := []routeCase{
+		{
+			name: "edited", method: http.MethodPatch, target: target, body: body, setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, service.EditBidInput{BidId: testBidId, Username: "alice", Description: "Cheaper"})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		invalidCase("nothing to edit", http.MethodPatch, target, `{}`),
+		invalidCase("empty name", http.MethodPatch, target, `{"name":"","description":"Cheaper"}`),
+		invalidCase("long name", http.MethodPatch, target, `{"name":"`+longString(101)+`"}`),
+		invalidCase("long description", http.MethodPatch, target, `{"description":"`+longString(501)+`"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPatch, target, body)...)
+	cases = append(cases, errorCases(http.MethodPatch, target, body, failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundBid:         http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestRollbackBid(t *testing.T) {
+	target := testBidPath + "/rollback/3"
+
+	cases := []routeCase{
+		{
+			name: "rolled back", method: http.MethodPut, target: target, setup: withBid,
+			code: http.StatusOK, want: testBidJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.bid.in, service.RollbackBidInput{BidId: testBidId, Version: 3, Username: "alice"})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		validationCase("version zero", http.MethodPut, testBidPath+"/rollback/0", ""),
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failBid, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundBid:         http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
@@ -0,0 +1,312 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type employeeRoutes struct {
+	employeeService service.Employee
+	identity        *identityResolver
+}
+
+func newEmployeeRoutes(s service.Employee, ir *identityResolver) *employeeRoutes {
+	return &employeeRoutes{s, ir}
+}
+
+type employeeResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Email     string    `json:"email,omitempty"`
+	IsActive  bool      `json:"isActive"`
+	IsAdmin   bool      `json:"isAdmin"`
+	CreatedAt string    `json:"createdAt"`
+}
+
+func newEmployeeResponse(emp e.Employee) employeeResponse {
+	resp := employeeResponse{
+		Id:        emp.Id,
+		Username:  emp.Username,
+		FirstName: emp.FirstName,
+		LastName:  emp.LastName,
+		IsActive:  emp.IsActive,
+		IsAdmin:   emp.IsAdmin,
+		CreatedAt: emp.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if emp.Email != nil {
+		resp.Email = *emp.Email
+	}
+	return resp
+}
+
+type NewEmployeeDTO struct {
+	NewUsername string `json:"username" validate:"required,max=50"`
+	FirstName   string `json:"firstName" validate:"max=50"`
+	LastName    string `json:"lastName" validate:"max=50"`
+	Email       string `json:"email" validate:"omitempty,email,max=254"`
+	Username    string `query:"username" validate:"max=50"`
+}
+
+func (r *employeeRoutes) newEmployee(c echo.Context) error {
+	// Binding and validation
+	var input NewEmployeeDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Create employee
+	emp, err := r.employeeService.Create(c.Request().Context(), service.CreateEmployeeInput{
+		Username:    username,
+		NewUsername: input.NewUsername,
+		FirstName:   input.FirstName,
+		LastName:    input.LastName,
+		Email:       input.Email,
+	})
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(emp))
+}
+
+type EmployeesDTO struct {
+	LimitAndOffset
+	Username        string `query:"username" validate:"max=50"`
+	Query           string `query:"query" validate:"max=50"`
+	IncludeInactive bool   `query:"includeInactive"`
+}
+
+func (r *employeeRoutes) employees(c echo.Context) error {
+	// Binding and validation
+	var input EmployeesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Search employees
+	employees, err := r.employeeService.Search(c.Request().Context(), service.SearchEmployeesInput{
+		Limit:           int(input.Limit.Int32),
+		Offset:          int(input.Offset.Int32),
+		Username:        username,
+		Query:           input.Query,
+		IncludeInactive: input.IncludeInactive,
+	})
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	// Create response
+	responseBatch := []employeeResponse{}
+	for _, emp := range employees {
+		responseBatch = append(responseBatch, newEmployeeResponse(emp))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type GetEmployeeDTO struct {
+	EmployeeId uuid.UUID `param:"employeeId" validate:"required"`
+	Username   string    `query:"username" validate:"max=50"`
+}
+
+func (r *employeeRoutes) getEmployee(c echo.Context) error {
+	// Binding and validation
+	var input GetEmployeeDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get employee
+	emp, err := r.employeeService.Get(c.Request().Context(), input.EmployeeId, username)
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(emp))
+}
+
+type EditEmployeeDTO struct {
+	EmployeeId uuid.UUID `param:"employeeId" validate:"required"`
+	Username   string    `query:"username" validate:"max=50"`
+	EditEmployeeBody
+}
+
+func (r *employeeRoutes) editEmployee(c echo.Context) error {
+	// Binding and validation
+	var input EditEmployeeDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := editEmployeeBodyValidate(&input.EditEmployeeBody); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Edit employee
+	emp, err := r.employeeService.Edit(c.Request().Context(), service.EditEmployeeInput{
+		EmployeeId: input.EmployeeId,
+		Username:   username,
+		FirstName:  input.FirstName.String,
+		LastName:   input.LastName.String,
+		Email:      input.Email.String,
+	})
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(emp))
+}
+
+type SetEmployeeActiveDTO struct {
+	EmployeeId uuid.UUID `param:"employeeId" validate:"required"`
+	Username   string    `query:"username" validate:"max=50"`
+}
+
+func (r *employeeRoutes) deactivateEmployee(c echo.Context) error {
+	return r.setActive(c, false)
+}
+
+func (r *employeeRoutes) activateEmployee(c echo.Context) error {
+	return r.setActive(c, true)
+}
+
+func (r *employeeRoutes) setActive(c echo.Context, active bool) error {
+	// Binding and validation
+	var input SetEmployeeActiveDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Change employee activity
+	emp, err := r.employeeService.SetActive(c.Request().Context(), input.EmployeeId, active, username)
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(emp))
+}
+
+func (r *employeeRoutes) grantAdmin(c echo.Context) error {
+	return r.setAdmin(c, true)
+}
+
+func (r *employeeRoutes) revokeAdmin(c echo.Context) error {
+	return r.setAdmin(c, false)
+}
+
+func (r *employeeRoutes) setAdmin(c echo.Context, admin bool) error {
+	// Binding and validation
+	var input SetEmployeeActiveDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Change admin rights
+	emp, err := r.employeeService.SetAdmin(c.Request().Context(), input.EmployeeId, admin, username)
+	if err != nil {
+		return employeeErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newEmployeeResponse(emp))
+}
+
+func employeeErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFoundEmployee):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrEmployeeExists), errors.Is(err, service.ErrEmailTaken):
+		return newErrReasonJSON(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrDeactivateSelf), errors.Is(err, service.ErrRevokeOwnAdmin):
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,204 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var testEmployeeId = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+const testEmployeePath = "/api/employees/00000000-0000-0000-0000-000000000003"
+
+func testEmployee() e.Employee {
+	email := "carol@example.com"
+	return e.Employee{
+		Id:        testEmployeeId,
+		Username:  "carol",
+		FirstName: "Carol",
+		LastName:  "Jones",
+		Email:     &email,
+		IsActive:  true,
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}
+}
+
+const testEmployeeJSON = `{"id":"00000000-0000-0000-0000-000000000003","username":"carol","firstName":"Carol",
+	"lastName":"Jones","email":"carol@example.com","isActive":true,"isAdmin":false,"createdAt":"2024-01-02T03:04:05Z"}`
+
+func withEmployee(s *stubs) { s.employee.employee = testEmployee() }
+
+func failEmployee(s *stubs, err error) { s.employee.err = err }
+
+// employeeErrors is mapping of employeeErrorResponse
+func employeeErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrForbidden:           http.StatusForbidden,
+		service.ErrNotFoundEmployee:    http.StatusNotFound,
+		service.ErrEmployeeExists:      http.StatusConflict,
+		service.ErrEmailTaken:          http.StatusConflict,
+		service.ErrDeactivateSelf:      http.StatusBadRequest,
+		service.ErrRevokeOwnAdmin:      http.StatusBadRequest,
+	}
+}
+
+func TestNewEmployee(t *testing.T) {
+	const target = "/api/employees/new"
+	body := `{"username":"carol","firstName":"Carol","lastName":"Jones","email":"carol@example.com"}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body, setup: withEmployee,
+			code: http.StatusOK, want: testEmployeeJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.employee.in, service.CreateEmployeeInput{
+					Username:    "alice",
+					NewUsername: "carol",
+					FirstName:   "Carol",
+					LastName:    "Jones",
+					Email:       "carol@example.com",
+				})
+			},
+		},
+		{
+			name: "without email", method: http.MethodPost, target: target, body: `{"username":"carol"}`,
+			setup: func(s *stubs) {
+				s.employee.employee = testEmployee()
+				s.employee.employee.Email = nil
+			},
+			code: http.StatusOK,
+			want: `{"id":"00000000-0000-0000-0000-000000000003","username":"carol","firstName":"Carol",
+				"lastName":"Jones","isActive":true,"isAdmin":false,"createdAt":"2024-01-02T03:04:05Z"}`,
+		},
+		validationCase("no username", http.MethodPost, target, `{"firstName":"Carol"}`),
+		validationCase("long username", http.MethodPost, target, `{"username":"`+longString(51)+`"}`),
+		validationCase("long first name", http.MethodPost, target, `{"username":"carol","firstName":"`+longString(51)+`"}`),
+		validationCase("bad email", http.MethodPost, target, `{"username":"carol","email":"carol"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failEmployee, employeeErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestEmployees(t *testing.T) {
+	const target = "/api/employees"
+
+	cases := []routeCase{
+		{
+			name: "search", method: http.MethodGet, target: target + "?query=car&includeInactive=true",
+			setup: func(s *stubs) { s.employee.employees = []e.Employee{testEmployee()} },
+			code:  http.StatusOK, want: "[" + testEmployeeJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.employee.in, service.SearchEmployeesInput{
+					Limit:           DefaultLimit,
+					Offset:          DefaultOffset,
+					Username:        "alice",
+					Query:           "car",
+					IncludeInactive: true,
+				})
+			},
+		},
+		{name: "empty", method: http.MethodGet, target: target, code: http.StatusOK, want: `[]`},
+		validationCase("long query", http.MethodGet, target+"?query="+longString(51), ""),
+		invalidCase("negative limit", http.MethodGet, target+"?limit=-1", ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failEmployee, employeeErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestGetEmployee(t *testing.T) {
+	cases := []routeCase{
+		{
+			name: "employee", method: http.MethodGet, target: testEmployeePath, setup: withEmployee,
+			code: http.StatusOK, want: testEmployeeJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.employee.in, []any{testEmployeeId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodGet, testEmployeePath, "")...)
+	cases = append(cases, errorCases(http.MethodGet, testEmployeePath, "", failEmployee, employeeErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestEditEmployee(t *testing.T) {
+	target := testEmployeePath + "/edit"
+	body := `{"lastName":"Brown"}`
+
+	cases := []routeCase{
+		{
+			name: "edited", method: http.MethodPatch, target: target, body: body, setup: withEmployee,
+			code: http.StatusOK, want: testEmployeeJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.employee.in, service.EditEmployeeInput{EmployeeId: testEmployeeId, Username: "alice", LastName: "Brown"})
+			},
+		},
+		invalidCase("nothing to edit", http.MethodPatch, target, `{}`),
+		invalidCase("empty first name", http.MethodPatch, target, `{"firstName":""}`),
+		invalidCase("long last name", http.MethodPatch, target, `{"lastName":"`+longString(51)+`"}`),
+		invalidCase("bad email", http.MethodPatch, target, `{"email":"Carol <carol@example.com>"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPatch, target, body)...)
+	cases = append(cases, errorCases(http.MethodPatch, target, body, failEmployee, employeeErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestSetEmployeeActive(t *testing.T) {
+	for _, tc := range []struct {
+		action string
+		active bool
+	}{
+		{"deactivate", false},
+		{"activate", true},
+	} {
+		t.Run(tc.action, func(t *testing.T) {
+			target := testEmployeePath + "/" + tc.action
+			cases := []routeCase{
+				{
+					name: "changed", method: http.MethodPut, target: target, setup: withEmployee,
+					code: http.StatusOK, want: testEmployeeJSON,
+					check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+						wantInput(t, s.employee.in, []any{testEmployeeId, tc.active, "alice"})
+					},
+				},
+			}
+			cases = append(cases, identityCases(http.MethodPut, target, "")...)
+			cases = append(cases, errorCases(http.MethodPut, target, "", failEmployee, employeeErrors())...)
+			runRouteCases(t, cases)
+		})
+	}
+}
+
+func TestSetEmployeeAdmin(t *testing.T) {
+	for _, tc := range []struct {
+		action string
+		admin  bool
+	}{
+		{"grant-admin", true},
+		{"revoke-admin", false},
+	} {
+		t.Run(tc.action, func(t *testing.T) {
+			target := testEmployeePath + "/" + tc.action
+			cases := []routeCase{
+				{
+					name: "changed", method: http.MethodPut, target: target, setup: withEmployee,
+					code: http.StatusOK, want: testEmployeeJSON,
+					check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+						wantInput(t, s.employee.in, []any{testEmployeeId, tc.admin, "alice"})
+					},
+				},
+			}
+			cases = append(cases, identityCases(http.MethodPut, target, "")...)
+			cases = append(cases, errorCases(http.MethodPut, target, "", failEmployee, employeeErrors())...)
+			runRouteCases(t, cases)
+		})
+	}
+}
@@ -7,8 +7,17 @@ import (
 )
 
 var (
-	ErrInternalServer    = errors.New("internal server error")
-	ErrInvalidParameters = errors.New("invalid request parameters")
+	ErrInternalServer       = errors.New("internal server error")
+	ErrInvalidParameters    = errors.New("invalid request parameters")
+	ErrInvalidAuthHeader    = errors.New("authorization header must be 'Bearer <token>'")
+	ErrUnauthenticated      = errors.New("authentication required")
+	ErrLegacyIdentity       = errors.New("username and authorId parameters are not accepted, identity is taken from access token")
+	ErrIdentityMismatch     = errors.New("username or authorId parameter doesn't match access token")
+	ErrAPIKeyNotAllowed     = errors.New("endpoint is not available with API key")
+	ErrAmbiguousCredentials = errors.New("use either access token or API key, not both")
+	ErrRateLimited          = errors.New("too many requests, retry later")
+	ErrIdempotencyKeyHeader = errors.New("Idempotency-Key header must be up to 255 printable ASCII characters")
+	ErrRequestSpec          = errors.New("request doesn't match API specification")
 )
 
 func newErrReasonJSON(c echo.Context, code int, msg interface{}) error {
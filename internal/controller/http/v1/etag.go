@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"app/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errIfMatch = errors.New("If-Match must hold single ETag returned by API")
+
+// ETag names exact state of tender or bid. Status change keeps version but
+// moves update time, so both are part of it
+func formatETag(version int, updatedAt time.Time) string {
+	return fmt.Sprintf(`"%d.%d"`, version, updatedAt.UnixMicro())
+}
+
+func setETag(c echo.Context, version int, updatedAt time.Time) {
+	c.Response().Header().Set("ETag", formatETag(version, updatedAt))
+}
+
+// precondition builds write precondition from If-Match header and
+// expectedVersion parameter. Missing header and "*" set no condition on state
+func precondition(c echo.Context, expectedVersion int) (service.Precondition, error) {
+	pre := service.Precondition{Version: expectedVersion}
+
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return pre, nil
+	}
+
+	// Weak tags never match
+	if len(ifMatch) < 2 || ifMatch[0] != '"' || ifMatch[len(ifMatch)-1] != '"' {
+		return service.Precondition{}, errIfMatch
+	}
+	tag := ifMatch[1 : len(ifMatch)-1]
+	versionPart, micros, ok := strings.Cut(tag, ".")
+	if !ok {
+		return service.Precondition{}, errIfMatch
+	}
+	version, err := strconv.Atoi(versionPart)
+	if err != nil || version <= 0 {
+		return service.Precondition{}, errIfMatch
+	}
+	updatedAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return service.Precondition{}, errIfMatch
+	}
+
+	// Both must name same version
+	if expectedVersion != 0 && expectedVersion != version {
+		return service.Precondition{}, service.ErrVersionMismatch
+	}
+	pre.Version = version
+	pre.UpdatedAt = time.UnixMicro(updatedAt)
+
+	return pre, nil
+}
+
+func preconditionErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,318 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/service"
+	"app/pkg/validator"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+var (
+	testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	// testTimeJSON is testTime as API formats it
+	testTimeJSON = "2024-01-02T03:04:05Z"
+
+	testOrgId = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+
+	alice = e.Employee{
+		Id:        uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Username:  "alice",
+		FirstName: "Alice",
+		LastName:  "Smith",
+		IsActive:  true,
+		CreatedAt: testTime,
+	}
+	inactive = e.Employee{
+		Id:       uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+		Username: "bob",
+		IsActive: false,
+	}
+	testKey = identity.Service{
+		KeyId:          uuid.MustParse("00000000-0000-0000-0000-0000000000c1"),
+		OrganizationId: testOrgId,
+		Name:           "ci",
+		Prefix:         "tk_ci",
+		Scopes:         []string{"tenders:read", "tenders:write", "bids:read"},
+	}
+)
+
+// Request headers of callers
+var (
+	asAlice     = header(echo.HeaderAuthorization, "Bearer alice-token")
+	asInactive  = header(echo.HeaderAuthorization, "Bearer bob-token")
+	asAPIKey    = header(HeaderAPIKey, "tk_ci.secret")
+	asAnonymous = http.Header{}
+)
+
+func header(kv ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		h.Add(kv[i], kv[i+1])
+	}
+	return h
+}
+
+// with returns copy of h with extra headers
+func with(h http.Header, kv ...string) http.Header {
+	h = h.Clone()
+	for k, v := range header(kv...) {
+		h[k] = v
+	}
+	return h
+}
+
+// stubs is set of services router is configured with
+type stubs struct {
+	tender       *stubTender
+	bid          *stubBid
+	organization *stubOrganization
+	employee     *stubEmployee
+	auth         *stubAuth
+	apiKey       *stubAPIKey
+	audit        *stubAudit
+	webhook      *stubWebhook
+	notification *stubNotification
+	inbox        *stubInbox
+	idempotency  *stubIdempotency
+	activity     service.Activity
+}
+
+func newStubs() *stubs {
+	return &stubs{
+		tender:       &stubTender{},
+		bid:          &stubBid{},
+		organization: &stubOrganization{},
+		employee:     &stubEmployee{},
+		auth: &stubAuth{tokens: map[string]e.Employee{
+			"alice-token": alice,
+			"bob-token":   inactive,
+		}},
+		apiKey:       &stubAPIKey{keys: map[string]identity.Service{"tk_ci.secret": testKey}},
+		audit:        &stubAudit{},
+		webhook:      &stubWebhook{},
+		notification: &stubNotification{},
+		inbox:        &stubInbox{},
+		idempotency:  &stubIdempotency{},
+	}
+}
+
+func (s *stubs) services() *service.Services {
+	return &service.Services{
+		Tender:       s.tender,
+		Bid:          s.bid,
+		Organization: s.organization,
+		Employee:     s.employee,
+		Auth:         s.auth,
+		APIKey:       s.apiKey,
+		Audit:        s.audit,
+		Webhook:      s.webhook,
+		Activity:     s.activity,
+		Notification: s.notification,
+		Inbox:        s.inbox,
+		Idempotency:  s.idempotency,
+	}
+}
+
+// newRouter configures router the way app does, request log is discarded
+func newRouter(s *stubs, opts ...Option) *echo.Echo {
+	handler := echo.New()
+	ConfigureRouter(handler, s.services(), append([]Option{LogOutput(io.Discard)}, opts...)...)
+	handler.Validator = validator.NewCustomValidator()
+	return handler
+}
+
+func serve(handler http.Handler, method, target, body string, h http.Header) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// routeCase is request to router built with fresh stubs. Requests are made by
+// alice unless header is set. Response body is compared as JSON if want is set
+type routeCase struct {
+	name   string
+	method string
+	target string
+	body   string
+	header http.Header
+	opts   []Option
+	setup  func(*stubs)
+	code   int
+	want   string
+	check  func(*testing.T, *stubs, *httptest.ResponseRecorder)
+}
+
+func runRouteCases(t *testing.T, cases []routeCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newStubs()
+			if tc.setup != nil {
+				tc.setup(s)
+			}
+			h := tc.header
+			if h == nil {
+				h = asAlice
+			}
+			rec := serve(newRouter(s, tc.opts...), tc.method, tc.target, tc.body, h)
+
+			if rec.Code != tc.code {
+				t.Fatalf("%s %s: status %d, want %d, body %s", tc.method, tc.target, rec.Code, tc.code, rec.Body)
+			}
+			if tc.want != "" {
+				wantJSON(t, rec.Body.Bytes(), tc.want)
+			}
+			if tc.check != nil {
+				tc.check(t, s, rec)
+			}
+
+			// Request handler accepts must match OpenAPI document too
+			if tc.code < http.StatusBadRequest {
+				s := newStubs()
+				if tc.setup != nil {
+					tc.setup(s)
+				}
+				opts := append(append([]Option{}, tc.opts...), ValidateRequests(true))
+				rec := serve(newRouter(s, opts...), tc.method, tc.target, tc.body, h)
+				if rec.Code != tc.code {
+					t.Fatalf("%s %s with request validation: status %d, want %d, body %s",
+						tc.method, tc.target, rec.Code, tc.code, rec.Body)
+				}
+			}
+		})
+	}
+}
+
+// wantJSON compares JSON documents ignoring formatting and key order
+func wantJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("response is not JSON: %v, body %s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("bad expected JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("body\n%s\nwant\n%s", strings.TrimSpace(string(got)), want)
+	}
+}
+
+// reason is error response body
+func reason(msg string) string {
+	b, _ := json.Marshal(map[string]string{"reason": msg})
+	return string(b)
+}
+
+// wantInput checks arguments stub service got
+func wantInput(t *testing.T, got, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("service input\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// errorCases checks how service errors are mapped to status codes. Errors not
+// in mapping are internal server errors
+func errorCases(method, target, body string, fail func(*stubs, error), mapping map[error]int) []routeCase {
+	mapping[errors.New("unexpected failure")] = http.StatusInternalServerError
+
+	var cases []routeCase
+	for err, code := range mapping {
+		cases = append(cases, routeCase{
+			name:   "service error " + err.Error(),
+			method: method,
+			target: target,
+			body:   body,
+			setup:  func(s *stubs) { fail(s, err) },
+			code:   code,
+			want:   reason(err.Error()),
+		})
+	}
+	return cases
+}
+
+// identityCases checks endpoints identifying caller by access token
+func identityCases(method, target, body string) []routeCase {
+	sep := "?"
+	if strings.Contains(target, "?") {
+		sep = "&"
+	}
+	return []routeCase{
+		{
+			name: "anonymous", method: method, target: target, body: body, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "api key", method: method, target: target, body: body, header: asAPIKey,
+			code: http.StatusForbidden, want: reason(ErrAPIKeyNotAllowed.Error()),
+		},
+		{
+			name: "legacy username", method: method, target: target + sep + "username=alice", body: body,
+			code: http.StatusBadRequest, want: reason(ErrLegacyIdentity.Error()),
+		},
+		{
+			name: "legacy username mismatch", method: method, target: target + sep + "username=carol", body: body,
+			opts: []Option{LegacyIdentity(true)},
+			code: http.StatusForbidden, want: reason(ErrIdentityMismatch.Error()),
+		},
+	}
+}
+
+// validationCase is request rejected by DTO validation
+func validationCase(name, method, target, body string) routeCase {
+	return routeCase{
+		name:   name,
+		method: method,
+		target: target,
+		body:   body,
+		code:   http.StatusBadRequest,
+		want:   reason(validator.ErrValidation.Error()),
+	}
+}
+
+// invalidCase is request rejected by hand-written parameter checks
+func invalidCase(name, method, target, body string) routeCase {
+	return routeCase{
+		name:   name,
+		method: method,
+		target: target,
+		body:   body,
+		code:   http.StatusBadRequest,
+		want:   reason(ErrInvalidParameters.Error()),
+	}
+}
+
+func longString(n int) string {
+	return strings.Repeat("a", n)
+}
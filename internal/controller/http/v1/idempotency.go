@@ -0,0 +1,141 @@
+package httpapi
+
+import (
+	"app/internal/identity"
+	"app/internal/service"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotencyMiddleware stores response of request with Idempotency-Key
+// header and replays it for requests repeated with same key and payload.
+// Keys are scoped by caller, anonymous requests are passed as is
+func idempotencyMiddleware(s service.Idempotency) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+			if !validIdempotencyKey(key) {
+				return newErrReasonJSON(c, http.StatusBadRequest, ErrIdempotencyKeyHeader.Error())
+			}
+			caller, ok := idempotencyCaller(c.Request().Context())
+			if !ok {
+				return next(c)
+			}
+
+			// Hash request, body is restored for handler
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return newErrReasonJSON(c, http.StatusBadRequest, ErrInvalidParameters.Error())
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			// Replay stored response or claim key
+			stored, replay, err := s.BeginIdempotentRequest(c.Request().Context(), service.BeginIdempotentRequestInput{
+				Caller:      caller,
+				Key:         key,
+				RequestHash: requestHash(c.Request(), body),
+			})
+			if err != nil {
+				if errors.Is(err, service.ErrIdempotencyMismatch) {
+					return newErrReasonJSON(c, http.StatusUnprocessableEntity, err.Error())
+				}
+				if errors.Is(err, service.ErrIdempotencyInProgress) {
+					return newErrReasonJSON(c, http.StatusConflict, err.Error())
+				}
+				return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+			}
+			if replay {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.Status, stored.ContentType, stored.Body)
+			}
+
+			// Handle request and store response, key is held while handler
+			// runs. Server errors are not stored, request can be retried with
+			// same key
+			ctx := context.WithoutCancel(c.Request().Context())
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = func() error {
+				defer s.KeepIdempotentRequest(ctx, caller, key)()
+				return next(c)
+			}()
+
+			if err != nil || c.Response().Status >= http.StatusInternalServerError {
+				if err := s.ReleaseIdempotentRequest(ctx, caller, key); err != nil {
+					log.Errorf("httpapi - idempotencyMiddleware - ReleaseIdempotentRequest: %v", err)
+				}
+				return err
+			}
+			err = s.CompleteIdempotentRequest(ctx, service.CompleteIdempotentRequestInput{
+				Caller: caller,
+				Key:    key,
+				Response: service.IdempotentResponse{
+					Status:      c.Response().Status,
+					ContentType: c.Response().Header().Get(echo.HeaderContentType),
+					Body:        recorder.body.Bytes(),
+				},
+			})
+			if err != nil {
+				log.Errorf("httpapi - idempotencyMiddleware - CompleteIdempotentRequest: %v", err)
+			}
+			return nil
+		}
+	}
+}
+
+func idempotencyCaller(ctx context.Context) (string, bool) {
+	if employee, ok := identity.EmployeeFromContext(ctx); ok {
+		return "employee:" + employee.Id.String(), true
+	}
+	if apiKey, ok := identity.ServiceFromContext(ctx); ok {
+		return "api-key:" + apiKey.KeyId.String(), true
+	}
+	return "", false
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestHash identifies payload of request, query carries parameters too
+func requestHash(r *http.Request, body []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery + "\n"))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// responseRecorder copies response body written by handler
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,214 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type inboxRoutes struct {
+	inboxService service.Inbox
+	identity     *identityResolver
+}
+
+func newInboxRoutes(s service.Inbox, ir *identityResolver) *inboxRoutes {
+	return &inboxRoutes{s, ir}
+}
+
+type notificationResponse struct {
+	Id             uuid.UUID  `json:"id"`
+	Kind           string     `json:"kind"`
+	Title          string     `json:"title"`
+	TenderId       uuid.UUID  `json:"tenderId"`
+	BidId          *uuid.UUID `json:"bidId,omitempty"`
+	OrganizationId *uuid.UUID `json:"organizationId,omitempty"`
+	Read           bool       `json:"read"`
+	ReadAt         *string    `json:"readAt,omitempty"`
+	CreatedAt      string     `json:"createdAt"`
+}
+
+func newNotificationResponse(n e.Notification) notificationResponse {
+	resp := notificationResponse{
+		Id:        n.Id,
+		Kind:      n.Kind,
+		Title:     n.Title,
+		TenderId:  n.TenderId,
+		Read:      n.ReadAt != nil,
+		ReadAt:    formatOptionalTime(n.ReadAt),
+		CreatedAt: n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if n.BidId.Valid {
+		resp.BidId = &n.BidId.UUID
+	}
+	if n.OrganizationId.Valid {
+		resp.OrganizationId = &n.OrganizationId.UUID
+	}
+	return resp
+}
+
+type NotificationsDTO struct {
+	LimitAndOffset
+	Username string `query:"username" validate:"max=50"`
+	Unread   bool   `query:"unread"`
+}
+
+func (r *inboxRoutes) notifications(c echo.Context) error {
+	// Binding and validation
+	var input NotificationsDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get notifications
+	notifications, err := r.inboxService.GetNotifications(c.Request().Context(), service.GetNotificationsInput{
+		Limit:      int(input.Limit.Int32),
+		Offset:     int(input.Offset.Int32),
+		Username:   username,
+		UnreadOnly: input.Unread,
+	})
+	if err != nil {
+		return inboxErrorResponse(c, err)
+	}
+
+	// Create response
+	responseBatch := []notificationResponse{}
+	for _, n := range notifications {
+		responseBatch = append(responseBatch, newNotificationResponse(n))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type UnreadCountDTO struct {
+	Username string `query:"username" validate:"max=50"`
+}
+
+func (r *inboxRoutes) unreadCount(c echo.Context) error {
+	// Binding and validation
+	var input UnreadCountDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Count unread notifications
+	count, err := r.inboxService.GetUnreadCount(c.Request().Context(), username)
+	if err != nil {
+		return inboxErrorResponse(c, err)
+	}
+
+	type response struct {
+		Unread int `json:"unread"`
+	}
+
+	return c.JSON(http.StatusOK, response{Unread: count})
+}
+
+type MarkNotificationReadDTO struct {
+	NotificationId uuid.UUID `param:"notificationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *inboxRoutes) markRead(c echo.Context) error {
+	// Binding and validation
+	var input MarkNotificationReadDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Mark notification read
+	notification, err := r.inboxService.MarkNotificationRead(c.Request().Context(), input.NotificationId, username)
+	if err != nil {
+		return inboxErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newNotificationResponse(notification))
+}
+
+type MarkAllNotificationsReadDTO struct {
+	Username string `query:"username" validate:"max=50"`
+}
+
+func (r *inboxRoutes) markAllRead(c echo.Context) error {
+	// Binding and validation
+	var input MarkAllNotificationsReadDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Mark all notifications read
+	n, err := r.inboxService.MarkAllNotificationsRead(c.Request().Context(), username)
+	if err != nil {
+		return inboxErrorResponse(c, err)
+	}
+
+	type response struct {
+		Marked int `json:"marked"`
+	}
+
+	return c.JSON(http.StatusOK, response{Marked: n})
+}
+
+func inboxErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrNotFoundNotification):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
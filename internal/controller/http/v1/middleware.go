@@ -0,0 +1,177 @@
+package httpapi
+
+import (
+	"app/internal/identity"
+	"app/internal/service"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// ipExtractor takes client IP from connection. Headers can be forged by client,
+// X-Forwarded-For is believed only for hops from trusted proxies
+func ipExtractor(trusted []*net.IPNet) echo.IPExtractor {
+	if len(trusted) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, n := range trusted {
+		opts = append(opts, echo.TrustIPRange(n))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
+
+// requestMiddleware puts request id and client IP into request context
+func requestMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := identity.WithRequest(c.Request().Context(), identity.Request{
+			Id:       c.Response().Header().Get(echo.HeaderXRequestID),
+			ClientIP: c.RealIP(),
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+// authMiddleware puts token subject into request context. Requests without
+// Authorization header pass through, handlers decide if identity is required
+func authMiddleware(s service.Auth) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" {
+				return next(c)
+			}
+
+			accessToken, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || accessToken == "" {
+				return newErrReasonJSON(c, http.StatusUnauthorized, ErrInvalidAuthHeader.Error())
+			}
+
+			employee, err := s.Authenticate(c.Request().Context(), accessToken)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrEmployeeDeactivated) {
+					return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+				}
+				return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+			}
+
+			ctx := identity.WithEmployee(c.Request().Context(), employee)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// apiKeyMiddleware puts organization API key identity into request context.
+// Key and access token can't be used together
+func apiKeyMiddleware(s service.APIKey) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rawKey := c.Request().Header.Get(HeaderAPIKey)
+			if rawKey == "" {
+				return next(c)
+			}
+			if _, ok := identity.EmployeeFromContext(c.Request().Context()); ok {
+				return newErrReasonJSON(c, http.StatusBadRequest, ErrAmbiguousCredentials.Error())
+			}
+
+			apiKey, err := s.Authenticate(c.Request().Context(), rawKey)
+			if err != nil {
+				if errors.Is(err, service.ErrInvalidAPIKey) {
+					return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+				}
+				return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+			}
+
+			ctx := identity.WithService(c.Request().Context(), apiKey)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+type identityResolver struct {
+	allowLegacy bool
+}
+
+// username returns authenticated employee username. Legacy parameter is accepted
+// in compatibility mode only and must match token subject
+func (ir *identityResolver) username(c echo.Context, legacy string) (string, error) {
+	employee, ok := identity.EmployeeFromContext(c.Request().Context())
+	if !ok {
+		if _, ok := identity.ServiceFromContext(c.Request().Context()); ok {
+			return "", ErrAPIKeyNotAllowed
+		}
+		return "", ErrUnauthenticated
+	}
+	if legacy == "" {
+		return employee.Username, nil
+	}
+	if !ir.allowLegacy {
+		return "", ErrLegacyIdentity
+	}
+	if legacy != employee.Username {
+		return "", ErrIdentityMismatch
+	}
+	return employee.Username, nil
+}
+
+// caller is username for endpoints API keys can call too. For API key it returns
+// key pseudo username, services take key identity from context
+func (ir *identityResolver) caller(c echo.Context, legacy string) (string, error) {
+	apiKey, ok := identity.ServiceFromContext(c.Request().Context())
+	if !ok {
+		return ir.username(c, legacy)
+	}
+	if legacy != "" && legacy != apiKey.Username() {
+		return "", ErrIdentityMismatch
+	}
+	return apiKey.Username(), nil
+}
+
+// optionalUsername is username for endpoints available anonymously, returns empty
+// string for anonymous request. API keys see what anonymous users see
+func (ir *identityResolver) optionalUsername(c echo.Context, legacy string) (string, error) {
+	if _, ok := identity.EmployeeFromContext(c.Request().Context()); !ok && legacy == "" {
+		return "", nil
+	}
+	return ir.username(c, legacy)
+}
+
+// employeeId is username counterpart for endpoints identifying caller by id
+func (ir *identityResolver) employeeId(c echo.Context, legacy uuid.UUID) (uuid.UUID, error) {
+	employee, ok := identity.EmployeeFromContext(c.Request().Context())
+	if !ok {
+		if _, ok := identity.ServiceFromContext(c.Request().Context()); ok {
+			return uuid.Nil, ErrAPIKeyNotAllowed
+		}
+		return uuid.Nil, ErrUnauthenticated
+	}
+	if legacy == uuid.Nil {
+		return employee.Id, nil
+	}
+	if !ir.allowLegacy {
+		return uuid.Nil, ErrLegacyIdentity
+	}
+	if legacy != employee.Id {
+		return uuid.Nil, ErrIdentityMismatch
+	}
+	return employee.Id, nil
+}
+
+func identityErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, ErrIdentityMismatch), errors.Is(err, ErrAPIKeyNotAllowed):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type notificationRoutes struct {
+	notificationService service.Notification
+	identity            *identityResolver
+}
+
+func newNotificationRoutes(s service.Notification, ir *identityResolver) *notificationRoutes {
+	return &notificationRoutes{s, ir}
+}
+
+type emailPreferencesResponse struct {
+	Email string   `json:"email"`
+	Kinds []string `json:"kinds"`
+}
+
+type EmailPreferencesDTO struct {
+	Username string `query:"username" validate:"max=50"`
+}
+
+func (r *notificationRoutes) emailPreferences(c echo.Context) error {
+	// Binding and validation
+	var input EmailPreferencesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get preferences
+	email, kinds, err := r.notificationService.GetEmailPreferences(c.Request().Context(), username)
+	if err != nil {
+		return notificationErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, emailPreferencesResponse{Email: email, Kinds: kinds})
+}
+
+type PutEmailPreferencesDTO struct {
+	Kinds    []string `json:"kinds" validate:"required,dive,oneof=bid_received bid_decided tender_amended deadline_approaching"`
+	Username string   `query:"username" validate:"max=50"`
+}
+
+func (r *notificationRoutes) putEmailPreferences(c echo.Context) error {
+	// Binding and validation
+	var input PutEmailPreferencesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Replace preferences
+	email, kinds, err := r.notificationService.SetEmailPreferences(c.Request().Context(), username, input.Kinds)
+	if err != nil {
+		return notificationErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, emailPreferencesResponse{Email: email, Kinds: kinds})
+}
+
+func notificationErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrEmailRequired):
+		return newErrReasonJSON(c, http.StatusConflict, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,202 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var testNotificationId = uuid.MustParse("00000000-0000-0000-0000-000000000021")
+
+func testNotification() e.Notification {
+	return e.Notification{
+		Id:         testNotificationId,
+		EmployeeId: alice.Id,
+		EventId:    uuid.MustParse("00000000-0000-0000-0000-000000000022"),
+		Kind:       "bid_received",
+		TenderId:   testTenderId,
+		BidId:      uuid.NullUUID{UUID: uuid.MustParse("00000000-0000-0000-0000-0000000000b1"), Valid: true},
+		Title:      "New bid on Tender",
+		CreatedAt:  testTime,
+	}
+}
+
+const testNotificationJSON = `{"id":"00000000-0000-0000-0000-000000000021","kind":"bid_received","title":"New bid on Tender",
+	"tenderId":"00000000-0000-0000-0000-0000000000f1","bidId":"00000000-0000-0000-0000-0000000000b1",
+	"read":false,"createdAt":"2024-01-02T03:04:05Z"}`
+
+func failNotification(s *stubs, err error) { s.notification.err = err }
+
+func failInbox(s *stubs, err error) { s.inbox.err = err }
+
+// notificationErrors is mapping of notificationErrorResponse
+func notificationErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrEmailRequired:       http.StatusConflict,
+	}
+}
+
+// inboxErrors is mapping of inboxErrorResponse
+func inboxErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:             http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:  http.StatusUnauthorized,
+		service.ErrNotFoundNotification: http.StatusNotFound,
+	}
+}
+
+func TestEmailPreferences(t *testing.T) {
+	const target = "/api/notifications/email-preferences"
+
+	cases := []routeCase{
+		{
+			name: "preferences", method: http.MethodGet, target: target,
+			setup: func(s *stubs) {
+				s.notification.email = "alice@example.com"
+				s.notification.kinds = []string{"bid_received"}
+			},
+			code: http.StatusOK, want: `{"email":"alice@example.com","kinds":["bid_received"]}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.notification.in, "alice")
+			},
+		},
+		validationCase("long username", http.MethodGet, target+"?username="+longString(51), ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failNotification, notificationErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestPutEmailPreferences(t *testing.T) {
+	const target = "/api/notifications/email-preferences"
+	body := `{"kinds":["bid_received","deadline_approaching"]}`
+
+	cases := []routeCase{
+		{
+			name: "replaced", method: http.MethodPut, target: target, body: body,
+			setup: func(s *stubs) { s.notification.email = "alice@example.com" },
+			code:  http.StatusOK,
+			want:  `{"email":"alice@example.com","kinds":["bid_received","deadline_approaching"]}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.notification.in, []any{"alice", []string{"bid_received", "deadline_approaching"}})
+			},
+		},
+		{
+			name: "opt out", method: http.MethodPut, target: target, body: `{"kinds":[]}`,
+			setup: func(s *stubs) { s.notification.email = "alice@example.com" },
+			code:  http.StatusOK, want: `{"email":"alice@example.com","kinds":[]}`,
+		},
+		validationCase("no kinds", http.MethodPut, target, `{}`),
+		validationCase("unknown kind", http.MethodPut, target, `{"kinds":["tender_deleted"]}`),
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, body)...)
+	cases = append(cases, errorCases(http.MethodPut, target, body, failNotification, notificationErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestNotifications(t *testing.T) {
+	const target = "/api/notifications"
+	readAt := testTime.Add(time.Hour)
+
+	cases := []routeCase{
+		{
+			name: "unread", method: http.MethodGet, target: target + "?unread=true&limit=10",
+			setup: func(s *stubs) { s.inbox.notification = testNotification() },
+			code:  http.StatusOK, want: "[" + testNotificationJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.inbox.in, service.GetNotificationsInput{
+					Limit:      10,
+					Offset:     DefaultOffset,
+					Username:   "alice",
+					UnreadOnly: true,
+				})
+			},
+		},
+		{
+			name: "read", method: http.MethodGet, target: target,
+			setup: func(s *stubs) {
+				s.inbox.notification = testNotification()
+				s.inbox.notification.BidId = uuid.NullUUID{}
+				s.inbox.notification.OrganizationId = uuid.NullUUID{UUID: testOrgId, Valid: true}
+				s.inbox.notification.ReadAt = &readAt
+			},
+			code: http.StatusOK,
+			want: `[{"id":"00000000-0000-0000-0000-000000000021","kind":"bid_received","title":"New bid on Tender",
+				"tenderId":"00000000-0000-0000-0000-0000000000f1","organizationId":"00000000-0000-0000-0000-00000000000a",
+				"read":true,"readAt":"2024-01-02T04:04:05Z","createdAt":"2024-01-02T03:04:05Z"}]`,
+		},
+		{
+			name: "malformed unread", method: http.MethodGet, target: target + "?unread=maybe",
+			code: http.StatusBadRequest, want: reason(`strconv.ParseBool: parsing "maybe": invalid syntax`),
+		},
+		invalidCase("negative offset", http.MethodGet, target+"?offset=-1", ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failInbox, inboxErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestUnreadCount(t *testing.T) {
+	const target = "/api/notifications/unread-count"
+
+	cases := []routeCase{
+		{
+			name: "count", method: http.MethodGet, target: target,
+			setup: func(s *stubs) { s.inbox.count = 3 },
+			code:  http.StatusOK, want: `{"unread":3}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.inbox.in, "alice")
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failInbox, inboxErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	const target = "/api/notifications/read-all"
+
+	cases := []routeCase{
+		{
+			name: "marked", method: http.MethodPut, target: target,
+			setup: func(s *stubs) { s.inbox.count = 2 },
+			code:  http.StatusOK, want: `{"marked":2}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.inbox.in, "alice")
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failInbox, inboxErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	target := "/api/notifications/" + testNotificationId.String() + "/read"
+
+	cases := []routeCase{
+		{
+			name: "marked", method: http.MethodPut, target: target,
+			setup: func(s *stubs) { s.inbox.notification = testNotification() },
+			code:  http.StatusOK, want: testNotificationJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.inbox.in, []any{testNotificationId, "alice"})
+			},
+		},
+		{
+			name: "malformed id", method: http.MethodPut, target: "/api/notifications/42/read",
+			code: http.StatusBadRequest, want: reason("invalid UUID length: 2"),
+		},
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failInbox, inboxErrors())...)
+	runRouteCases(t, cases)
+}
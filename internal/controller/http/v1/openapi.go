@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"app/pkg/openapi"
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// openAPISpec describes every route in ConfigureRouter, router test fails when
+// they drift apart
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func serveOpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPISpec)
+}
+
+// openAPIPath turns echo route /tenders/:tenderId into document path /tenders/{tenderId}
+func openAPIPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// openAPIMiddleware rejects requests not matching document with 400 before
+// they reach handlers. Routes document doesn't know are passed through
+func openAPIMiddleware(doc *openapi.Document) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op := doc.Operation(c.Request().Method, openAPIPath(c.Path()))
+			if op == nil {
+				return next(c)
+			}
+
+			params := make(map[string]string, len(c.ParamNames()))
+			for i, name := range c.ParamNames() {
+				params[name] = c.ParamValues()[i]
+			}
+			if err := op.ValidateRequest(c.Request(), params); err != nil {
+				return newErrReasonJSON(c, http.StatusBadRequest, ErrRequestSpec.Error()+": "+err.Error())
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package httpapi
+
+import (
+	"app/internal/service"
+	"app/pkg/openapi"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestOpenAPIRoutes(t *testing.T) {
+	doc, err := openapi.Load(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[openapi.Route]bool{}
+	for _, r := range doc.Routes() {
+		documented[r] = true
+	}
+
+	routed := map[openapi.Route]bool{}
+	for _, r := range newRouter(newStubs()).Routes() {
+		// Group middleware registers catch-all routes
+		if r.Method == echo.RouteNotFound {
+			continue
+		}
+		routed[openapi.Route{Method: r.Method, Path: openAPIPath(r.Path)}] = true
+	}
+
+	for r := range routed {
+		if !documented[r] {
+			t.Errorf("%s %s is routed but not documented", r.Method, r.Path)
+		}
+	}
+	for r := range documented {
+		if !routed[r] {
+			t.Errorf("%s %s is documented but not routed", r.Method, r.Path)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	rec := serve(newRouter(newStubs()), http.MethodGet, "/api/openapi.json", "", asAnonymous)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, echo.MIMEApplicationJSON) {
+		t.Errorf("Content-Type %q, want JSON", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), openAPISpec) {
+		t.Errorf("served document differs from embedded one")
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	validate := []Option{ValidateRequests(true)}
+	rejected := func(msg string) string {
+		return reason(ErrRequestSpec.Error() + ": " + msg)
+	}
+	tenderBody := `{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"` + testOrgId.String() + `"}`
+
+	runRouteCases(t, []routeCase{
+		{
+			name: "malformed path parameter", method: http.MethodGet, target: "/api/tenders/42/status", opts: validate,
+			code: http.StatusBadRequest, want: rejected("path.tenderId must be uuid"),
+		},
+		{
+			name: "unknown enum value", method: http.MethodPut, target: testTenderPath + "/status?status=Open", opts: validate,
+			code: http.StatusBadRequest, want: rejected("query.status must be one of [Created Published Closed]"),
+		},
+		{
+			name: "missing required parameter", method: http.MethodPut, target: testTenderPath + "/status", opts: validate,
+			code: http.StatusBadRequest, want: rejected("query.status is required"),
+		},
+		{
+			name: "empty optional parameter", method: http.MethodGet, target: "/api/tenders/my?limit=", opts: validate,
+			code: http.StatusOK, want: `[]`,
+		},
+		{
+			name: "malformed integer", method: http.MethodGet, target: "/api/tenders/my?limit=five", opts: validate,
+			code: http.StatusBadRequest, want: rejected("query.limit must be integer"),
+		},
+		{
+			name: "negative integer", method: http.MethodGet, target: "/api/tenders/my?offset=-1", opts: validate,
+			code: http.StatusBadRequest, want: rejected("query.offset must be at least 0"),
+		},
+		{
+			name: "repeated parameter item", method: http.MethodGet,
+			target: "/api/tenders?service_type=Delivery&service_type=Mining", opts: validate,
+			code: http.StatusBadRequest, want: rejected("query.service_type[1] must be one of [Construction Delivery Manufacture]"),
+		},
+		{
+			name: "long header", method: http.MethodPost, target: "/api/tenders/new", body: tenderBody,
+			header: with(asAlice, HeaderIdempotencyKey, strings.Repeat("k", 256)), opts: validate,
+			code: http.StatusBadRequest, want: rejected("header.Idempotency-Key must be at most 255 characters"),
+		},
+		{
+			name: "missing body", method: http.MethodPost, target: "/api/tenders/new", opts: validate,
+			code: http.StatusBadRequest, want: rejected(openapi.ErrRequestBodyRequired.Error()),
+		},
+		{
+			name: "malformed body", method: http.MethodPost, target: "/api/tenders/new", body: `{"name":`, opts: validate,
+			code: http.StatusBadRequest, want: rejected("body must be JSON"),
+		},
+		{
+			name: "unsupported content type", method: http.MethodPost, target: "/api/tenders/new", body: tenderBody,
+			header: with(asAlice, echo.HeaderContentType, "text/plain"), opts: validate,
+			code: http.StatusBadRequest, want: rejected(`content type "text/plain" is not supported`),
+		},
+		{
+			name: "missing property", method: http.MethodPost, target: "/api/tenders/new",
+			body: `{"name":"Build","description":"Bridge","serviceType":"Construction"}`, opts: validate,
+			code: http.StatusBadRequest, want: rejected("body.organizationId is required"),
+		},
+		{
+			name: "wrong property type", method: http.MethodPost, target: "/api/organizations/" + testOrgId.String() + "/api-keys",
+			body: `{"name":"ci","scopes":["tenders:read"],"expiresInDays":"30"}`, opts: validate,
+			code: http.StatusBadRequest, want: rejected("body.expiresInDays must be integer"),
+		},
+		{
+			name: "long property", method: http.MethodPatch, target: testTenderPath + "/edit",
+			body: `{"name":"` + strings.Repeat("ж", 101) + `"}`, opts: validate,
+			code: http.StatusBadRequest, want: rejected("body.name must be at most 100 characters"),
+		},
+		{
+			name: "empty edit", method: http.MethodPatch, target: testTenderPath + "/edit", body: `{}`, opts: validate,
+			code: http.StatusBadRequest, want: rejected("body must have at least 1 properties"),
+		},
+		{
+			name: "null edit property", method: http.MethodPatch, target: testTenderPath + "/edit",
+			body: `{"name":"Road","deadline":null}`, setup: func(s *stubs) { s.tender.tender = testTender() }, opts: validate,
+			code: http.StatusOK,
+		},
+		{
+			name: "authentication comes first", method: http.MethodGet, target: "/api/tenders/42/status",
+			header: header("Authorization", "Bearer nope"), opts: validate,
+			code: http.StatusUnauthorized, want: reason(service.ErrInvalidToken.Error()),
+		},
+		{
+			name: "unknown route", method: http.MethodGet, target: "/api/nope", opts: validate,
+			code: http.StatusNotFound,
+		},
+	})
+}
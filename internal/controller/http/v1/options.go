@@ -0,0 +1,67 @@
+package httpapi
+
+import (
+	"app/internal/ratelimit"
+	"io"
+	"net"
+	"time"
+)
+
+const defaultStreamHeartbeat = 15 * time.Second
+
+type routerOptions struct {
+	legacyIdentity   bool
+	streamHeartbeat  time.Duration
+	rateLimiter      *ratelimit.Limiter
+	logOutput        io.Writer
+	validateRequests bool
+	trustedProxies   []*net.IPNet
+}
+
+type Option func(*routerOptions)
+
+// LegacyIdentity allows username/authorId request parameters alongside bearer token
+// as long as they match token subject
+func LegacyIdentity(allow bool) Option {
+	return func(o *routerOptions) {
+		o.legacyIdentity = allow
+	}
+}
+
+// StreamHeartbeat is interval of keep-alive comments in idle event streams, it
+// keeps proxies from closing them
+func StreamHeartbeat(interval time.Duration) Option {
+	return func(o *routerOptions) {
+		o.streamHeartbeat = interval
+	}
+}
+
+// RateLimiter limits requests to API, requests are not limited without it
+func RateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(o *routerOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// LogOutput is destination of request log, ./logs/logfile.log is used without it
+func LogOutput(w io.Writer) Option {
+	return func(o *routerOptions) {
+		o.logOutput = w
+	}
+}
+
+// ValidateRequests rejects requests not matching OpenAPI document before they
+// reach handlers
+func ValidateRequests(validate bool) Option {
+	return func(o *routerOptions) {
+		o.validateRequests = validate
+	}
+}
+
+// TrustedProxies are networks of reverse proxies whose X-Forwarded-For is
+// believed. Without them client IP is address of connection
+func TrustedProxies(nets []*net.IPNet) Option {
+	return func(o *routerOptions) {
+		o.trustedProxies = nets
+	}
+}
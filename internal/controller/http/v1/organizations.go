@@ -0,0 +1,447 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type organizationRoutes struct {
+	organizationService service.Organization
+	identity            *identityResolver
+}
+
+func newOrganizationRoutes(s service.Organization, ir *identityResolver) *organizationRoutes {
+	return &organizationRoutes{s, ir}
+}
+
+type organizationResponse struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	CreatedAt   string    `json:"createdAt"`
+}
+
+func newOrganizationResponse(org e.Organization) organizationResponse {
+	return organizationResponse{
+		Id:          org.Id,
+		Name:        org.Name,
+		Description: org.Description,
+		Type:        org.Type,
+		CreatedAt:   org.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+type NewOrganizationDTO struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	Type        string `json:"type" validate:"required,oneof=IE LLC JSC"`
+	Username    string `query:"username" validate:"max=50"`
+}
+
+func (r *organizationRoutes) newOrganization(c echo.Context) error {
+	// Binding and validation
+	var input NewOrganizationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Create organization
+	org, err := r.organizationService.Create(c.Request().Context(), service.CreateOrganizationInput{
+		Name:        input.Name,
+		Description: input.Description,
+		Type:        input.Type,
+		Username:    username,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, newOrganizationResponse(org))
+}
+
+type OrganizationsDTO struct {
+	LimitAndOffset
+	Query string `query:"query" validate:"max=100"`
+	Type  string `query:"type" validate:"omitempty,oneof=IE LLC JSC"`
+}
+
+func (r *organizationRoutes) organizations(c echo.Context) error {
+	// Binding and validation
+	var input OrganizationsDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Search organizations
+	orgs, err := r.organizationService.GetOrganizations(c.Request().Context(), service.GetOrganizationsInput{
+		Limit:  int(input.Limit.Int32),
+		Offset: int(input.Offset.Int32),
+		Query:  input.Query,
+		Type:   input.Type,
+	})
+	if err != nil {
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Create response
+	responseBatch := []organizationResponse{}
+	for _, org := range orgs {
+		responseBatch = append(responseBatch, newOrganizationResponse(org))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type GetOrganizationDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+}
+
+func (r *organizationRoutes) getOrganization(c echo.Context) error {
+	// Binding and validation
+	var input GetOrganizationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Get organization
+	org, err := r.organizationService.Get(c.Request().Context(), input.OrganizationId)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFoundOrganization) {
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, newOrganizationResponse(org))
+}
+
+type EditOrganizationDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+	EditOrganizationBody
+}
+
+func (r *organizationRoutes) editOrganization(c echo.Context) error {
+	// Binding and validation
+	var input EditOrganizationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := editOrganizationBodyValidate(&input.EditOrganizationBody); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Edit organization
+	org, err := r.organizationService.Edit(c.Request().Context(), service.EditOrganizationInput{
+		OrganizationId: input.OrganizationId,
+		Username:       username,
+		Name:           input.Name.String,
+		Description:    input.Description.String,
+		Type:           input.Type.String,
+	})
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newOrganizationResponse(org))
+}
+
+type DeleteOrganizationDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *organizationRoutes) deleteOrganization(c echo.Context) error {
+	// Binding and validation
+	var input DeleteOrganizationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Delete organization
+	if err := r.organizationService.Delete(c.Request().Context(), input.OrganizationId, username); err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type ResponsiblesDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *organizationRoutes) responsibles(c echo.Context) error {
+	// Binding and validation
+	var input ResponsiblesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get responsibles
+	employees, err := r.organizationService.GetResponsibles(c.Request().Context(), input.OrganizationId, username)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	// Create response
+	type response struct {
+		Id        uuid.UUID `json:"id"`
+		Username  string    `json:"username"`
+		FirstName string    `json:"firstName"`
+		LastName  string    `json:"lastName"`
+	}
+	responseBatch := []response{}
+	for _, emp := range employees {
+		responseBatch = append(responseBatch, response{
+			Id:        emp.Id,
+			Username:  emp.Username,
+			FirstName: emp.FirstName,
+			LastName:  emp.LastName,
+		})
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type ResponsibleDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	UserId         uuid.UUID `param:"userId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *organizationRoutes) assignResponsible(c echo.Context) error {
+	// Binding and validation
+	var input ResponsibleDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Assign responsible
+	err = r.organizationService.AssignResponsible(c.Request().Context(), service.ResponsibleInput{
+		OrganizationId: input.OrganizationId,
+		UserId:         input.UserId,
+		Username:       username,
+	})
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *organizationRoutes) unassignResponsible(c echo.Context) error {
+	// Binding and validation
+	var input ResponsibleDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Unassign responsible
+	err = r.organizationService.UnassignResponsible(c.Request().Context(), service.ResponsibleInput{
+		OrganizationId: input.OrganizationId,
+		UserId:         input.UserId,
+		Username:       username,
+	})
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *organizationRoutes) roles(c echo.Context) error {
+	// Binding and validation
+	var input ResponsibleDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get roles
+	roles, err := r.organizationService.GetRoles(c.Request().Context(), service.ResponsibleInput{
+		OrganizationId: input.OrganizationId,
+		UserId:         input.UserId,
+		Username:       username,
+	})
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newRolesResponse(input.OrganizationId, input.UserId, roles))
+}
+
+type PutRolesDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	UserId         uuid.UUID `param:"userId" validate:"required"`
+	Roles          []string  `json:"roles" validate:"required,min=1,dive,oneof=viewer editor publisher approver admin"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *organizationRoutes) putRoles(c echo.Context) error {
+	// Binding and validation
+	var input PutRolesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Set roles
+	roles, err := r.organizationService.SetRoles(c.Request().Context(), service.SetRolesInput{
+		OrganizationId: input.OrganizationId,
+		UserId:         input.UserId,
+		Username:       username,
+		Roles:          input.Roles,
+	})
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newRolesResponse(input.OrganizationId, input.UserId, roles))
+}
+
+type rolesResponse struct {
+	OrganizationId uuid.UUID `json:"organizationId"`
+	UserId         uuid.UUID `json:"userId"`
+	Roles          []string  `json:"roles"`
+}
+
+func newRolesResponse(orgId, userId uuid.UUID, roles []string) rolesResponse {
+	return rolesResponse{
+		OrganizationId: orgId,
+		UserId:         userId,
+		Roles:          roles,
+	}
+}
+
+func organizationErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFoundOrganization),
+		errors.Is(err, service.ErrNotFoundEmployee),
+		errors.Is(err, service.ErrNotFoundResponsible):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrResponsibleExists),
+		errors.Is(err, service.ErrLastResponsible),
+		errors.Is(err, service.ErrLastAdmin):
+		return newErrReasonJSON(c, http.StatusConflict, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,250 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testOrgPath = "/api/organizations/00000000-0000-0000-0000-00000000000a"
+
+func testOrganization() e.Organization {
+	return e.Organization{
+		Id:          testOrgId,
+		Name:        "Acme",
+		Description: "Bridges",
+		Type:        "LLC",
+		CreatedAt:   testTime,
+		UpdatedAt:   testTime,
+	}
+}
+
+const testOrganizationJSON = `{"id":"00000000-0000-0000-0000-00000000000a","name":"Acme","description":"Bridges",
+	"type":"LLC","createdAt":"2024-01-02T03:04:05Z"}`
+
+func withOrganization(s *stubs) { s.organization.organization = testOrganization() }
+
+func failOrganization(s *stubs, err error) { s.organization.err = err }
+
+// organizationErrors is mapping of organizationErrorResponse
+func organizationErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:             http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:  http.StatusUnauthorized,
+		service.ErrForbidden:            http.StatusForbidden,
+		service.ErrNotFoundOrganization: http.StatusNotFound,
+		service.ErrNotFoundEmployee:     http.StatusNotFound,
+		service.ErrNotFoundResponsible:  http.StatusNotFound,
+		service.ErrResponsibleExists:    http.StatusConflict,
+		service.ErrLastResponsible:      http.StatusConflict,
+		service.ErrLastAdmin:            http.StatusConflict,
+	}
+}
+
+func TestNewOrganization(t *testing.T) {
+	const target = "/api/organizations/new"
+	body := `{"name":"Acme","description":"Bridges","type":"LLC"}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body, setup: withOrganization,
+			code: http.StatusOK, want: testOrganizationJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, service.CreateOrganizationInput{
+					Name:        "Acme",
+					Description: "Bridges",
+					Type:        "LLC",
+					Username:    "alice",
+				})
+			},
+		},
+		validationCase("no name", http.MethodPost, target, `{"type":"LLC"}`),
+		validationCase("long name", http.MethodPost, target, `{"name":"`+longString(101)+`","type":"LLC"}`),
+		validationCase("long description", http.MethodPost, target, `{"name":"Acme","description":"`+longString(501)+`","type":"LLC"}`),
+		validationCase("unknown type", http.MethodPost, target, `{"name":"Acme","type":"GmbH"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failOrganization, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrForbidden:           http.StatusInternalServerError,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestOrganizations(t *testing.T) {
+	const target = "/api/organizations"
+
+	cases := []routeCase{
+		{
+			name: "anonymous", method: http.MethodGet, target: target, header: asAnonymous,
+			setup: func(s *stubs) { s.organization.organizations = []e.Organization{testOrganization()} },
+			code:  http.StatusOK, want: "[" + testOrganizationJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, service.GetOrganizationsInput{Limit: DefaultLimit, Offset: DefaultOffset})
+			},
+		},
+		{
+			name: "search", method: http.MethodGet, target: target + "?query=ac&type=JSC&limit=1&offset=2",
+			code: http.StatusOK, want: `[]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, service.GetOrganizationsInput{Limit: 1, Offset: 2, Query: "ac", Type: "JSC"})
+			},
+		},
+		validationCase("unknown type", http.MethodGet, target+"?type=GmbH", ""),
+		validationCase("long query", http.MethodGet, target+"?query="+longString(101), ""),
+		invalidCase("negative offset", http.MethodGet, target+"?offset=-1", ""),
+	}
+	cases = append(cases, errorCases(http.MethodGet, target, "", failOrganization, map[error]int{})...)
+	runRouteCases(t, cases)
+}
+
+func TestGetOrganization(t *testing.T) {
+	cases := []routeCase{
+		{
+			name: "anonymous", method: http.MethodGet, target: testOrgPath, header: asAnonymous, setup: withOrganization,
+			code: http.StatusOK, want: testOrganizationJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, testOrgId)
+			},
+		},
+	}
+	cases = append(cases, errorCases(http.MethodGet, testOrgPath, "", failOrganization, map[error]int{
+		service.ErrNotFoundOrganization: http.StatusNotFound,
+		service.ErrForbidden:            http.StatusInternalServerError,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestEditOrganization(t *testing.T) {
+	target := testOrgPath + "/edit"
+	body := `{"type":"JSC"}`
+
+	cases := []routeCase{
+		{
+			name: "edited", method: http.MethodPatch, target: target, body: body, setup: withOrganization,
+			code: http.StatusOK, want: testOrganizationJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, service.EditOrganizationInput{
+					OrganizationId: testOrgId,
+					Username:       "alice",
+					Type:           "JSC",
+				})
+			},
+		},
+		invalidCase("nothing to edit", http.MethodPatch, target, `{}`),
+		invalidCase("empty name", http.MethodPatch, target, `{"name":""}`),
+		invalidCase("long description", http.MethodPatch, target, `{"description":"`+longString(501)+`"}`),
+		invalidCase("unknown type", http.MethodPatch, target, `{"type":"GmbH"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPatch, target, body)...)
+	cases = append(cases, errorCases(http.MethodPatch, target, body, failOrganization, organizationErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	cases := []routeCase{
+		{
+			name: "deleted", method: http.MethodDelete, target: testOrgPath,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.organization.in, []any{testOrgId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodDelete, testOrgPath, "")...)
+	cases = append(cases, errorCases(http.MethodDelete, testOrgPath, "", failOrganization, organizationErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestResponsibles(t *testing.T) {
+	target := testOrgPath + "/responsibles"
+	responsible := testOrgPath + "/responsibles/" + alice.Id.String()
+	wantResponsible := func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+		wantInput(t, s.organization.in, service.ResponsibleInput{OrganizationId: testOrgId, UserId: alice.Id, Username: "alice"})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		cases := []routeCase{
+			{
+				name: "responsibles", method: http.MethodGet, target: target,
+				setup: func(s *stubs) { s.organization.employees = []e.Employee{alice} },
+				code:  http.StatusOK,
+				want: `[{"id":"00000000-0000-0000-0000-000000000001","username":"alice",
+					"firstName":"Alice","lastName":"Smith"}]`,
+				check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+					wantInput(t, s.organization.in, []any{testOrgId, "alice"})
+				},
+			},
+		}
+		cases = append(cases, identityCases(http.MethodGet, target, "")...)
+		cases = append(cases, errorCases(http.MethodGet, target, "", failOrganization, organizationErrors())...)
+		runRouteCases(t, cases)
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		cases := []routeCase{
+			{name: "assigned", method: http.MethodPut, target: responsible, code: http.StatusNoContent, check: wantResponsible},
+		}
+		cases = append(cases, identityCases(http.MethodPut, responsible, "")...)
+		cases = append(cases, errorCases(http.MethodPut, responsible, "", failOrganization, organizationErrors())...)
+		runRouteCases(t, cases)
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		cases := []routeCase{
+			{name: "unassigned", method: http.MethodDelete, target: responsible, code: http.StatusNoContent, check: wantResponsible},
+		}
+		cases = append(cases, identityCases(http.MethodDelete, responsible, "")...)
+		cases = append(cases, errorCases(http.MethodDelete, responsible, "", failOrganization, organizationErrors())...)
+		runRouteCases(t, cases)
+	})
+}
+
+func TestResponsibleRoles(t *testing.T) {
+	target := testOrgPath + "/responsibles/" + alice.Id.String() + "/roles"
+	const rolesJSON = `{"organizationId":"00000000-0000-0000-0000-00000000000a",
+		"userId":"00000000-0000-0000-0000-000000000001","roles":["editor","publisher"]}`
+	withRoles := func(s *stubs) { s.organization.roles = []string{"editor", "publisher"} }
+
+	t.Run("get", func(t *testing.T) {
+		cases := []routeCase{
+			{
+				name: "roles", method: http.MethodGet, target: target, setup: withRoles,
+				code: http.StatusOK, want: rolesJSON,
+				check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+					wantInput(t, s.organization.in, service.ResponsibleInput{OrganizationId: testOrgId, UserId: alice.Id, Username: "alice"})
+				},
+			},
+		}
+		cases = append(cases, identityCases(http.MethodGet, target, "")...)
+		cases = append(cases, errorCases(http.MethodGet, target, "", failOrganization, organizationErrors())...)
+		runRouteCases(t, cases)
+	})
+
+	t.Run("put", func(t *testing.T) {
+		body := `{"roles":["editor","publisher"]}`
+		cases := []routeCase{
+			{
+				name: "set", method: http.MethodPut, target: target, body: body, setup: withRoles,
+				code: http.StatusOK, want: rolesJSON,
+				check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+					wantInput(t, s.organization.in, service.SetRolesInput{
+						OrganizationId: testOrgId,
+						UserId:         alice.Id,
+						Username:       "alice",
+						Roles:          []string{"editor", "publisher"},
+					})
+				},
+			},
+			validationCase("no roles", http.MethodPut, target, `{"roles":[]}`),
+			validationCase("missing roles", http.MethodPut, target, `{}`),
+			validationCase("unknown role", http.MethodPut, target, `{"roles":["owner"]}`),
+		}
+		cases = append(cases, identityCases(http.MethodPut, target, body)...)
+		cases = append(cases, errorCases(http.MethodPut, target, body, failOrganization, organizationErrors())...)
+		runRouteCases(t, cases)
+	})
+}
@@ -0,0 +1,67 @@
+package httpapi
+
+import (
+	"app/internal/identity"
+	"app/internal/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// rateLimitMiddleware rejects requests over limits with 429. Headers describe
+// most restrictive limit request is subject to, Reset is seconds until it is
+// fully restored
+func rateLimitMiddleware(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := ratelimit.Request{
+				IP:    c.RealIP(),
+				Write: !isReadMethod(c.Request().Method),
+			}
+			if employee, ok := identity.EmployeeFromContext(c.Request().Context()); ok {
+				req.EmployeeId = uuid.NullUUID{UUID: employee.Id, Valid: true}
+				// Employee managing organization shares its limit with API keys of it
+				if orgId, err := uuid.Parse(c.Param("organizationId")); err == nil {
+					req.OrganizationId = uuid.NullUUID{UUID: orgId, Valid: true}
+				}
+			}
+			if apiKey, ok := identity.ServiceFromContext(c.Request().Context()); ok {
+				req.OrganizationId = uuid.NullUUID{UUID: apiKey.OrganizationId, Valid: true}
+			}
+
+			res, ok := limiter.Take(c.Request().Context(), req)
+			if !ok {
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(max(1, ceilSeconds(res.RetryAfter))))
+				return newErrReasonJSON(c, http.StatusTooManyRequests, ErrRateLimited.Error())
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
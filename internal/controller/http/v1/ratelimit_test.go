@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"app/internal/ratelimit"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ipLimiter lets one read per client IP
+func ipLimiter() *ratelimit.Limiter {
+	return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Policy{
+		IP: ratelimit.Limits{Read: ratelimit.Rule{Limit: 1, Period: time.Minute}},
+	})
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	// httptest requests come from 192.0.2.1
+	_, proxy, _ := net.ParseCIDR("192.0.2.0/24")
+	_, other, _ := net.ParseCIDR("198.51.100.0/24")
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		forward []string
+		want    []int
+	}{
+		{
+			name:    "forwarded for ignored",
+			forward: []string{"203.0.113.1", "203.0.113.2"},
+			want:    []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "forwarded for from untrusted proxy",
+			opts:    []Option{TrustedProxies([]*net.IPNet{other})},
+			forward: []string{"203.0.113.1", "203.0.113.2"},
+			want:    []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:    "forwarded for from trusted proxy",
+			opts:    []Option{TrustedProxies([]*net.IPNet{proxy})},
+			forward: []string{"203.0.113.1", "203.0.113.2", "203.0.113.1"},
+			want:    []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newRouter(newStubs(), append([]Option{RateLimiter(ipLimiter())}, tt.opts...)...)
+			for i, ip := range tt.forward {
+				rec := serve(handler, http.MethodGet, "/api/ping", "", header(echo.HeaderXForwardedFor, ip))
+				if rec.Code != tt.want[i] {
+					t.Errorf("request %d from %s: status %d, want %d", i+1, ip, rec.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitOrganization(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Policy{
+		Organization: ratelimit.Limits{Read: ratelimit.Rule{Limit: 1, Period: time.Minute}},
+	})
+	handler := newRouter(newStubs(), RateLimiter(limiter))
+
+	// Employee managing organization uses up limit of its API keys
+	rec := serve(handler, http.MethodGet, "/api/organizations/"+testOrgId.String()+"/api-keys", "", asAlice)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("employee: status %d, want %d, body %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	rec = serve(handler, http.MethodGet, testTenderPath+"/status", "", asAPIKey)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("api key: status %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "60" {
+		t.Errorf("Retry-After %q, want 60", got)
+	}
+}
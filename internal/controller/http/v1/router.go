@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"app/internal/service"
+	"app/pkg/openapi"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,36 +12,138 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func ConfigureRouter(handler *echo.Echo, services *service.Services) {
-	handler.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Output: setLogsFile()}))
+func ConfigureRouter(handler *echo.Echo, services *service.Services, opts ...Option) {
+	options := &routerOptions{streamHeartbeat: defaultStreamHeartbeat}
+	for _, opt := range opts {
+		opt(options)
+	}
+	ir := &identityResolver{allowLegacy: options.legacyIdentity}
+	handler.IPExtractor = ipExtractor(options.trustedProxies)
+	if options.logOutput == nil {
+		options.logOutput = setLogsFile()
+	}
+
+	handler.Use(middleware.RequestID())
+	handler.Use(requestMiddleware)
+	handler.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Output: options.logOutput}))
 	handler.Use(middleware.Recover())
 
 	api := handler.Group("/api")
+	api.Use(authMiddleware(services.Auth))
+	api.Use(apiKeyMiddleware(services.APIKey))
+	if options.rateLimiter != nil {
+		api.Use(rateLimitMiddleware(options.rateLimiter))
+	}
+	if options.validateRequests {
+		doc, err := openapi.Load(openAPISpec)
+		if err != nil {
+			log.Fatal(fmt.Errorf("httpapi - ConfigureRouter - openapi.Load: %w", err))
+		}
+		api.Use(openAPIMiddleware(doc))
+	}
+	idempotent := idempotencyMiddleware(services.Idempotency)
 	{
 		api.GET("/ping", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+		api.GET("/openapi.json", serveOpenAPI)
+
+		auth := api.Group("/auth")
+		{
+			r := newAuthRoutes(services.Auth, ir)
+			auth.POST("/login", r.login)
+			auth.GET("/me", r.me)
+			auth.PUT("/password", r.setPassword)
+			auth.POST("/password/change", r.changePassword)
+			auth.POST("/password/reset-tokens", r.newResetToken)
+			auth.POST("/password/reset", r.resetPassword)
+			auth.GET("/login-attempts", r.loginAttempts)
+		}
 
 		tenders := api.Group("/tenders")
 		{
-			r := newTenderRoutes(services.Tender)
-			tenders.POST("/new", r.newTender)
+			r := newTenderRoutes(services.Tender, ir)
+			tenders.POST("/new", r.newTender, idempotent)
 			tenders.GET("/my", r.myTenders)
+			tenders.GET("/invited", r.invitedTenders)
 			tenders.GET("", r.tenders)
 			tenders.PUT("/:tenderId/status", r.putStatus)
 			tenders.GET("/:tenderId/status", r.getStatus)
 			tenders.PATCH("/:tenderId/edit", r.editTender)
 			tenders.PUT("/:tenderId/rollback/:version", r.rollbackTender)
+			tenders.PUT("/:tenderId/visibility", r.putVisibility)
+			tenders.GET("/:tenderId/invitations", r.invitations)
+			tenders.POST("/:tenderId/invitations", r.newInvitation)
+			tenders.DELETE("/:tenderId/invitations/:invitationId", r.deleteInvitation)
+
+			a := newActivityRoutes(services.Activity, ir, options.streamHeartbeat)
+			tenders.GET("/activity", a.activity)
 		}
 
 		bids := api.Group("/bids")
 		{
-			r := newBidRoutes(services.Bid)
-			bids.POST("/new", r.newBid)
+			r := newBidRoutes(services.Bid, ir)
+			bids.POST("/new", r.newBid, idempotent)
 			bids.PUT("/:bidId/submit_decision", r.submitDecision)
 			bids.PUT("/:bidId/status", r.putStatus)
 			bids.GET("/:bidId/status", r.getStatus)
 			bids.PATCH("/:bidId/edit", r.editBid)
 			bids.PUT("/:bidId/rollback/:version", r.rollbackBid)
 		}
+
+		organizations := api.Group("/organizations")
+		{
+			r := newOrganizationRoutes(services.Organization, ir)
+			organizations.POST("/new", r.newOrganization)
+			organizations.GET("", r.organizations)
+			organizations.GET("/:organizationId", r.getOrganization)
+			organizations.PATCH("/:organizationId/edit", r.editOrganization)
+			organizations.DELETE("/:organizationId", r.deleteOrganization)
+			organizations.GET("/:organizationId/responsibles", r.responsibles)
+			organizations.PUT("/:organizationId/responsibles/:userId", r.assignResponsible)
+			organizations.DELETE("/:organizationId/responsibles/:userId", r.unassignResponsible)
+			organizations.GET("/:organizationId/responsibles/:userId/roles", r.roles)
+			organizations.PUT("/:organizationId/responsibles/:userId/roles", r.putRoles)
+
+			k := newAPIKeyRoutes(services.APIKey, ir)
+			organizations.POST("/:organizationId/api-keys", k.newAPIKey)
+			organizations.GET("/:organizationId/api-keys", k.apiKeys)
+			organizations.DELETE("/:organizationId/api-keys/:keyId", k.revokeAPIKey)
+
+			a := newAuditRoutes(services.Audit, ir)
+			organizations.GET("/:organizationId/audit", a.auditLog)
+
+			w := newWebhookRoutes(services.Webhook, ir)
+			organizations.POST("/:organizationId/webhooks", w.newWebhook)
+			organizations.GET("/:organizationId/webhooks", w.webhooks)
+			organizations.DELETE("/:organizationId/webhooks/:webhookId", w.deleteWebhook)
+			organizations.GET("/:organizationId/webhooks/:webhookId/deliveries", w.deliveries)
+			organizations.POST("/:organizationId/webhooks/:webhookId/deliveries/:deliveryId/redeliver", w.redeliver)
+		}
+
+		employees := api.Group("/employees")
+		{
+			r := newEmployeeRoutes(services.Employee, ir)
+			employees.POST("/new", r.newEmployee)
+			employees.GET("", r.employees)
+			employees.GET("/:employeeId", r.getEmployee)
+			employees.PATCH("/:employeeId/edit", r.editEmployee)
+			employees.PUT("/:employeeId/deactivate", r.deactivateEmployee)
+			employees.PUT("/:employeeId/activate", r.activateEmployee)
+			employees.PUT("/:employeeId/grant-admin", r.grantAdmin)
+			employees.PUT("/:employeeId/revoke-admin", r.revokeAdmin)
+		}
+
+		notifications := api.Group("/notifications")
+		{
+			r := newNotificationRoutes(services.Notification, ir)
+			notifications.GET("/email-preferences", r.emailPreferences)
+			notifications.PUT("/email-preferences", r.putEmailPreferences)
+
+			i := newInboxRoutes(services.Inbox, ir)
+			notifications.GET("", i.notifications)
+			notifications.GET("/unread-count", i.unreadCount)
+			notifications.PUT("/read-all", i.markAllRead)
+			notifications.PUT("/:notificationId/read", i.markRead)
+		}
 	}
 }
 
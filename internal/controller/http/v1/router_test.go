@@ -0,0 +1,352 @@
+package httpapi
+
+import (
+	"app/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testTenderPath = "/api/tenders/00000000-0000-0000-0000-0000000000f1"
+
+var testTenderId = uuid.MustParse("00000000-0000-0000-0000-0000000000f1")
+
+func TestPing(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{name: "anonymous", method: http.MethodGet, target: "/api/ping", header: asAnonymous, code: http.StatusOK},
+		{name: "unknown route", method: http.MethodGet, target: "/api/nope", code: http.StatusNotFound},
+	})
+	rec := serve(newRouter(newStubs()), http.MethodGet, "/api/ping", "", asAnonymous)
+	if rec.Body.String() != "ok" {
+		t.Errorf("body %q, want ok", rec.Body)
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{
+			name: "not bearer", method: http.MethodGet, target: "/api/auth/me",
+			header: header("Authorization", "Basic YWxpY2U6c2VjcmV0"),
+			code:   http.StatusUnauthorized, want: reason(ErrInvalidAuthHeader.Error()),
+		},
+		{
+			name: "empty token", method: http.MethodGet, target: "/api/auth/me",
+			header: header("Authorization", "Bearer "),
+			code:   http.StatusUnauthorized, want: reason(ErrInvalidAuthHeader.Error()),
+		},
+		{
+			name: "unknown token", method: http.MethodGet, target: "/api/auth/me",
+			header: header("Authorization", "Bearer nope"),
+			code:   http.StatusUnauthorized, want: reason(service.ErrInvalidToken.Error()),
+		},
+		{
+			name: "deactivated employee", method: http.MethodGet, target: "/api/auth/me", header: asInactive,
+			code: http.StatusUnauthorized, want: reason(service.ErrEmployeeDeactivated.Error()),
+		},
+		{
+			name: "token on anonymous endpoint", method: http.MethodGet, target: "/api/ping",
+			header: header("Authorization", "Bearer nope"),
+			code:   http.StatusUnauthorized, want: reason(service.ErrInvalidToken.Error()),
+		},
+		{
+			name: "authenticated", method: http.MethodGet, target: "/api/auth/me",
+			code: http.StatusOK,
+			want: `{"id":"00000000-0000-0000-0000-000000000001","username":"alice","firstName":"Alice",
+				"lastName":"Smith","isActive":true,"isAdmin":false,"createdAt":"` + testTimeJSON + `"}`,
+		},
+	})
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{
+			name: "unknown key", method: http.MethodGet, target: "/api/ping",
+			header: header(HeaderAPIKey, "tk_nope.secret"),
+			code:   http.StatusUnauthorized, want: reason(service.ErrInvalidAPIKey.Error()),
+		},
+		{
+			name: "key with access token", method: http.MethodGet, target: "/api/ping",
+			header: with(asAlice, HeaderAPIKey, "tk_ci.secret"),
+			code:   http.StatusBadRequest, want: reason(ErrAmbiguousCredentials.Error()),
+		},
+		{
+			name: "key on employee endpoint", method: http.MethodGet, target: "/api/auth/me", header: asAPIKey,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "key caller", method: http.MethodGet, target: testTenderPath + "/status", header: asAPIKey,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, []any{testTenderId, "api-key:tk_ci"})
+			},
+		},
+		{
+			name: "key caller with own pseudo username", method: http.MethodGet,
+			target: testTenderPath + "/status?username=api-key:tk_ci", header: asAPIKey,
+			code: http.StatusOK,
+		},
+		{
+			name: "key caller with other username", method: http.MethodGet,
+			target: testTenderPath + "/status?username=alice", header: asAPIKey,
+			code: http.StatusForbidden, want: reason(ErrIdentityMismatch.Error()),
+		},
+	})
+}
+
+func TestLegacyIdentity(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{
+			name: "rejected by default", method: http.MethodGet, target: "/api/tenders/my?username=alice",
+			code: http.StatusBadRequest, want: reason(ErrLegacyIdentity.Error()),
+		},
+		{
+			name: "matching token", method: http.MethodGet, target: "/api/tenders/my?username=alice",
+			opts: []Option{LegacyIdentity(true)},
+			code: http.StatusOK, want: `[]`,
+		},
+		{
+			name: "mismatching token", method: http.MethodGet, target: "/api/tenders/my?username=carol",
+			opts: []Option{LegacyIdentity(true)},
+			code: http.StatusForbidden, want: reason(ErrIdentityMismatch.Error()),
+		},
+		{
+			name: "without token", method: http.MethodGet, target: "/api/tenders/my?username=alice", header: asAnonymous,
+			opts: []Option{LegacyIdentity(true)},
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "author id", method: http.MethodPost, target: "/api/bids/new",
+			body: `{"name":"b","description":"d","tenderId":"` + testTenderId.String() + `","authorType":"User",
+				"authorId":"00000000-0000-0000-0000-000000000009"}`,
+			opts: []Option{LegacyIdentity(true)},
+			code: http.StatusForbidden, want: reason(ErrIdentityMismatch.Error()),
+		},
+	})
+}
+
+func TestPrecondition(t *testing.T) {
+	updatedAt := testTime.Add(time.Second)
+	etag := fmt.Sprintf(`"3.%d"`, updatedAt.UnixMicro())
+	target := testTenderPath + "/status?status=Closed"
+	published := func(s *stubs) {
+		s.tender.tender = testTender()
+		s.tender.tender.Version = 3
+		s.tender.tender.UpdatedAt = updatedAt
+	}
+	wantPrecondition := func(want service.Precondition) func(*testing.T, *stubs, *httptest.ResponseRecorder) {
+		return func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+			in, _ := s.tender.in.(service.ChangeTenderStatusInput)
+			if in.Precondition.Version != want.Version || !in.Precondition.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("precondition %+v, want %+v", in.Precondition, want)
+			}
+		}
+	}
+
+	runRouteCases(t, []routeCase{
+		{
+			name: "no precondition", method: http.MethodPut, target: target, setup: published,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				if got := rec.Header().Get("ETag"); got != etag {
+					t.Errorf("ETag %s, want %s", got, etag)
+				}
+				wantPrecondition(service.Precondition{})(t, s, rec)
+			},
+		},
+		{
+			name: "expected version", method: http.MethodPut, target: target + "&expectedVersion=3", setup: published,
+			code:  http.StatusOK,
+			check: wantPrecondition(service.Precondition{Version: 3}),
+		},
+		{
+			name: "any", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", "*"), setup: published,
+			code:  http.StatusOK,
+			check: wantPrecondition(service.Precondition{}),
+		},
+		{
+			name: "etag", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", etag), setup: published,
+			code:  http.StatusOK,
+			check: wantPrecondition(service.Precondition{Version: 3, UpdatedAt: updatedAt}),
+		},
+		{
+			name: "etag with same expected version", method: http.MethodPut, target: target + "&expectedVersion=3",
+			header: with(asAlice, "If-Match", etag), setup: published,
+			code:  http.StatusOK,
+			check: wantPrecondition(service.Precondition{Version: 3, UpdatedAt: updatedAt}),
+		},
+		{
+			name: "etag with other expected version", method: http.MethodPut, target: target + "&expectedVersion=2",
+			header: with(asAlice, "If-Match", etag),
+			code:   http.StatusPreconditionFailed, want: reason(service.ErrVersionMismatch.Error()),
+		},
+		{
+			name: "weak etag", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", "W/"+etag),
+			code: http.StatusBadRequest, want: reason(errIfMatch.Error()),
+		},
+		{
+			name: "etag list", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", etag+", "+etag),
+			code: http.StatusBadRequest, want: reason(errIfMatch.Error()),
+		},
+		{
+			name: "etag without time", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", `"3"`),
+			code: http.StatusBadRequest, want: reason(errIfMatch.Error()),
+		},
+		{
+			name: "etag of version zero", method: http.MethodPut, target: target, header: with(asAlice, "If-Match", `"0.1"`),
+			code: http.StatusBadRequest, want: reason(errIfMatch.Error()),
+		},
+		{
+			name: "negative expected version", method: http.MethodPut, target: target + "&expectedVersion=-1",
+			code: http.StatusBadRequest, want: reason("validation error"),
+		},
+	})
+}
+
+func TestIdempotency(t *testing.T) {
+	body := `{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"` + testOrgId.String() + `"}`
+	runRouteCases(t, []routeCase{
+		{
+			name: "without key", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if len(s.idempotency.begun) != 0 {
+					t.Errorf("request without key was claimed")
+				}
+			},
+		},
+		{
+			name: "stored on success", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.tender.tender = testTender() },
+			code:   http.StatusOK,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				if len(s.idempotency.begun) != 1 || s.idempotency.begun[0].Caller != "employee:"+alice.Id.String() {
+					t.Fatalf("begun %+v", s.idempotency.begun)
+				}
+				if len(s.idempotency.kept) != 1 || s.idempotency.kept[0] != "k1" {
+					t.Errorf("kept %v while handled, want [k1]", s.idempotency.kept)
+				}
+				if len(s.idempotency.completed) != 1 {
+					t.Fatalf("completed %d requests, want 1", len(s.idempotency.completed))
+				}
+				stored := s.idempotency.completed[0].Response
+				if stored.Status != http.StatusOK || string(stored.Body) != rec.Body.String() {
+					t.Errorf("stored %d %s, sent %d %s", stored.Status, stored.Body, rec.Code, rec.Body)
+				}
+			},
+		},
+		{
+			name: "client error stored", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.tender.err = service.ErrForbidden },
+			code:   http.StatusForbidden,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if len(s.idempotency.completed) != 1 || len(s.idempotency.released) != 0 {
+					t.Errorf("completed %d, released %d", len(s.idempotency.completed), len(s.idempotency.released))
+				}
+			},
+		},
+		{
+			name: "server error released", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.tender.err = service.ErrCreateTender },
+			code:   http.StatusInternalServerError,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if len(s.idempotency.completed) != 0 || len(s.idempotency.released) != 1 {
+					t.Errorf("completed %d, released %d", len(s.idempotency.completed), len(s.idempotency.released))
+				}
+			},
+		},
+		{
+			name: "replay", method: http.MethodPost, target: "/api/bids/new",
+			body:   `{"name":"Offer","description":"Cheap","tenderId":"` + testTenderId.String() + `","authorType":"User"}`,
+			header: with(asAPIKey, HeaderIdempotencyKey, "k1"),
+			setup: func(s *stubs) {
+				s.idempotency.replay = true
+				s.idempotency.stored = service.IdempotentResponse{
+					Status:      http.StatusOK,
+					ContentType: "application/json",
+					Body:        []byte(`{"id":"stored"}`),
+				}
+			},
+			code: http.StatusOK, want: `{"id":"stored"}`,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				if rec.Header().Get(HeaderIdempotentReplayed) != "true" {
+					t.Errorf("replayed response is not marked")
+				}
+				if len(s.idempotency.kept) != 0 {
+					t.Errorf("replayed request kept key %v", s.idempotency.kept)
+				}
+				if s.idempotency.begun[0].Caller != "api-key:"+testKey.KeyId.String() {
+					t.Errorf("caller %s", s.idempotency.begun[0].Caller)
+				}
+				if s.bid.in != nil {
+					t.Errorf("replayed request reached service")
+				}
+			},
+		},
+		{
+			name: "anonymous passed as is", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: header(HeaderIdempotencyKey, "k1"),
+			code:   http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "invalid key", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k\x01"),
+			code:   http.StatusBadRequest, want: reason(ErrIdempotencyKeyHeader.Error()),
+		},
+		{
+			name: "mismatch", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.idempotency.err = service.ErrIdempotencyMismatch },
+			code:   http.StatusUnprocessableEntity, want: reason(service.ErrIdempotencyMismatch.Error()),
+		},
+		{
+			name: "in progress", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.idempotency.err = service.ErrIdempotencyInProgress },
+			code:   http.StatusConflict, want: reason(service.ErrIdempotencyInProgress.Error()),
+		},
+		{
+			name: "storage failure", method: http.MethodPost, target: "/api/tenders/new", body: body,
+			header: with(asAlice, HeaderIdempotencyKey, "k1"),
+			setup:  func(s *stubs) { s.idempotency.err = service.ErrIdempotencyKey },
+			code:   http.StatusInternalServerError, want: reason(service.ErrIdempotencyKey.Error()),
+		},
+	})
+}
+
+func TestBindingErrors(t *testing.T) {
+	runRouteCases(t, []routeCase{
+		{
+			name: "malformed path uuid", method: http.MethodGet, target: "/api/tenders/not-a-uuid/status",
+			code: http.StatusBadRequest, want: reason("invalid UUID length: 10"),
+		},
+		{
+			name: "malformed path version", method: http.MethodPut, target: testTenderPath + "/rollback/abc",
+			code: http.StatusBadRequest, want: reason(`strconv.ParseInt: parsing "abc": invalid syntax`),
+		},
+		{
+			name: "malformed query number", method: http.MethodPut, target: testTenderPath + "/status?status=Closed&expectedVersion=x",
+			code: http.StatusBadRequest, want: reason(`strconv.ParseInt: parsing "x": invalid syntax`),
+		},
+		{
+			name: "malformed limit", method: http.MethodGet, target: "/api/tenders/my?limit=abc",
+			code: http.StatusBadRequest, want: reason(`null: couldn't unmarshal text: strconv.ParseInt: parsing "abc": invalid syntax`),
+		},
+		{
+			name: "malformed JSON", method: http.MethodPost, target: "/api/tenders/new", body: `{bad`,
+			code: http.StatusBadRequest,
+			want: reason("Syntax error: offset=2, error=invalid character 'b' looking for beginning of object key string"),
+		},
+		{
+			name: "JSON type mismatch", method: http.MethodPost, target: "/api/tenders/new", body: `{"name": 5}`,
+			code: http.StatusBadRequest,
+			want: reason("Unmarshal type error: expected=string, got=number, field=name, offset=10"),
+		},
+	})
+}
@@ -0,0 +1,436 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/service"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Stub services return canned results, or err if it is set, and keep
+// arguments of last call in in
+
+type stubTender struct {
+	service.Tender
+	tender      e.Tender
+	tenders     []e.Tender
+	invitation  e.TenderInvitation
+	invitations []e.TenderInvitation
+	err         error
+	in          any
+}
+
+func (s *stubTender) CreateTender(ctx context.Context, in service.CreateTenderInput) (e.Tender, error) {
+	s.in = in
+	return s.tender, s.err
+}
+
+func (s *stubTender) ChangeStatus(ctx context.Context, in service.ChangeTenderStatusInput) (e.Tender, error) {
+	s.in = in
+	return s.tender, s.err
+}
+
+func (s *stubTender) Edit(ctx context.Context, in service.EditTenderInput) (e.Tender, error) {
+	s.in = in
+	return s.tender, s.err
+}
+
+func (s *stubTender) Rollback(ctx context.Context, in service.RollbackTenderInput) (e.Tender, error) {
+	s.in = in
+	return s.tender, s.err
+}
+
+func (s *stubTender) GetTendersByUsername(ctx context.Context, in service.GetByUsernameInput) ([]e.Tender, error) {
+	s.in = in
+	return s.tenders, s.err
+}
+
+func (s *stubTender) GetTenders(ctx context.Context, in service.GetTendersInput) ([]e.Tender, error) {
+	s.in = in
+	return s.tenders, s.err
+}
+
+func (s *stubTender) GetTender(ctx context.Context, tenderId uuid.UUID, username string) (e.Tender, error) {
+	s.in = []any{tenderId, username}
+	return s.tender, s.err
+}
+
+func (s *stubTender) ChangeVisibility(ctx context.Context, in service.ChangeTenderVisibilityInput) (e.Tender, error) {
+	s.in = in
+	return s.tender, s.err
+}
+
+func (s *stubTender) Invite(ctx context.Context, in service.InviteToTenderInput) (e.TenderInvitation, error) {
+	s.in = in
+	return s.invitation, s.err
+}
+
+func (s *stubTender) GetInvitations(ctx context.Context, tenderId uuid.UUID, username string) ([]e.TenderInvitation, error) {
+	s.in = []any{tenderId, username}
+	return s.invitations, s.err
+}
+
+func (s *stubTender) RevokeInvitation(ctx context.Context, in service.RevokeInvitationInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubTender) GetInvitedTenders(ctx context.Context, in service.GetByUsernameInput) ([]e.Tender, error) {
+	s.in = in
+	return s.tenders, s.err
+}
+
+type stubBid struct {
+	service.Bid
+	bid e.Bid
+	err error
+	in  any
+}
+
+func (s *stubBid) CreateBid(ctx context.Context, in service.CreateBidInput) (e.Bid, error) {
+	s.in = in
+	return s.bid, s.err
+}
+
+func (s *stubBid) SubmitDecision(ctx context.Context, bidId uuid.UUID, username string, decision string) (e.Bid, error) {
+	s.in = []any{bidId, username, decision}
+	return s.bid, s.err
+}
+
+func (s *stubBid) ChangeStatus(ctx context.Context, in service.ChangeBidStatusInput) (e.Bid, error) {
+	s.in = in
+	return s.bid, s.err
+}
+
+func (s *stubBid) Get(ctx context.Context, bidId uuid.UUID, username string) (e.Bid, error) {
+	s.in = []any{bidId, username}
+	return s.bid, s.err
+}
+
+func (s *stubBid) Edit(ctx context.Context, in service.EditBidInput) (e.Bid, error) {
+	s.in = in
+	return s.bid, s.err
+}
+
+func (s *stubBid) Rollback(ctx context.Context, in service.RollbackBidInput) (e.Bid, error) {
+	s.in = in
+	return s.bid, s.err
+}
+
+type stubOrganization struct {
+	service.Organization
+	organization  e.Organization
+	organizations []e.Organization
+	employees     []e.Employee
+	roles         []string
+	err           error
+	in            any
+}
+
+func (s *stubOrganization) Create(ctx context.Context, in service.CreateOrganizationInput) (e.Organization, error) {
+	s.in = in
+	return s.organization, s.err
+}
+
+func (s *stubOrganization) Get(ctx context.Context, orgId uuid.UUID) (e.Organization, error) {
+	s.in = orgId
+	return s.organization, s.err
+}
+
+func (s *stubOrganization) GetOrganizations(ctx context.Context, in service.GetOrganizationsInput) ([]e.Organization, error) {
+	s.in = in
+	return s.organizations, s.err
+}
+
+func (s *stubOrganization) Edit(ctx context.Context, in service.EditOrganizationInput) (e.Organization, error) {
+	s.in = in
+	return s.organization, s.err
+}
+
+func (s *stubOrganization) Delete(ctx context.Context, orgId uuid.UUID, username string) error {
+	s.in = []any{orgId, username}
+	return s.err
+}
+
+func (s *stubOrganization) GetResponsibles(ctx context.Context, orgId uuid.UUID, username string) ([]e.Employee, error) {
+	s.in = []any{orgId, username}
+	return s.employees, s.err
+}
+
+func (s *stubOrganization) AssignResponsible(ctx context.Context, in service.ResponsibleInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubOrganization) UnassignResponsible(ctx context.Context, in service.ResponsibleInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubOrganization) GetRoles(ctx context.Context, in service.ResponsibleInput) ([]string, error) {
+	s.in = in
+	return s.roles, s.err
+}
+
+func (s *stubOrganization) SetRoles(ctx context.Context, in service.SetRolesInput) ([]string, error) {
+	s.in = in
+	return s.roles, s.err
+}
+
+type stubEmployee struct {
+	service.Employee
+	employee  e.Employee
+	employees []e.Employee
+	err       error
+	in        any
+}
+
+func (s *stubEmployee) Create(ctx context.Context, in service.CreateEmployeeInput) (e.Employee, error) {
+	s.in = in
+	return s.employee, s.err
+}
+
+func (s *stubEmployee) Get(ctx context.Context, employeeId uuid.UUID, username string) (e.Employee, error) {
+	s.in = []any{employeeId, username}
+	return s.employee, s.err
+}
+
+func (s *stubEmployee) Edit(ctx context.Context, in service.EditEmployeeInput) (e.Employee, error) {
+	s.in = in
+	return s.employee, s.err
+}
+
+func (s *stubEmployee) SetActive(ctx context.Context, employeeId uuid.UUID, active bool, username string) (e.Employee, error) {
+	s.in = []any{employeeId, active, username}
+	return s.employee, s.err
+}
+
+func (s *stubEmployee) SetAdmin(ctx context.Context, employeeId uuid.UUID, admin bool, username string) (e.Employee, error) {
+	s.in = []any{employeeId, admin, username}
+	return s.employee, s.err
+}
+
+func (s *stubEmployee) Search(ctx context.Context, in service.SearchEmployeesInput) ([]e.Employee, error) {
+	s.in = in
+	return s.employees, s.err
+}
+
+// stubAuth authenticates access tokens from tokens, err fails other methods only
+type stubAuth struct {
+	service.Auth
+	tokens     map[string]e.Employee
+	token      service.Token
+	resetToken service.ResetToken
+	attempts   []e.LoginAttempt
+	err        error
+	in         any
+	// request is metadata audit entries of login would be stamped with
+	request identity.Request
+}
+
+func (s *stubAuth) Login(ctx context.Context, in service.LoginInput) (service.Token, error) {
+	s.in = in
+	s.request = identity.RequestFromContext(ctx)
+	return s.token, s.err
+}
+
+func (s *stubAuth) Authenticate(ctx context.Context, accessToken string) (e.Employee, error) {
+	employee, ok := s.tokens[accessToken]
+	if !ok {
+		return e.Employee{}, service.ErrInvalidToken
+	}
+	if !employee.IsActive {
+		return e.Employee{}, service.ErrEmployeeDeactivated
+	}
+	return employee, nil
+}
+
+func (s *stubAuth) SetPassword(ctx context.Context, in service.SetPasswordInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubAuth) ChangePassword(ctx context.Context, in service.ChangePasswordInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubAuth) CreateResetToken(ctx context.Context, employeeId uuid.UUID, username string) (service.ResetToken, error) {
+	s.in = []any{employeeId, username}
+	return s.resetToken, s.err
+}
+
+func (s *stubAuth) ResetPassword(ctx context.Context, in service.ResetPasswordInput) error {
+	s.in = in
+	return s.err
+}
+
+func (s *stubAuth) GetLoginAttempts(ctx context.Context, in service.GetLoginAttemptsInput) ([]e.LoginAttempt, error) {
+	s.in = in
+	return s.attempts, s.err
+}
+
+// stubAPIKey authenticates raw keys from keys, err fails other methods only
+type stubAPIKey struct {
+	service.APIKey
+	keys   map[string]identity.Service
+	key    e.APIKey
+	secret string
+	err    error
+	in     any
+}
+
+func (s *stubAPIKey) Create(ctx context.Context, in service.CreateAPIKeyInput) (e.APIKey, string, error) {
+	s.in = in
+	return s.key, s.secret, s.err
+}
+
+func (s *stubAPIKey) GetByOrganization(ctx context.Context, orgId uuid.UUID, username string) ([]e.APIKey, error) {
+	s.in = []any{orgId, username}
+	return []e.APIKey{s.key}, s.err
+}
+
+func (s *stubAPIKey) Revoke(ctx context.Context, orgId, keyId uuid.UUID, username string) error {
+	s.in = []any{orgId, keyId, username}
+	return s.err
+}
+
+func (s *stubAPIKey) Authenticate(ctx context.Context, rawKey string) (identity.Service, error) {
+	key, ok := s.keys[rawKey]
+	if !ok {
+		return identity.Service{}, service.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+type stubAudit struct {
+	service.Audit
+	entries []e.AuditEntry
+	err     error
+	in      any
+}
+
+func (s *stubAudit) GetOrganizationLog(ctx context.Context, in service.GetAuditLogInput) ([]e.AuditEntry, error) {
+	s.in = in
+	return s.entries, s.err
+}
+
+type stubWebhook struct {
+	service.Webhook
+	webhook  e.WebhookSubscription
+	secret   string
+	delivery e.WebhookDelivery
+	err      error
+	in       any
+}
+
+func (s *stubWebhook) CreateWebhook(ctx context.Context, in service.CreateWebhookInput) (e.WebhookSubscription, string, error) {
+	s.in = in
+	return s.webhook, s.secret, s.err
+}
+
+func (s *stubWebhook) GetWebhooks(ctx context.Context, orgId uuid.UUID, username string) ([]e.WebhookSubscription, error) {
+	s.in = []any{orgId, username}
+	return []e.WebhookSubscription{s.webhook}, s.err
+}
+
+func (s *stubWebhook) DeleteWebhook(ctx context.Context, orgId, webhookId uuid.UUID, username string) error {
+	s.in = []any{orgId, webhookId, username}
+	return s.err
+}
+
+func (s *stubWebhook) GetWebhookDeliveries(ctx context.Context, in service.GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error) {
+	s.in = in
+	return []e.WebhookDelivery{s.delivery}, s.err
+}
+
+func (s *stubWebhook) RedeliverWebhook(ctx context.Context, orgId, webhookId, deliveryId uuid.UUID, username string) (e.WebhookDelivery, error) {
+	s.in = []any{orgId, webhookId, deliveryId, username}
+	return s.delivery, s.err
+}
+
+type stubNotification struct {
+	service.Notification
+	email string
+	kinds []string
+	err   error
+	in    any
+}
+
+func (s *stubNotification) GetEmailPreferences(ctx context.Context, username string) (string, []string, error) {
+	s.in = username
+	return s.email, s.kinds, s.err
+}
+
+func (s *stubNotification) SetEmailPreferences(ctx context.Context, username string, kinds []string) (string, []string, error) {
+	s.in = []any{username, kinds}
+	return s.email, kinds, s.err
+}
+
+func (s *stubNotification) RemindDeadlines(ctx context.Context, window time.Duration) (int, error) {
+	return 0, s.err
+}
+
+type stubInbox struct {
+	service.Inbox
+	notification e.Notification
+	count        int
+	err          error
+	in           any
+}
+
+func (s *stubInbox) GetNotifications(ctx context.Context, in service.GetNotificationsInput) ([]e.Notification, error) {
+	s.in = in
+	return []e.Notification{s.notification}, s.err
+}
+
+func (s *stubInbox) GetUnreadCount(ctx context.Context, username string) (int, error) {
+	s.in = username
+	return s.count, s.err
+}
+
+func (s *stubInbox) MarkNotificationRead(ctx context.Context, notificationId uuid.UUID, username string) (e.Notification, error) {
+	s.in = []any{notificationId, username}
+	return s.notification, s.err
+}
+
+func (s *stubInbox) MarkAllNotificationsRead(ctx context.Context, username string) (int, error) {
+	s.in = username
+	return s.count, s.err
+}
+
+// stubIdempotency replays stored response if replay is set, otherwise claims key
+type stubIdempotency struct {
+	service.Idempotency
+	stored    service.IdempotentResponse
+	replay    bool
+	err       error
+	begun     []service.BeginIdempotentRequestInput
+	completed []service.CompleteIdempotentRequestInput
+	released  []string
+	// Keys held while handler ran
+	kept []string
+}
+
+func (s *stubIdempotency) BeginIdempotentRequest(ctx context.Context, in service.BeginIdempotentRequestInput) (service.IdempotentResponse, bool, error) {
+	s.begun = append(s.begun, in)
+	return s.stored, s.replay, s.err
+}
+
+func (s *stubIdempotency) CompleteIdempotentRequest(ctx context.Context, in service.CompleteIdempotentRequestInput) error {
+	s.completed = append(s.completed, in)
+	return nil
+}
+
+func (s *stubIdempotency) KeepIdempotentRequest(ctx context.Context, caller, key string) func() {
+	s.kept = append(s.kept, key)
+	return func() {}
+}
+
+func (s *stubIdempotency) ReleaseIdempotentRequest(ctx context.Context, caller, key string) error {
+	s.released = append(s.released, key)
+	return nil
+}
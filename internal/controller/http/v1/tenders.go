@@ -4,6 +4,7 @@ import (
 	"app/internal/service"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -11,18 +12,21 @@ import (
 
 type tenderRoutes struct {
 	tenderService service.Tender
+	identity      *identityResolver
 }
 
-func newTenderRoutes(s service.Tender) *tenderRoutes {
-	return &tenderRoutes{s}
+func newTenderRoutes(s service.Tender, ir *identityResolver) *tenderRoutes {
+	return &tenderRoutes{s, ir}
 }
 
 type NewTenderDTO struct {
-	Name            string    `json:"name" validate:"required,max=100"`
-	Description     string    `json:"description" validate:"required,max=500"`
-	ServiceType     string    `json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
-	OrganizationId  uuid.UUID `json:"organizationId" validate:"required"`
-	CreatorUsername string    `json:"creatorUsername" validate:"required,max=50"`
+	Name            string     `json:"name" validate:"required,max=100"`
+	Description     string     `json:"description" validate:"required,max=500"`
+	ServiceType     string     `json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
+	OrganizationId  uuid.UUID  `json:"organizationId" validate:"required"`
+	CreatorUsername string     `json:"creatorUsername" validate:"max=50"`
+	Visibility      string     `json:"visibility" validate:"omitempty,oneof=Public InviteOnly Internal"`
+	Deadline        *time.Time `json:"deadline"`
 }
 
 func (r *tenderRoutes) newTender(c echo.Context) error {
@@ -36,18 +40,29 @@ func (r *tenderRoutes) newTender(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.CreatorUsername)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Creating tender
 	tender, err := r.tenderService.CreateTender(c.Request().Context(), service.CreateTenderInput{
 		Name:            input.Name,
 		Description:     input.Description,
 		ServiceType:     input.ServiceType,
 		OrganizationId:  input.OrganizationId,
-		CreatorUsername: input.CreatorUsername,
+		CreatorUsername: username,
+		Visibility:      input.Visibility,
+		Deadline:        input.Deadline,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
+		if errors.Is(err, service.ErrTenderDeadline) {
+			return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
@@ -60,9 +75,11 @@ func (r *tenderRoutes) newTender(c echo.Context) error {
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 
 	return c.JSON(http.StatusOK, response{
@@ -70,15 +87,17 @@ func (r *tenderRoutes) newTender(c echo.Context) error {
 		Name:        tender.Name,
 		Description: tender.Description,
 		Status:      tender.Status,
+		Visibility:  tender.Visibility,
 		ServiceType: tender.Type,
 		Version:     tender.Version,
 		CreatedAt:   tender.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Deadline:    formatOptionalTime(tender.Deadline),
 	})
 }
 
 type MyTendersDTO struct {
 	LimitAndOffset
-	Username string `query:"username" validate:"required,max=50"`
+	Username string `query:"username" validate:"max=50"`
 }
 
 func (r *tenderRoutes) myTenders(c echo.Context) error {
@@ -95,14 +114,20 @@ func (r *tenderRoutes) myTenders(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Get tender list by username
 	tenders, err := r.tenderService.GetTendersByUsername(c.Request().Context(), service.GetByUsernameInput{
 		Limit:    int(input.Limit.Int32),
 		Offset:   int(input.Offset.Int32),
-		Username: input.Username,
+		Username: username,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
@@ -114,9 +139,11 @@ func (r *tenderRoutes) myTenders(c echo.Context) error {
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 	responseBatch := []response{}
 	for _, t := range tenders {
@@ -125,9 +152,11 @@ func (r *tenderRoutes) myTenders(c echo.Context) error {
 			Name:        t.Name,
 			Description: t.Description,
 			Status:      t.Status,
+			Visibility:  t.Visibility,
 			ServiceType: t.Type,
 			Version:     t.Version,
 			CreatedAt:   t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Deadline:    formatOptionalTime(t.Deadline),
 		})
 	}
 
@@ -137,6 +166,7 @@ func (r *tenderRoutes) myTenders(c echo.Context) error {
 type TendersDTO struct {
 	LimitAndOffset
 	ServiceType []string `query:"service_type" validate:"dive,oneof=Construction Delivery Manufacture"`
+	Username    string   `query:"username" validate:"max=50"`
 }
 
 func (r *tenderRoutes) tenders(c echo.Context) error {
@@ -153,13 +183,23 @@ func (r *tenderRoutes) tenders(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.optionalUsername(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Get tender list
 	tenders, err := r.tenderService.GetTenders(c.Request().Context(), service.GetTendersInput{
 		Limit:       int(input.Limit.Int32),
 		Offset:      int(input.Offset.Int32),
 		ServiceType: input.ServiceType,
+		Username:    username,
 	})
 	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -169,9 +209,11 @@ func (r *tenderRoutes) tenders(c echo.Context) error {
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 	responseBatch := []response{}
 	for _, t := range tenders {
@@ -180,9 +222,11 @@ func (r *tenderRoutes) tenders(c echo.Context) error {
 			Name:        t.Name,
 			Description: t.Description,
 			Status:      t.Status,
+			Visibility:  t.Visibility,
 			ServiceType: t.Type,
 			Version:     t.Version,
 			CreatedAt:   t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Deadline:    formatOptionalTime(t.Deadline),
 		})
 	}
 
@@ -190,9 +234,10 @@ func (r *tenderRoutes) tenders(c echo.Context) error {
 }
 
 type PutStatusDTO struct {
-	TenderId uuid.UUID `param:"tenderId" validate:"required"`
-	Status   string    `query:"status" validate:"required,oneof=Created Published Closed"`
-	Username string    `query:"username" validate:"required,max=50"`
+	TenderId        uuid.UUID `param:"tenderId" validate:"required"`
+	Status          string    `query:"status" validate:"required,oneof=Created Published Closed"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 }
 
 func (r *tenderRoutes) putStatus(c echo.Context) error {
@@ -210,34 +255,57 @@ func (r *tenderRoutes) putStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Change status
 	tender, err := r.tenderService.ChangeStatus(c.Request().Context(), service.ChangeTenderStatusInput{
-		TenderId: input.TenderId,
-		Status:   input.Status,
-		Username: input.Username,
+		TenderId:     input.TenderId,
+		Status:       input.Status,
+		Username:     username,
+		Precondition: pre,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundTender) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, tender.Version, tender.UpdatedAt)
+
 	// Create response
 	type response struct {
 		Id          uuid.UUID `json:"id"`
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 
 	return c.JSON(http.StatusOK, response{
@@ -245,15 +313,17 @@ func (r *tenderRoutes) putStatus(c echo.Context) error {
 		Name:        tender.Name,
 		Description: tender.Description,
 		Status:      tender.Status,
+		Visibility:  tender.Visibility,
 		ServiceType: tender.Type,
 		Version:     tender.Version,
 		CreatedAt:   tender.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Deadline:    formatOptionalTime(tender.Deadline),
 	})
 }
 
 type GetStatusDTO struct {
 	TenderId uuid.UUID `param:"tenderId" validate:"required"`
-	Username string    `query:"username" validate:"required,max=50"`
+	Username string    `query:"username" validate:"max=50"`
 }
 
 func (r *tenderRoutes) getStatus(c echo.Context) error {
@@ -267,10 +337,16 @@ func (r *tenderRoutes) getStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
 	// Get status
-	tender, err := r.tenderService.GetTender(c.Request().Context(), input.TenderId, input.Username)
+	tender, err := r.tenderService.GetTender(c.Request().Context(), input.TenderId, username)
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundTender) {
@@ -282,13 +358,16 @@ func (r *tenderRoutes) getStatus(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, tender.Version, tender.UpdatedAt)
+
 	// Create response
 	return c.JSON(http.StatusOK, tender.Status)
 }
 
 type EditTenderDTO struct {
-	TenderId uuid.UUID `param:"tenderId" validate:"required"`
-	Username string    `query:"username" validate:"required,max=50"`
+	TenderId        uuid.UUID `param:"tenderId" validate:"required"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 	EditTenderBody
 }
 
@@ -310,36 +389,63 @@ func (r *tenderRoutes) editTender(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Edit tender
 	tender, err := r.tenderService.Edit(c.Request().Context(), service.EditTenderInput{
-		TenderId:    input.TenderId,
-		Username:    input.Username,
-		Name:        input.Name.String,
-		Description: input.Description.String,
-		ServiceType: input.ServiceType.String,
+		TenderId:     input.TenderId,
+		Username:     username,
+		Name:         input.Name.String,
+		Description:  input.Description.String,
+		ServiceType:  input.ServiceType.String,
+		Deadline:     input.Deadline.Ptr(),
+		Precondition: pre,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
+		if errors.Is(err, service.ErrTenderDeadline) {
+			return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+		}
 		if errors.Is(err, service.ErrNotFoundTender) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, tender.Version, tender.UpdatedAt)
+
 	// Create response
 	type response struct {
 		Id          uuid.UUID `json:"id"`
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 
 	return c.JSON(http.StatusOK, response{
@@ -347,16 +453,19 @@ func (r *tenderRoutes) editTender(c echo.Context) error {
 		Name:        tender.Name,
 		Description: tender.Description,
 		Status:      tender.Status,
+		Visibility:  tender.Visibility,
 		ServiceType: tender.Type,
 		Version:     tender.Version,
 		CreatedAt:   tender.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Deadline:    formatOptionalTime(tender.Deadline),
 	})
 }
 
 type RollbackTenderDTO struct {
-	TenderId uuid.UUID `param:"tenderId" validate:"required"`
-	Version  int       `param:"version" validate:"required,gt=0"`
-	Username string    `query:"username" validate:"required,max=50"`
+	TenderId        uuid.UUID `param:"tenderId" validate:"required"`
+	Version         int       `param:"version" validate:"required,gt=0"`
+	Username        string    `query:"username" validate:"max=50"`
+	ExpectedVersion int       `query:"expectedVersion" validate:"gte=0"`
 }
 
 func (r *tenderRoutes) rollbackTender(c echo.Context) error {
@@ -374,34 +483,57 @@ func (r *tenderRoutes) rollbackTender(c echo.Context) error {
 		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// Resolve caller identity
+	username, err := r.identity.caller(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Read write precondition
+	pre, err := precondition(c, input.ExpectedVersion)
+	if err != nil {
+		return preconditionErrorResponse(c, err)
+	}
+
 	// Rollback tender
 	tender, err := r.tenderService.Rollback(c.Request().Context(), service.RollbackTenderInput{
-		TenderId: input.TenderId,
-		Version:  input.Version,
-		Username: input.Username,
+		TenderId:     input.TenderId,
+		Version:      input.Version,
+		Username:     username,
+		Precondition: pre,
 	})
 	if err != nil {
-		if errors.Is(err, service.ErrUsername) {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
 			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
 		}
 		if errors.Is(err, service.ErrNotFoundTender) {
 			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrVersionMismatch) {
+			return newErrReasonJSON(c, http.StatusPreconditionFailed, err.Error())
+		}
+		if errors.Is(err, service.ErrConcurrentUpdate) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
 		if errors.Is(err, service.ErrForbidden) {
 			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
 		}
 		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
+	setETag(c, tender.Version, tender.UpdatedAt)
+
 	// Create response
 	type response struct {
 		Id          uuid.UUID `json:"id"`
 		Name        string    `json:"name"`
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
 		ServiceType string    `json:"serviceType"`
 		Version     int       `json:"version"`
 		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
 	}
 
 	return c.JSON(http.StatusOK, response{
@@ -409,8 +541,299 @@ func (r *tenderRoutes) rollbackTender(c echo.Context) error {
 		Name:        tender.Name,
 		Description: tender.Description,
 		Status:      tender.Status,
+		Visibility:  tender.Visibility,
 		ServiceType: tender.Type,
 		Version:     tender.Version,
 		CreatedAt:   tender.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Deadline:    formatOptionalTime(tender.Deadline),
+	})
+}
+
+type PutVisibilityDTO struct {
+	TenderId   uuid.UUID `param:"tenderId" validate:"required"`
+	Visibility string    `query:"visibility" validate:"required,oneof=Public InviteOnly Internal"`
+	Username   string    `query:"username" validate:"max=50"`
+}
+
+func (r *tenderRoutes) putVisibility(c echo.Context) error {
+	// Binding and validation
+	var input PutVisibilityDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Change visibility
+	tender, err := r.tenderService.ChangeVisibility(c.Request().Context(), service.ChangeTenderVisibilityInput{
+		TenderId:   input.TenderId,
+		Visibility: input.Visibility,
+		Username:   username,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		if errors.Is(err, service.ErrNotFoundTender) {
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Create response
+	type response struct {
+		Id          uuid.UUID `json:"id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
+		ServiceType string    `json:"serviceType"`
+		Version     int       `json:"version"`
+		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Id:          tender.Id,
+		Name:        tender.Name,
+		Description: tender.Description,
+		Status:      tender.Status,
+		Visibility:  tender.Visibility,
+		ServiceType: tender.Type,
+		Version:     tender.Version,
+		CreatedAt:   tender.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Deadline:    formatOptionalTime(tender.Deadline),
+	})
+}
+
+type NewInvitationDTO struct {
+	TenderId uuid.UUID `param:"tenderId" validate:"required"`
+	Username string    `query:"username" validate:"max=50"`
+	InvitationBody
+}
+
+func (r *tenderRoutes) newInvitation(c echo.Context) error {
+	// Binding and validation
+	var input NewInvitationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := invitationBodyValidate(&input.InvitationBody); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Invite organization or employee
+	invitation, err := r.tenderService.Invite(c.Request().Context(), service.InviteToTenderInput{
+		TenderId:       input.TenderId,
+		Username:       username,
+		OrganizationId: uuid.NullUUID{UUID: input.OrganizationId, Valid: input.OrganizationId != uuid.Nil},
+		UserId:         uuid.NullUUID{UUID: input.UserId, Valid: input.UserId != uuid.Nil},
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		if errors.Is(err, service.ErrNotFoundTender) {
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+		}
+		if errors.Is(err, service.ErrInvitee) {
+			return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, service.ErrInvitationExists) {
+			return newErrReasonJSON(c, http.StatusConflict, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, newInvitationResponse(invitation))
+}
+
+type TenderInvitationsDTO struct {
+	TenderId uuid.UUID `param:"tenderId" validate:"required"`
+	Username string    `query:"username" validate:"max=50"`
+}
+
+func (r *tenderRoutes) invitations(c echo.Context) error {
+	// Binding and validation
+	var input TenderInvitationsDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get invitations
+	invitations, err := r.tenderService.GetInvitations(c.Request().Context(), input.TenderId, username)
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		if errors.Is(err, service.ErrNotFoundTender) {
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Create response
+	responseBatch := []invitationResponse{}
+	for _, inv := range invitations {
+		responseBatch = append(responseBatch, newInvitationResponse(inv))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type DeleteInvitationDTO struct {
+	TenderId     uuid.UUID `param:"tenderId" validate:"required"`
+	InvitationId uuid.UUID `param:"invitationId" validate:"required"`
+	Username     string    `query:"username" validate:"max=50"`
+}
+
+func (r *tenderRoutes) deleteInvitation(c echo.Context) error {
+	// Binding and validation
+	var input DeleteInvitationDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Revoke invitation
+	err = r.tenderService.RevokeInvitation(c.Request().Context(), service.RevokeInvitationInput{
+		TenderId:     input.TenderId,
+		InvitationId: input.InvitationId,
+		Username:     username,
 	})
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		if errors.Is(err, service.ErrNotFoundTender) || errors.Is(err, service.ErrNotFoundInvitation) {
+			return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (r *tenderRoutes) invitedTenders(c echo.Context) error {
+	// Binding and validation
+	var input MyTendersDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get tenders user is invited to
+	tenders, err := r.tenderService.GetInvitedTenders(c.Request().Context(), service.GetByUsernameInput{
+		Limit:    int(input.Limit.Int32),
+		Offset:   int(input.Offset.Int32),
+		Username: username,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUsername) || errors.Is(err, service.ErrEmployeeDeactivated) {
+			return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+		}
+		return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Create response
+	type response struct {
+		Id          uuid.UUID `json:"id"`
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Status      string    `json:"status"`
+		Visibility  string    `json:"visibility"`
+		ServiceType string    `json:"serviceType"`
+		Version     int       `json:"version"`
+		CreatedAt   string    `json:"createdAt"`
+		Deadline    *string   `json:"deadline,omitempty"`
+	}
+	responseBatch := []response{}
+	for _, t := range tenders {
+		responseBatch = append(responseBatch, response{
+			Id:          t.Id,
+			Name:        t.Name,
+			Description: t.Description,
+			Status:      t.Status,
+			Visibility:  t.Visibility,
+			ServiceType: t.Type,
+			Version:     t.Version,
+			CreatedAt:   t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Deadline:    formatOptionalTime(t.Deadline),
+		})
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
 }
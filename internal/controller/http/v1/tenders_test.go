@@ -0,0 +1,502 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testInvitationId = uuid.MustParse("00000000-0000-0000-0000-0000000000e1")
+	testDeadline     = testTime.Add(72 * time.Hour)
+)
+
+func testTender() e.Tender {
+	return e.Tender{
+		Id:              testTenderId,
+		Name:            "Build",
+		Description:     "Bridge",
+		Type:            "Construction",
+		Status:          "Published",
+		Visibility:      "Public",
+		OrganizationId:  testOrgId,
+		Version:         1,
+		CreatorUsername: "alice",
+		CreatedAt:       testTime,
+		UpdatedAt:       testTime,
+	}
+}
+
+const testTenderJSON = `{"id":"00000000-0000-0000-0000-0000000000f1","name":"Build","description":"Bridge",
+	"status":"Published","visibility":"Public","serviceType":"Construction","version":1,
+	"createdAt":"2024-01-02T03:04:05Z"}`
+
+func withTender(s *stubs) { s.tender.tender = testTender() }
+
+func failTender(s *stubs, err error) { s.tender.err = err }
+
+func TestNewTender(t *testing.T) {
+	const target = "/api/tenders/new"
+	body := `{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"` + testOrgId.String() + `"}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body, setup: withTender,
+			code: http.StatusOK, want: testTenderJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.CreateTenderInput{
+					Name:            "Build",
+					Description:     "Bridge",
+					ServiceType:     "Construction",
+					OrganizationId:  testOrgId,
+					CreatorUsername: "alice",
+				})
+			},
+		},
+		{
+			name: "with deadline and visibility", method: http.MethodPost, target: target,
+			body: `{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"` + testOrgId.String() + `",
+				"visibility":"InviteOnly","deadline":"2024-01-05T03:04:05Z"}`,
+			setup: func(s *stubs) {
+				s.tender.tender = testTender()
+				s.tender.tender.Visibility = "InviteOnly"
+				s.tender.tender.Deadline = &testDeadline
+			},
+			code: http.StatusOK,
+			want: `{"id":"00000000-0000-0000-0000-0000000000f1","name":"Build","description":"Bridge",
+				"status":"Published","visibility":"InviteOnly","serviceType":"Construction","version":1,
+				"createdAt":"2024-01-02T03:04:05Z","deadline":"2024-01-05T03:04:05Z"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				in := s.tender.in.(service.CreateTenderInput)
+				if in.Visibility != "InviteOnly" || in.Deadline == nil || !in.Deadline.Equal(testDeadline) {
+					t.Errorf("input %+v", in)
+				}
+			},
+		},
+		{
+			name: "by api key", method: http.MethodPost, target: target, body: body, header: asAPIKey, setup: withTender,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if in := s.tender.in.(service.CreateTenderInput); in.CreatorUsername != "api-key:tk_ci" {
+					t.Errorf("creator %s", in.CreatorUsername)
+				}
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodPost, target: target, body: body, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "legacy creator", method: http.MethodPost, target: target,
+			body: `{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"` + testOrgId.String() + `",
+				"creatorUsername":"alice"}`,
+			code: http.StatusBadRequest, want: reason(ErrLegacyIdentity.Error()),
+		},
+		validationCase("no name", http.MethodPost, target,
+			`{"description":"Bridge","serviceType":"Construction","organizationId":"`+testOrgId.String()+`"}`),
+		validationCase("long name", http.MethodPost, target,
+			`{"name":"`+longString(101)+`","description":"Bridge","serviceType":"Construction","organizationId":"`+testOrgId.String()+`"}`),
+		validationCase("no description", http.MethodPost, target,
+			`{"name":"Build","serviceType":"Construction","organizationId":"`+testOrgId.String()+`"}`),
+		validationCase("long description", http.MethodPost, target,
+			`{"name":"Build","description":"`+longString(501)+`","serviceType":"Construction","organizationId":"`+testOrgId.String()+`"}`),
+		validationCase("unknown service type", http.MethodPost, target,
+			`{"name":"Build","description":"Bridge","serviceType":"Repair","organizationId":"`+testOrgId.String()+`"}`),
+		validationCase("no organization", http.MethodPost, target,
+			`{"name":"Build","description":"Bridge","serviceType":"Construction"}`),
+		validationCase("unknown visibility", http.MethodPost, target,
+			`{"name":"Build","description":"Bridge","serviceType":"Construction","organizationId":"`+testOrgId.String()+`","visibility":"Secret"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPost, target, body, failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrTenderDeadline:      http.StatusBadRequest,
+		service.ErrForbidden:           http.StatusForbidden,
+		service.ErrNotFoundTender:      http.StatusInternalServerError,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestMyTenders(t *testing.T) {
+	testTenderList(t, "/api/tenders/my")
+}
+
+func TestInvitedTenders(t *testing.T) {
+	testTenderList(t, "/api/tenders/invited")
+}
+
+// testTenderList checks listing of tenders employee is related to
+func testTenderList(t *testing.T, target string) {
+	t.Helper()
+	withTenders := func(s *stubs) { s.tender.tenders = []e.Tender{testTender()} }
+
+	cases := []routeCase{
+		{
+			name: "default page", method: http.MethodGet, target: target, setup: withTenders,
+			code: http.StatusOK, want: "[" + testTenderJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.GetByUsernameInput{Limit: DefaultLimit, Offset: DefaultOffset, Username: "alice"})
+			},
+		},
+		{
+			name: "page", method: http.MethodGet, target: target + "?limit=0&offset=10",
+			code: http.StatusOK, want: `[]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.GetByUsernameInput{Limit: 0, Offset: 10, Username: "alice"})
+			},
+		},
+		invalidCase("negative limit", http.MethodGet, target+"?limit=-1", ""),
+		invalidCase("negative offset", http.MethodGet, target+"?offset=-1", ""),
+		validationCase("long username", http.MethodGet, target+"?username="+longString(51), ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrForbidden:           http.StatusInternalServerError,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestTenders(t *testing.T) {
+	const target = "/api/tenders"
+	withTenders := func(s *stubs) { s.tender.tenders = []e.Tender{testTender()} }
+
+	cases := []routeCase{
+		{
+			name: "anonymous", method: http.MethodGet, target: target, header: asAnonymous, setup: withTenders,
+			code: http.StatusOK, want: "[" + testTenderJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.GetTendersInput{Limit: DefaultLimit, Offset: DefaultOffset})
+			},
+		},
+		{
+			name: "api key sees what anonymous sees", method: http.MethodGet, target: target, header: asAPIKey,
+			code: http.StatusOK, want: `[]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.GetTendersInput{Limit: DefaultLimit, Offset: DefaultOffset})
+			},
+		},
+		{
+			name: "filtered", method: http.MethodGet, target: target + "?service_type=Delivery&service_type=Manufacture&limit=2",
+			code: http.StatusOK, want: `[]`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.GetTendersInput{
+					Limit:       2,
+					ServiceType: []string{"Delivery", "Manufacture"},
+					Username:    "alice",
+				})
+			},
+		},
+		{
+			name: "legacy username without token", method: http.MethodGet, target: target + "?username=alice",
+			header: asAnonymous, opts: []Option{LegacyIdentity(true)},
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		{
+			name: "legacy username", method: http.MethodGet, target: target + "?username=alice",
+			code: http.StatusBadRequest, want: reason(ErrLegacyIdentity.Error()),
+		},
+		validationCase("unknown service type", http.MethodGet, target+"?service_type=Repair", ""),
+		invalidCase("negative limit", http.MethodGet, target+"?limit=-5", ""),
+	}
+	cases = append(cases, errorCases(http.MethodGet, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestGetTenderStatus(t *testing.T) {
+	target := testTenderPath + "/status"
+
+	cases := []routeCase{
+		{
+			name: "status", method: http.MethodGet, target: target, setup: withTender,
+			code: http.StatusOK, want: `"Published"`,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, []any{testTenderId, "alice"})
+				if got, want := rec.Header().Get("ETag"), formatETag(1, testTime); got != want {
+					t.Errorf("ETag %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodGet, target: target, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+	}
+	cases = append(cases, errorCases(http.MethodGet, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestPutTenderStatus(t *testing.T) {
+	target := testTenderPath + "/status?status=Closed"
+
+	cases := []routeCase{
+		{
+			name: "changed", method: http.MethodPut, target: target, setup: withTender,
+			code: http.StatusOK, want: testTenderJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.ChangeTenderStatusInput{
+					TenderId: testTenderId,
+					Status:   "Closed",
+					Username: "alice",
+				})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		{
+			name: "by api key", method: http.MethodPut, target: target, header: asAPIKey, setup: withTender,
+			code: http.StatusOK,
+		},
+		{
+			name: "anonymous", method: http.MethodPut, target: target, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		validationCase("no status", http.MethodPut, testTenderPath+"/status", ""),
+		validationCase("unknown status", http.MethodPut, testTenderPath+"/status?status=Canceled", ""),
+	}
+	cases = append(cases, errorCases(http.MethodPut, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestEditTender(t *testing.T) {
+	target := testTenderPath + "/edit"
+
+	cases := []routeCase{
+		{
+			name: "edited", method: http.MethodPatch, target: target + "?expectedVersion=1",
+			body: `{"name":"Rebuild","serviceType":"Delivery"}`, setup: withTender,
+			code: http.StatusOK, want: testTenderJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.EditTenderInput{
+					TenderId:     testTenderId,
+					Username:     "alice",
+					Name:         "Rebuild",
+					ServiceType:  "Delivery",
+					Precondition: service.Precondition{Version: 1},
+				})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		{
+			name: "deadline only", method: http.MethodPatch, target: target,
+			body: `{"deadline":"2024-01-05T03:04:05Z"}`, setup: withTender,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if in := s.tender.in.(service.EditTenderInput); in.Deadline == nil || !in.Deadline.Equal(testDeadline) {
+					t.Errorf("deadline %v", in.Deadline)
+				}
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodPatch, target: target, body: `{"name":"Rebuild"}`, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		invalidCase("nothing to edit", http.MethodPatch, target, `{}`),
+		invalidCase("empty name", http.MethodPatch, target, `{"name":""}`),
+		invalidCase("long name", http.MethodPatch, target, `{"name":"`+longString(101)+`"}`),
+		invalidCase("long description", http.MethodPatch, target, `{"description":"`+longString(501)+`"}`),
+		invalidCase("unknown service type", http.MethodPatch, target, `{"name":"Rebuild","serviceType":"Repair"}`),
+		validationCase("negative expected version", http.MethodPatch, target+"?expectedVersion=-1", `{"name":"Rebuild"}`),
+	}
+	cases = append(cases, errorCases(http.MethodPatch, target, `{"name":"Rebuild"}`, failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrTenderDeadline:      http.StatusBadRequest,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestRollbackTender(t *testing.T) {
+	target := testTenderPath + "/rollback/2"
+
+	cases := []routeCase{
+		{
+			name: "rolled back", method: http.MethodPut, target: target, setup: withTender,
+			code: http.StatusOK, want: testTenderJSON,
+			check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.RollbackTenderInput{TenderId: testTenderId, Version: 2, Username: "alice"})
+				if rec.Header().Get("ETag") == "" {
+					t.Errorf("no ETag")
+				}
+			},
+		},
+		{
+			name: "anonymous", method: http.MethodPut, target: target, header: asAnonymous,
+			code: http.StatusUnauthorized, want: reason(ErrUnauthenticated.Error()),
+		},
+		validationCase("version zero", http.MethodPut, testTenderPath+"/rollback/0", ""),
+		validationCase("negative version", http.MethodPut, testTenderPath+"/rollback/-1", ""),
+	}
+	cases = append(cases, errorCases(http.MethodPut, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrVersionMismatch:     http.StatusPreconditionFailed,
+		service.ErrConcurrentUpdate:    http.StatusConflict,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestPutTenderVisibility(t *testing.T) {
+	target := testTenderPath + "/visibility?visibility=Internal"
+
+	cases := []routeCase{
+		{
+			name: "changed", method: http.MethodPut, target: target, setup: withTender,
+			code: http.StatusOK, want: testTenderJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.tender.in, service.ChangeTenderVisibilityInput{
+					TenderId:   testTenderId,
+					Visibility: "Internal",
+					Username:   "alice",
+				})
+			},
+		},
+		validationCase("no visibility", http.MethodPut, testTenderPath+"/visibility", ""),
+		validationCase("unknown visibility", http.MethodPut, testTenderPath+"/visibility?visibility=Secret", ""),
+	}
+	cases = append(cases, identityCases(http.MethodPut, target, "")...)
+	cases = append(cases, errorCases(http.MethodPut, target, "", failTender, map[error]int{
+		service.ErrUsername:            http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+		service.ErrNotFoundTender:      http.StatusNotFound,
+		service.ErrForbidden:           http.StatusForbidden,
+	})...)
+	runRouteCases(t, cases)
+}
+
+func TestTenderInvitations(t *testing.T) {
+	target := testTenderPath + "/invitations"
+	body := `{"organizationId":"` + testOrgId.String() + `"}`
+	orgInvitation := e.TenderInvitation{
+		Id:             testInvitationId,
+		TenderId:       testTenderId,
+		OrganizationId: uuid.NullUUID{UUID: testOrgId, Valid: true},
+		CreatedAt:      testTime,
+	}
+	userInvitation := e.TenderInvitation{
+		Id:        testInvitationId,
+		TenderId:  testTenderId,
+		UserId:    uuid.NullUUID{UUID: alice.Id, Valid: true},
+		CreatedAt: testTime,
+	}
+	const orgInvitationJSON = `{"id":"00000000-0000-0000-0000-0000000000e1","tenderId":"00000000-0000-0000-0000-0000000000f1",
+		"organizationId":"00000000-0000-0000-0000-00000000000a","createdAt":"2024-01-02T03:04:05Z"}`
+	const userInvitationJSON = `{"id":"00000000-0000-0000-0000-0000000000e1","tenderId":"00000000-0000-0000-0000-0000000000f1",
+		"userId":"00000000-0000-0000-0000-000000000001","createdAt":"2024-01-02T03:04:05Z"}`
+
+	t.Run("invite", func(t *testing.T) {
+		cases := []routeCase{
+			{
+				name: "organization", method: http.MethodPost, target: target, body: body,
+				setup: func(s *stubs) { s.tender.invitation = orgInvitation },
+				code:  http.StatusOK, want: orgInvitationJSON,
+				check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+					wantInput(t, s.tender.in, service.InviteToTenderInput{
+						TenderId:       testTenderId,
+						Username:       "alice",
+						OrganizationId: uuid.NullUUID{UUID: testOrgId, Valid: true},
+					})
+				},
+			},
+			{
+				name: "user", method: http.MethodPost, target: target, body: `{"userId":"` + alice.Id.String() + `"}`,
+				setup: func(s *stubs) { s.tender.invitation = userInvitation },
+				code:  http.StatusOK, want: userInvitationJSON,
+			},
+			invalidCase("no invitee", http.MethodPost, target, `{}`),
+			invalidCase("both invitees", http.MethodPost, target,
+				`{"organizationId":"`+testOrgId.String()+`","userId":"`+alice.Id.String()+`"}`),
+		}
+		cases = append(cases, identityCases(http.MethodPost, target, body)...)
+		cases = append(cases, errorCases(http.MethodPost, target, body, failTender, map[error]int{
+			service.ErrUsername:            http.StatusUnauthorized,
+			service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+			service.ErrNotFoundTender:      http.StatusNotFound,
+			service.ErrForbidden:           http.StatusForbidden,
+			service.ErrInvitee:             http.StatusBadRequest,
+			service.ErrInvitationExists:    http.StatusConflict,
+		})...)
+		runRouteCases(t, cases)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		cases := []routeCase{
+			{
+				name: "invitations", method: http.MethodGet, target: target,
+				setup: func(s *stubs) { s.tender.invitations = []e.TenderInvitation{orgInvitation, userInvitation} },
+				code:  http.StatusOK, want: "[" + orgInvitationJSON + "," + userInvitationJSON + "]",
+				check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+					wantInput(t, s.tender.in, []any{testTenderId, "alice"})
+				},
+			},
+			{name: "empty", method: http.MethodGet, target: target, code: http.StatusOK, want: `[]`},
+		}
+		cases = append(cases, identityCases(http.MethodGet, target, "")...)
+		cases = append(cases, errorCases(http.MethodGet, target, "", failTender, map[error]int{
+			service.ErrUsername:            http.StatusUnauthorized,
+			service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+			service.ErrNotFoundTender:      http.StatusNotFound,
+			service.ErrForbidden:           http.StatusForbidden,
+		})...)
+		runRouteCases(t, cases)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		target := target + "/" + testInvitationId.String()
+		cases := []routeCase{
+			{
+				name: "revoked", method: http.MethodDelete, target: target,
+				code: http.StatusNoContent,
+				check: func(t *testing.T, s *stubs, rec *httptest.ResponseRecorder) {
+					wantInput(t, s.tender.in, service.RevokeInvitationInput{
+						TenderId:     testTenderId,
+						InvitationId: testInvitationId,
+						Username:     "alice",
+					})
+					if rec.Body.Len() != 0 {
+						t.Errorf("body %s", rec.Body)
+					}
+				},
+			},
+		}
+		cases = append(cases, identityCases(http.MethodDelete, target, "")...)
+		cases = append(cases, errorCases(http.MethodDelete, target, "", failTender, map[error]int{
+			service.ErrUsername:            http.StatusUnauthorized,
+			service.ErrEmployeeDeactivated: http.StatusUnauthorized,
+			service.ErrNotFoundTender:      http.StatusNotFound,
+			service.ErrNotFoundInvitation:  http.StatusNotFound,
+			service.ErrForbidden:           http.StatusForbidden,
+		})...)
+		runRouteCases(t, cases)
+	})
+}
@@ -1,9 +1,12 @@
 package httpapi
 
 import (
+	e "app/internal/entity"
 	"errors"
 	"net/http"
+	"net/mail"
 
+	"github.com/google/uuid"
 	"github.com/guregu/null/v5"
 	"github.com/labstack/echo/v4"
 )
@@ -39,11 +42,15 @@ type EditTenderBody struct {
 	Name        null.String `json:"name"`
 	Description null.String `json:"description"`
 	ServiceType null.String `json:"serviceType"`
+	Deadline    null.Time   `json:"deadline"`
 }
 
 func editTenderBodyValidate(input *EditTenderBody) error {
 	if !input.Name.Valid && !input.Description.Valid && !input.ServiceType.Valid {
-		return ErrInvalidParameters
+		if !input.Deadline.Valid {
+			return ErrInvalidParameters
+		}
+		return nil
 	}
 	if input.Name.Valid && !input.Description.Valid && !input.ServiceType.Valid {
 		if len(input.Name.String) > 100 || len(input.Name.String) <= 0 {
@@ -138,6 +145,95 @@ func editBidBodyValidate(input *EditBidBody) error {
 	return ErrInvalidParameters
 }
 
+type EditOrganizationBody struct {
+	Name        null.String `json:"name"`
+	Description null.String `json:"description"`
+	Type        null.String `json:"type"`
+}
+
+func editOrganizationBodyValidate(input *EditOrganizationBody) error {
+	if !input.Name.Valid && !input.Description.Valid && !input.Type.Valid {
+		return ErrInvalidParameters
+	}
+	if input.Name.Valid && (len(input.Name.String) > 100 || len(input.Name.String) <= 0) {
+		return ErrInvalidParameters
+	}
+	if input.Description.Valid && (len(input.Description.String) > 500 || len(input.Description.String) <= 0) {
+		return ErrInvalidParameters
+	}
+	if input.Type.Valid && input.Type.String != "IE" && input.Type.String != "LLC" && input.Type.String != "JSC" {
+		return ErrInvalidParameters
+	}
+	return nil
+}
+
+type EditEmployeeBody struct {
+	FirstName null.String `json:"firstName"`
+	LastName  null.String `json:"lastName"`
+	Email     null.String `json:"email"`
+}
+
+func editEmployeeBodyValidate(input *EditEmployeeBody) error {
+	if !input.FirstName.Valid && !input.LastName.Valid && !input.Email.Valid {
+		return ErrInvalidParameters
+	}
+	if input.Email.Valid && !validEmail(input.Email.String) {
+		return ErrInvalidParameters
+	}
+	if input.FirstName.Valid && (len(input.FirstName.String) > 50 || len(input.FirstName.String) <= 0) {
+		return ErrInvalidParameters
+	}
+	if input.LastName.Valid && (len(input.LastName.String) > 50 || len(input.LastName.String) <= 0) {
+		return ErrInvalidParameters
+	}
+	return nil
+}
+
+// validEmail accepts bare address only, without display name
+func validEmail(s string) bool {
+	if len(s) > 254 {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+type InvitationBody struct {
+	OrganizationId uuid.UUID `json:"organizationId"`
+	UserId         uuid.UUID `json:"userId"`
+}
+
+// Exactly one invitee must be specified
+func invitationBodyValidate(input *InvitationBody) error {
+	if (input.OrganizationId == uuid.Nil) == (input.UserId == uuid.Nil) {
+		return ErrInvalidParameters
+	}
+	return nil
+}
+
+type invitationResponse struct {
+	Id             uuid.UUID  `json:"id"`
+	TenderId       uuid.UUID  `json:"tenderId"`
+	OrganizationId *uuid.UUID `json:"organizationId,omitempty"`
+	UserId         *uuid.UUID `json:"userId,omitempty"`
+	CreatedAt      string     `json:"createdAt"`
+}
+
+func newInvitationResponse(inv e.TenderInvitation) invitationResponse {
+	resp := invitationResponse{
+		Id:        inv.Id,
+		TenderId:  inv.TenderId,
+		CreatedAt: inv.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if inv.OrganizationId.Valid {
+		resp.OrganizationId = &inv.OrganizationId.UUID
+	}
+	if inv.UserId.Valid {
+		resp.UserId = &inv.UserId.UUID
+	}
+	return resp
+}
+
 func handleBindingError(c echo.Context, err error) error {
 	var httpErr *echo.HTTPError
 	if ok := errors.As(err, &httpErr); ok {
@@ -0,0 +1,291 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type webhookRoutes struct {
+	webhookService service.Webhook
+	identity       *identityResolver
+}
+
+func newWebhookRoutes(s service.Webhook, ir *identityResolver) *webhookRoutes {
+	return &webhookRoutes{s, ir}
+}
+
+type webhookResponse struct {
+	Id             uuid.UUID `json:"id"`
+	OrganizationId uuid.UUID `json:"organizationId"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"eventTypes"`
+	CreatedAt      string    `json:"createdAt"`
+}
+
+func newWebhookResponse(sub e.WebhookSubscription) webhookResponse {
+	return webhookResponse{
+		Id:             sub.Id,
+		OrganizationId: sub.OrganizationId,
+		URL:            sub.URL,
+		EventTypes:     sub.EventTypes,
+		CreatedAt:      sub.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+type webhookDeliveryResponse struct {
+	Id             uuid.UUID `json:"id"`
+	WebhookId      uuid.UUID `json:"webhookId"`
+	EventId        uuid.UUID `json:"eventId"`
+	EventType      string    `json:"eventType"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  *string   `json:"nextAttemptAt"`
+	LastStatusCode *int      `json:"lastStatusCode"`
+	LastError      string    `json:"lastError"`
+	DeliveredAt    *string   `json:"deliveredAt"`
+	CreatedAt      string    `json:"createdAt"`
+}
+
+func newWebhookDeliveryResponse(d e.WebhookDelivery) webhookDeliveryResponse {
+	resp := webhookDeliveryResponse{
+		Id:             d.Id,
+		WebhookId:      d.SubscriptionId,
+		EventId:        d.EventId,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    formatOptionalTime(d.DeliveredAt),
+		CreatedAt:      d.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if d.Status == "Pending" {
+		resp.NextAttemptAt = formatOptionalTime(&d.NextAttemptAt)
+	}
+	return resp
+}
+
+type NewWebhookDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	URL            string    `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes     []string  `json:"eventTypes" validate:"required,min=1,dive,oneof=tender.published tender.status_changed tender.version_created tender.deadline_approaching bid.created bid.decision_submitted"`
+	Secret         string    `json:"secret" validate:"omitempty,min=16,max=128"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *webhookRoutes) newWebhook(c echo.Context) error {
+	// Binding and validation
+	var input NewWebhookDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Create webhook
+	sub, secret, err := r.webhookService.CreateWebhook(c.Request().Context(), service.CreateWebhookInput{
+		OrganizationId: input.OrganizationId,
+		Username:       username,
+		URL:            input.URL,
+		EventTypes:     input.EventTypes,
+		Secret:         input.Secret,
+	})
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	// Create response, secret is shown only once
+	type response struct {
+		webhookResponse
+		Secret string `json:"secret"`
+	}
+
+	return c.JSON(http.StatusOK, response{
+		webhookResponse: newWebhookResponse(sub),
+		Secret:          secret,
+	})
+}
+
+type WebhooksDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *webhookRoutes) webhooks(c echo.Context) error {
+	// Binding and validation
+	var input WebhooksDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get webhooks
+	subs, err := r.webhookService.GetWebhooks(c.Request().Context(), input.OrganizationId, username)
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	// Create response
+	responseBatch := []webhookResponse{}
+	for _, sub := range subs {
+		responseBatch = append(responseBatch, newWebhookResponse(sub))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type DeleteWebhookDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	WebhookId      uuid.UUID `param:"webhookId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *webhookRoutes) deleteWebhook(c echo.Context) error {
+	// Binding and validation
+	var input DeleteWebhookDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Delete webhook
+	if err := r.webhookService.DeleteWebhook(c.Request().Context(), input.OrganizationId, input.WebhookId, username); err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type WebhookDeliveriesDTO struct {
+	LimitAndOffset
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	WebhookId      uuid.UUID `param:"webhookId" validate:"required"`
+	Status         string    `query:"status" validate:"omitempty,oneof=Pending Delivered DeadLetter"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *webhookRoutes) deliveries(c echo.Context) error {
+	// Binding and validation
+	var input WebhookDeliveriesDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := limitAndOffsetValidate(&input.LimitAndOffset); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Get deliveries
+	deliveries, err := r.webhookService.GetWebhookDeliveries(c.Request().Context(), service.GetWebhookDeliveriesInput{
+		Limit:          int(input.Limit.Int32),
+		Offset:         int(input.Offset.Int32),
+		OrganizationId: input.OrganizationId,
+		WebhookId:      input.WebhookId,
+		Username:       username,
+		Status:         input.Status,
+	})
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	// Create response
+	responseBatch := []webhookDeliveryResponse{}
+	for _, d := range deliveries {
+		responseBatch = append(responseBatch, newWebhookDeliveryResponse(d))
+	}
+
+	return c.JSON(http.StatusOK, responseBatch)
+}
+
+type RedeliverWebhookDTO struct {
+	OrganizationId uuid.UUID `param:"organizationId" validate:"required"`
+	WebhookId      uuid.UUID `param:"webhookId" validate:"required"`
+	DeliveryId     uuid.UUID `param:"deliveryId" validate:"required"`
+	Username       string    `query:"username" validate:"max=50"`
+}
+
+func (r *webhookRoutes) redeliver(c echo.Context) error {
+	// Binding and validation
+	var input RedeliverWebhookDTO
+	if err := c.Bind(&input); err != nil {
+		return handleBindingError(c, err)
+	}
+	queryBinder := &echo.DefaultBinder{}
+	if err := queryBinder.BindQueryParams(c, &input); err != nil {
+		return handleBindingError(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return newErrReasonJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Resolve caller identity
+	username, err := r.identity.username(c, input.Username)
+	if err != nil {
+		return identityErrorResponse(c, err)
+	}
+
+	// Queue delivery again
+	delivery, err := r.webhookService.RedeliverWebhook(c.Request().Context(), input.OrganizationId, input.WebhookId, input.DeliveryId, username)
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newWebhookDeliveryResponse(delivery))
+}
+
+func webhookErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUsername), errors.Is(err, service.ErrEmployeeDeactivated):
+		return newErrReasonJSON(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return newErrReasonJSON(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFoundOrganization), errors.Is(err, service.ErrNotFoundWebhook), errors.Is(err, service.ErrNotFoundDelivery):
+		return newErrReasonJSON(c, http.StatusNotFound, err.Error())
+	}
+	return newErrReasonJSON(c, http.StatusInternalServerError, err.Error())
+}
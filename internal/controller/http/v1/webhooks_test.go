@@ -0,0 +1,208 @@
+package httpapi
+
+import (
+	e "app/internal/entity"
+	"app/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testWebhookId  = uuid.MustParse("00000000-0000-0000-0000-000000000011")
+	testDeliveryId = uuid.MustParse("00000000-0000-0000-0000-000000000012")
+)
+
+const testWebhookPath = testOrgPath + "/webhooks/00000000-0000-0000-0000-000000000011"
+
+func testWebhook() e.WebhookSubscription {
+	return e.WebhookSubscription{
+		Id:             testWebhookId,
+		OrganizationId: testOrgId,
+		URL:            "https://example.com/hook",
+		EventTypes:     []string{"tender.published", "bid.created"},
+		Secret:         "not exposed in listings",
+		CreatedAt:      testTime,
+	}
+}
+
+const testWebhookJSON = `{"id":"00000000-0000-0000-0000-000000000011","organizationId":"00000000-0000-0000-0000-00000000000a",
+	"url":"https://example.com/hook","eventTypes":["tender.published","bid.created"],"createdAt":"2024-01-02T03:04:05Z"`
+
+func testDelivery() e.WebhookDelivery {
+	code := 503
+	return e.WebhookDelivery{
+		Id:             testDeliveryId,
+		SubscriptionId: testWebhookId,
+		EventId:        testTenderId,
+		EventType:      "tender.published",
+		Payload:        []byte(`{}`),
+		Status:         "Pending",
+		Attempts:       2,
+		NextAttemptAt:  testTime.Add(time.Minute),
+		LastStatusCode: &code,
+		LastError:      "service unavailable",
+		CreatedAt:      testTime,
+		UpdatedAt:      testTime,
+	}
+}
+
+const testDeliveryJSON = `{"id":"00000000-0000-0000-0000-000000000012","webhookId":"00000000-0000-0000-0000-000000000011",
+	"eventId":"00000000-0000-0000-0000-0000000000f1","eventType":"tender.published","status":"Pending","attempts":2,
+	"nextAttemptAt":"2024-01-02T03:05:05Z","lastStatusCode":503,"lastError":"service unavailable",
+	"deliveredAt":null,"createdAt":"2024-01-02T03:04:05Z"}`
+
+func failWebhook(s *stubs, err error) { s.webhook.err = err }
+
+func webhookErrors() map[error]int {
+	return map[error]int{
+		service.ErrUsername:             http.StatusUnauthorized,
+		service.ErrEmployeeDeactivated:  http.StatusUnauthorized,
+		service.ErrForbidden:            http.StatusForbidden,
+		service.ErrNotFoundOrganization: http.StatusNotFound,
+		service.ErrNotFoundWebhook:      http.StatusNotFound,
+		service.ErrNotFoundDelivery:     http.StatusNotFound,
+	}
+}
+
+func TestNewWebhook(t *testing.T) {
+	target := testOrgPath + "/webhooks"
+	body := `{"url":"https://example.com/hook","eventTypes":["tender.published","bid.created"]}`
+
+	cases := []routeCase{
+		{
+			name: "created", method: http.MethodPost, target: target, body: body,
+			setup: func(s *stubs) {
+				s.webhook.webhook = testWebhook()
+				s.webhook.secret = "generated-secret-value"
+			},
+			code: http.StatusOK, want: testWebhookJSON + `,"secret":"generated-secret-value"}`,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.webhook.in, service.CreateWebhookInput{
+					OrganizationId: testOrgId,
+					Username:       "alice",
+					URL:            "https://example.com/hook",
+					EventTypes:     []string{"tender.published", "bid.created"},
+				})
+			},
+		},
+		{
+			name: "own secret", method: http.MethodPost, target: target,
+			body: `{"url":"https://example.com/hook","eventTypes":["bid.created"],"secret":"0123456789abcdef"}`,
+			code: http.StatusOK,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				if in := s.webhook.in.(service.CreateWebhookInput); in.Secret != "0123456789abcdef" {
+					t.Errorf("secret %s", in.Secret)
+				}
+			},
+		},
+		validationCase("no url", http.MethodPost, target, `{"eventTypes":["bid.created"]}`),
+		validationCase("not http url", http.MethodPost, target, `{"url":"ftp://example.com","eventTypes":["bid.created"]}`),
+		validationCase("no event types", http.MethodPost, target, `{"url":"https://example.com/hook","eventTypes":[]}`),
+		validationCase("unknown event type", http.MethodPost, target, `{"url":"https://example.com/hook","eventTypes":["bid.deleted"]}`),
+		validationCase("short secret", http.MethodPost, target,
+			`{"url":"https://example.com/hook","eventTypes":["bid.created"],"secret":"short"}`),
+		validationCase("long secret", http.MethodPost, target,
+			`{"url":"https://example.com/hook","eventTypes":["bid.created"],"secret":"`+longString(129)+`"}`),
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, body)...)
+	cases = append(cases, errorCases(http.MethodPost, target, body, failWebhook, webhookErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestWebhooks(t *testing.T) {
+	target := testOrgPath + "/webhooks"
+
+	cases := []routeCase{
+		{
+			name: "webhooks", method: http.MethodGet, target: target,
+			setup: func(s *stubs) { s.webhook.webhook = testWebhook() },
+			code:  http.StatusOK, want: "[" + testWebhookJSON + "}]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.webhook.in, []any{testOrgId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failWebhook, webhookErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	cases := []routeCase{
+		{
+			name: "deleted", method: http.MethodDelete, target: testWebhookPath,
+			code: http.StatusNoContent,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.webhook.in, []any{testOrgId, testWebhookId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodDelete, testWebhookPath, "")...)
+	cases = append(cases, errorCases(http.MethodDelete, testWebhookPath, "", failWebhook, webhookErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	target := testWebhookPath + "/deliveries"
+	delivered := testTime.Add(time.Hour)
+
+	cases := []routeCase{
+		{
+			name: "pending", method: http.MethodGet, target: target + "?status=Pending&offset=5",
+			setup: func(s *stubs) { s.webhook.delivery = testDelivery() },
+			code:  http.StatusOK, want: "[" + testDeliveryJSON + "]",
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.webhook.in, service.GetWebhookDeliveriesInput{
+					Limit:          DefaultLimit,
+					Offset:         5,
+					OrganizationId: testOrgId,
+					WebhookId:      testWebhookId,
+					Username:       "alice",
+					Status:         "Pending",
+				})
+			},
+		},
+		{
+			name: "delivered", method: http.MethodGet, target: target,
+			setup: func(s *stubs) {
+				s.webhook.delivery = testDelivery()
+				s.webhook.delivery.Status = "Delivered"
+				s.webhook.delivery.LastStatusCode = nil
+				s.webhook.delivery.LastError = ""
+				s.webhook.delivery.DeliveredAt = &delivered
+			},
+			code: http.StatusOK,
+			want: `[{"id":"00000000-0000-0000-0000-000000000012","webhookId":"00000000-0000-0000-0000-000000000011",
+				"eventId":"00000000-0000-0000-0000-0000000000f1","eventType":"tender.published","status":"Delivered",
+				"attempts":2,"nextAttemptAt":null,"lastStatusCode":null,"lastError":"",
+				"deliveredAt":"2024-01-02T04:04:05Z","createdAt":"2024-01-02T03:04:05Z"}]`,
+		},
+		validationCase("unknown status", http.MethodGet, target+"?status=Failed", ""),
+		invalidCase("negative limit", http.MethodGet, target+"?limit=-1", ""),
+	}
+	cases = append(cases, identityCases(http.MethodGet, target, "")...)
+	cases = append(cases, errorCases(http.MethodGet, target, "", failWebhook, webhookErrors())...)
+	runRouteCases(t, cases)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	target := testWebhookPath + "/deliveries/" + testDeliveryId.String() + "/redeliver"
+
+	cases := []routeCase{
+		{
+			name: "redelivered", method: http.MethodPost, target: target,
+			setup: func(s *stubs) { s.webhook.delivery = testDelivery() },
+			code:  http.StatusOK, want: testDeliveryJSON,
+			check: func(t *testing.T, s *stubs, _ *httptest.ResponseRecorder) {
+				wantInput(t, s.webhook.in, []any{testOrgId, testWebhookId, testDeliveryId, "alice"})
+			},
+		},
+	}
+	cases = append(cases, identityCases(http.MethodPost, target, "")...)
+	cases = append(cases, errorCases(http.MethodPost, target, "", failWebhook, webhookErrors())...)
+	runRouteCases(t, cases)
+}
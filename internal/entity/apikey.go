@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	Id             uuid.UUID     `db:"id"`
+	OrganizationId uuid.UUID     `db:"organization_id"`
+	Name           string        `db:"name"`
+	Prefix         string        `db:"prefix"`
+	SecretHash     string        `db:"secret_hash"`
+	Scopes         []string      `db:"scopes"`
+	ExpiresAt      *time.Time    `db:"expires_at"`
+	LastUsedAt     *time.Time    `db:"last_used_at"`
+	RevokedAt      *time.Time    `db:"revoked_at"`
+	CreatedBy      uuid.NullUUID `db:"created_by"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records single mutation, Before and After are JSON snapshots of entity.
+// Entries are chained by Seq, Hash covers PrevHash and the entry itself
+type AuditEntry struct {
+	Id             uuid.UUID     `db:"id"`
+	Seq            int64         `db:"seq"`
+	OrganizationId uuid.NullUUID `db:"organization_id"`
+	ActorType      string        `db:"actor_type"`
+	ActorId        uuid.UUID     `db:"actor_id"`
+	ActorName      string        `db:"actor_name"`
+	Action         string        `db:"action"`
+	EntityType     string        `db:"entity_type"`
+	EntityId       uuid.UUID     `db:"entity_id"`
+	EntityVersion  int           `db:"entity_version"`
+	Before         []byte        `db:"before"`
+	After          []byte        `db:"after"`
+	RequestId      string        `db:"request_id"`
+	ClientIP       string        `db:"client_ip"`
+	CreatedAt      time.Time     `db:"created_at"`
+	PrevHash       string        `db:"prev_hash"`
+	Hash           string        `db:"hash"`
+}
@@ -15,6 +15,8 @@ type Bid struct {
 	Status      string    `db:"status"`
 	Version     int       `db:"version"`
 	TenderId    uuid.UUID `db:"tender_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	// Organization bid is made on behalf of, empty for user bids
+	OrganizationId uuid.NullUUID `db:"organization_id"`
+	CreatedAt      time.Time     `db:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at"`
 }
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Credential struct {
+	EmployeeId        uuid.UUID  `db:"employee_id"`
+	PasswordHash      string     `db:"password_hash"`
+	FailedAttempts    int        `db:"failed_attempts"`
+	LockedUntil       *time.Time `db:"locked_until"`
+	IsLocked          bool       `db:"is_locked"`
+	PasswordChangedAt time.Time  `db:"password_changed_at"`
+}
+
+type LoginAttempt struct {
+	Id         uuid.UUID     `db:"id"`
+	EmployeeId uuid.NullUUID `db:"employee_id"`
+	Username   string        `db:"username"`
+	ClientIP   string        `db:"client_ip"`
+	Success    bool          `db:"success"`
+	Reason     string        `db:"reason"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailMessage is rendered notification waiting for delivery
+type EmailMessage struct {
+	Id            uuid.UUID  `db:"id"`
+	EmployeeId    uuid.UUID  `db:"employee_id"`
+	EventId       uuid.UUID  `db:"event_id"`
+	Kind          string     `db:"kind"`
+	ToAddress     string     `db:"to_address"`
+	Subject       string     `db:"subject"`
+	TextBody      string     `db:"text_body"`
+	HTMLBody      string     `db:"html_body"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	LastError     string     `db:"last_error"`
+	SentAt        *time.Time `db:"sent_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
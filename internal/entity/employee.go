@@ -11,6 +11,9 @@ type Employee struct {
 	Username  string    `db:"username"`
 	FirstName string    `db:"first_name"`
 	LastName  string    `db:"last_name"`
+	Email     *string   `db:"email"`
+	IsActive  bool      `db:"is_active"`
+	IsAdmin   bool      `db:"is_admin"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
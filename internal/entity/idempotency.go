@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is response stored for request repeated with same key.
+// ResponseStatus is not set while first request is in progress
+type IdempotencyKey struct {
+	Caller              string     `db:"caller"`
+	Key                 string     `db:"key"`
+	RequestHash         []byte     `db:"request_hash"`
+	ResponseStatus      *int       `db:"response_status"`
+	ResponseContentType *string    `db:"response_content_type"`
+	ResponseBody        []byte     `db:"response_body"`
+	LockedUntil         time.Time  `db:"locked_until"`
+	CreatedAt           time.Time  `db:"created_at"`
+	CompletedAt         *time.Time `db:"completed_at"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Exactly one of OrganizationId and UserId is set
+type TenderInvitation struct {
+	Id             uuid.UUID     `db:"id"`
+	TenderId       uuid.UUID     `db:"tender_id"`
+	OrganizationId uuid.NullUUID `db:"organization_id"`
+	UserId         uuid.NullUUID `db:"user_id"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
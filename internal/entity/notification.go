@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is inbox entry of employee. OrganizationId is organization
+// employee is notified for, it is not set for personal notifications
+type Notification struct {
+	Id             uuid.UUID     `db:"id"`
+	EmployeeId     uuid.UUID     `db:"employee_id"`
+	EventId        uuid.UUID     `db:"event_id"`
+	Kind           string        `db:"kind"`
+	OrganizationId uuid.NullUUID `db:"organization_id"`
+	TenderId       uuid.UUID     `db:"tender_id"`
+	BidId          uuid.NullUUID `db:"bid_id"`
+	Title          string        `db:"title"`
+	ReadAt         *time.Time    `db:"read_at"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
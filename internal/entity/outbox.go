@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is domain event waiting for delivery to sinks, Payload is JSON
+type OutboxEvent struct {
+	Id             uuid.UUID     `db:"id"`
+	Type           string        `db:"type"`
+	AggregateType  string        `db:"aggregate_type"`
+	AggregateId    uuid.UUID     `db:"aggregate_id"`
+	OrganizationId uuid.NullUUID `db:"organization_id"`
+	Payload        []byte        `db:"payload"`
+	Attempts       int           `db:"attempts"`
+	NextAttemptAt  time.Time     `db:"next_attempt_at"`
+	ProcessedAt    *time.Time    `db:"processed_at"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// RateLimitBucket is token bucket of shared rate limiting. Allowed is
+// decision on last request taken from it
+type RateLimitBucket struct {
+	Key       string    `db:"key"`
+	Tokens    float64   `db:"tokens"`
+	Allowed   bool      `db:"allowed"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
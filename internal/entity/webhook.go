@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription struct {
+	Id             uuid.UUID     `db:"id"`
+	OrganizationId uuid.UUID     `db:"organization_id"`
+	URL            string        `db:"url"`
+	EventTypes     []string      `db:"event_types"`
+	Secret         string        `db:"secret"`
+	CreatedBy      uuid.NullUUID `db:"created_by"`
+	CreatedAt      time.Time     `db:"created_at"`
+}
+
+// WebhookDelivery is one event sent to one subscription, Payload is request body
+type WebhookDelivery struct {
+	Id             uuid.UUID  `db:"id"`
+	SubscriptionId uuid.UUID  `db:"subscription_id"`
+	EventId        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// WebhookDispatch is pending delivery with target of its subscription
+type WebhookDispatch struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Pruning deletes idempotency keys older than retention
+type Pruning interface {
+	PruneIdempotencyKeys(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// Pruner periodically deletes idempotency keys older than retention, their
+// responses are not replayed anymore
+type Pruner struct {
+	pruning   Pruning
+	interval  time.Duration
+	retention time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPruner(pruning Pruning, interval, retention time.Duration) *Pruner {
+	return &Pruner{
+		pruning:   pruning,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+func (p *Pruner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run(ctx)
+	}()
+}
+
+func (p *Pruner) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Pruner) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := p.pruning.PruneIdempotencyKeys(ctx, p.retention)
+		if err != nil {
+			log.Errorf("idempotency - Pruner.run - PruneIdempotencyKeys: %v", err)
+		} else if n > 0 {
+			log.Infof("idempotency - Pruner.run - pruned %d keys", n)
+		}
+
+		timer.Reset(p.interval)
+	}
+}
@@ -0,0 +1,73 @@
+package identity
+
+import (
+	e "app/internal/entity"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+type employeeKey struct{}
+
+// WithEmployee returns context carrying authenticated employee
+func WithEmployee(ctx context.Context, employee e.Employee) context.Context {
+	return context.WithValue(ctx, employeeKey{}, employee)
+}
+
+// EmployeeFromContext returns authenticated employee if request was authenticated
+func EmployeeFromContext(ctx context.Context) (e.Employee, bool) {
+	employee, ok := ctx.Value(employeeKey{}).(e.Employee)
+	return employee, ok
+}
+
+// Service is organization API key request is authenticated with
+type Service struct {
+	KeyId          uuid.UUID
+	OrganizationId uuid.UUID
+	Name           string
+	Prefix         string
+	Scopes         []string
+}
+
+// Username is stored as creator of objects made with the key
+func (s Service) Username() string {
+	return "api-key:" + s.Prefix
+}
+
+func (s Service) HasScope(scope string) bool {
+	return slices.Contains(s.Scopes, scope)
+}
+
+type serviceKey struct{}
+
+// WithService returns context carrying authenticated API key
+func WithService(ctx context.Context, service Service) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+// ServiceFromContext returns API key identity if request was authenticated with one
+func ServiceFromContext(ctx context.Context) (Service, bool) {
+	service, ok := ctx.Value(serviceKey{}).(Service)
+	return service, ok
+}
+
+// Request is where request came from, recorded in audit log. ClientIP is
+// connection address or one reported by trusted proxy, never client's claim
+type Request struct {
+	Id       string
+	ClientIP string
+}
+
+type requestKey struct{}
+
+// WithRequest returns context carrying request metadata
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFromContext returns request metadata, empty outside of HTTP requests
+func RequestFromContext(ctx context.Context) Request {
+	request, _ := ctx.Value(requestKey{}).(Request)
+	return request
+}
@@ -0,0 +1,73 @@
+package inbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Pruning deletes notifications older than retention
+type Pruning interface {
+	PruneNotifications(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// Pruner periodically deletes notifications older than retention
+type Pruner struct {
+	pruning   Pruning
+	interval  time.Duration
+	retention time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPruner(pruning Pruning, interval, retention time.Duration) *Pruner {
+	return &Pruner{
+		pruning:   pruning,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+func (p *Pruner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run(ctx)
+	}()
+}
+
+func (p *Pruner) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Pruner) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := p.pruning.PruneNotifications(ctx, p.retention)
+		if err != nil {
+			log.Errorf("inbox - Pruner.run - PruneNotifications: %v", err)
+		} else if n > 0 {
+			log.Infof("inbox - Pruner.run - pruned %d notifications", n)
+		}
+
+		timer.Reset(p.interval)
+	}
+}
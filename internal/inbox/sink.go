@@ -0,0 +1,101 @@
+package inbox
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Sink is outbox sink filling inboxes of employees. Organization notifications
+// go to its responsibles, so they follow responsibility of recipient
+type Sink struct {
+	notificationRepo repo.Notification
+	bidRepo          repo.Bid
+}
+
+func NewSink(nRepo repo.Notification, bRepo repo.Bid) *Sink {
+	return &Sink{notificationRepo: nRepo, bidRepo: bRepo}
+}
+
+func (s *Sink) Name() string {
+	return "inbox"
+}
+
+// eventKinds maps events to notification kinds, other events are not shown
+var eventKinds = map[string]string{
+	service.EventBidCreated:           service.NotificationBidReceived,
+	service.EventBidDecisionSubmitted: service.NotificationBidDecided,
+	service.EventTenderVersionCreated: service.NotificationTenderAmended,
+}
+
+// eventPayload has fields of tender and bid event payloads used in inbox
+type eventPayload struct {
+	TenderId       uuid.UUID  `json:"tenderId"`
+	BidId          uuid.UUID  `json:"bidId"`
+	Name           string     `json:"name"`
+	TenderName     string     `json:"tenderName"`
+	AuthorType     string     `json:"authorType"`
+	AuthorId       uuid.UUID  `json:"authorId"`
+	OrganizationId *uuid.UUID `json:"organizationId"`
+	Decision       string     `json:"decision"`
+}
+
+func (s *Sink) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	kind, ok := eventKinds[event.Type]
+	if !ok {
+		return nil
+	}
+
+	var p eventPayload
+	if err := json.Unmarshal(event.Payload, &p); err != nil {
+		return fmt.Errorf("inbox - Sink.Deliver - json.Unmarshal: %w", err)
+	}
+	in := rt.AddNotificationsInput{
+		EventId:  event.Id,
+		Kind:     kind,
+		TenderId: p.TenderId,
+	}
+
+	switch kind {
+	case service.NotificationBidReceived:
+		// Tender owner hears about bids
+		in.BidId = uuid.NullUUID{UUID: p.BidId, Valid: true}
+		in.Title = fmt.Sprintf("New bid \"%s\" on tender \"%s\"", p.Name, p.TenderName)
+		if event.OrganizationId.Valid {
+			in.OrganizationIds = []uuid.UUID{event.OrganizationId.UUID}
+		}
+	case service.NotificationBidDecided:
+		// Bid author hears about decisions
+		in.BidId = uuid.NullUUID{UUID: p.BidId, Valid: true}
+		in.Title = fmt.Sprintf("Bid \"%s\" on tender \"%s\" got decision: %s", p.Name, p.TenderName, p.Decision)
+		if p.AuthorType == "User" {
+			in.EmployeeIds = []uuid.UUID{p.AuthorId}
+		} else if p.OrganizationId != nil {
+			in.OrganizationIds = []uuid.UUID{*p.OrganizationId}
+		}
+	case service.NotificationTenderAmended:
+		// Bidders hear about tender changes
+		in.Title = fmt.Sprintf("Tender \"%s\" was amended", p.Name)
+		bidders, err := s.bidRepo.GetBidders(ctx, p.TenderId)
+		if err != nil {
+			return fmt.Errorf("inbox - Sink.Deliver - bidRepo.GetBidders: %w", err)
+		}
+		in.OrganizationIds = bidders.OrganizationIds
+		in.EmployeeIds = bidders.EmployeeIds
+	}
+
+	if len(in.OrganizationIds) == 0 && len(in.EmployeeIds) == 0 {
+		return nil
+	}
+	if _, err := s.notificationRepo.Add(ctx, in); err != nil {
+		return fmt.Errorf("inbox - Sink.Deliver - notificationRepo.Add: %w", err)
+	}
+
+	return nil
+}
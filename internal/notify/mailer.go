@@ -0,0 +1,173 @@
+package notify
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Message statuses
+const (
+	StatusPending = "Pending"
+	StatusSent    = "Sent"
+	StatusFailed  = "Failed"
+)
+
+const (
+	defaultPollInterval   = 5 * time.Second
+	defaultBatchSize      = 20
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Minute
+	defaultMaxBackoff     = time.Hour
+)
+
+// Mailer sends queued messages through transport, retries failed ones with
+// exponential backoff and gives up after MaxAttempts
+type Mailer struct {
+	emailRepo repo.Email
+	tx        repo.Transactor
+	transport Transport
+	from      string
+
+	pollInterval   time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewMailer(mRepo repo.Email, tx repo.Transactor, transport Transport, from string, opts ...Option) *Mailer {
+	m := &Mailer{
+		emailRepo:      mRepo,
+		tx:             tx,
+		transport:      transport,
+		from:           from,
+		pollInterval:   defaultPollInterval,
+		batchSize:      defaultBatchSize,
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *Mailer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx)
+	}()
+}
+
+// Stop cancels current batch and waits for mailer to exit. Messages of
+// cancelled batch stay pending
+func (m *Mailer) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *Mailer) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := m.sendBatch(ctx)
+		if err != nil {
+			log.Errorf("notify - Mailer.run - sendBatch: %v", err)
+		}
+
+		// Full batch means there are more pending messages
+		if err == nil && n == m.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(m.pollInterval)
+		}
+	}
+}
+
+// sendBatch locks batch of due messages while sending them, so several
+// mailers never send same message concurrently
+func (m *Mailer) sendBatch(ctx context.Context) (int, error) {
+	var n int
+	err := m.tx.InTx(ctx, func(ctx context.Context) error {
+		messages, err := m.emailRepo.GetPending(ctx, m.batchSize)
+		if err != nil {
+			return err
+		}
+		n = len(messages)
+
+		for _, msg := range messages {
+			if err := m.emailRepo.RecordAttempt(ctx, m.send(ctx, msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// send makes one attempt and returns its result
+func (m *Mailer) send(ctx context.Context, msg e.EmailMessage) rt.RecordEmailAttemptInput {
+	err := m.transport.Send(ctx, Message{
+		Id:       msg.Id.String(),
+		From:     m.from,
+		To:       msg.ToAddress,
+		Subject:  msg.Subject,
+		TextBody: msg.TextBody,
+		HTMLBody: msg.HTMLBody,
+	})
+	if err == nil {
+		return rt.RecordEmailAttemptInput{MessageId: msg.Id, Status: StatusSent}
+	}
+
+	result := rt.RecordEmailAttemptInput{
+		MessageId: msg.Id,
+		Status:    StatusPending,
+		Error:     err.Error(),
+		Delay:     m.backoff(msg.Attempts),
+	}
+	if msg.Attempts+1 >= m.maxAttempts {
+		log.Warnf("notify - Mailer.send - message %s failed: %v", msg.Id, err)
+		result.Status = StatusFailed
+		result.Delay = 0
+	}
+
+	return result
+}
+
+func (m *Mailer) backoff(attempts int) time.Duration {
+	backoff := m.initialBackoff
+	for i := 0; i < attempts && backoff < m.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, m.maxBackoff)
+}
@@ -0,0 +1,36 @@
+package notify
+
+import "time"
+
+type Option func(*Mailer)
+
+func PollInterval(interval time.Duration) Option {
+	return func(m *Mailer) {
+		m.pollInterval = interval
+	}
+}
+
+func BatchSize(size int) Option {
+	return func(m *Mailer) {
+		m.batchSize = size
+	}
+}
+
+// MaxAttempts is number of attempts before message is marked failed
+func MaxAttempts(attempts int) Option {
+	return func(m *Mailer) {
+		m.maxAttempts = attempts
+	}
+}
+
+func InitialBackoff(backoff time.Duration) Option {
+	return func(m *Mailer) {
+		m.initialBackoff = backoff
+	}
+}
+
+func MaxBackoff(backoff time.Duration) Option {
+	return func(m *Mailer) {
+		m.maxBackoff = backoff
+	}
+}
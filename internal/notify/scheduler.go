@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Reminder publishes deadline events for tenders closing within window
+type Reminder interface {
+	RemindDeadlines(ctx context.Context, window time.Duration) (int, error)
+}
+
+// DeadlineScheduler periodically asks reminder about tenders with approaching
+// deadlines. Reminders are claimed in database, so running it on several
+// instances sends each reminder once
+type DeadlineScheduler struct {
+	reminder Reminder
+	interval time.Duration
+	window   time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDeadlineScheduler(reminder Reminder, interval, window time.Duration) *DeadlineScheduler {
+	return &DeadlineScheduler{
+		reminder: reminder,
+		interval: interval,
+		window:   window,
+	}
+}
+
+func (s *DeadlineScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx)
+	}()
+}
+
+func (s *DeadlineScheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *DeadlineScheduler) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := s.reminder.RemindDeadlines(ctx, s.window)
+		if err != nil {
+			log.Errorf("notify - DeadlineScheduler.run - RemindDeadlines: %v", err)
+		} else if n > 0 {
+			log.Infof("notify - DeadlineScheduler.run - reminded about %d tenders", n)
+		}
+
+		timer.Reset(s.interval)
+	}
+}
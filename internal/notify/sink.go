@@ -0,0 +1,157 @@
+package notify
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"app/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sink is outbox sink rendering email notifications of opted in employees.
+// Messages are queued in outbox transaction and sent by Mailer
+type Sink struct {
+	emailRepo repo.Email
+	bidRepo   repo.Bid
+	renderer  *Renderer
+}
+
+func NewSink(mRepo repo.Email, bRepo repo.Bid, renderer *Renderer) *Sink {
+	return &Sink{emailRepo: mRepo, bidRepo: bRepo, renderer: renderer}
+}
+
+func (s *Sink) Name() string {
+	return "email"
+}
+
+// eventPayload has fields of tender and bid event payloads used in emails
+type eventPayload struct {
+	TenderId       uuid.UUID  `json:"tenderId"`
+	BidId          uuid.UUID  `json:"bidId"`
+	Name           string     `json:"name"`
+	TenderName     string     `json:"tenderName"`
+	AuthorType     string     `json:"authorType"`
+	AuthorId       uuid.UUID  `json:"authorId"`
+	OrganizationId *uuid.UUID `json:"organizationId"`
+	Version        int        `json:"version"`
+	Deadline       *time.Time `json:"deadline"`
+	Decision       string     `json:"decision"`
+}
+
+func (s *Sink) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	kind, ok := eventKinds[event.Type]
+	if !ok {
+		return nil
+	}
+
+	var payload eventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("notify - Sink.Deliver - json.Unmarshal: %w", err)
+	}
+
+	// Find employees opted in to kind
+	in, err := s.recipients(ctx, kind, event, payload)
+	if err != nil {
+		return fmt.Errorf("notify - Sink.Deliver - recipients: %w", err)
+	}
+	if len(in.OrganizationIds) == 0 && len(in.EmployeeIds) == 0 {
+		return nil
+	}
+	employees, err := s.emailRepo.GetRecipients(ctx, in)
+	if err != nil {
+		return fmt.Errorf("notify - Sink.Deliver - emailRepo.GetRecipients: %w", err)
+	}
+
+	// Queue personal message for each of them
+	data := newMessageData(payload)
+	for _, emp := range employees {
+		data.FirstName = emp.FirstName
+		if data.FirstName == "" {
+			data.FirstName = emp.Username
+		}
+		msg, err := s.renderer.render(kind, data)
+		if err != nil {
+			return fmt.Errorf("notify - Sink.Deliver - render %s: %w", kind, err)
+		}
+
+		err = s.emailRepo.AddMessage(ctx, rt.CreateEmailMessageInput{
+			EmployeeId: emp.Id,
+			EventId:    event.Id,
+			Kind:       kind,
+			ToAddress:  *emp.Email,
+			Subject:    msg.Subject,
+			TextBody:   msg.TextBody,
+			HTMLBody:   msg.HTMLBody,
+		})
+		if err != nil {
+			return fmt.Errorf("notify - Sink.Deliver - emailRepo.AddMessage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// eventKinds maps events to notification kinds, other events send no email
+var eventKinds = map[string]string{
+	service.EventBidCreated:                service.EmailKindBidReceived,
+	service.EventBidDecisionSubmitted:      service.EmailKindBidDecided,
+	service.EventTenderVersionCreated:      service.EmailKindTenderAmended,
+	service.EventTenderDeadlineApproaching: service.EmailKindDeadlineApproaching,
+}
+
+// recipients returns who is interested in event: tender owner hears about bids,
+// bid author hears about decisions, bidders hear about tender changes
+func (s *Sink) recipients(ctx context.Context, kind string, event e.OutboxEvent, p eventPayload) (rt.GetEmailRecipientsInput, error) {
+	in := rt.GetEmailRecipientsInput{Kind: kind}
+
+	switch kind {
+	case service.EmailKindBidReceived:
+		if event.OrganizationId.Valid {
+			in.OrganizationIds = []uuid.UUID{event.OrganizationId.UUID}
+		}
+	case service.EmailKindBidDecided:
+		if p.AuthorType == "User" {
+			in.EmployeeIds = []uuid.UUID{p.AuthorId}
+		} else if p.OrganizationId != nil {
+			in.OrganizationIds = []uuid.UUID{*p.OrganizationId}
+		}
+	case service.EmailKindTenderAmended, service.EmailKindDeadlineApproaching:
+		bidders, err := s.bidRepo.GetBidders(ctx, p.TenderId)
+		if err != nil {
+			return rt.GetEmailRecipientsInput{}, err
+		}
+		in.OrganizationIds = bidders.OrganizationIds
+		in.EmployeeIds = bidders.EmployeeIds
+		// Tender owner is reminded about its own deadline too
+		if kind == service.EmailKindDeadlineApproaching && event.OrganizationId.Valid {
+			in.OrganizationIds = append(in.OrganizationIds, event.OrganizationId.UUID)
+		}
+	}
+
+	return in, nil
+}
+
+func newMessageData(p eventPayload) messageData {
+	data := messageData{
+		TenderId:   p.TenderId.String(),
+		TenderName: p.TenderName,
+		Version:    p.Version,
+		Decision:   p.Decision,
+	}
+	// Bid events name bid, tender events name tender
+	if p.BidId != uuid.Nil {
+		data.BidId = p.BidId.String()
+		data.BidName = p.Name
+	} else {
+		data.TenderName = p.Name
+	}
+	if p.Deadline != nil {
+		data.Deadline = p.Deadline.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return data
+}
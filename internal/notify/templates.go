@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// messageData is available to templates of every kind, fields not related to
+// kind are empty
+type messageData struct {
+	FirstName  string
+	TenderId   string
+	TenderName string
+	BidId      string
+	BidName    string
+	Decision   string
+	Version    int
+	Deadline   string
+}
+
+type renderedMessage struct {
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Renderer renders message of every kind in plain text and HTML. Kind <k> is
+// rendered with templates <k>.subject, <k>.text and <k>.html
+type Renderer struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+func NewRenderer() (*Renderer, error) {
+	text, err := texttemplate.ParseFS(templatesFS, "templates/*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("notify - NewRenderer - texttemplate.ParseFS: %w", err)
+	}
+	html, err := htmltemplate.ParseFS(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("notify - NewRenderer - htmltemplate.ParseFS: %w", err)
+	}
+
+	return &Renderer{text: text, html: html}, nil
+}
+
+func (r *Renderer) render(kind string, data messageData) (renderedMessage, error) {
+	var subject, text, html bytes.Buffer
+	if err := r.text.ExecuteTemplate(&subject, kind+".subject", data); err != nil {
+		return renderedMessage{}, fmt.Errorf("subject: %w", err)
+	}
+	if err := r.text.ExecuteTemplate(&text, kind+".text", data); err != nil {
+		return renderedMessage{}, fmt.Errorf("text: %w", err)
+	}
+	if err := r.html.ExecuteTemplate(&html, kind+".html", data); err != nil {
+		return renderedMessage{}, fmt.Errorf("html: %w", err)
+	}
+
+	return renderedMessage{
+		// Line breaks in subject would inject headers
+		Subject:  strings.Join(strings.Fields(subject.String()), " "),
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
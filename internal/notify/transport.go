@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is email ready to be sent
+type Message struct {
+	Id       string
+	From     string
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Transport sends messages, error means message should be retried
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPTransport sends messages through SMTP relay. STARTTLS is used when relay
+// supports it, authentication is used when username is set
+type SMTPTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTPTransport(host string, port int, username, password string) *SMTPTransport {
+	t := &SMTPTransport{addr: net.JoinHostPort(host, strconv.Itoa(port))}
+	if username != "" {
+		t.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return t
+}
+
+// Send ignores ctx, net/smtp has no way to cancel exchange in progress
+func (t *SMTPTransport) Send(ctx context.Context, msg Message) error {
+	body, err := buildMessage(msg)
+	if err != nil {
+		return fmt.Errorf("notify - SMTPTransport.Send - buildMessage: %w", err)
+	}
+	// Envelope takes bare addresses, headers may have display names
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("notify - SMTPTransport.Send - mail.ParseAddress: %w", err)
+	}
+	if err := smtp.SendMail(t.addr, t.auth, from.Address, []string{msg.To}, body); err != nil {
+		return fmt.Errorf("notify - SMTPTransport.Send - smtp.SendMail: %w", err)
+	}
+	return nil
+}
+
+// FileTransport writes messages to directory as .eml files instead of sending
+// them, it is meant for development
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) *FileTransport {
+	return &FileTransport{dir: dir}
+}
+
+// Send names file after message Id, so retried message overwrites its file
+func (t *FileTransport) Send(ctx context.Context, msg Message) error {
+	body, err := buildMessage(msg)
+	if err != nil {
+		return fmt.Errorf("notify - FileTransport.Send - buildMessage: %w", err)
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("notify - FileTransport.Send - os.MkdirAll: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, msg.Id+".eml"), body, 0o644); err != nil {
+		return fmt.Errorf("notify - FileTransport.Send - os.WriteFile: %w", err)
+	}
+	return nil
+}
+
+// buildMessage encodes message as multipart/alternative with plain text and
+// HTML parts
+func buildMessage(msg Message) ([]byte, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", msg.Id, domain(from.Address))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.TextBody},
+		{"text/html", msg.HTMLBody},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		w := quotedprintable.NewWriter(&b)
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+
+	return b.Bytes(), nil
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func domain(address string) string {
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return "localhost"
+	}
+	return address[i+1:]
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultBatchSize       = 100
+	defaultMaxBackoff      = 10 * time.Minute
+	defaultDeliveryTimeout = 10 * time.Second
+	defaultLease           = 5 * time.Minute
+	initialBackoff         = time.Second
+)
+
+// Dispatcher polls outbox and delivers pending events to sinks. Delivery state
+// lives in database, so events written before shutdown are delivered after restart
+type Dispatcher struct {
+	outboxRepo repo.Outbox
+	tx         repo.Transactor
+	sinks      []Sink
+
+	pollInterval    time.Duration
+	batchSize       int
+	maxBackoff      time.Duration
+	deliveryTimeout time.Duration
+	lease           time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(oRepo repo.Outbox, tx repo.Transactor, sinks []Sink, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		outboxRepo:      oRepo,
+		tx:              tx,
+		sinks:           sinks,
+		pollInterval:    defaultPollInterval,
+		batchSize:       defaultBatchSize,
+		maxBackoff:      defaultMaxBackoff,
+		deliveryTimeout: defaultDeliveryTimeout,
+		lease:           defaultLease,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.run(ctx)
+	}()
+}
+
+// Stop cancels current batch and waits for dispatcher to exit. Undelivered
+// events of cancelled batch are delivered again once their lease expires
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := d.dispatchBatch(ctx)
+		if err != nil {
+			log.Errorf("outbox - Dispatcher.run - dispatchBatch: %v", err)
+		}
+
+		// Full batch means there are more pending events
+		if err == nil && n == d.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(d.pollInterval)
+		}
+	}
+}
+
+// dispatchBatch claims batch of due events for lease, so several dispatchers
+// never deliver same event concurrently. No transaction is held while sinks
+// are called. Events which can't be delivered before lease expires are left
+// for next claim
+func (d *Dispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	claimedAt := time.Now()
+	events, err := d.outboxRepo.Claim(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	// Event takes at most deliveryTimeout per sink. First one is always tried,
+	// so too short lease doesn't stall dispatcher
+	budget := time.Duration(len(d.sinks)) * d.deliveryTimeout
+	for i, event := range events {
+		if i > 0 && time.Since(claimedAt)+budget > d.lease {
+			break
+		}
+		if err := d.dispatch(ctx, event); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// dispatch delivers event to sinks which don't have it yet. Event is processed
+// once every sink succeeded, otherwise it is retried with exponential backoff.
+// Results of event are recorded in one transaction
+func (d *Dispatcher) dispatch(ctx context.Context, event e.OutboxEvent) error {
+	delivered, err := d.outboxRepo.GetDeliveredSinks(ctx, event.Id)
+	if err != nil {
+		return err
+	}
+
+	// lastErrors holds empty string for sinks event was delivered to
+	lastErrors := make(map[string]string, len(d.sinks))
+	failed := false
+	for _, sink := range d.sinks {
+		if slices.Contains(delivered, sink.Name()) {
+			continue
+		}
+
+		lastErrors[sink.Name()] = ""
+		if err := d.deliver(ctx, sink, event); err != nil {
+			log.Warnf("outbox - Dispatcher.dispatch - %s.Deliver %s: %v", sink.Name(), event.Id, err)
+			lastErrors[sink.Name()] = err.Error()
+			failed = true
+		}
+	}
+
+	return d.tx.InTx(ctx, func(ctx context.Context) error {
+		for _, sink := range d.sinks {
+			lastError, attempted := lastErrors[sink.Name()]
+			if !attempted {
+				continue
+			}
+			if err := d.outboxRepo.RecordDelivery(ctx, event.Id, sink.Name(), lastError); err != nil {
+				return err
+			}
+		}
+
+		if failed {
+			return d.outboxRepo.Reschedule(ctx, event.Id, d.backoff(event.Attempts))
+		}
+		return d.outboxRepo.MarkProcessed(ctx, event.Id)
+	})
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, sink Sink, event e.OutboxEvent) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, d.deliveryTimeout)
+	defer cancel()
+
+	// Misbehaving sink must not stop dispatcher
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return sink.Deliver(ctx, event)
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := initialBackoff
+	for i := 0; i < attempts && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, d.maxBackoff)
+}
@@ -0,0 +1,249 @@
+package outbox
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type txKey struct{}
+
+// fakeTransactor numbers transactions and puts number into context
+type fakeTransactor struct {
+	n int
+}
+
+func (t *fakeTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.n++
+	return fn(context.WithValue(ctx, txKey{}, t.n))
+}
+
+func (t *fakeTransactor) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.InTx(ctx, fn)
+}
+
+// txOf returns number of transaction context belongs to, 0 outside of one
+func txOf(ctx context.Context) int {
+	n, _ := ctx.Value(txKey{}).(int)
+	return n
+}
+
+// call is repository write and transaction it was made in
+type call struct {
+	op      string
+	eventId uuid.UUID
+	sink    string
+	tx      int
+}
+
+// fakeOutboxRepo serves fixed due events and records writes
+type fakeOutboxRepo struct {
+	repo.Outbox
+	due       []e.OutboxEvent
+	delivered map[uuid.UUID][]string
+	lease     time.Duration
+	claimTx   int
+	calls     []call
+}
+
+func (r *fakeOutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]e.OutboxEvent, error) {
+	r.lease = lease
+	r.claimTx = txOf(ctx)
+	events := r.due[:min(limit, len(r.due))]
+	r.due = r.due[len(events):]
+	return events, nil
+}
+
+func (r *fakeOutboxRepo) GetDeliveredSinks(ctx context.Context, eventId uuid.UUID) ([]string, error) {
+	return r.delivered[eventId], nil
+}
+
+func (r *fakeOutboxRepo) RecordDelivery(ctx context.Context, eventId uuid.UUID, sink, lastError string) error {
+	op := "delivered"
+	if lastError != "" {
+		op = "failed"
+	}
+	r.calls = append(r.calls, call{op, eventId, sink, txOf(ctx)})
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkProcessed(ctx context.Context, eventId uuid.UUID) error {
+	r.calls = append(r.calls, call{"processed", eventId, "", txOf(ctx)})
+	return nil
+}
+
+func (r *fakeOutboxRepo) Reschedule(ctx context.Context, eventId uuid.UUID, delay time.Duration) error {
+	r.calls = append(r.calls, call{"rescheduled " + delay.String(), eventId, "", txOf(ctx)})
+	return nil
+}
+
+// fakeSink fails events in fail, sleeps for delay and records events it got
+// with transaction it was called in
+type fakeSink struct {
+	name  string
+	fail  map[uuid.UUID]bool
+	delay time.Duration
+	got   []uuid.UUID
+	tx    []int
+}
+
+func (s *fakeSink) Name() string {
+	return s.name
+}
+
+func (s *fakeSink) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	time.Sleep(s.delay)
+	s.got = append(s.got, event.Id)
+	s.tx = append(s.tx, txOf(ctx))
+	if s.fail[event.Id] {
+		return errors.New("sink is down")
+	}
+	return nil
+}
+
+type panicSink struct{}
+
+func (panicSink) Name() string { return "panic" }
+
+func (panicSink) Deliver(ctx context.Context, event e.OutboxEvent) error { panic("boom") }
+
+func events(n int) []e.OutboxEvent {
+	events := make([]e.OutboxEvent, n)
+	for i := range events {
+		events[i] = e.OutboxEvent{Id: uuid.New(), Type: "bid.created", Attempts: i}
+	}
+	return events
+}
+
+func TestDispatchBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []e.OutboxEvent
+		delivered func(events []e.OutboxEvent) map[uuid.UUID][]string
+		fail      func(events []e.OutboxEvent) map[uuid.UUID]bool
+		want      func(events []e.OutboxEvent) []call
+	}{
+		{
+			name:   "delivered",
+			events: events(2),
+			want: func(ev []e.OutboxEvent) []call {
+				return []call{
+					{"delivered", ev[0].Id, "a", 1}, {"delivered", ev[0].Id, "b", 1}, {"processed", ev[0].Id, "", 1},
+					{"delivered", ev[1].Id, "a", 2}, {"delivered", ev[1].Id, "b", 2}, {"processed", ev[1].Id, "", 2},
+				}
+			},
+		},
+		{
+			name:   "failed sink",
+			events: events(2),
+			fail: func(ev []e.OutboxEvent) map[uuid.UUID]bool {
+				return map[uuid.UUID]bool{ev[1].Id: true}
+			},
+			want: func(ev []e.OutboxEvent) []call {
+				return []call{
+					{"delivered", ev[0].Id, "a", 1}, {"delivered", ev[0].Id, "b", 1}, {"processed", ev[0].Id, "", 1},
+					// Second event was attempted once before, backoff doubles
+					{"delivered", ev[1].Id, "a", 2}, {"failed", ev[1].Id, "b", 2}, {"rescheduled 2s", ev[1].Id, "", 2},
+				}
+			},
+		},
+		{
+			name:   "already delivered sink skipped",
+			events: events(1),
+			delivered: func(ev []e.OutboxEvent) map[uuid.UUID][]string {
+				return map[uuid.UUID][]string{ev[0].Id: {"a"}}
+			},
+			want: func(ev []e.OutboxEvent) []call {
+				return []call{{"delivered", ev[0].Id, "b", 1}, {"processed", ev[0].Id, "", 1}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeOutboxRepo{due: tt.events}
+			if tt.delivered != nil {
+				r.delivered = tt.delivered(tt.events)
+			}
+			a := &fakeSink{name: "a"}
+			b := &fakeSink{name: "b"}
+			if tt.fail != nil {
+				b.fail = tt.fail(tt.events)
+			}
+			tx := &fakeTransactor{}
+			d := New(r, tx, []Sink{a, b}, Lease(time.Minute))
+
+			n, err := d.dispatchBatch(context.Background())
+			if err != nil {
+				t.Fatalf("dispatchBatch: %v", err)
+			}
+			if n != len(tt.events) {
+				t.Errorf("dispatched %d, want %d", n, len(tt.events))
+			}
+			if r.lease != time.Minute || r.claimTx != 0 {
+				t.Errorf("claimed for %s in transaction %d, want %s outside of transaction", r.lease, r.claimTx, time.Minute)
+			}
+			for _, s := range []*fakeSink{a, b} {
+				for _, n := range s.tx {
+					if n != 0 {
+						t.Errorf("sink %s called in transaction %d", s.name, n)
+					}
+				}
+			}
+
+			want := tt.want(tt.events)
+			if len(r.calls) != len(want) {
+				t.Fatalf("calls\n%+v\nwant\n%+v", r.calls, want)
+			}
+			for i := range want {
+				if r.calls[i] != want[i] {
+					t.Errorf("call %d %+v, want %+v", i, r.calls[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDispatchBatchStopsBeforeLeaseExpires(t *testing.T) {
+	r := &fakeOutboxRepo{due: events(3)}
+	sink := &fakeSink{name: "slow", delay: 20 * time.Millisecond}
+	// Event may take 30ms, after first one less than that is left of lease
+	d := New(r, &fakeTransactor{}, []Sink{sink}, DeliveryTimeout(30*time.Millisecond), Lease(40*time.Millisecond))
+
+	n, err := d.dispatchBatch(context.Background())
+	if err != nil {
+		t.Fatalf("dispatchBatch: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("claimed %d, want 3", n)
+	}
+	if len(sink.got) != 1 {
+		t.Errorf("delivered %d events, want 1", len(sink.got))
+	}
+}
+
+func TestDispatchRecoversPanickingSink(t *testing.T) {
+	ev := events(1)
+	r := &fakeOutboxRepo{due: ev}
+	d := New(r, &fakeTransactor{}, []Sink{panicSink{}})
+
+	if _, err := d.dispatchBatch(context.Background()); err != nil {
+		t.Fatalf("dispatchBatch: %v", err)
+	}
+	want := []call{{"failed", ev[0].Id, "panic", 1}, {"rescheduled 1s", ev[0].Id, "", 1}}
+	if len(r.calls) != 2 || r.calls[0] != want[0] || r.calls[1] != want[1] {
+		t.Errorf("calls %+v, want %+v", r.calls, want)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	d := New(&fakeOutboxRepo{}, &fakeTransactor{}, nil, MaxBackoff(3*time.Second))
+	if got := d.backoff(10); got != 3*time.Second {
+		t.Errorf("backoff(10) = %v, want %v", got, 3*time.Second)
+	}
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import "time"
+
+type Option func(*Dispatcher)
+
+func PollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+func BatchSize(size int) Option {
+	return func(d *Dispatcher) {
+		d.batchSize = size
+	}
+}
+
+func MaxBackoff(backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.maxBackoff = backoff
+	}
+}
+
+func DeliveryTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.deliveryTimeout = timeout
+	}
+}
+
+// Lease is how long claimed batch is hidden from other dispatchers. It should
+// fit delivering event to every sink, events are left for next claim otherwise
+func Lease(lease time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.lease = lease
+	}
+}
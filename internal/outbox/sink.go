@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	e "app/internal/entity"
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Sink receives domain events. Delivery is at-least-once, so sinks must
+// tolerate duplicates (event Id is stable between attempts). Name identifies
+// sink in delivery state and must not change between restarts
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event e.OutboxEvent) error
+}
+
+// LogSink writes events to application log
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	log.WithFields(log.Fields{
+		"eventId":     event.Id,
+		"type":        event.Type,
+		"aggregateId": event.AggregateId,
+	}).Infof("outbox event: %s", event.Payload)
+	return nil
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Request is what limits of policy are chosen by. EmployeeId is set for
+// employee requests, OrganizationId for API key requests and for employees
+// acting for organization
+type Request struct {
+	EmployeeId     uuid.NullUUID
+	OrganizationId uuid.NullUUID
+	IP             string
+	Write          bool
+}
+
+// Limiter applies policy to requests
+type Limiter struct {
+	store  Store
+	policy Policy
+}
+
+func NewLimiter(store Store, policy Policy) *Limiter {
+	return &Limiter{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// Take takes token from every bucket request is limited by and returns most
+// restrictive result. Buckets after denying one are left untouched, rejected
+// request doesn't use up other limits. False is returned when no limit
+// applies. Store failures let request through
+func (l *Limiter) Take(ctx context.Context, req Request) (Result, bool) {
+	kind := ":read"
+	if req.Write {
+		kind = ":write"
+	}
+
+	type bucketRule struct {
+		key  string
+		rule Rule
+	}
+	buckets := []bucketRule{{"ip:" + req.IP + kind, l.policy.IP.rule(req.Write)}}
+	if req.EmployeeId.Valid {
+		buckets = append(buckets, bucketRule{"employee:" + req.EmployeeId.UUID.String() + kind, l.policy.Employee.rule(req.Write)})
+	}
+	if req.OrganizationId.Valid {
+		buckets = append(buckets, bucketRule{"organization:" + req.OrganizationId.UUID.String() + kind, l.policy.Organization.rule(req.Write)})
+	}
+
+	var tightest Result
+	applied := false
+	for _, b := range buckets {
+		if b.rule.disabled() {
+			continue
+		}
+
+		res, err := l.store.Take(ctx, b.key, b.rule)
+		if err != nil {
+			log.Errorf("ratelimit - Limiter.Take - store.Take: %v", err)
+			continue
+		}
+		if !applied || tighter(res, tightest) {
+			tightest = res
+		}
+		applied = true
+		if !res.Allowed {
+			break
+		}
+	}
+
+	return tightest, applied
+}
+
+// tighter reports if a restricts request more than b. Denial is tightest,
+// among denials the longest one
+func tighter(a, b Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
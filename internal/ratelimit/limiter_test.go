@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	testNow      = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEmployee = uuid.NullUUID{UUID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Valid: true}
+	testOther    = uuid.NullUUID{UUID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Valid: true}
+	testOrg      = uuid.NullUUID{UUID: uuid.MustParse("00000000-0000-0000-0000-00000000000a"), Valid: true}
+)
+
+// newTestLimiter returns limiter over memory store with stopped clock
+func newTestLimiter(policy Policy) (*Limiter, *MemoryStore) {
+	store := NewMemoryStore()
+	store.now = func() time.Time { return testNow }
+	return NewLimiter(store, policy), store
+}
+
+func limits(read, write int) Limits {
+	return Limits{
+		Read:  Rule{Limit: read, Period: time.Minute},
+		Write: Rule{Limit: write, Period: time.Minute},
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		reqs   []Request
+		// want is whether each request is allowed
+		want      []bool
+		remaining int
+	}{
+		{
+			name:   "ip",
+			policy: Policy{IP: limits(2, 1)},
+			reqs:   []Request{{IP: "a"}, {IP: "a"}, {IP: "b"}, {IP: "a"}},
+			want:   []bool{true, true, true, false},
+		},
+		{
+			name:   "reads and writes apart",
+			policy: Policy{IP: limits(1, 1)},
+			reqs:   []Request{{IP: "a"}, {IP: "a", Write: true}, {IP: "a", Write: true}},
+			want:   []bool{true, true, false},
+		},
+		{
+			name:   "employee across ips",
+			policy: Policy{IP: limits(10, 10), Employee: limits(1, 1)},
+			reqs: []Request{
+				{IP: "a", EmployeeId: testEmployee},
+				{IP: "b", EmployeeId: testEmployee},
+				{IP: "b", EmployeeId: testOther},
+			},
+			want: []bool{true, false, true},
+		},
+		{
+			name:   "organization shared by employees and api keys",
+			policy: Policy{IP: limits(10, 10), Employee: limits(10, 10), Organization: limits(2, 2)},
+			reqs: []Request{
+				{IP: "a", OrganizationId: testOrg},
+				{IP: "b", EmployeeId: testEmployee, OrganizationId: testOrg},
+				{IP: "c", EmployeeId: testOther, OrganizationId: testOrg},
+				{IP: "c", EmployeeId: testOther},
+			},
+			want: []bool{true, true, false, true},
+		},
+		{
+			name:   "tightest remaining",
+			policy: Policy{IP: limits(10, 10), Employee: limits(3, 3)},
+			reqs:   []Request{{IP: "a", EmployeeId: testEmployee}},
+			want:   []bool{true},
+			// Employee bucket has 2 tokens left, IP bucket 9
+			remaining: 2,
+		},
+		{
+			name:   "denial spares later buckets",
+			policy: Policy{IP: limits(1, 1), Employee: limits(2, 2)},
+			reqs: []Request{
+				{IP: "a", EmployeeId: testEmployee},
+				{IP: "a", EmployeeId: testEmployee},
+				{IP: "a", EmployeeId: testEmployee},
+				{IP: "b", EmployeeId: testEmployee},
+			},
+			// Requests denied by IP limit don't use up employee limit
+			want: []bool{true, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestLimiter(tt.policy)
+			var res Result
+			for i, req := range tt.reqs {
+				var ok bool
+				res, ok = l.Take(context.Background(), req)
+				if !ok {
+					t.Fatalf("request %d: no limit applied", i+1)
+				}
+				if res.Allowed != tt.want[i] {
+					t.Errorf("request %d %+v: allowed %t, want %t", i+1, req, res.Allowed, tt.want[i])
+				}
+			}
+			if tt.remaining != 0 && res.Remaining != tt.remaining {
+				t.Errorf("remaining %d, want %d", res.Remaining, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l, _ := newTestLimiter(Policy{Employee: limits(0, 1)})
+	if _, ok := l.Take(context.Background(), Request{IP: "a", EmployeeId: testEmployee}); ok {
+		t.Errorf("limit applied to read with all read limits disabled")
+	}
+	if _, ok := l.Take(context.Background(), Request{IP: "a", EmployeeId: testEmployee, Write: true}); !ok {
+		t.Errorf("write limit not applied")
+	}
+}
+
+func TestLimiterRefill(t *testing.T) {
+	l, store := newTestLimiter(Policy{IP: limits(2, 2)})
+	req := Request{IP: "a"}
+	for i := 0; i < 2; i++ {
+		l.Take(context.Background(), req)
+	}
+
+	res, _ := l.Take(context.Background(), req)
+	if res.Allowed {
+		t.Fatalf("request over limit allowed")
+	}
+	if res.RetryAfter != 30*time.Second {
+		t.Errorf("retry after %s, want 30s", res.RetryAfter)
+	}
+
+	store.now = func() time.Time { return testNow.Add(res.RetryAfter) }
+	if res, _ := l.Take(context.Background(), req); !res.Allowed {
+		t.Errorf("request after retry delay denied")
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are dropped
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	rule    Rule
+}
+
+// MemoryStore keeps buckets in process memory, each instance limits requests
+// it serves on its own
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Limit), updated: now}
+		s.buckets[key] = b
+	}
+	b.rule = rule
+
+	tokens := rule.refill(b.tokens, now.Sub(b.updated))
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	b.tokens = tokens
+	b.updated = now
+
+	return rule.result(tokens, allowed), nil
+}
+
+// sweep drops buckets which became full, they are same as missing ones
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.rule.refill(b.tokens, now.Sub(b.updated)) >= float64(b.rule.Limit) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// pruneInterval is how often unused buckets are deleted from database
+const pruneInterval = time.Hour
+
+// PostgresStore keeps buckets in database, so instances sharing it share limits
+type PostgresStore struct {
+	rateLimitRepo repo.RateLimit
+
+	mu        sync.Mutex
+	lastPrune time.Time
+	maxPeriod time.Duration
+}
+
+func NewPostgresStore(rRepo repo.RateLimit) *PostgresStore {
+	return &PostgresStore{
+		rateLimitRepo: rRepo,
+		lastPrune:     time.Now(),
+	}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	s.prune(ctx, rule)
+
+	b, err := s.rateLimitRepo.Take(ctx, rt.TakeRateLimitTokenInput{
+		Key:      key,
+		Capacity: rule.Limit,
+		Rate:     rule.rate(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit - PostgresStore.Take - rateLimitRepo.Take: %w", err)
+	}
+
+	return rule.result(b.Tokens, b.Allowed), nil
+}
+
+// prune deletes buckets unused for longest period in background, they are
+// full and same as missing ones
+func (s *PostgresStore) prune(ctx context.Context, rule Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxPeriod = max(s.maxPeriod, rule.Period)
+	if time.Since(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = time.Now()
+
+	age := s.maxPeriod
+	go func() {
+		n, err := s.rateLimitRepo.DeleteIdle(context.WithoutCancel(ctx), age)
+		if err != nil {
+			log.Errorf("ratelimit - PostgresStore.prune - rateLimitRepo.DeleteIdle: %v", err)
+		} else if n > 0 {
+			log.Infof("ratelimit - PostgresStore.prune - deleted %d buckets", n)
+		}
+	}()
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule lets Limit requests per Period, unused requests accumulate up to Limit.
+// Zero Limit means no limit
+type Rule struct {
+	Limit  int
+	Period time.Duration
+}
+
+func (r Rule) disabled() bool {
+	return r.Limit <= 0 || r.Period <= 0
+}
+
+// rate is number of tokens added to bucket per second
+func (r Rule) rate() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// refill returns tokens in bucket after elapsed time
+func (r Rule) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(r.Limit), tokens+elapsed.Seconds()*r.rate())
+}
+
+// result describes bucket left with tokens after request was let or denied
+func (r Rule) result(tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     r.Limit,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     r.wait(float64(r.Limit) - tokens),
+	}
+	if !allowed {
+		res.RetryAfter = r.wait(1 - tokens)
+	}
+	return res
+}
+
+func (r Rule) wait(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / r.rate() * float64(time.Second))
+}
+
+// Result of taking token. Reset is time bucket needs to become full again,
+// RetryAfter is time until next request is let, it is set for denied request
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// Limits are rules for reading and changing requests
+type Limits struct {
+	Read  Rule
+	Write Rule
+}
+
+func (l Limits) rule(write bool) Rule {
+	if write {
+		return l.Write
+	}
+	return l.Read
+}
+
+// Policy limits requests by authenticated employee, by organization of API
+// key and by client IP. Request must fit all limits that apply to it
+type Policy struct {
+	Employee     Limits
+	Organization Limits
+	IP           Limits
+}
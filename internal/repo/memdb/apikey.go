@@ -0,0 +1,133 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepo struct {
+	*Store
+}
+
+func NewAPIKeyRepo(s *Store) *APIKeyRepo {
+	return &APIKeyRepo{s}
+}
+
+func (r *APIKeyRepo) Create(ctx context.Context, in rt.CreateAPIKeyInput) (e.APIKey, error) {
+	var (
+		key e.APIKey
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		for _, other := range r.apiKeys {
+			if other.Prefix == in.Prefix || other.SecretHash == in.SecretHash {
+				err = repoerrors.ErrAlreadyExists
+				return
+			}
+		}
+		if !r.organizationExists(in.OrganizationId) || !r.employeeExists(in.CreatedBy) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+
+		ts := now()
+		key = e.APIKey{
+			Id:             uuid.New(),
+			OrganizationId: in.OrganizationId,
+			Name:           in.Name,
+			Prefix:         in.Prefix,
+			SecretHash:     in.SecretHash,
+			Scopes:         append([]string{}, in.Scopes...),
+			CreatedBy:      uuid.NullUUID{UUID: in.CreatedBy, Valid: true},
+			CreatedAt:      ts,
+		}
+		if in.TTL > 0 {
+			expiresAt := ts.Add(in.TTL)
+			key.ExpiresAt = &expiresAt
+		}
+		put(t, r.apiKeys, key.Id, key)
+	})
+	if err != nil {
+		return e.APIKey{}, err
+	}
+
+	return cloneAPIKey(key), nil
+}
+
+func (r *APIKeyRepo) GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.APIKey, error) {
+	keys := []e.APIKey{}
+	r.exec(ctx, func(*tx) {
+		for _, key := range r.apiKeys {
+			if key.OrganizationId == orgId {
+				keys = append(keys, cloneAPIKey(key))
+			}
+		}
+	})
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return lessId(keys[i].Id, keys[j].Id)
+	})
+	return keys, nil
+}
+
+// Revoke is not idempotent: revoking already revoked key returns ErrNotFound
+func (r *APIKeyRepo) Revoke(ctx context.Context, orgId, id uuid.UUID) error {
+	var ok bool
+	r.exec(ctx, func(t *tx) {
+		key, found := r.apiKeys[id]
+		if !found || key.OrganizationId != orgId || key.RevokedAt != nil {
+			return
+		}
+		revokedAt := now()
+		key.RevokedAt = &revokedAt
+		put(t, r.apiKeys, id, key)
+		ok = true
+	})
+	if !ok {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// Use returns active key by secret hash and updates its last usage time
+func (r *APIKeyRepo) Use(ctx context.Context, secretHash string) (e.APIKey, error) {
+	var (
+		key e.APIKey
+		ok  bool
+	)
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		for _, k := range r.apiKeys {
+			if k.SecretHash != secretHash || k.RevokedAt != nil || (k.ExpiresAt != nil && !k.ExpiresAt.After(ts)) {
+				continue
+			}
+			key, ok = k, true
+			key.LastUsedAt = &ts
+			put(t, r.apiKeys, key.Id, key)
+			return
+		}
+	})
+	if !ok {
+		return e.APIKey{}, repoerrors.ErrNotFound
+	}
+
+	return cloneAPIKey(key), nil
+}
+
+func cloneAPIKey(key e.APIKey) e.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	key.ExpiresAt = cloneTime(key.ExpiresAt)
+	key.LastUsedAt = cloneTime(key.LastUsedAt)
+	key.RevokedAt = cloneTime(key.RevokedAt)
+	return key
+}
@@ -0,0 +1,135 @@
+package memdb
+
+import (
+	"app/internal/auditchain"
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepo struct {
+	*Store
+}
+
+func NewAuditRepo(s *Store) *AuditRepo {
+	return &AuditRepo{s}
+}
+
+// Add appends entry to the chain and seals it
+func (r *AuditRepo) Add(ctx context.Context, in rt.CreateAuditEntryInput) error {
+	var err error
+	r.exec(ctx, func(t *tx) {
+		n := len(r.auditLog)
+		r.auditLog = append(r.auditLog, e.AuditEntry{
+			Id:             uuid.New(),
+			Seq:            int64(n) + 1,
+			OrganizationId: in.OrganizationId,
+			ActorType:      in.ActorType,
+			ActorId:        in.ActorId,
+			ActorName:      in.ActorName,
+			Action:         in.Action,
+			EntityType:     in.EntityType,
+			EntityId:       in.EntityId,
+			EntityVersion:  in.EntityVersion,
+			Before:         bytes.Clone(in.Before),
+			After:          bytes.Clone(in.After),
+			RequestId:      in.RequestId,
+			ClientIP:       in.ClientIP,
+			CreatedAt:      now(),
+		})
+		t.onRollback(func() { r.auditLog = r.auditLog[:n] })
+
+		if err = r.sealPending(); err != nil {
+			err = fmt.Errorf("memdb - AuditRepo.Add - %w", err)
+		}
+	})
+
+	return err
+}
+
+// Seal hashes unsealed entries, every entry is sealed when added
+func (r *AuditRepo) Seal(ctx context.Context) error {
+	var err error
+	r.exec(ctx, func(*tx) {
+		if err = r.sealPending(); err != nil {
+			err = fmt.Errorf("memdb - AuditRepo.Seal - %w", err)
+		}
+	})
+
+	return err
+}
+
+// GetRange returns chained entries created in [from, to) ordered by seq
+func (r *AuditRepo) GetRange(ctx context.Context, from, to time.Time) ([]e.AuditEntry, error) {
+	entries := []e.AuditEntry{}
+	r.exec(ctx, func(*tx) {
+		for _, entry := range r.auditLog {
+			if !entry.CreatedAt.Before(from) && entry.CreatedAt.Before(to) {
+				entries = append(entries, cloneAuditEntry(entry))
+			}
+		}
+	})
+
+	return entries, nil
+}
+
+func (r *AuditRepo) GetOrganizationLog(ctx context.Context, in rt.GetAuditLogInput) ([]e.AuditEntry, error) {
+	entries := []e.AuditEntry{}
+	r.exec(ctx, func(*tx) {
+		for _, entry := range r.auditLog {
+			if !entry.OrganizationId.Valid || entry.OrganizationId.UUID != in.OrganizationId {
+				continue
+			}
+			if (in.EntityType != "" && entry.EntityType != in.EntityType) ||
+				(in.EntityId != uuid.Nil && entry.EntityId != in.EntityId) ||
+				(in.Action != "" && entry.Action != in.Action) ||
+				(in.ActorName != "" && entry.ActorName != in.ActorName) {
+				continue
+			}
+			entries = append(entries, cloneAuditEntry(entry))
+		}
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return lessId(entries[i].Id, entries[j].Id)
+	})
+	return page(entries, in.Limit, in.Offset), nil
+}
+
+// sealPending hashes unsealed entries in seq order, must be called with tables
+// locked. Hashes are never undone, entry rolled back takes its hash with it
+func (s *Store) sealPending() error {
+	prevHash := auditchain.GenesisHash
+	for i, entry := range s.auditLog {
+		if entry.Hash != "" {
+			prevHash = entry.Hash
+			continue
+		}
+
+		entry.PrevHash = prevHash
+		hash, err := auditchain.FromEntry(entry).ComputeHash()
+		if err != nil {
+			return fmt.Errorf("sealPending - ComputeHash: %w", err)
+		}
+		entry.Hash = hash
+		s.auditLog[i] = entry
+		prevHash = hash
+	}
+
+	return nil
+}
+
+func cloneAuditEntry(entry e.AuditEntry) e.AuditEntry {
+	entry.Before = bytes.Clone(entry.Before)
+	entry.After = bytes.Clone(entry.After)
+	return entry
+}
@@ -0,0 +1,225 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BidRepo struct {
+	*Store
+}
+
+func NewBidRepo(s *Store) *BidRepo {
+	return &BidRepo{s}
+}
+
+// use repotypes.VersionLatest if need latest version
+func (r *BidRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Bid, error) {
+	var (
+		bid e.Bid
+		ok  bool
+	)
+	r.exec(ctx, func(*tx) {
+		if version == rt.VersionLatest {
+			bid, ok = r.latestBid(id)
+		} else {
+			bid, ok = r.bids[versionKey{id, version}]
+		}
+	})
+	if !ok {
+		return e.Bid{}, repoerrors.ErrNotFound
+	}
+
+	return bid, nil
+}
+
+func (r *BidRepo) Create(ctx context.Context, in rt.CreateBidInput) (e.Bid, error) {
+	var (
+		bid e.Bid
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		if err = r.checkBid(in.AuthorType, in.AuthorId, in.OrganizationId); err != nil {
+			err = fmt.Errorf("memdb.BidRepo - Create: %w", err)
+			return
+		}
+
+		ts := now()
+		bid = e.Bid{
+			Id:             uuid.New(),
+			Name:           in.Name,
+			Description:    in.Description,
+			AuthorType:     in.AuthorType,
+			AuthorId:       in.AuthorId,
+			Status:         "Created",
+			Version:        1,
+			TenderId:       in.TenderId,
+			OrganizationId: in.OrganizationId,
+			CreatedAt:      ts,
+			UpdatedAt:      ts,
+		}
+		put(t, r.bids, versionKey{bid.Id, bid.Version}, bid)
+	})
+	if err != nil {
+		return e.Bid{}, err
+	}
+
+	return bid, nil
+}
+
+func (r *BidRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string) (e.Bid, error) {
+	var (
+		bid e.Bid
+		ok  bool
+	)
+	r.exec(ctx, func(t *tx) {
+		bid, ok = r.latestBid(id)
+		if !ok {
+			return
+		}
+		bid.Status = status
+		bid.UpdatedAt = now()
+		put(t, r.bids, versionKey{bid.Id, bid.Version}, bid)
+	})
+	if !ok {
+		return e.Bid{}, repoerrors.ErrNotFound
+	}
+
+	return bid, nil
+}
+
+func (r *BidRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedBidInput) (e.Bid, error) {
+	var (
+		bid e.Bid
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		key := versionKey{in.Id, in.Version}
+		// Version was created by concurrent write
+		if _, ok := r.bids[key]; ok {
+			err = repoerrors.ErrVersionConflict
+			return
+		}
+		if err = r.checkBid(in.AuthorType, in.AuthorId, in.OrganizationId); err != nil {
+			err = fmt.Errorf("memdb - BidRepo.CreateSpecified: %w", err)
+			return
+		}
+
+		ts := now()
+		bid = e.Bid{
+			Id:             in.Id,
+			Name:           in.Name,
+			Description:    in.Description,
+			AuthorType:     in.AuthorType,
+			AuthorId:       in.AuthorId,
+			Status:         in.Status,
+			Version:        in.Version,
+			TenderId:       in.TenderId,
+			OrganizationId: in.OrganizationId,
+			CreatedAt:      ts,
+			UpdatedAt:      ts,
+		}
+		put(t, r.bids, key, bid)
+	})
+	if err != nil {
+		return e.Bid{}, err
+	}
+
+	return bid, nil
+}
+
+// GetBidders collects authors of all versions of tender bids
+func (r *BidRepo) GetBidders(ctx context.Context, tenderId uuid.UUID) (rt.TenderBidders, error) {
+	orgs := map[uuid.UUID]bool{}
+	employees := map[uuid.UUID]bool{}
+	r.exec(ctx, func(*tx) {
+		for _, b := range r.bids {
+			if b.TenderId != tenderId {
+				continue
+			}
+			switch {
+			case b.AuthorType == "Organization" && b.OrganizationId.Valid:
+				orgs[b.OrganizationId.UUID] = true
+			case b.AuthorType == "User":
+				employees[b.AuthorId] = true
+			}
+		}
+	})
+
+	bidders := rt.TenderBidders{
+		OrganizationIds: make([]uuid.UUID, 0, len(orgs)),
+		EmployeeIds:     make([]uuid.UUID, 0, len(employees)),
+	}
+	for id := range orgs {
+		bidders.OrganizationIds = append(bidders.OrganizationIds, id)
+	}
+	for id := range employees {
+		bidders.EmployeeIds = append(bidders.EmployeeIds, id)
+	}
+	sortIds(bidders.OrganizationIds)
+	sortIds(bidders.EmployeeIds)
+
+	return bidders, nil
+}
+
+// Lock checks that version is latest and wasn't changed after updatedAt.
+// Transactions don't run concurrently, so there is nothing else to lock
+func (r *BidRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	var err error
+	r.exec(ctx, func(*tx) {
+		bid, ok := r.bids[versionKey{id, version}]
+		if !ok || !bid.UpdatedAt.Equal(updatedAt) {
+			err = repoerrors.ErrVersionConflict
+			return
+		}
+		if latest, _ := r.latestBid(id); latest.Version != version {
+			err = repoerrors.ErrVersionConflict
+		}
+	})
+
+	return err
+}
+
+// GetForUpdate returns latest version of bid, see Lock
+func (r *BidRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Bid, error) {
+	return r.Get(ctx, id, rt.VersionLatest)
+}
+
+// checkBid enforces foreign keys and bid_user_without_organization check of
+// bid table, must be called with tables locked
+func (s *Store) checkBid(authorType string, authorId uuid.UUID, orgId uuid.NullUUID) error {
+	if !s.employeeExists(authorId) {
+		return repoerrors.ErrInvalidReference
+	}
+	if !orgId.Valid {
+		return nil
+	}
+	if authorType != "Organization" {
+		return errors.New("user bid can't have organization")
+	}
+	if !s.organizationExists(orgId.UUID) {
+		return repoerrors.ErrInvalidReference
+	}
+	return nil
+}
+
+// latestBid must be called with tables locked
+func (s *Store) latestBid(id uuid.UUID) (e.Bid, bool) {
+	var (
+		latest e.Bid
+		found  bool
+	)
+	for key, b := range s.bids {
+		if key.id == id && (!found || key.version > latest.Version) {
+			latest, found = b, true
+		}
+	}
+	return latest, found
+}
@@ -0,0 +1,204 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resetTokenRow struct {
+	employeeId uuid.UUID
+	expiresAt  time.Time
+	used       bool
+}
+
+type CredentialRepo struct {
+	*Store
+}
+
+func NewCredentialRepo(s *Store) *CredentialRepo {
+	return &CredentialRepo{s}
+}
+
+func (r *CredentialRepo) Get(ctx context.Context, employeeId uuid.UUID) (e.Credential, error) {
+	var (
+		cred e.Credential
+		ok   bool
+	)
+	r.exec(ctx, func(*tx) {
+		cred, ok = r.credentials[employeeId]
+	})
+	if !ok {
+		return e.Credential{}, repoerrors.ErrNotFound
+	}
+
+	return withLockState(cred), nil
+}
+
+// SetPassword creates or replaces password hash and lifts lockout
+func (r *CredentialRepo) SetPassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	var err error
+	r.exec(ctx, func(t *tx) {
+		if !r.employeeExists(employeeId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+		put(t, r.credentials, employeeId, e.Credential{
+			EmployeeId:        employeeId,
+			PasswordHash:      passwordHash,
+			PasswordChangedAt: now(),
+		})
+	})
+
+	return err
+}
+
+// CreatePassword stores first password hash, ErrAlreadyExists is returned if
+// employee has one
+func (r *CredentialRepo) CreatePassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	var err error
+	r.exec(ctx, func(t *tx) {
+		if !r.employeeExists(employeeId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+		if _, ok := r.credentials[employeeId]; ok {
+			err = repoerrors.ErrAlreadyExists
+			return
+		}
+		put(t, r.credentials, employeeId, e.Credential{
+			EmployeeId:        employeeId,
+			PasswordHash:      passwordHash,
+			PasswordChangedAt: now(),
+		})
+	})
+
+	return err
+}
+
+// RegisterFailure increments failed attempts counter. Once it reaches maxAttempts
+// credential is locked for lockout and counter starts over
+func (r *CredentialRepo) RegisterFailure(ctx context.Context, employeeId uuid.UUID, maxAttempts int, lockout time.Duration) (e.Credential, error) {
+	var (
+		cred e.Credential
+		ok   bool
+	)
+	r.exec(ctx, func(t *tx) {
+		if cred, ok = r.credentials[employeeId]; !ok {
+			return
+		}
+		cred.FailedAttempts++
+		if cred.FailedAttempts >= maxAttempts {
+			cred.FailedAttempts = 0
+			lockedUntil := now().Add(lockout)
+			cred.LockedUntil = &lockedUntil
+		}
+		put(t, r.credentials, employeeId, cred)
+	})
+	if !ok {
+		return e.Credential{}, repoerrors.ErrNotFound
+	}
+
+	return withLockState(cred), nil
+}
+
+func (r *CredentialRepo) ResetFailures(ctx context.Context, employeeId uuid.UUID) error {
+	r.exec(ctx, func(t *tx) {
+		if cred, ok := r.credentials[employeeId]; ok {
+			cred.FailedAttempts = 0
+			cred.LockedUntil = nil
+			put(t, r.credentials, employeeId, cred)
+		}
+	})
+
+	return nil
+}
+
+func (r *CredentialRepo) CreateResetToken(ctx context.Context, in rt.CreateResetTokenInput) (time.Time, error) {
+	var (
+		expiresAt time.Time
+		err       error
+	)
+	r.exec(ctx, func(t *tx) {
+		if _, ok := r.resetTokens[in.TokenHash]; ok {
+			err = repoerrors.ErrAlreadyExists
+			return
+		}
+		if !r.employeeExists(in.EmployeeId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+		expiresAt = now().Add(in.TTL)
+		put(t, r.resetTokens, in.TokenHash, resetTokenRow{employeeId: in.EmployeeId, expiresAt: expiresAt})
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+// UseResetToken marks unexpired unused token as used and returns its employee
+func (r *CredentialRepo) UseResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var (
+		token resetTokenRow
+		ok    bool
+	)
+	r.exec(ctx, func(t *tx) {
+		token, ok = r.resetTokens[tokenHash]
+		if !ok || token.used || !token.expiresAt.After(now()) {
+			ok = false
+			return
+		}
+		token.used = true
+		put(t, r.resetTokens, tokenHash, token)
+	})
+	if !ok {
+		return uuid.Nil, repoerrors.ErrNotFound
+	}
+
+	return token.employeeId, nil
+}
+
+func (r *CredentialRepo) AddLoginAttempt(ctx context.Context, in rt.CreateLoginAttemptInput) error {
+	r.exec(ctx, func(t *tx) {
+		n := len(r.loginAttempts)
+		r.loginAttempts = append(r.loginAttempts, e.LoginAttempt{
+			Id:         uuid.New(),
+			EmployeeId: in.EmployeeId,
+			Username:   in.Username,
+			ClientIP:   in.ClientIP,
+			Success:    in.Success,
+			Reason:     in.Reason,
+			CreatedAt:  now(),
+		})
+		t.onRollback(func() { r.loginAttempts = r.loginAttempts[:n] })
+	})
+
+	return nil
+}
+
+func (r *CredentialRepo) GetLoginAttempts(ctx context.Context, in rt.GetLoginAttemptsInput) ([]e.LoginAttempt, error) {
+	attempts := []e.LoginAttempt{}
+	r.exec(ctx, func(*tx) {
+		// Attempts are appended in time order, latest are returned first
+		for i := len(r.loginAttempts) - 1; i >= 0; i-- {
+			if a := r.loginAttempts[i]; in.Username == "" || a.Username == in.Username {
+				attempts = append(attempts, a)
+			}
+		}
+	})
+
+	return page(attempts, in.Limit, in.Offset), nil
+}
+
+// withLockState computes IsLocked like credentialColumns of pgdb do
+func withLockState(cred e.Credential) e.Credential {
+	cred.LockedUntil = cloneTime(cred.LockedUntil)
+	cred.IsLocked = cred.LockedUntil != nil && cred.LockedUntil.After(now())
+	return cred
+}
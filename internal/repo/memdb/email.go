@@ -0,0 +1,149 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// emailKinds are values of email_notification_kind in enum order, preferences
+// are returned in it
+var emailKinds = []string{"bid_received", "bid_decided", "tender_amended", "deadline_approaching"}
+
+type EmailRepo struct {
+	*Store
+}
+
+func NewEmailRepo(s *Store) *EmailRepo {
+	return &EmailRepo{s}
+}
+
+func (r *EmailRepo) GetPreferences(ctx context.Context, employeeId uuid.UUID) ([]string, error) {
+	var kinds []string
+	r.exec(ctx, func(*tx) {
+		kinds = append([]string{}, r.emailPrefs[employeeId]...)
+	})
+
+	return kinds, nil
+}
+
+// SetPreferences replaces opted in kinds of employee
+func (r *EmailRepo) SetPreferences(ctx context.Context, employeeId uuid.UUID, kinds []string) error {
+	r.exec(ctx, func(t *tx) {
+		set := []string{}
+		for _, kind := range emailKinds {
+			if slices.Contains(kinds, kind) {
+				set = append(set, kind)
+			}
+		}
+		put(t, r.emailPrefs, employeeId, set)
+	})
+
+	return nil
+}
+
+func (r *EmailRepo) GetRecipients(ctx context.Context, in rt.GetEmailRecipientsInput) ([]e.Employee, error) {
+	employees := []e.Employee{}
+	r.exec(ctx, func(*tx) {
+		for _, emp := range r.employees {
+			if !emp.IsActive || emp.Email == nil || !slices.Contains(r.emailPrefs[emp.Id], in.Kind) {
+				continue
+			}
+			if slices.Contains(in.EmployeeIds, emp.Id) || r.isResponsibleOfAny(in.OrganizationIds, emp.Id) {
+				employees = append(employees, emp)
+			}
+		}
+	})
+
+	sortEmployees(employees)
+	return cloneEmployees(employees), nil
+}
+
+// AddMessage is idempotent, employee gets one message per event
+func (r *EmailRepo) AddMessage(ctx context.Context, in rt.CreateEmailMessageInput) error {
+	r.exec(ctx, func(t *tx) {
+		for _, m := range r.emailMessages {
+			if m.EventId == in.EventId && m.EmployeeId == in.EmployeeId {
+				return
+			}
+		}
+
+		ts := now()
+		m := e.EmailMessage{
+			Id:            uuid.New(),
+			EmployeeId:    in.EmployeeId,
+			EventId:       in.EventId,
+			Kind:          in.Kind,
+			ToAddress:     in.ToAddress,
+			Subject:       in.Subject,
+			TextBody:      in.TextBody,
+			HTMLBody:      in.HTMLBody,
+			Status:        "Pending",
+			NextAttemptAt: ts,
+			CreatedAt:     ts,
+		}
+		put(t, r.emailMessages, m.Id, m)
+	})
+
+	return nil
+}
+
+func (r *EmailRepo) GetPending(ctx context.Context, limit int) ([]e.EmailMessage, error) {
+	messages := []e.EmailMessage{}
+	r.exec(ctx, func(*tx) {
+		ts := now()
+		for _, m := range r.emailMessages {
+			if m.Status == "Pending" && !m.NextAttemptAt.After(ts) {
+				messages = append(messages, m)
+			}
+		}
+	})
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].NextAttemptAt.Equal(messages[j].NextAttemptAt) {
+			return messages[i].NextAttemptAt.Before(messages[j].NextAttemptAt)
+		}
+		return lessId(messages[i].Id, messages[j].Id)
+	})
+	messages = page(messages, limit, 0)
+	for i := range messages {
+		messages[i].SentAt = cloneTime(messages[i].SentAt)
+	}
+	return messages, nil
+}
+
+func (r *EmailRepo) RecordAttempt(ctx context.Context, in rt.RecordEmailAttemptInput) error {
+	r.exec(ctx, func(t *tx) {
+		m, ok := r.emailMessages[in.MessageId]
+		if !ok {
+			return
+		}
+
+		ts := now()
+		m.Attempts++
+		m.Status = in.Status
+		m.LastError = in.Error
+		m.SentAt = nil
+		if in.Status == "Sent" {
+			m.SentAt = &ts
+		}
+		m.NextAttemptAt = ts.Add(in.Delay)
+		put(t, r.emailMessages, m.Id, m)
+	})
+
+	return nil
+}
+
+// isResponsibleOfAny must be called with tables locked
+func (s *Store) isResponsibleOfAny(orgIds []uuid.UUID, userId uuid.UUID) bool {
+	for _, orgId := range orgIds {
+		if s.isResponsible(orgId, userId) {
+			return true
+		}
+	}
+	return false
+}
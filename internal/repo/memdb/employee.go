@@ -0,0 +1,245 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type EmployeeRepo struct {
+	*Store
+}
+
+func NewEmployeeRepo(s *Store) *EmployeeRepo {
+	return &EmployeeRepo{s}
+}
+
+func (r *EmployeeRepo) GetByUsername(ctx context.Context, username string) (e.Employee, error) {
+	var (
+		employee e.Employee
+		ok       bool
+	)
+	r.exec(ctx, func(*tx) {
+		for _, emp := range r.employees {
+			if emp.Username == username {
+				employee, ok = emp, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return e.Employee{}, repoerrors.ErrNotFound
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) IsResponsible(ctx context.Context, orgId, userId uuid.UUID) (bool, error) {
+	var isResponsible bool
+	r.exec(ctx, func(*tx) {
+		isResponsible = r.isResponsible(orgId, userId)
+	})
+
+	return isResponsible, nil
+}
+
+// GetRoles returns roles of employee in organization, empty if they aren't its responsible
+func (r *EmployeeRepo) GetRoles(ctx context.Context, orgId, userId uuid.UUID) ([]string, error) {
+	var roles []string
+	r.exec(ctx, func(*tx) {
+		roles = append([]string{}, r.responsibles[membership{orgId, userId}]...)
+	})
+
+	return roles, nil
+}
+
+func (r *EmployeeRepo) GetById(ctx context.Context, id uuid.UUID) (e.Employee, error) {
+	var (
+		employee e.Employee
+		ok       bool
+	)
+	r.exec(ctx, func(*tx) {
+		employee, ok = r.employees[id]
+	})
+	if !ok {
+		return e.Employee{}, repoerrors.ErrNotFound
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) Create(ctx context.Context, in rt.CreateEmployeeInput) (e.Employee, error) {
+	var (
+		employee e.Employee
+		err      error
+	)
+	r.exec(ctx, func(t *tx) {
+		if err = r.checkEmployee(uuid.Nil, in.Username, in.Email); err != nil {
+			return
+		}
+
+		ts := now()
+		employee = e.Employee{
+			Id:        uuid.New(),
+			Username:  in.Username,
+			FirstName: in.FirstName,
+			LastName:  in.LastName,
+			Email:     cloneString(in.Email),
+			IsActive:  true,
+			CreatedAt: ts,
+			UpdatedAt: ts,
+		}
+		put(t, r.employees, employee.Id, employee)
+	})
+	if err != nil {
+		return e.Employee{}, err
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) Update(ctx context.Context, in rt.UpdateEmployeeInput) (e.Employee, error) {
+	var (
+		employee e.Employee
+		err      error
+	)
+	r.exec(ctx, func(t *tx) {
+		var ok bool
+		if employee, ok = r.employees[in.Id]; !ok {
+			err = repoerrors.ErrNotFound
+			return
+		}
+		if err = r.checkEmployee(in.Id, employee.Username, in.Email); err != nil {
+			return
+		}
+
+		employee.FirstName = in.FirstName
+		employee.LastName = in.LastName
+		employee.Email = cloneString(in.Email)
+		employee.UpdatedAt = now()
+		put(t, r.employees, employee.Id, employee)
+	})
+	if err != nil {
+		return e.Employee{}, err
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) SetActive(ctx context.Context, id uuid.UUID, active bool) (e.Employee, error) {
+	var (
+		employee e.Employee
+		ok       bool
+	)
+	r.exec(ctx, func(t *tx) {
+		if employee, ok = r.employees[id]; !ok {
+			return
+		}
+		employee.IsActive = active
+		employee.UpdatedAt = now()
+		put(t, r.employees, employee.Id, employee)
+	})
+	if !ok {
+		return e.Employee{}, repoerrors.ErrNotFound
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) SetAdmin(ctx context.Context, id uuid.UUID, admin bool) (e.Employee, error) {
+	var (
+		employee e.Employee
+		ok       bool
+	)
+	r.exec(ctx, func(t *tx) {
+		if employee, ok = r.employees[id]; !ok {
+			return
+		}
+		employee.IsAdmin = admin
+		employee.UpdatedAt = now()
+		put(t, r.employees, employee.Id, employee)
+	})
+	if !ok {
+		return e.Employee{}, repoerrors.ErrNotFound
+	}
+
+	return cloneEmployee(employee), nil
+}
+
+func (r *EmployeeRepo) Search(ctx context.Context, in rt.SearchEmployeesInput) ([]e.Employee, error) {
+	var employees []e.Employee
+	r.exec(ctx, func(*tx) {
+		for _, emp := range r.employees {
+			if !emp.IsActive && !in.IncludeInactive {
+				continue
+			}
+			if matches(in.Query, emp.Username, emp.FirstName, emp.LastName) {
+				employees = append(employees, emp)
+			}
+		}
+	})
+
+	sortEmployees(employees)
+	return cloneEmployees(page(employees, in.Limit, in.Offset)), nil
+}
+
+// checkEmployee enforces unique username and case-insensitive unique email of
+// employee table, must be called with tables locked. id is employee being
+// updated, uuid.Nil for new one
+func (s *Store) checkEmployee(id uuid.UUID, username string, email *string) error {
+	for _, emp := range s.employees {
+		if emp.Id != id && emp.Username == username {
+			return repoerrors.ErrAlreadyExists
+		}
+	}
+	for _, emp := range s.employees {
+		if emp.Id != id && email != nil && emp.Email != nil && strings.EqualFold(*emp.Email, *email) {
+			return repoerrors.ErrEmailTaken
+		}
+	}
+	return nil
+}
+
+// isResponsible must be called with tables locked
+func (s *Store) isResponsible(orgId, userId uuid.UUID) bool {
+	_, ok := s.responsibles[membership{orgId, userId}]
+	return ok
+}
+
+// matches is ILIKE '%query%' on any of values, empty query matches everything
+func matches(query string, values ...string) bool {
+	if query == "" {
+		return true
+	}
+	query = strings.ToLower(query)
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortEmployees(employees []e.Employee) {
+	sort.Slice(employees, func(i, j int) bool {
+		return employees[i].Username < employees[j].Username
+	})
+}
+
+func cloneEmployee(emp e.Employee) e.Employee {
+	emp.Email = cloneString(emp.Email)
+	return emp
+}
+
+func cloneEmployees(employees []e.Employee) []e.Employee {
+	cloned := make([]e.Employee, 0, len(employees))
+	for _, emp := range employees {
+		cloned = append(cloned, cloneEmployee(emp))
+	}
+	return cloned
+}
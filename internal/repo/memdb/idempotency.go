@@ -0,0 +1,142 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"bytes"
+	"context"
+	"time"
+)
+
+type idempotencyKey struct {
+	caller string
+	key    string
+}
+
+type IdempotencyRepo struct {
+	*Store
+}
+
+func NewIdempotencyRepo(s *Store) *IdempotencyRepo {
+	return &IdempotencyRepo{s}
+}
+
+// Claim reports if key was claimed. Expired key is claimed as new one, key
+// abandoned by request with same payload is claimed again
+func (r *IdempotencyRepo) Claim(ctx context.Context, in rt.ClaimIdempotencyKeyInput) (bool, error) {
+	var claimed bool
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		key := idempotencyKey{in.Caller, in.Key}
+		if k, ok := r.idempotencyKeys[key]; ok {
+			expired := k.CreatedAt.Before(ts.Add(-in.TTL))
+			abandoned := k.ResponseStatus == nil && k.LockedUntil.Before(ts) && bytes.Equal(k.RequestHash, in.RequestHash)
+			if !expired && !abandoned {
+				return
+			}
+		}
+
+		put(t, r.idempotencyKeys, key, e.IdempotencyKey{
+			Caller:      in.Caller,
+			Key:         in.Key,
+			RequestHash: bytes.Clone(in.RequestHash),
+			LockedUntil: ts.Add(in.Lease),
+			CreatedAt:   ts,
+		})
+		claimed = true
+	})
+
+	return claimed, nil
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, caller, key string) (e.IdempotencyKey, error) {
+	var (
+		k  e.IdempotencyKey
+		ok bool
+	)
+	r.exec(ctx, func(*tx) {
+		k, ok = r.idempotencyKeys[idempotencyKey{caller, key}]
+	})
+	if !ok {
+		return e.IdempotencyKey{}, repoerrors.ErrNotFound
+	}
+
+	return cloneIdempotencyKey(k), nil
+}
+
+// Complete stores response of claimed key, completed key is left as is
+func (r *IdempotencyRepo) Complete(ctx context.Context, in rt.CompleteIdempotencyKeyInput) error {
+	r.exec(ctx, func(t *tx) {
+		key := idempotencyKey{in.Caller, in.Key}
+		k, ok := r.idempotencyKeys[key]
+		if !ok || k.ResponseStatus != nil {
+			return
+		}
+
+		ts := now()
+		status, contentType := in.Status, in.ContentType
+		k.ResponseStatus = &status
+		k.ResponseContentType = &contentType
+		k.ResponseBody = bytes.Clone(in.Body)
+		k.CompletedAt = &ts
+		put(t, r.idempotencyKeys, key, k)
+	})
+
+	return nil
+}
+
+// Extend holds claimed key for lease from now, completed key is left as is
+func (r *IdempotencyRepo) Extend(ctx context.Context, caller, key string, lease time.Duration) error {
+	r.exec(ctx, func(t *tx) {
+		k := idempotencyKey{caller, key}
+		row, ok := r.idempotencyKeys[k]
+		if !ok || row.ResponseStatus != nil {
+			return
+		}
+
+		row.LockedUntil = now().Add(lease)
+		put(t, r.idempotencyKeys, k, row)
+	})
+
+	return nil
+}
+
+// Release deletes claim of key, so request can be made with it again
+func (r *IdempotencyRepo) Release(ctx context.Context, caller, key string) error {
+	r.exec(ctx, func(t *tx) {
+		k := idempotencyKey{caller, key}
+		if row, ok := r.idempotencyKeys[k]; ok && row.ResponseStatus == nil {
+			remove(t, r.idempotencyKeys, k)
+		}
+	})
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	var count int
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		for key, k := range r.idempotencyKeys {
+			if k.CreatedAt.Before(ts.Add(-age)) && k.LockedUntil.Before(ts) {
+				remove(t, r.idempotencyKeys, key)
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+func cloneIdempotencyKey(k e.IdempotencyKey) e.IdempotencyKey {
+	k.RequestHash = bytes.Clone(k.RequestHash)
+	k.ResponseBody = bytes.Clone(k.ResponseBody)
+	if k.ResponseStatus != nil {
+		status := *k.ResponseStatus
+		k.ResponseStatus = &status
+	}
+	k.ResponseContentType = cloneString(k.ResponseContentType)
+	k.CompletedAt = cloneTime(k.CompletedAt)
+	return k
+}
@@ -0,0 +1,104 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type InvitationRepo struct {
+	*Store
+}
+
+func NewInvitationRepo(s *Store) *InvitationRepo {
+	return &InvitationRepo{s}
+}
+
+func (r *InvitationRepo) Create(ctx context.Context, in rt.CreateInvitationInput) (e.TenderInvitation, error) {
+	var (
+		inv e.TenderInvitation
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		if in.OrganizationId.Valid == in.UserId.Valid {
+			err = errors.New("memdb - InvitationRepo.Create: invitation must have exactly one invitee")
+			return
+		}
+		for _, other := range r.invitations {
+			if other.TenderId == in.TenderId &&
+				((in.OrganizationId.Valid && other.OrganizationId == in.OrganizationId) ||
+					(in.UserId.Valid && other.UserId == in.UserId)) {
+				err = repoerrors.ErrAlreadyExists
+				return
+			}
+		}
+		if (in.OrganizationId.Valid && !r.organizationExists(in.OrganizationId.UUID)) ||
+			(in.UserId.Valid && !r.employeeExists(in.UserId.UUID)) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+
+		inv = e.TenderInvitation{
+			Id:             uuid.New(),
+			TenderId:       in.TenderId,
+			OrganizationId: in.OrganizationId,
+			UserId:         in.UserId,
+			CreatedAt:      now(),
+		}
+		put(t, r.invitations, inv.Id, inv)
+	})
+	if err != nil {
+		return e.TenderInvitation{}, err
+	}
+
+	return inv, nil
+}
+
+func (r *InvitationRepo) Delete(ctx context.Context, tenderId, invitationId uuid.UUID) error {
+	var ok bool
+	r.exec(ctx, func(t *tx) {
+		if inv, found := r.invitations[invitationId]; found && inv.TenderId == tenderId {
+			remove(t, r.invitations, invitationId)
+			ok = true
+		}
+	})
+	if !ok {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *InvitationRepo) GetByTender(ctx context.Context, tenderId uuid.UUID) ([]e.TenderInvitation, error) {
+	invitations := []e.TenderInvitation{}
+	r.exec(ctx, func(*tx) {
+		for _, inv := range r.invitations {
+			if inv.TenderId == tenderId {
+				invitations = append(invitations, inv)
+			}
+		}
+	})
+
+	sort.Slice(invitations, func(i, j int) bool {
+		if !invitations[i].CreatedAt.Equal(invitations[j].CreatedAt) {
+			return invitations[i].CreatedAt.Before(invitations[j].CreatedAt)
+		}
+		return lessId(invitations[i].Id, invitations[j].Id)
+	})
+	return invitations, nil
+}
+
+// Employee is invited either personally or through any organization they are responsible for
+func (r *InvitationRepo) IsInvited(ctx context.Context, tenderId, userId uuid.UUID) (bool, error) {
+	var isInvited bool
+	r.exec(ctx, func(*tx) {
+		isInvited = r.isInvited(tenderId, userId)
+	})
+
+	return isInvited, nil
+}
@@ -0,0 +1,70 @@
+package memdb_test
+
+import (
+	"app/internal/repo"
+	"app/internal/repo/memdb"
+	"app/internal/repo/repoerrors"
+	"app/internal/repo/repotest"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T, test func(ctx context.Context, t *testing.T, repos *repo.Repositories)) {
+		store := memdb.NewStore()
+		store.Seed()
+		test(context.Background(), t, repo.NewMemoryRepo(store))
+	})
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	repos := repo.NewMemoryRepo(memdb.NewStore())
+	errFailed := errors.New("failed")
+
+	create := func(ctx context.Context, username string) {
+		t.Helper()
+		if _, err := repos.Employee.Create(ctx, rt.CreateEmployeeInput{Username: username}); err != nil {
+			t.Fatalf("Create %s: %v", username, err)
+		}
+	}
+	exists := func(username string) bool {
+		t.Helper()
+		_, err := repos.Employee.GetByUsername(ctx, username)
+		if err != nil && !errors.Is(err, repoerrors.ErrNotFound) {
+			t.Fatalf("GetByUsername %s: %v", username, err)
+		}
+		return err == nil
+	}
+
+	err := repos.Transactor.InTx(ctx, func(ctx context.Context) error {
+		create(ctx, "outer")
+		err := repos.Transactor.InTx(ctx, func(ctx context.Context) error {
+			create(ctx, "inner")
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("nested InTx err = %v, want %v", err, errFailed)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("InTx: %v", err)
+	}
+	if !exists("outer") || exists("inner") {
+		t.Errorf("after failed savepoint outer = %v, inner = %v, want true, false", exists("outer"), exists("inner"))
+	}
+
+	err = repos.Transactor.InTx(ctx, func(ctx context.Context) error {
+		create(ctx, "discarded")
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("InTx err = %v, want %v", err, errFailed)
+	}
+	if exists("discarded") {
+		t.Error("write of failed transaction was kept")
+	}
+}
@@ -0,0 +1,179 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepo struct {
+	*Store
+}
+
+func NewNotificationRepo(s *Store) *NotificationRepo {
+	return &NotificationRepo{s}
+}
+
+// Add is idempotent, employee gets one notification per event. Employee
+// responsible for several recipient organizations is notified for first of them
+func (r *NotificationRepo) Add(ctx context.Context, in rt.AddNotificationsInput) (int, error) {
+	var count int
+	r.exec(ctx, func(t *tx) {
+		recipients := map[uuid.UUID]uuid.NullUUID{}
+		for m := range r.responsibles {
+			if !slices.Contains(in.OrganizationIds, m.orgId) {
+				continue
+			}
+			if orgId, ok := recipients[m.userId]; !ok || lessId(m.orgId, orgId.UUID) {
+				recipients[m.userId] = uuid.NullUUID{UUID: m.orgId, Valid: true}
+			}
+		}
+		for _, id := range in.EmployeeIds {
+			if _, ok := recipients[id]; !ok && r.employeeExists(id) {
+				recipients[id] = uuid.NullUUID{}
+			}
+		}
+
+		for employeeId, orgId := range recipients {
+			if !r.employees[employeeId].IsActive || r.hasNotification(in.EventId, employeeId) {
+				continue
+			}
+
+			n := e.Notification{
+				Id:             uuid.New(),
+				EmployeeId:     employeeId,
+				EventId:        in.EventId,
+				Kind:           in.Kind,
+				OrganizationId: orgId,
+				TenderId:       in.TenderId,
+				BidId:          in.BidId,
+				Title:          in.Title,
+				CreatedAt:      now(),
+			}
+			put(t, r.notifications, n.Id, n)
+			count++
+		}
+	})
+
+	return count, nil
+}
+
+func (r *NotificationRepo) GetByEmployee(ctx context.Context, in rt.GetNotificationsInput) ([]e.Notification, error) {
+	notifications := []e.Notification{}
+	r.exec(ctx, func(*tx) {
+		for _, n := range r.notifications {
+			if n.EmployeeId != in.EmployeeId || (in.UnreadOnly && n.ReadAt != nil) {
+				continue
+			}
+			if r.notificationVisible(n) {
+				notifications = append(notifications, n)
+			}
+		}
+	})
+
+	sort.Slice(notifications, func(i, j int) bool {
+		if !notifications[i].CreatedAt.Equal(notifications[j].CreatedAt) {
+			return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+		}
+		return lessId(notifications[i].Id, notifications[j].Id)
+	})
+	notifications = page(notifications, in.Limit, in.Offset)
+	for i := range notifications {
+		notifications[i].ReadAt = cloneTime(notifications[i].ReadAt)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepo) CountUnread(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	var count int
+	r.exec(ctx, func(*tx) {
+		for _, n := range r.notifications {
+			if n.EmployeeId == employeeId && n.ReadAt == nil && r.notificationVisible(n) {
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+// MarkRead keeps time of first reading
+func (r *NotificationRepo) MarkRead(ctx context.Context, employeeId, id uuid.UUID) (e.Notification, error) {
+	var (
+		n  e.Notification
+		ok bool
+	)
+	r.exec(ctx, func(t *tx) {
+		n, ok = r.notifications[id]
+		if !ok || n.EmployeeId != employeeId || !r.notificationVisible(n) {
+			ok = false
+			return
+		}
+		if n.ReadAt == nil {
+			ts := now()
+			n.ReadAt = &ts
+			put(t, r.notifications, n.Id, n)
+		}
+	})
+	if !ok {
+		return e.Notification{}, repoerrors.ErrNotFound
+	}
+
+	n.ReadAt = cloneTime(n.ReadAt)
+	return n, nil
+}
+
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	var count int
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		for _, n := range r.notifications {
+			if n.EmployeeId != employeeId || n.ReadAt != nil || !r.notificationVisible(n) {
+				continue
+			}
+			readAt := ts
+			n.ReadAt = &readAt
+			put(t, r.notifications, n.Id, n)
+			count++
+		}
+	})
+
+	return count, nil
+}
+
+func (r *NotificationRepo) DeleteOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	var count int
+	r.exec(ctx, func(t *tx) {
+		before := now().Add(-age)
+		for id, n := range r.notifications {
+			if n.CreatedAt.Before(before) {
+				remove(t, r.notifications, id)
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+// hasNotification must be called with tables locked
+func (s *Store) hasNotification(eventId, employeeId uuid.UUID) bool {
+	for _, n := range s.notifications {
+		if n.EventId == eventId && n.EmployeeId == employeeId {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationVisible hides notifications for organizations employee is no
+// longer responsible for, must be called with tables locked
+func (s *Store) notificationVisible(n e.Notification) bool {
+	return !n.OrganizationId.Valid || s.isResponsible(n.OrganizationId.UUID, n.EmployeeId)
+}
@@ -0,0 +1,266 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// organizationRoles are values of organization_role_type in enum order, roles
+// are kept and returned in it
+var organizationRoles = []string{"viewer", "editor", "publisher", "approver", "admin"}
+
+type OrganizationRepo struct {
+	*Store
+}
+
+func NewOrganizationRepo(s *Store) *OrganizationRepo {
+	return &OrganizationRepo{s}
+}
+
+// Create inserts organization and makes ResponsibleId its first responsible
+// with all roles
+func (r *OrganizationRepo) Create(ctx context.Context, in rt.CreateOrganizationInput) (e.Organization, error) {
+	var (
+		org e.Organization
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		if !r.employeeExists(in.ResponsibleId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+
+		ts := now()
+		org = e.Organization{
+			Id:          uuid.New(),
+			Name:        in.Name,
+			Description: in.Description,
+			Type:        in.Type,
+			CreatedAt:   ts,
+			UpdatedAt:   ts,
+		}
+		put(t, r.organizations, org.Id, org)
+		put(t, r.responsibles, membership{org.Id, in.ResponsibleId}, slices.Clone(organizationRoles))
+	})
+	if err != nil {
+		return e.Organization{}, err
+	}
+
+	return org, nil
+}
+
+func (r *OrganizationRepo) Get(ctx context.Context, id uuid.UUID) (e.Organization, error) {
+	var (
+		org e.Organization
+		ok  bool
+	)
+	r.exec(ctx, func(*tx) {
+		org, ok = r.organizations[id]
+	})
+	if !ok {
+		return e.Organization{}, repoerrors.ErrNotFound
+	}
+
+	return org, nil
+}
+
+func (r *OrganizationRepo) Update(ctx context.Context, in rt.UpdateOrganizationInput) (e.Organization, error) {
+	var (
+		org e.Organization
+		ok  bool
+	)
+	r.exec(ctx, func(t *tx) {
+		if org, ok = r.organizations[in.Id]; !ok {
+			return
+		}
+		org.Name = in.Name
+		org.Description = in.Description
+		org.Type = in.Type
+		org.UpdatedAt = now()
+		put(t, r.organizations, org.Id, org)
+	})
+	if !ok {
+		return e.Organization{}, repoerrors.ErrNotFound
+	}
+
+	return org, nil
+}
+
+// Delete removes organization with rows referencing it, like ON DELETE CASCADE does
+func (r *OrganizationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	var ok bool
+	r.exec(ctx, func(t *tx) {
+		if _, ok = r.organizations[id]; !ok {
+			return
+		}
+		remove(t, r.organizations, id)
+
+		for key, tender := range r.tenders {
+			if tender.OrganizationId == id {
+				remove(t, r.tenders, key)
+			}
+		}
+		for key, bid := range r.bids {
+			if bid.OrganizationId.Valid && bid.OrganizationId.UUID == id {
+				remove(t, r.bids, key)
+			}
+		}
+		for m := range r.responsibles {
+			if m.orgId == id {
+				remove(t, r.responsibles, m)
+			}
+		}
+		for invId, inv := range r.invitations {
+			if inv.OrganizationId.Valid && inv.OrganizationId.UUID == id {
+				remove(t, r.invitations, invId)
+			}
+		}
+		for keyId, key := range r.apiKeys {
+			if key.OrganizationId == id {
+				remove(t, r.apiKeys, keyId)
+			}
+		}
+		for subId, sub := range r.webhooks {
+			if sub.OrganizationId == id {
+				r.removeWebhook(t, subId)
+			}
+		}
+		for nId, n := range r.notifications {
+			if n.OrganizationId.Valid && n.OrganizationId.UUID == id {
+				remove(t, r.notifications, nId)
+			}
+		}
+	})
+	if !ok {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) Search(ctx context.Context, in rt.SearchOrganizationsInput) ([]e.Organization, error) {
+	orgs := []e.Organization{}
+	r.exec(ctx, func(*tx) {
+		for _, org := range r.organizations {
+			if in.Type != "" && org.Type != in.Type {
+				continue
+			}
+			if matches(in.Query, org.Name, org.Description) {
+				orgs = append(orgs, org)
+			}
+		}
+	})
+
+	sort.Slice(orgs, func(i, j int) bool {
+		if orgs[i].Name != orgs[j].Name {
+			return orgs[i].Name < orgs[j].Name
+		}
+		return lessId(orgs[i].Id, orgs[j].Id)
+	})
+	return page(orgs, in.Limit, in.Offset), nil
+}
+
+// AddResponsible assigns responsible with viewer role
+func (r *OrganizationRepo) AddResponsible(ctx context.Context, orgId, userId uuid.UUID) error {
+	var err error
+	r.exec(ctx, func(t *tx) {
+		if r.isResponsible(orgId, userId) {
+			err = repoerrors.ErrAlreadyExists
+			return
+		}
+		if !r.organizationExists(orgId) || !r.employeeExists(userId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+		put(t, r.responsibles, membership{orgId, userId}, []string{"viewer"})
+	})
+
+	return err
+}
+
+func (r *OrganizationRepo) RemoveResponsible(ctx context.Context, orgId, userId uuid.UUID) error {
+	var ok bool
+	r.exec(ctx, func(t *tx) {
+		if ok = r.isResponsible(orgId, userId); ok {
+			remove(t, r.responsibles, membership{orgId, userId})
+		}
+	})
+	if !ok {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) GetResponsibles(ctx context.Context, orgId uuid.UUID) ([]e.Employee, error) {
+	var employees []e.Employee
+	r.exec(ctx, func(*tx) {
+		for m := range r.responsibles {
+			if emp, ok := r.employees[m.userId]; ok && m.orgId == orgId {
+				employees = append(employees, emp)
+			}
+		}
+	})
+
+	sortEmployees(employees)
+	return cloneEmployees(employees), nil
+}
+
+// SetRoles replaces roles of organization responsible
+func (r *OrganizationRepo) SetRoles(ctx context.Context, orgId, userId uuid.UUID, roles []string) error {
+	var err error
+	r.exec(ctx, func(t *tx) {
+		if !r.isResponsible(orgId, userId) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+
+		set := []string{}
+		for _, role := range organizationRoles {
+			if slices.Contains(roles, role) {
+				set = append(set, role)
+			}
+		}
+		put(t, r.responsibles, membership{orgId, userId}, set)
+	})
+
+	return err
+}
+
+// GetRoleHolders returns ids of organization responsibles having role
+func (r *OrganizationRepo) GetRoleHolders(ctx context.Context, orgId uuid.UUID, role string) ([]uuid.UUID, error) {
+	userIds := []uuid.UUID{}
+	r.exec(ctx, func(*tx) {
+		for m, roles := range r.responsibles {
+			if m.orgId == orgId && slices.Contains(roles, role) {
+				userIds = append(userIds, m.userId)
+			}
+		}
+	})
+
+	sortIds(userIds)
+	return userIds, nil
+}
+
+// organizationExists must be called with tables locked
+func (s *Store) organizationExists(id uuid.UUID) bool {
+	_, ok := s.organizations[id]
+	return ok
+}
+
+// employeeExists must be called with tables locked
+func (s *Store) employeeExists(id uuid.UUID) bool {
+	_, ok := s.employees[id]
+	return ok
+}
+
+// LockResponsibles does nothing, transactions of store run one at a time
+func (r *OrganizationRepo) LockResponsibles(ctx context.Context, orgId uuid.UUID) error {
+	return nil
+}
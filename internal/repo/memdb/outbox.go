@@ -0,0 +1,133 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type outboxDeliveryKey struct {
+	eventId uuid.UUID
+	sink    string
+}
+
+type outboxDeliveryRow struct {
+	attempts  int
+	lastError string
+	delivered bool
+}
+
+type OutboxRepo struct {
+	*Store
+}
+
+func NewOutboxRepo(s *Store) *OutboxRepo {
+	return &OutboxRepo{s}
+}
+
+// Add writes event in transaction of context, so it is stored only with the change
+func (r *OutboxRepo) Add(ctx context.Context, in rt.CreateOutboxEventInput) error {
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		event := e.OutboxEvent{
+			Id:             uuid.New(),
+			Type:           in.Type,
+			AggregateType:  in.AggregateType,
+			AggregateId:    in.AggregateId,
+			OrganizationId: in.OrganizationId,
+			Payload:        bytes.Clone(in.Payload),
+			NextAttemptAt:  ts,
+			CreatedAt:      ts,
+		}
+		put(t, r.outboxEvents, event.Id, event)
+	})
+
+	return nil
+}
+
+// Claim returns due unprocessed events and postpones them by lease
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]e.OutboxEvent, error) {
+	events := []e.OutboxEvent{}
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		for _, event := range r.outboxEvents {
+			if event.ProcessedAt == nil && !event.NextAttemptAt.After(ts) {
+				events = append(events, event)
+			}
+		}
+
+		sort.Slice(events, func(i, j int) bool {
+			if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+				return events[i].CreatedAt.Before(events[j].CreatedAt)
+			}
+			return lessId(events[i].Id, events[j].Id)
+		})
+		events = page(events, limit, 0)
+		for i := range events {
+			events[i].NextAttemptAt = ts.Add(lease)
+			put(t, r.outboxEvents, events[i].Id, events[i])
+			events[i].Payload = bytes.Clone(events[i].Payload)
+		}
+	})
+
+	return events, nil
+}
+
+// GetDeliveredSinks returns names of sinks event was delivered to
+func (r *OutboxRepo) GetDeliveredSinks(ctx context.Context, eventId uuid.UUID) ([]string, error) {
+	sinks := []string{}
+	r.exec(ctx, func(*tx) {
+		for key, d := range r.outboxDelivery {
+			if key.eventId == eventId && d.delivered {
+				sinks = append(sinks, key.sink)
+			}
+		}
+	})
+
+	return sinks, nil
+}
+
+// RecordDelivery stores attempt of delivering event to sink, empty lastError means success
+func (r *OutboxRepo) RecordDelivery(ctx context.Context, eventId uuid.UUID, sink, lastError string) error {
+	r.exec(ctx, func(t *tx) {
+		key := outboxDeliveryKey{eventId, sink}
+		d := r.outboxDelivery[key]
+		d.attempts++
+		d.lastError = lastError
+		d.delivered = lastError == ""
+		put(t, r.outboxDelivery, key, d)
+	})
+
+	return nil
+}
+
+func (r *OutboxRepo) MarkProcessed(ctx context.Context, eventId uuid.UUID) error {
+	r.exec(ctx, func(t *tx) {
+		if event, ok := r.outboxEvents[eventId]; ok {
+			processedAt := now()
+			event.ProcessedAt = &processedAt
+			event.Attempts++
+			put(t, r.outboxEvents, eventId, event)
+		}
+	})
+
+	return nil
+}
+
+// Reschedule postpones event after failed delivery
+func (r *OutboxRepo) Reschedule(ctx context.Context, eventId uuid.UUID, delay time.Duration) error {
+	r.exec(ctx, func(t *tx) {
+		if event, ok := r.outboxEvents[eventId]; ok {
+			event.Attempts++
+			event.NextAttemptAt = now().Add(delay)
+			put(t, r.outboxEvents, eventId, event)
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"time"
+)
+
+type RateLimitRepo struct {
+	*Store
+}
+
+func NewRateLimitRepo(s *Store) *RateLimitRepo {
+	return &RateLimitRepo{s}
+}
+
+// Take refills bucket for time passed since last request and takes token if
+// there is one
+func (r *RateLimitRepo) Take(ctx context.Context, in rt.TakeRateLimitTokenInput) (e.RateLimitBucket, error) {
+	var b e.RateLimitBucket
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		old, ok := r.rateLimits[in.Key]
+		if !ok {
+			b = e.RateLimitBucket{Key: in.Key, Tokens: float64(in.Capacity) - 1, Allowed: true, UpdatedAt: ts}
+			put(t, r.rateLimits, in.Key, b)
+			return
+		}
+
+		refilled := min(float64(in.Capacity), old.Tokens+ts.Sub(old.UpdatedAt).Seconds()*in.Rate)
+		b = e.RateLimitBucket{Key: in.Key, Tokens: refilled, Allowed: refilled >= 1, UpdatedAt: ts}
+		if b.Allowed {
+			b.Tokens--
+		}
+		put(t, r.rateLimits, in.Key, b)
+	})
+
+	return b, nil
+}
+
+// DeleteIdle deletes buckets not used for age, they are full by then
+func (r *RateLimitRepo) DeleteIdle(ctx context.Context, age time.Duration) (int, error) {
+	var count int
+	r.exec(ctx, func(t *tx) {
+		before := now().Add(-age)
+		for key, b := range r.rateLimits {
+			if b.UpdatedAt.Before(before) {
+				remove(t, r.rateLimits, key)
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
@@ -0,0 +1,56 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seedTime is creation time of rows inserted by initial migration
+var seedTime = time.Date(2024, time.September, 9, 17, 47, 19, 920110000, time.UTC)
+
+// Seed fills store with employees, organizations and responsibles inserted by
+// initial migration, so memory backend starts with the same data as database
+func (s *Store) Seed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 1; i <= 30; i++ {
+		emp := e.Employee{
+			Id:        seedId(i),
+			Username:  fmt.Sprintf("user%d", i),
+			FirstName: fmt.Sprintf("First%d", i),
+			LastName:  fmt.Sprintf("Last%d", i),
+			IsActive:  true,
+			// First administrator as in employee_management migration
+			IsAdmin:   i == 1,
+			CreatedAt: seedTime,
+			UpdatedAt: seedTime,
+		}
+		s.employees[emp.Id] = emp
+	}
+
+	for i, orgType := range []string{"LLC", "IE", "JSC", "LLC"} {
+		org := e.Organization{
+			Id:          seedId(0x20 + i),
+			Name:        fmt.Sprintf("Organization %d", i+1),
+			Description: fmt.Sprintf("Description %d", i+1),
+			Type:        orgType,
+			CreatedAt:   seedTime,
+			UpdatedAt:   seedTime,
+		}
+		s.organizations[org.Id] = org
+
+		// Each organization has three responsibles with all roles
+		for j := 1; j <= 3; j++ {
+			s.responsibles[membership{org.Id, seedId(3*i + j)}] = slices.Clone(organizationRoles)
+		}
+	}
+}
+
+func seedId(n int) uuid.UUID {
+	return uuid.MustParse(fmt.Sprintf("550e8400-e29b-41d4-a716-4466554400%02x", n))
+}
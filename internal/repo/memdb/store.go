@@ -0,0 +1,227 @@
+// Package memdb keeps repositories in process memory, so the server can run
+// without database. Versioning and constraints follow pgdb, transactions are
+// run one at a time and their writes are undone if they fail
+package memdb
+
+import (
+	e "app/internal/entity"
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store holds tables of repositories created on it and runs transactions
+type Store struct {
+	// Held by transaction until it ends, calls outside transaction wait for it
+	txMu sync.Mutex
+	// Guards tables
+	mu sync.Mutex
+
+	tenders         map[versionKey]e.Tender
+	bids            map[versionKey]e.Bid
+	employees       map[uuid.UUID]e.Employee
+	organizations   map[uuid.UUID]e.Organization
+	responsibles    map[membership][]string
+	invitations     map[uuid.UUID]e.TenderInvitation
+	reminders       map[reminderKey]bool
+	credentials     map[uuid.UUID]e.Credential
+	resetTokens     map[string]resetTokenRow
+	loginAttempts   []e.LoginAttempt
+	apiKeys         map[uuid.UUID]e.APIKey
+	auditLog        []e.AuditEntry
+	outboxEvents    map[uuid.UUID]e.OutboxEvent
+	outboxDelivery  map[outboxDeliveryKey]outboxDeliveryRow
+	webhooks        map[uuid.UUID]e.WebhookSubscription
+	webhookDelivery map[uuid.UUID]e.WebhookDelivery
+	emailPrefs      map[uuid.UUID][]string
+	emailMessages   map[uuid.UUID]e.EmailMessage
+	notifications   map[uuid.UUID]e.Notification
+	idempotencyKeys map[idempotencyKey]e.IdempotencyKey
+	rateLimits      map[string]e.RateLimitBucket
+}
+
+// versionKey is primary key of tender and bid
+type versionKey struct {
+	id      uuid.UUID
+	version int
+}
+
+// membership is responsible of organization, roles are stored under it
+type membership struct {
+	orgId  uuid.UUID
+	userId uuid.UUID
+}
+
+func NewStore() *Store {
+	return &Store{
+		tenders:         map[versionKey]e.Tender{},
+		bids:            map[versionKey]e.Bid{},
+		employees:       map[uuid.UUID]e.Employee{},
+		organizations:   map[uuid.UUID]e.Organization{},
+		responsibles:    map[membership][]string{},
+		invitations:     map[uuid.UUID]e.TenderInvitation{},
+		reminders:       map[reminderKey]bool{},
+		credentials:     map[uuid.UUID]e.Credential{},
+		resetTokens:     map[string]resetTokenRow{},
+		apiKeys:         map[uuid.UUID]e.APIKey{},
+		outboxEvents:    map[uuid.UUID]e.OutboxEvent{},
+		outboxDelivery:  map[outboxDeliveryKey]outboxDeliveryRow{},
+		webhooks:        map[uuid.UUID]e.WebhookSubscription{},
+		webhookDelivery: map[uuid.UUID]e.WebhookDelivery{},
+		emailPrefs:      map[uuid.UUID][]string{},
+		emailMessages:   map[uuid.UUID]e.EmailMessage{},
+		notifications:   map[uuid.UUID]e.Notification{},
+		idempotencyKeys: map[idempotencyKey]e.IdempotencyKey{},
+		rateLimits:      map[string]e.RateLimitBucket{},
+	}
+}
+
+type txKey struct{}
+
+// tx collects undo of writes made in transaction, latest last
+type tx struct {
+	undo []func()
+}
+
+// onRollback registers undo of write, no-op outside transaction
+func (t *tx) onRollback(undo func()) {
+	if t != nil {
+		t.undo = append(t.undo, undo)
+	}
+}
+
+// exec runs fn with tables locked. Call outside transaction waits for running
+// one to end, so every call sees committed state only
+func (s *Store) exec(ctx context.Context, fn func(t *tx)) {
+	t, ok := ctx.Value(txKey{}).(*tx)
+	if !ok {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(t)
+}
+
+// InTx runs fn in transaction, repositories called with fn context join it.
+// Nested call undoes only its own writes on failure, like savepoint does.
+// Error returned by fn is passed as is
+func (s *Store) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if outer, ok := ctx.Value(txKey{}).(*tx); ok {
+		s.mu.Lock()
+		mark := len(outer.undo)
+		s.mu.Unlock()
+
+		return s.run(ctx, outer, mark, fn)
+	}
+
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	t := &tx{}
+	return s.run(context.WithValue(ctx, txKey{}, t), t, 0, fn)
+}
+
+// InTxRetry is InTx, transactions run one at a time never conflict
+func (s *Store) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.InTx(ctx, fn)
+}
+
+// run undoes writes made after mark if fn fails or panics
+func (s *Store) run(ctx context.Context, t *tx, mark int, fn func(ctx context.Context) error) (err error) {
+	done := false
+	defer func() {
+		if !done || err != nil {
+			s.rollback(t, mark)
+		}
+	}()
+
+	err = fn(ctx)
+	done = true
+	return err
+}
+
+func (s *Store) rollback(t *tx, mark int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(t.undo) - 1; i >= mark; i-- {
+		t.undo[i]()
+	}
+	t.undo = t.undo[:mark]
+}
+
+// put sets row of table and registers restoring of its previous state
+func put[K comparable, V any](t *tx, table map[K]V, key K, row V) {
+	old, existed := table[key]
+	table[key] = row
+	t.onRollback(func() {
+		if existed {
+			table[key] = old
+		} else {
+			delete(table, key)
+		}
+	})
+}
+
+// remove deletes row of table and registers restoring of it
+func remove[K comparable, V any](t *tx, table map[K]V, key K) {
+	old, existed := table[key]
+	if !existed {
+		return
+	}
+	delete(table, key)
+	t.onRollback(func() { table[key] = old })
+}
+
+// now is current time with precision of Postgres timestamp
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// page applies LIMIT and OFFSET, limit <= 0 means no limit
+func page[T any](items []T, limit, offset int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// lessId orders ids like Postgres orders uuid values
+func lessId(a, b uuid.UUID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
+
+func sortIds(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return lessId(ids[i], ids[j])
+	})
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := t.UTC().Truncate(time.Microsecond)
+	return &c
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
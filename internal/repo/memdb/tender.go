@@ -0,0 +1,351 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// reminderKey is deadline of tender reminded about
+type reminderKey struct {
+	tenderId uuid.UUID
+	deadline time.Time
+}
+
+type TenderRepo struct {
+	*Store
+}
+
+func NewTenderRepo(s *Store) *TenderRepo {
+	return &TenderRepo{s}
+}
+
+func (r *TenderRepo) CreateTender(ctx context.Context, in rt.CreateTenderInput) (e.Tender, error) {
+	var (
+		tender e.Tender
+		err    error
+	)
+	r.exec(ctx, func(t *tx) {
+		if !r.organizationExists(in.OrganizationId) {
+			err = fmt.Errorf("memdb - CreateTender: %w", repoerrors.ErrInvalidReference)
+			return
+		}
+
+		ts := now()
+		tender = e.Tender{
+			Id:              uuid.New(),
+			Name:            in.Name,
+			Description:     in.Description,
+			Type:            in.ServiceType,
+			Status:          "Created",
+			Visibility:      in.Visibility,
+			OrganizationId:  in.OrganizationId,
+			Version:         1,
+			CreatorUsername: in.CreatorUsername,
+			Deadline:        cloneTime(in.Deadline),
+			CreatedAt:       ts,
+			UpdatedAt:       ts,
+		}
+		put(t, r.tenders, versionKey{tender.Id, tender.Version}, tender)
+	})
+	if err != nil {
+		return e.Tender{}, err
+	}
+
+	return cloneTender(tender), nil
+}
+
+func (r *TenderRepo) GetTendersByUsername(ctx context.Context, in rt.GetByUsernameInput) ([]e.Tender, error) {
+	var tenders []e.Tender
+	r.exec(ctx, func(*tx) {
+		tenders = r.lastVersions(func(t e.Tender) bool {
+			return t.CreatorUsername == in.Username
+		})
+	})
+
+	sortTenders(tenders)
+	return cloneTenders(page(tenders, in.Limit, in.Offset)), nil
+}
+
+// GetPublishedTenders follows visibility rules of pgdb.publishedTendersQuery
+func (r *TenderRepo) GetPublishedTenders(ctx context.Context, in rt.GetPublishedTendersInput) ([]e.Tender, error) {
+	var tenders []e.Tender
+	r.exec(ctx, func(*tx) {
+		tenders = r.lastVersions(nil)
+		visible := tenders[:0]
+		for _, t := range tenders {
+			if t.Status != "Published" {
+				continue
+			}
+			if len(in.ServiceType) > 0 && !slices.Contains(in.ServiceType, t.Type) {
+				continue
+			}
+			if t.Visibility == "Public" ||
+				r.isResponsible(t.OrganizationId, in.ViewerId) ||
+				(t.Visibility == "InviteOnly" && r.isInvited(t.Id, in.ViewerId)) {
+				visible = append(visible, t)
+			}
+		}
+		tenders = visible
+	})
+
+	sortTenders(tenders)
+	return cloneTenders(page(tenders, in.Limit, in.Offset)), nil
+}
+
+func (r *TenderRepo) GetInvitedTenders(ctx context.Context, in rt.GetInvitedTendersInput) ([]e.Tender, error) {
+	var tenders []e.Tender
+	r.exec(ctx, func(*tx) {
+		tenders = r.lastVersions(nil)
+		invited := tenders[:0]
+		for _, t := range tenders {
+			if t.Status == "Published" && t.Visibility != "Internal" && r.isInvited(t.Id, in.UserId) {
+				invited = append(invited, t)
+			}
+		}
+		tenders = invited
+	})
+
+	sortTenders(tenders)
+	return cloneTenders(page(tenders, in.Limit, in.Offset)), nil
+}
+
+// use repotypes.VersionLatest if need latest version
+func (r *TenderRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Tender, error) {
+	var (
+		tender e.Tender
+		ok     bool
+	)
+	r.exec(ctx, func(*tx) {
+		if version == rt.VersionLatest {
+			tender, ok = r.latestTender(id)
+		} else {
+			tender, ok = r.tenders[versionKey{id, version}]
+		}
+	})
+	if !ok {
+		return e.Tender{}, repoerrors.ErrNotFound
+	}
+
+	return cloneTender(tender), nil
+}
+
+func (r *TenderRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string) (e.Tender, error) {
+	return r.updateLatest(ctx, id, func(t *e.Tender) {
+		t.Status = status
+	})
+}
+
+func (r *TenderRepo) ChangeVisibility(ctx context.Context, id uuid.UUID, visibility string) (e.Tender, error) {
+	return r.updateLatest(ctx, id, func(t *e.Tender) {
+		t.Visibility = visibility
+	})
+}
+
+func (r *TenderRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedInput) (e.Tender, error) {
+	var (
+		tender e.Tender
+		err    error
+	)
+	r.exec(ctx, func(t *tx) {
+		key := versionKey{in.Id, in.Version}
+		// Version was created by concurrent write
+		if _, ok := r.tenders[key]; ok {
+			err = repoerrors.ErrVersionConflict
+			return
+		}
+		if !r.organizationExists(in.OrganizationId) {
+			err = fmt.Errorf("memdb - TenderRepo.CreateSpecified: %w", repoerrors.ErrInvalidReference)
+			return
+		}
+
+		ts := now()
+		tender = e.Tender{
+			Id:              in.Id,
+			Name:            in.Name,
+			Description:     in.Description,
+			Type:            in.ServiceType,
+			Status:          in.Status,
+			Visibility:      in.Visibility,
+			OrganizationId:  in.OrganizationId,
+			Version:         in.Version,
+			CreatorUsername: in.CreatorUsername,
+			Deadline:        cloneTime(in.Deadline),
+			CreatedAt:       ts,
+			UpdatedAt:       ts,
+		}
+		put(t, r.tenders, key, tender)
+	})
+	if err != nil {
+		return e.Tender{}, err
+	}
+
+	return cloneTender(tender), nil
+}
+
+func (r *TenderRepo) GetLatestVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	var (
+		tender e.Tender
+		ok     bool
+	)
+	r.exec(ctx, func(*tx) {
+		tender, ok = r.latestTender(id)
+	})
+	if !ok {
+		return 0, fmt.Errorf("memdb - TenderRepo.GetLatestVersion: %w", repoerrors.ErrNotFound)
+	}
+
+	return tender.Version, nil
+}
+
+// ClaimDeadlineReminders returns published tenders with deadline within window
+// which weren't reminded about yet and marks them reminded
+func (r *TenderRepo) ClaimDeadlineReminders(ctx context.Context, window time.Duration) ([]e.Tender, error) {
+	var claimed []e.Tender
+	r.exec(ctx, func(t *tx) {
+		ts := now()
+		for _, tender := range r.lastVersions(nil) {
+			if tender.Status != "Published" || tender.Deadline == nil {
+				continue
+			}
+			if !tender.Deadline.After(ts) || tender.Deadline.After(ts.Add(window)) {
+				continue
+			}
+			key := reminderKey{tender.Id, *tender.Deadline}
+			if r.reminders[key] {
+				continue
+			}
+			put(t, r.reminders, key, true)
+			claimed = append(claimed, tender)
+		}
+	})
+
+	sort.SliceStable(claimed, func(i, j int) bool {
+		return claimed[i].Deadline.Before(*claimed[j].Deadline)
+	})
+	return cloneTenders(claimed), nil
+}
+
+// Lock checks that version is latest and wasn't changed after updatedAt.
+// Transactions don't run concurrently, so there is nothing else to lock
+func (r *TenderRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	var err error
+	r.exec(ctx, func(*tx) {
+		tender, ok := r.tenders[versionKey{id, version}]
+		if !ok || !tender.UpdatedAt.Equal(updatedAt) {
+			err = repoerrors.ErrVersionConflict
+			return
+		}
+		if latest, _ := r.latestTender(id); latest.Version != version {
+			err = repoerrors.ErrVersionConflict
+		}
+	})
+
+	return err
+}
+
+// GetForUpdate returns latest version of tender, see Lock
+func (r *TenderRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Tender, error) {
+	return r.Get(ctx, id, rt.VersionLatest)
+}
+
+func (r *TenderRepo) updateLatest(ctx context.Context, id uuid.UUID, update func(t *e.Tender)) (e.Tender, error) {
+	var (
+		tender e.Tender
+		ok     bool
+	)
+	r.exec(ctx, func(t *tx) {
+		tender, ok = r.latestTender(id)
+		if !ok {
+			return
+		}
+		update(&tender)
+		tender.UpdatedAt = now()
+		put(t, r.tenders, versionKey{tender.Id, tender.Version}, tender)
+	})
+	if !ok {
+		return e.Tender{}, repoerrors.ErrNotFound
+	}
+
+	return cloneTender(tender), nil
+}
+
+// latestTender must be called with tables locked
+func (s *Store) latestTender(id uuid.UUID) (e.Tender, bool) {
+	var (
+		latest e.Tender
+		found  bool
+	)
+	for key, t := range s.tenders {
+		if key.id == id && (!found || key.version > latest.Version) {
+			latest, found = t, true
+		}
+	}
+	return latest, found
+}
+
+// lastVersions returns latest version of each tender among versions matching
+// filter, nil filter matches all. Must be called with tables locked
+func (s *Store) lastVersions(filter func(t e.Tender) bool) []e.Tender {
+	latest := map[uuid.UUID]e.Tender{}
+	for key, t := range s.tenders {
+		if filter != nil && !filter(t) {
+			continue
+		}
+		if l, ok := latest[key.id]; !ok || key.version > l.Version {
+			latest[key.id] = t
+		}
+	}
+
+	tenders := make([]e.Tender, 0, len(latest))
+	for _, t := range latest {
+		tenders = append(tenders, t)
+	}
+	return tenders
+}
+
+// isInvited must be called with tables locked, see InvitationRepo.IsInvited
+func (s *Store) isInvited(tenderId, userId uuid.UUID) bool {
+	for _, inv := range s.invitations {
+		if inv.TenderId != tenderId {
+			continue
+		}
+		if inv.UserId.Valid && inv.UserId.UUID == userId {
+			return true
+		}
+		if inv.OrganizationId.Valid && s.isResponsible(inv.OrganizationId.UUID, userId) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTenders orders by name, id makes order of namesakes stable
+func sortTenders(tenders []e.Tender) {
+	sort.Slice(tenders, func(i, j int) bool {
+		if tenders[i].Name != tenders[j].Name {
+			return tenders[i].Name < tenders[j].Name
+		}
+		return lessId(tenders[i].Id, tenders[j].Id)
+	})
+}
+
+func cloneTender(t e.Tender) e.Tender {
+	t.Deadline = cloneTime(t.Deadline)
+	return t
+}
+
+func cloneTenders(tenders []e.Tender) []e.Tender {
+	cloned := make([]e.Tender, 0, len(tenders))
+	for _, t := range tenders {
+		cloned = append(cloned, cloneTender(t))
+	}
+	return cloned
+}
@@ -0,0 +1,270 @@
+package memdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepo struct {
+	*Store
+}
+
+func NewWebhookRepo(s *Store) *WebhookRepo {
+	return &WebhookRepo{s}
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, in rt.CreateWebhookInput) (e.WebhookSubscription, error) {
+	var (
+		sub e.WebhookSubscription
+		err error
+	)
+	r.exec(ctx, func(t *tx) {
+		if !r.organizationExists(in.OrganizationId) || !r.employeeExists(in.CreatedBy) {
+			err = repoerrors.ErrInvalidReference
+			return
+		}
+
+		sub = e.WebhookSubscription{
+			Id:             uuid.New(),
+			OrganizationId: in.OrganizationId,
+			URL:            in.URL,
+			EventTypes:     slices.Clone(in.EventTypes),
+			Secret:         in.Secret,
+			CreatedBy:      uuid.NullUUID{UUID: in.CreatedBy, Valid: true},
+			CreatedAt:      now(),
+		}
+		put(t, r.webhooks, sub.Id, sub)
+	})
+	if err != nil {
+		return e.WebhookSubscription{}, err
+	}
+
+	return cloneWebhook(sub), nil
+}
+
+func (r *WebhookRepo) Get(ctx context.Context, orgId, id uuid.UUID) (e.WebhookSubscription, error) {
+	var (
+		sub e.WebhookSubscription
+		ok  bool
+	)
+	r.exec(ctx, func(*tx) {
+		sub, ok = r.webhooks[id]
+	})
+	if !ok || sub.OrganizationId != orgId {
+		return e.WebhookSubscription{}, repoerrors.ErrNotFound
+	}
+
+	return cloneWebhook(sub), nil
+}
+
+func (r *WebhookRepo) GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.WebhookSubscription, error) {
+	subs := []e.WebhookSubscription{}
+	r.exec(ctx, func(*tx) {
+		for _, sub := range r.webhooks {
+			if sub.OrganizationId == orgId {
+				subs = append(subs, cloneWebhook(sub))
+			}
+		}
+	})
+
+	sort.Slice(subs, func(i, j int) bool {
+		if !subs[i].CreatedAt.Equal(subs[j].CreatedAt) {
+			return subs[i].CreatedAt.After(subs[j].CreatedAt)
+		}
+		return lessId(subs[i].Id, subs[j].Id)
+	})
+	return subs, nil
+}
+
+// Delete removes subscription with its delivery log
+func (r *WebhookRepo) Delete(ctx context.Context, orgId, id uuid.UUID) error {
+	var ok bool
+	r.exec(ctx, func(t *tx) {
+		if sub, found := r.webhooks[id]; found && sub.OrganizationId == orgId {
+			r.removeWebhook(t, id)
+			ok = true
+		}
+	})
+	if !ok {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// AddDeliveries is idempotent, event is added to each subscription once
+func (r *WebhookRepo) AddDeliveries(ctx context.Context, in rt.AddWebhookDeliveriesInput) error {
+	r.exec(ctx, func(t *tx) {
+		for _, sub := range r.webhooks {
+			if !slices.Contains(in.OrganizationIds, sub.OrganizationId) || !slices.Contains(sub.EventTypes, in.EventType) {
+				continue
+			}
+			if r.hasDelivery(sub.Id, in.EventId) {
+				continue
+			}
+
+			ts := now()
+			d := e.WebhookDelivery{
+				Id:             uuid.New(),
+				SubscriptionId: sub.Id,
+				EventId:        in.EventId,
+				EventType:      in.EventType,
+				Payload:        bytes.Clone(in.Payload),
+				Status:         "Pending",
+				NextAttemptAt:  ts,
+				CreatedAt:      ts,
+				UpdatedAt:      ts,
+			}
+			put(t, r.webhookDelivery, d.Id, d)
+		}
+	})
+
+	return nil
+}
+
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, in rt.GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error) {
+	deliveries := []e.WebhookDelivery{}
+	r.exec(ctx, func(*tx) {
+		for _, d := range r.webhookDelivery {
+			if d.SubscriptionId == in.SubscriptionId && (in.Status == "" || d.Status == in.Status) {
+				deliveries = append(deliveries, d)
+			}
+		}
+	})
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return lessId(deliveries[i].Id, deliveries[j].Id)
+	})
+	deliveries = page(deliveries, in.Limit, in.Offset)
+	for i := range deliveries {
+		deliveries[i] = cloneDelivery(deliveries[i])
+	}
+	return deliveries, nil
+}
+
+// GetPending returns due deliveries with targets of their subscriptions
+func (r *WebhookRepo) GetPending(ctx context.Context, limit int) ([]e.WebhookDispatch, error) {
+	dispatches := []e.WebhookDispatch{}
+	r.exec(ctx, func(*tx) {
+		ts := now()
+		for _, d := range r.webhookDelivery {
+			if d.Status != "Pending" || d.NextAttemptAt.After(ts) {
+				continue
+			}
+			sub := r.webhooks[d.SubscriptionId]
+			dispatches = append(dispatches, e.WebhookDispatch{
+				WebhookDelivery: cloneDelivery(d),
+				URL:             sub.URL,
+				Secret:          sub.Secret,
+			})
+		}
+	})
+
+	sort.Slice(dispatches, func(i, j int) bool {
+		if !dispatches[i].NextAttemptAt.Equal(dispatches[j].NextAttemptAt) {
+			return dispatches[i].NextAttemptAt.Before(dispatches[j].NextAttemptAt)
+		}
+		return lessId(dispatches[i].Id, dispatches[j].Id)
+	})
+	return page(dispatches, limit, 0), nil
+}
+
+func (r *WebhookRepo) RecordAttempt(ctx context.Context, in rt.RecordWebhookAttemptInput) error {
+	r.exec(ctx, func(t *tx) {
+		d, ok := r.webhookDelivery[in.DeliveryId]
+		if !ok {
+			return
+		}
+
+		ts := now()
+		d.Attempts++
+		d.Status = in.Status
+		d.LastStatusCode = nil
+		if in.StatusCode != 0 {
+			code := in.StatusCode
+			d.LastStatusCode = &code
+		}
+		d.LastError = in.Error
+		d.DeliveredAt = nil
+		if in.Status == "Delivered" {
+			d.DeliveredAt = &ts
+		}
+		d.NextAttemptAt = ts.Add(in.Delay)
+		d.UpdatedAt = ts
+		put(t, r.webhookDelivery, d.Id, d)
+	})
+
+	return nil
+}
+
+// Redeliver puts delivery back to queue with fresh attempts budget
+func (r *WebhookRepo) Redeliver(ctx context.Context, subscriptionId, id uuid.UUID) (e.WebhookDelivery, error) {
+	var (
+		d  e.WebhookDelivery
+		ok bool
+	)
+	r.exec(ctx, func(t *tx) {
+		if d, ok = r.webhookDelivery[id]; !ok || d.SubscriptionId != subscriptionId {
+			ok = false
+			return
+		}
+
+		ts := now()
+		d.Status = "Pending"
+		d.Attempts = 0
+		d.NextAttemptAt = ts
+		d.UpdatedAt = ts
+		put(t, r.webhookDelivery, d.Id, d)
+	})
+	if !ok {
+		return e.WebhookDelivery{}, repoerrors.ErrNotFound
+	}
+
+	return cloneDelivery(d), nil
+}
+
+// hasDelivery must be called with tables locked
+func (s *Store) hasDelivery(subscriptionId, eventId uuid.UUID) bool {
+	for _, d := range s.webhookDelivery {
+		if d.SubscriptionId == subscriptionId && d.EventId == eventId {
+			return true
+		}
+	}
+	return false
+}
+
+// removeWebhook deletes subscription with its deliveries, must be called with
+// tables locked
+func (s *Store) removeWebhook(t *tx, id uuid.UUID) {
+	remove(t, s.webhooks, id)
+	for deliveryId, d := range s.webhookDelivery {
+		if d.SubscriptionId == id {
+			remove(t, s.webhookDelivery, deliveryId)
+		}
+	}
+}
+
+func cloneWebhook(sub e.WebhookSubscription) e.WebhookSubscription {
+	sub.EventTypes = slices.Clone(sub.EventTypes)
+	return sub
+}
+
+func cloneDelivery(d e.WebhookDelivery) e.WebhookDelivery {
+	d.Payload = bytes.Clone(d.Payload)
+	if d.LastStatusCode != nil {
+		code := *d.LastStatusCode
+		d.LastStatusCode = &code
+	}
+	d.DeliveredAt = cloneTime(d.DeliveredAt)
+	return d
+}
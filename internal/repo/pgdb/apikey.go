@@ -0,0 +1,124 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type APIKeyRepo struct {
+	*postgres.Postgres
+}
+
+func NewAPIKeyRepo(pg *postgres.Postgres) *APIKeyRepo {
+	return &APIKeyRepo{pg}
+}
+
+func (r *APIKeyRepo) Create(ctx context.Context, in rt.CreateAPIKeyInput) (e.APIKey, error) {
+	sql := `
+		INSERT INTO api_key
+			(organization_id, name, prefix, secret_hash, scopes, expires_at, created_by)
+		VALUES
+			($1, $2, $3, $4, $5, CASE WHEN $6::float8 > 0 THEN CURRENT_TIMESTAMP + make_interval(secs => $6) END, $7)
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql,
+		in.OrganizationId,
+		in.Name,
+		in.Prefix,
+		in.SecretHash,
+		in.Scopes,
+		in.TTL.Seconds(),
+		in.CreatedBy,
+	)
+	if err != nil {
+		return e.APIKey{}, fmt.Errorf("pgdb - APIKeyRepo.Create - Conn.Query: %w", err)
+	}
+
+	key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.APIKey])
+	if err != nil {
+		if isPgError(err, codeUniqueViolation) {
+			return e.APIKey{}, repoerrors.ErrAlreadyExists
+		}
+		if isPgError(err, codeForeignKeyViolation) {
+			return e.APIKey{}, repoerrors.ErrInvalidReference
+		}
+		return e.APIKey{}, fmt.Errorf("pgdb - APIKeyRepo.Create - CollectExactlyOneRow: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepo) GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.APIKey, error) {
+	sql := `
+		SELECT * FROM api_key
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, orgId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - APIKeyRepo.GetByOrganization - Conn.Query: %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.APIKey])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - APIKeyRepo.GetByOrganization - CollectRows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke is not idempotent: revoking already revoked key returns ErrNotFound
+func (r *APIKeyRepo) Revoke(ctx context.Context, orgId, id uuid.UUID) error {
+	sql := `
+		UPDATE api_key
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND organization_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, id, orgId)
+	if err != nil {
+		return fmt.Errorf("pgdb - APIKeyRepo.Revoke - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// Use returns active key by secret hash and updates its last usage time
+func (r *APIKeyRepo) Use(ctx context.Context, secretHash string) (e.APIKey, error) {
+	sql := `
+		UPDATE api_key
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE secret_hash = $1
+		AND revoked_at IS NULL
+		AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, secretHash)
+	if err != nil {
+		return e.APIKey{}, fmt.Errorf("pgdb - APIKeyRepo.Use - Conn.Query: %w", err)
+	}
+
+	key, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.APIKey])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.APIKey{}, repoerrors.ErrNotFound
+		}
+		return e.APIKey{}, fmt.Errorf("pgdb - APIKeyRepo.Use - CollectExactlyOneRow: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,185 @@
+package pgdb
+
+import (
+	"app/internal/auditchain"
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// auditChainLock is advisory lock key serializing writers of audit chain
+const auditChainLock = 4_812_730_001
+
+type AuditRepo struct {
+	*postgres.Postgres
+}
+
+func NewAuditRepo(pg *postgres.Postgres) *AuditRepo {
+	return &AuditRepo{pg}
+}
+
+// Add appends entry to the chain in transaction of context if there is one.
+// Writers are serialized until commit, so entries are chained in commit order.
+// created_at is taken under the lock too, transaction start time could order
+// entries differently than seq
+func (r *AuditRepo) Add(ctx context.Context, in rt.CreateAuditEntryInput) error {
+	sql := `
+		INSERT INTO audit_log
+			(seq, organization_id, actor_type, actor_id, actor_name, action, entity_type,
+			entity_id, entity_version, before, after, request_id, client_ip, created_at)
+		SELECT
+			COALESCE(MAX(seq), 0) + 1, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, clock_timestamp()
+		FROM audit_log
+	`
+
+	return r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.lockChain(ctx); err != nil {
+			return fmt.Errorf("pgdb - AuditRepo.Add - %w", err)
+		}
+
+		_, err := r.Conn(ctx).Exec(ctx, sql,
+			in.OrganizationId,
+			in.ActorType,
+			in.ActorId,
+			in.ActorName,
+			in.Action,
+			in.EntityType,
+			in.EntityId,
+			in.EntityVersion,
+			in.Before,
+			in.After,
+			in.RequestId,
+			in.ClientIP,
+		)
+		if err != nil {
+			return fmt.Errorf("pgdb - AuditRepo.Add - Conn.Exec: %w", err)
+		}
+
+		if err := r.sealPending(ctx); err != nil {
+			return fmt.Errorf("pgdb - AuditRepo.Add - %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Seal hashes entries written before chaining was introduced
+func (r *AuditRepo) Seal(ctx context.Context) error {
+	return r.InTx(ctx, func(ctx context.Context) error {
+		if err := r.lockChain(ctx); err != nil {
+			return fmt.Errorf("pgdb - AuditRepo.Seal - %w", err)
+		}
+		if err := r.sealPending(ctx); err != nil {
+			return fmt.Errorf("pgdb - AuditRepo.Seal - %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRange returns chained entries from first to last one created in [from, to)
+// ordered by seq. Range is contiguous even if older entries were stamped out of
+// seq order, so export of untouched chain always verifies
+func (r *AuditRepo) GetRange(ctx context.Context, from, to time.Time) ([]e.AuditEntry, error) {
+	sql := `
+		WITH bounds AS (
+			SELECT MIN(seq) AS first_seq, MAX(seq) AS last_seq
+			FROM audit_log
+			WHERE created_at >= $1 AND created_at < $2
+		)
+		SELECT audit_log.* FROM audit_log, bounds
+		WHERE seq BETWEEN bounds.first_seq AND bounds.last_seq
+		ORDER BY seq
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - AuditRepo.GetRange - Conn.Query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.AuditEntry])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - AuditRepo.GetRange - CollectRows: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *AuditRepo) lockChain(ctx context.Context) error {
+	if _, err := r.Conn(ctx).Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return fmt.Errorf("lockChain - Conn.Exec: %w", err)
+	}
+	return nil
+}
+
+// sealPending hashes unsealed entries in seq order, chain lock must be held.
+// Hash is computed over entry as stored, so jsonb normalization doesn't break it
+func (r *AuditRepo) sealPending(ctx context.Context) error {
+	prevHash := auditchain.GenesisHash
+	err := r.Conn(ctx).QueryRow(ctx, `
+		SELECT hash FROM audit_log
+		WHERE hash <> ''
+		ORDER BY seq DESC
+		LIMIT 1
+	`).Scan(&prevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("sealPending - QueryRow: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, `
+		SELECT * FROM audit_log
+		WHERE hash = ''
+		ORDER BY seq
+	`)
+	if err != nil {
+		return fmt.Errorf("sealPending - Conn.Query: %w", err)
+	}
+	pending, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.AuditEntry])
+	if err != nil {
+		return fmt.Errorf("sealPending - CollectRows: %w", err)
+	}
+
+	for _, entry := range pending {
+		entry.PrevHash = prevHash
+		hash, err := auditchain.FromEntry(entry).ComputeHash()
+		if err != nil {
+			return fmt.Errorf("sealPending - ComputeHash: %w", err)
+		}
+
+		_, err = r.Conn(ctx).Exec(ctx, `
+			UPDATE audit_log
+			SET prev_hash = $1, hash = $2
+			WHERE id = $3
+		`, prevHash, hash, entry.Id)
+		if err != nil {
+			return fmt.Errorf("sealPending - Conn.Exec: %w", err)
+		}
+		prevHash = hash
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) GetOrganizationLog(ctx context.Context, in rt.GetAuditLogInput) ([]e.AuditEntry, error) {
+	sql, args, err := auditLogQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - AuditRepo.GetOrganizationLog - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - AuditRepo.GetOrganizationLog - Conn.Query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.AuditEntry])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - AuditRepo.GetOrganizationLog - CollectRows: %w", err)
+	}
+
+	return entries, nil
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,18 +24,14 @@ func NewBidRepo(pg *postgres.Postgres) *BidRepo {
 
 // use repotypes.VersionLatest if need latest version
 func (r *BidRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Bid, error) {
-	sql := `
-		SELECT DISTINCT ON (id) * FROM bid
-		WHERE id = $1
-	`
-	if version != rt.VersionLatest {
-		sql = fmt.Sprintf("%s AND version = %d", sql, version)
+	sql, args, err := versionQuery("bid", id, version).Build()
+	if err != nil {
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.Get - Build: %w", err)
 	}
-	sql += "\n\t\tORDER BY id, version DESC"
 
-	rows, err := r.Pool.Query(ctx, sql, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.Get - Pool.Query: %w", err)
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.Get - Conn.Query: %w", err)
 	}
 
 	b, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Bid])
@@ -51,21 +48,22 @@ func (r *BidRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Bid, er
 func (r *BidRepo) Create(ctx context.Context, in rt.CreateBidInput) (e.Bid, error) {
 	sql := `
 		INSERT INTO bid
-			(name, description, author, author_id, tender_id)
+			(name, description, author, author_id, tender_id, organization_id)
 		VALUES
-			($1, $2, $3, $4, $5)
+			($1, $2, $3, $4, $5, $6)
 		RETURNING *
 	`
 
-	rows, err := r.Pool.Query(ctx, sql,
+	rows, err := r.Conn(ctx).Query(ctx, sql,
 		in.Name,
 		in.Description,
 		in.AuthorType,
 		in.AuthorId,
 		in.TenderId,
+		in.OrganizationId,
 	)
 	if err != nil {
-		return e.Bid{}, fmt.Errorf("pgdb.BidRepo - Create - Pool.Query: %w", err)
+		return e.Bid{}, fmt.Errorf("pgdb.BidRepo - Create - Conn.Query: %w", err)
 	}
 
 	b, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Bid])
@@ -90,9 +88,9 @@ func (r *BidRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string)
 		RETURNING *;
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, status, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, status, id)
 	if err != nil {
-		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.ChangeStatus - Pool.Query: %w", err)
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.ChangeStatus - Conn.Query: %w", err)
 	}
 
 	b, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Bid])
@@ -109,13 +107,13 @@ func (r *BidRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string)
 func (r *BidRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedBidInput) (e.Bid, error) {
 	sql := `
 		INSERT INTO bid
-			(id, name, description, author, author_id, status, version, tender_id)
+			(id, name, description, author, author_id, status, version, tender_id, organization_id)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING *
 	`
 
-	rows, err := r.Pool.Query(ctx, sql,
+	rows, err := r.Conn(ctx).Query(ctx, sql,
 		in.Id,
 		in.Name,
 		in.Description,
@@ -124,15 +122,103 @@ func (r *BidRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedBidI
 		in.Status,
 		in.Version,
 		in.TenderId,
+		in.OrganizationId,
 	)
 	if err != nil {
-		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.CreateSpecified - Pool.Query: %w", err)
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.CreateSpecified - Conn.Query: %w", err)
 	}
 
 	b, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Bid])
 	if err != nil {
+		// Version was created by concurrent write
+		if isPgError(err, codeUniqueViolation) {
+			return e.Bid{}, repoerrors.ErrVersionConflict
+		}
 		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.CreateSpecified - pgx.CollectExactlyOneRow: %w", err)
 	}
 
 	return b, nil
 }
+
+func (r *BidRepo) GetBidders(ctx context.Context, tenderId uuid.UUID) (rt.TenderBidders, error) {
+	sql := `
+		SELECT
+			COALESCE(array_agg(DISTINCT organization_id) FILTER (WHERE author = 'Organization'), '{}'),
+			COALESCE(array_agg(DISTINCT author_id) FILTER (WHERE author = 'User'), '{}')
+		FROM bid
+		WHERE tender_id = $1
+	`
+
+	var bidders rt.TenderBidders
+	err := r.Conn(ctx).QueryRow(ctx, sql, tenderId).Scan(&bidders.OrganizationIds, &bidders.EmployeeIds)
+	if err != nil {
+		return rt.TenderBidders{}, fmt.Errorf("pgdb - BidRepo.GetBidders - QueryRow: %w", err)
+	}
+
+	return bidders, nil
+}
+
+// Lock locks version of bid until end of transaction, must be called in
+// transaction. ErrVersionConflict means version was changed after updatedAt
+// or is not latest anymore
+func (r *BidRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	sql := `
+		SELECT version FROM bid
+		WHERE id = $1 AND version = $2 AND updated_at = $3
+		FOR UPDATE
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id, version, updatedAt)
+	if err != nil {
+		return fmt.Errorf("pgdb - BidRepo.Lock - Conn.Query: %w", err)
+	}
+
+	if _, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int]); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerrors.ErrVersionConflict
+		}
+		return fmt.Errorf("pgdb - BidRepo.Lock - CollectExactlyOneRow: %w", err)
+	}
+
+	// Next statement sees versions committed before lock was granted
+	sql = `
+		SELECT MAX(version) FROM bid
+		WHERE id = $1
+	`
+
+	var latest int
+	if err := r.Conn(ctx).QueryRow(ctx, sql, id).Scan(&latest); err != nil {
+		return fmt.Errorf("pgdb - BidRepo.Lock - QueryRow: %w", err)
+	}
+	if latest != version {
+		return repoerrors.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// GetForUpdate returns latest version of bid locked until end of transaction,
+// must be called in transaction. ErrVersionConflict is returned when bid is
+// changed before it is locked
+func (r *BidRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Bid, error) {
+	b, err := r.Get(ctx, id, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Bid{}, err
+		}
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.GetForUpdate - %w", err)
+	}
+
+	// Version read may be changed before lock is granted. Lock checks
+	// updated_at of locked row and MAX(version), under read committed they
+	// see changes committed while waiting, so conflict is returned and caller
+	// runs transaction again instead of reading in same transaction
+	if err := r.Lock(ctx, b.Id, b.Version, b.UpdatedAt); err != nil {
+		if errors.Is(err, repoerrors.ErrVersionConflict) {
+			return e.Bid{}, err
+		}
+		return e.Bid{}, fmt.Errorf("pgdb - BidRepo.GetForUpdate - %w", err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,45 @@
+package pgdb_test
+
+import (
+	"app/internal/repo"
+	"app/internal/repo/repotest"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// errRollback discards writes of test
+var errRollback = errors.New("rollback")
+
+// TestContract needs migrated database in TEST_POSTGRES_CONN, each test runs in
+// transaction rolled back at its end
+func TestContract(t *testing.T) {
+	url := os.Getenv("TEST_POSTGRES_CONN")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_CONN not set")
+	}
+
+	pg, err := postgres.New(url, postgres.MaxPoolSize(4))
+	if err != nil {
+		t.Fatalf("postgres.New: %v", err)
+	}
+	defer pg.Close()
+
+	txManager, err := postgres.NewTxManager(pg)
+	if err != nil {
+		t.Fatalf("postgres.NewTxManager: %v", err)
+	}
+	repos := repo.NewPostgresRepo(pg, txManager)
+
+	repotest.Run(t, func(t *testing.T, test func(ctx context.Context, t *testing.T, repos *repo.Repositories)) {
+		err := repos.Transactor.InTx(context.Background(), func(ctx context.Context) error {
+			test(ctx, t, repos)
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("InTx: %v", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Lock state is computed by the database so it doesn't depend on application clock
+const credentialColumns = `employee_id, password_hash, failed_attempts, locked_until,
+	COALESCE(locked_until > CURRENT_TIMESTAMP, false) AS is_locked, password_changed_at`
+
+type CredentialRepo struct {
+	*postgres.Postgres
+}
+
+func NewCredentialRepo(pg *postgres.Postgres) *CredentialRepo {
+	return &CredentialRepo{pg}
+}
+
+func (r *CredentialRepo) Get(ctx context.Context, employeeId uuid.UUID) (e.Credential, error) {
+	sql := `
+		SELECT ` + credentialColumns + ` FROM employee_credential
+		WHERE employee_id = $1
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, employeeId)
+	if err != nil {
+		return e.Credential{}, fmt.Errorf("pgdb - CredentialRepo.Get - Conn.Query: %w", err)
+	}
+
+	cred, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Credential])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Credential{}, repoerrors.ErrNotFound
+		}
+		return e.Credential{}, fmt.Errorf("pgdb - CredentialRepo.Get - CollectExactlyOneRow: %w", err)
+	}
+
+	return cred, nil
+}
+
+// SetPassword creates or replaces password hash and lifts lockout
+func (r *CredentialRepo) SetPassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	sql := `
+		INSERT INTO employee_credential
+			(employee_id, password_hash)
+		VALUES
+			($1, $2)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET password_hash = EXCLUDED.password_hash,
+			failed_attempts = 0,
+			locked_until = NULL,
+			password_changed_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, employeeId, passwordHash); err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return repoerrors.ErrInvalidReference
+		}
+		return fmt.Errorf("pgdb - CredentialRepo.SetPassword - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// CreatePassword stores first password hash, ErrAlreadyExists is returned if
+// employee has one
+func (r *CredentialRepo) CreatePassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	sql := `
+		INSERT INTO employee_credential
+			(employee_id, password_hash)
+		VALUES
+			($1, $2)
+		ON CONFLICT (employee_id) DO NOTHING
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, employeeId, passwordHash)
+	if err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return repoerrors.ErrInvalidReference
+		}
+		return fmt.Errorf("pgdb - CredentialRepo.CreatePassword - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrAlreadyExists
+	}
+
+	return nil
+}
+
+// RegisterFailure increments failed attempts counter. Once it reaches maxAttempts
+// credential is locked for lockout and counter starts over
+func (r *CredentialRepo) RegisterFailure(ctx context.Context, employeeId uuid.UUID, maxAttempts int, lockout time.Duration) (e.Credential, error) {
+	sql := `
+		UPDATE employee_credential
+		SET failed_attempts = CASE WHEN failed_attempts + 1 >= $2 THEN 0 ELSE failed_attempts + 1 END,
+			locked_until = CASE
+				WHEN failed_attempts + 1 >= $2 THEN CURRENT_TIMESTAMP + make_interval(secs => $3)
+				ELSE locked_until
+			END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $1
+		RETURNING ` + credentialColumns + `
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, employeeId, maxAttempts, lockout.Seconds())
+	if err != nil {
+		return e.Credential{}, fmt.Errorf("pgdb - CredentialRepo.RegisterFailure - Conn.Query: %w", err)
+	}
+
+	cred, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Credential])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Credential{}, repoerrors.ErrNotFound
+		}
+		return e.Credential{}, fmt.Errorf("pgdb - CredentialRepo.RegisterFailure - CollectExactlyOneRow: %w", err)
+	}
+
+	return cred, nil
+}
+
+func (r *CredentialRepo) ResetFailures(ctx context.Context, employeeId uuid.UUID) error {
+	sql := `
+		UPDATE employee_credential
+		SET failed_attempts = 0, locked_until = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $1
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, employeeId); err != nil {
+		return fmt.Errorf("pgdb - CredentialRepo.ResetFailures - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CredentialRepo) CreateResetToken(ctx context.Context, in rt.CreateResetTokenInput) (time.Time, error) {
+	sql := `
+		INSERT INTO password_reset_token
+			(employee_id, token_hash, expires_at, created_by)
+		VALUES
+			($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3), $4)
+		RETURNING expires_at
+	`
+
+	var expiresAt time.Time
+	err := r.Conn(ctx).QueryRow(ctx, sql, in.EmployeeId, in.TokenHash, in.TTL.Seconds(), in.CreatedBy).Scan(&expiresAt)
+	if err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return time.Time{}, repoerrors.ErrInvalidReference
+		}
+		return time.Time{}, fmt.Errorf("pgdb - CredentialRepo.CreateResetToken - QueryRow: %w", err)
+	}
+
+	return expiresAt, nil
+}
+
+// UseResetToken marks unexpired unused token as used and returns its employee
+func (r *CredentialRepo) UseResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	sql := `
+		UPDATE password_reset_token
+		SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING employee_id
+	`
+
+	var employeeId uuid.UUID
+	err := r.Conn(ctx).QueryRow(ctx, sql, tokenHash).Scan(&employeeId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, repoerrors.ErrNotFound
+		}
+		return uuid.Nil, fmt.Errorf("pgdb - CredentialRepo.UseResetToken - QueryRow: %w", err)
+	}
+
+	return employeeId, nil
+}
+
+func (r *CredentialRepo) AddLoginAttempt(ctx context.Context, in rt.CreateLoginAttemptInput) error {
+	sql := `
+		INSERT INTO login_attempt
+			(employee_id, username, client_ip, success, reason)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, in.EmployeeId, in.Username, in.ClientIP, in.Success, in.Reason); err != nil {
+		return fmt.Errorf("pgdb - CredentialRepo.AddLoginAttempt - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CredentialRepo) GetLoginAttempts(ctx context.Context, in rt.GetLoginAttemptsInput) ([]e.LoginAttempt, error) {
+	sql, args, err := loginAttemptsQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - CredentialRepo.GetLoginAttempts - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - CredentialRepo.GetLoginAttempts - Conn.Query: %w", err)
+	}
+
+	attempts, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.LoginAttempt])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - CredentialRepo.GetLoginAttempts - CollectRows: %w", err)
+	}
+
+	return attempts, nil
+}
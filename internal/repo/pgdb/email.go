@@ -0,0 +1,159 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EmailRepo struct {
+	*postgres.Postgres
+}
+
+func NewEmailRepo(pg *postgres.Postgres) *EmailRepo {
+	return &EmailRepo{pg}
+}
+
+func (r *EmailRepo) GetPreferences(ctx context.Context, employeeId uuid.UUID) ([]string, error) {
+	sql := `
+		SELECT kind::text FROM email_preference
+		WHERE employee_id = $1
+		ORDER BY kind
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, employeeId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetPreferences - Conn.Query: %w", err)
+	}
+
+	kinds, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetPreferences - CollectRows: %w", err)
+	}
+
+	return kinds, nil
+}
+
+// SetPreferences replaces opted in kinds of employee
+func (r *EmailRepo) SetPreferences(ctx context.Context, employeeId uuid.UUID, kinds []string) error {
+	sql := `
+		WITH removed AS (
+			DELETE FROM email_preference
+			WHERE employee_id = $1 AND kind::text <> ALL($2::text[])
+		)
+		INSERT INTO email_preference (employee_id, kind)
+		SELECT $1, unnest($2::text[])::email_notification_kind
+		ON CONFLICT (employee_id, kind) DO NOTHING
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, employeeId, kinds); err != nil {
+		return fmt.Errorf("pgdb - EmailRepo.SetPreferences - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EmailRepo) GetRecipients(ctx context.Context, in rt.GetEmailRecipientsInput) ([]e.Employee, error) {
+	sql := `
+		SELECT employee.* FROM employee
+		JOIN email_preference ON email_preference.employee_id = employee.id
+		WHERE email_preference.kind::text = $1
+		AND employee.is_active
+		AND employee.email IS NOT NULL
+		AND (
+			employee.id = ANY($2)
+			OR employee.id IN (
+				SELECT user_id FROM organization_responsible
+				WHERE organization_id = ANY($3)
+			)
+		)
+		ORDER BY employee.username
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Kind, in.EmployeeIds, in.OrganizationIds)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetRecipients - Conn.Query: %w", err)
+	}
+
+	employees, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetRecipients - CollectRows: %w", err)
+	}
+
+	return employees, nil
+}
+
+// AddMessage is idempotent, employee gets one message per event
+func (r *EmailRepo) AddMessage(ctx context.Context, in rt.CreateEmailMessageInput) error {
+	sql := `
+		INSERT INTO email_message
+			(employee_id, event_id, kind, to_address, subject, text_body, html_body)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (event_id, employee_id) DO NOTHING
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql,
+		in.EmployeeId,
+		in.EventId,
+		in.Kind,
+		in.ToAddress,
+		in.Subject,
+		in.TextBody,
+		in.HTMLBody,
+	)
+	if err != nil {
+		return fmt.Errorf("pgdb - EmailRepo.AddMessage - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetPending locks due messages, must be called in transaction.
+// Messages locked by other mailers are skipped
+func (r *EmailRepo) GetPending(ctx context.Context, limit int) ([]e.EmailMessage, error) {
+	sql := `
+		SELECT * FROM email_message
+		WHERE status = 'Pending'
+		AND next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY next_attempt_at, id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, limit)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetPending - Conn.Query: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.EmailMessage])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmailRepo.GetPending - CollectRows: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *EmailRepo) RecordAttempt(ctx context.Context, in rt.RecordEmailAttemptInput) error {
+	sql := `
+		UPDATE email_message
+		SET attempts = attempts + 1,
+			status = $2::text::email_message_status,
+			last_error = $3,
+			sent_at = CASE WHEN $2::text = 'Sent' THEN CURRENT_TIMESTAMP END,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $4)
+		WHERE id = $1
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, in.MessageId, in.Status, in.Error, in.Delay.Seconds())
+	if err != nil {
+		return fmt.Errorf("pgdb - EmailRepo.RecordAttempt - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
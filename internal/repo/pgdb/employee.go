@@ -3,6 +3,7 @@ package pgdb
 import (
 	e "app/internal/entity"
 	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
 	"app/pkg/postgres"
 	"context"
 	"errors"
@@ -26,7 +27,7 @@ func (r *EmployeeRepo) GetByUsername(ctx context.Context, username string) (e.Em
 		WHERE username = $1
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, username)
+	rows, err := r.Conn(ctx).Query(ctx, sql, username)
 	if err != nil {
 		return e.Employee{}, fmt.Errorf("pgdb - GetByUsername - Query: %w", err)
 	}
@@ -53,7 +54,7 @@ func (r *EmployeeRepo) IsResponsible(ctx context.Context, orgId, userId uuid.UUI
 	`
 
 	var isResponsible bool
-	err := r.Pool.QueryRow(ctx, sql, orgId, userId).Scan(&isResponsible)
+	err := r.Conn(ctx).QueryRow(ctx, sql, orgId, userId).Scan(&isResponsible)
 	if err != nil {
 		return false, fmt.Errorf("pgdb - IsResponsible - QueryRow: %w", err)
 	}
@@ -61,38 +62,26 @@ func (r *EmployeeRepo) IsResponsible(ctx context.Context, orgId, userId uuid.UUI
 	return isResponsible, nil
 }
 
-func (r *EmployeeRepo) IsResponsibleSimplified(ctx context.Context, userId uuid.UUID) (bool, error) {
+// GetRoles returns roles of employee in organization, empty if they aren't its responsible
+func (r *EmployeeRepo) GetRoles(ctx context.Context, orgId, userId uuid.UUID) ([]string, error) {
 	sql := `
-		SELECT EXISTS(
-    		SELECT 1
-    		FROM organization_responsible
-    		WHERE user_id = $1
-		) AS is_responsible
+		SELECT role::text FROM organization_role
+		WHERE organization_id = $1
+		AND user_id = $2
+		ORDER BY role
 	`
 
-	var isResponsible bool
-	err := r.Pool.QueryRow(ctx, sql, userId).Scan(&isResponsible)
+	rows, err := r.Conn(ctx).Query(ctx, sql, orgId, userId)
 	if err != nil {
-		return false, fmt.Errorf("pgdb - IsResponsibleSimplified - QueryRow: %w", err)
+		return nil, fmt.Errorf("pgdb - GetRoles - Conn.Query: %w", err)
 	}
 
-	return isResponsible, nil
-}
-
-func (r *EmployeeRepo) GetOrgIdFromResponsible(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
-	sql := `
-		SELECT organization_id
-		FROM organization_responsible
-		WHERE user_id = $1
-	`
-
-	var orgId uuid.UUID
-	err := r.Pool.QueryRow(ctx, sql, id).Scan(&orgId)
+	roles, err := pgx.CollectRows(rows, pgx.RowTo[string])
 	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("pgdb - GetOrgIdFromResponsible - QueryRow: %w", err)
+		return nil, fmt.Errorf("pgdb - GetRoles - CollectRows: %w", err)
 	}
 
-	return orgId, nil
+	return roles, nil
 }
 
 func (r *EmployeeRepo) GetById(ctx context.Context, id uuid.UUID) (e.Employee, error) {
@@ -101,7 +90,7 @@ func (r *EmployeeRepo) GetById(ctx context.Context, id uuid.UUID) (e.Employee, e
 		WHERE id = $1
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, id)
 	if err != nil {
 		return e.Employee{}, fmt.Errorf("pgdb - GetById - Query: %w", err)
 	}
@@ -116,3 +105,125 @@ func (r *EmployeeRepo) GetById(ctx context.Context, id uuid.UUID) (e.Employee, e
 
 	return employee, nil
 }
+
+func (r *EmployeeRepo) Create(ctx context.Context, in rt.CreateEmployeeInput) (e.Employee, error) {
+	sql := `
+		INSERT INTO employee
+			(username, first_name, last_name, email)
+		VALUES
+			($1, $2, $3, $4)
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Username, in.FirstName, in.LastName, in.Email)
+	if err != nil {
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.Create - Conn.Query: %w", err)
+	}
+
+	employee, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		if isConstraintViolation(err, employeeEmailKey) {
+			return e.Employee{}, repoerrors.ErrEmailTaken
+		}
+		if isPgError(err, codeUniqueViolation) {
+			return e.Employee{}, repoerrors.ErrAlreadyExists
+		}
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.Create - CollectExactlyOneRow: %w", err)
+	}
+
+	return employee, nil
+}
+
+func (r *EmployeeRepo) Update(ctx context.Context, in rt.UpdateEmployeeInput) (e.Employee, error) {
+	sql := `
+		UPDATE employee
+		SET first_name = $1, last_name = $2, email = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.FirstName, in.LastName, in.Email, in.Id)
+	if err != nil {
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.Update - Conn.Query: %w", err)
+	}
+
+	employee, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Employee{}, repoerrors.ErrNotFound
+		}
+		if isConstraintViolation(err, employeeEmailKey) {
+			return e.Employee{}, repoerrors.ErrEmailTaken
+		}
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.Update - CollectExactlyOneRow: %w", err)
+	}
+
+	return employee, nil
+}
+
+func (r *EmployeeRepo) SetActive(ctx context.Context, id uuid.UUID, active bool) (e.Employee, error) {
+	sql := `
+		UPDATE employee
+		SET is_active = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, active, id)
+	if err != nil {
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.SetActive - Conn.Query: %w", err)
+	}
+
+	employee, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Employee{}, repoerrors.ErrNotFound
+		}
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.SetActive - CollectExactlyOneRow: %w", err)
+	}
+
+	return employee, nil
+}
+
+func (r *EmployeeRepo) SetAdmin(ctx context.Context, id uuid.UUID, admin bool) (e.Employee, error) {
+	sql := `
+		UPDATE employee
+		SET is_admin = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, admin, id)
+	if err != nil {
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.SetAdmin - Conn.Query: %w", err)
+	}
+
+	employee, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Employee{}, repoerrors.ErrNotFound
+		}
+		return e.Employee{}, fmt.Errorf("pgdb - EmployeeRepo.SetAdmin - CollectExactlyOneRow: %w", err)
+	}
+
+	return employee, nil
+}
+
+func (r *EmployeeRepo) Search(ctx context.Context, in rt.SearchEmployeesInput) ([]e.Employee, error) {
+	sql, args, err := employeesQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmployeeRepo.Search - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmployeeRepo.Search - Conn.Query: %w", err)
+	}
+
+	employees, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - EmployeeRepo.Search - CollectRows: %w", err)
+	}
+
+	return employees, nil
+}
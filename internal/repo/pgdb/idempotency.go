@@ -0,0 +1,143 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdempotencyRepo struct {
+	*postgres.Postgres
+}
+
+func NewIdempotencyRepo(pg *postgres.Postgres) *IdempotencyRepo {
+	return &IdempotencyRepo{pg}
+}
+
+// Claim reports if key was claimed. Expired key is claimed as new one, key
+// abandoned by request with same payload is claimed again. Concurrent claims
+// of one key wait for each other, only one of them succeeds
+func (r *IdempotencyRepo) Claim(ctx context.Context, in rt.ClaimIdempotencyKeyInput) (bool, error) {
+	sql := `
+		INSERT INTO idempotency_key (caller, key, request_hash, locked_until)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + make_interval(secs => $4))
+		ON CONFLICT (caller, key) DO UPDATE
+		SET
+			request_hash = EXCLUDED.request_hash,
+			response_status = NULL,
+			response_content_type = NULL,
+			response_body = NULL,
+			locked_until = EXCLUDED.locked_until,
+			created_at = CURRENT_TIMESTAMP,
+			completed_at = NULL
+		WHERE
+			idempotency_key.created_at < CURRENT_TIMESTAMP - make_interval(secs => $5)
+			OR (
+				idempotency_key.response_status IS NULL
+				AND idempotency_key.locked_until < CURRENT_TIMESTAMP
+				AND idempotency_key.request_hash = EXCLUDED.request_hash
+			)
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, in.Caller, in.Key, in.RequestHash, in.Lease.Seconds(), in.TTL.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("pgdb - IdempotencyRepo.Claim - Conn.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, caller, key string) (e.IdempotencyKey, error) {
+	sql := `
+		SELECT * FROM idempotency_key
+		WHERE caller = $1 AND key = $2
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, caller, key)
+	if err != nil {
+		return e.IdempotencyKey{}, fmt.Errorf("pgdb - IdempotencyRepo.Get - Conn.Query: %w", err)
+	}
+
+	k, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.IdempotencyKey])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.IdempotencyKey{}, repoerrors.ErrNotFound
+		}
+		return e.IdempotencyKey{}, fmt.Errorf("pgdb - IdempotencyRepo.Get - CollectExactlyOneRow: %w", err)
+	}
+
+	return k, nil
+}
+
+// Complete stores response of claimed key, completed key is left as is
+func (r *IdempotencyRepo) Complete(ctx context.Context, in rt.CompleteIdempotencyKeyInput) error {
+	sql := `
+		UPDATE idempotency_key
+		SET
+			response_status = $3,
+			response_content_type = $4,
+			response_body = $5,
+			completed_at = CURRENT_TIMESTAMP
+		WHERE caller = $1 AND key = $2 AND response_status IS NULL
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, in.Caller, in.Key, in.Status, in.ContentType, in.Body)
+	if err != nil {
+		return fmt.Errorf("pgdb - IdempotencyRepo.Complete - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Extend holds claimed key for lease from now, completed key is left as is
+func (r *IdempotencyRepo) Extend(ctx context.Context, caller, key string, lease time.Duration) error {
+	sql := `
+		UPDATE idempotency_key
+		SET locked_until = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		WHERE caller = $1 AND key = $2 AND response_status IS NULL
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, caller, key, lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("pgdb - IdempotencyRepo.Extend - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Release deletes claim of key, so request can be made with it again
+func (r *IdempotencyRepo) Release(ctx context.Context, caller, key string) error {
+	sql := `
+		DELETE FROM idempotency_key
+		WHERE caller = $1 AND key = $2 AND response_status IS NULL
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, caller, key)
+	if err != nil {
+		return fmt.Errorf("pgdb - IdempotencyRepo.Release - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) DeleteOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	sql := `
+		DELETE FROM idempotency_key
+		WHERE created_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+		AND locked_until < CURRENT_TIMESTAMP
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("pgdb - IdempotencyRepo.DeleteOlderThan - Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,112 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type InvitationRepo struct {
+	*postgres.Postgres
+}
+
+func NewInvitationRepo(pg *postgres.Postgres) *InvitationRepo {
+	return &InvitationRepo{pg}
+}
+
+func (r *InvitationRepo) Create(ctx context.Context, in rt.CreateInvitationInput) (e.TenderInvitation, error) {
+	sql := `
+		INSERT INTO tender_invitation
+			(tender_id, organization_id, user_id)
+		VALUES
+			($1, $2, $3)
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.TenderId, in.OrganizationId, in.UserId)
+	if err != nil {
+		return e.TenderInvitation{}, fmt.Errorf("pgdb - InvitationRepo.Create - Conn.Query: %w", err)
+	}
+
+	inv, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.TenderInvitation])
+	if err != nil {
+		if isPgError(err, codeUniqueViolation) {
+			return e.TenderInvitation{}, repoerrors.ErrAlreadyExists
+		}
+		if isPgError(err, codeForeignKeyViolation) {
+			return e.TenderInvitation{}, repoerrors.ErrInvalidReference
+		}
+		return e.TenderInvitation{}, fmt.Errorf("pgdb - InvitationRepo.Create - CollectExactlyOneRow: %w", err)
+	}
+
+	return inv, nil
+}
+
+func (r *InvitationRepo) Delete(ctx context.Context, tenderId, invitationId uuid.UUID) error {
+	sql := `
+		DELETE FROM tender_invitation
+		WHERE id = $1 AND tender_id = $2
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, invitationId, tenderId)
+	if err != nil {
+		return fmt.Errorf("pgdb - InvitationRepo.Delete - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *InvitationRepo) GetByTender(ctx context.Context, tenderId uuid.UUID) ([]e.TenderInvitation, error) {
+	sql := `
+		SELECT * FROM tender_invitation
+		WHERE tender_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - InvitationRepo.GetByTender - Conn.Query: %w", err)
+	}
+
+	invitations, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.TenderInvitation])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - InvitationRepo.GetByTender - CollectRows: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// Employee is invited either personally or through any organization they are responsible for
+func (r *InvitationRepo) IsInvited(ctx context.Context, tenderId, userId uuid.UUID) (bool, error) {
+	sql := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM tender_invitation
+			WHERE tender_id = $1
+			AND (
+				user_id = $2
+				OR organization_id IN (
+					SELECT organization_id FROM organization_responsible
+					WHERE user_id = $2
+				)
+			)
+		) AS is_invited
+	`
+
+	var isInvited bool
+	err := r.Conn(ctx).QueryRow(ctx, sql, tenderId, userId).Scan(&isInvited)
+	if err != nil {
+		return false, fmt.Errorf("pgdb - InvitationRepo.IsInvited - QueryRow: %w", err)
+	}
+
+	return isInvited, nil
+}
@@ -0,0 +1,163 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// visibleNotification hides notifications for organizations employee is no
+// longer responsible for
+const visibleNotification = `
+	(notification.organization_id IS NULL OR EXISTS (
+		SELECT 1 FROM organization_responsible
+		WHERE organization_responsible.organization_id = notification.organization_id
+		AND organization_responsible.user_id = notification.employee_id
+	))
+`
+
+type NotificationRepo struct {
+	*postgres.Postgres
+}
+
+func NewNotificationRepo(pg *postgres.Postgres) *NotificationRepo {
+	return &NotificationRepo{pg}
+}
+
+// Add is idempotent, employee gets one notification per event. Employee
+// responsible for several recipient organizations is notified for first of them
+func (r *NotificationRepo) Add(ctx context.Context, in rt.AddNotificationsInput) (int, error) {
+	sql := `
+		WITH recipients AS (
+			SELECT DISTINCT ON (employee_id) employee_id, organization_id FROM (
+				SELECT user_id AS employee_id, organization_id FROM organization_responsible
+				WHERE organization_id = ANY($3)
+				UNION ALL
+				SELECT id, NULL FROM employee
+				WHERE id = ANY($4)
+			) AS candidates
+			ORDER BY employee_id, organization_id NULLS LAST
+		)
+		INSERT INTO notification
+			(employee_id, event_id, kind, organization_id, tender_id, bid_id, title)
+		SELECT recipients.employee_id, $1, $2::text::notification_kind, recipients.organization_id, $5, $6, $7
+		FROM recipients
+		JOIN employee ON employee.id = recipients.employee_id
+		WHERE employee.is_active
+		ON CONFLICT (event_id, employee_id) DO NOTHING
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql,
+		in.EventId,
+		in.Kind,
+		in.OrganizationIds,
+		in.EmployeeIds,
+		in.TenderId,
+		in.BidId,
+		in.Title,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("pgdb - NotificationRepo.Add - Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *NotificationRepo) GetByEmployee(ctx context.Context, in rt.GetNotificationsInput) ([]e.Notification, error) {
+	sql, args, err := notificationsQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - NotificationRepo.GetByEmployee - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - NotificationRepo.GetByEmployee - Conn.Query: %w", err)
+	}
+
+	notifications, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Notification])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - NotificationRepo.GetByEmployee - CollectRows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepo) CountUnread(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	sql := `
+		SELECT COUNT(*) FROM notification
+		WHERE employee_id = $1
+		AND read_at IS NULL
+		AND ` + visibleNotification
+
+	var count int
+	if err := r.Conn(ctx).QueryRow(ctx, sql, employeeId).Scan(&count); err != nil {
+		return 0, fmt.Errorf("pgdb - NotificationRepo.CountUnread - QueryRow: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead keeps time of first reading
+func (r *NotificationRepo) MarkRead(ctx context.Context, employeeId, id uuid.UUID) (e.Notification, error) {
+	sql := `
+		UPDATE notification
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+		AND employee_id = $2
+		AND ` + visibleNotification + `
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id, employeeId)
+	if err != nil {
+		return e.Notification{}, fmt.Errorf("pgdb - NotificationRepo.MarkRead - Conn.Query: %w", err)
+	}
+
+	notification, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Notification])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Notification{}, repoerrors.ErrNotFound
+		}
+		return e.Notification{}, fmt.Errorf("pgdb - NotificationRepo.MarkRead - CollectExactlyOneRow: %w", err)
+	}
+
+	return notification, nil
+}
+
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, employeeId uuid.UUID) (int, error) {
+	sql := `
+		UPDATE notification
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $1
+		AND read_at IS NULL
+		AND ` + visibleNotification
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, employeeId)
+	if err != nil {
+		return 0, fmt.Errorf("pgdb - NotificationRepo.MarkAllRead - Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *NotificationRepo) DeleteOlderThan(ctx context.Context, age time.Duration) (int, error) {
+	sql := `
+		DELETE FROM notification
+		WHERE created_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("pgdb - NotificationRepo.DeleteOlderThan - Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,282 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// organization.type is nullable for rows created before the management API
+const organizationColumns = `id, name, description, COALESCE(type::text, '') AS type, created_at, updated_at`
+
+type OrganizationRepo struct {
+	*postgres.Postgres
+}
+
+func NewOrganizationRepo(pg *postgres.Postgres) *OrganizationRepo {
+	return &OrganizationRepo{pg}
+}
+
+// Create inserts organization and makes ResponsibleId its first responsible
+// with all roles
+func (r *OrganizationRepo) Create(ctx context.Context, in rt.CreateOrganizationInput) (e.Organization, error) {
+	sql := `
+		WITH org AS (
+			INSERT INTO organization
+				(name, description, type)
+			VALUES
+				($1, $2, $3)
+			RETURNING *
+		), responsible AS (
+			INSERT INTO organization_responsible
+				(organization_id, user_id)
+			SELECT id, $4 FROM org
+			RETURNING organization_id, user_id
+		), roles AS (
+			INSERT INTO organization_role
+				(organization_id, user_id, role)
+			SELECT organization_id, user_id, unnest(enum_range(NULL::organization_role_type))
+			FROM responsible
+		)
+		SELECT ` + organizationColumns + ` FROM org
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Name, in.Description, in.Type, in.ResponsibleId)
+	if err != nil {
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Create - Conn.Query: %w", err)
+	}
+
+	org, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Organization])
+	if err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return e.Organization{}, repoerrors.ErrInvalidReference
+		}
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Create - CollectExactlyOneRow: %w", err)
+	}
+
+	return org, nil
+}
+
+func (r *OrganizationRepo) Get(ctx context.Context, id uuid.UUID) (e.Organization, error) {
+	sql := `
+		SELECT ` + organizationColumns + ` FROM organization
+		WHERE id = $1
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id)
+	if err != nil {
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Get - Conn.Query: %w", err)
+	}
+
+	org, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Organization])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Organization{}, repoerrors.ErrNotFound
+		}
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Get - CollectExactlyOneRow: %w", err)
+	}
+
+	return org, nil
+}
+
+func (r *OrganizationRepo) Update(ctx context.Context, in rt.UpdateOrganizationInput) (e.Organization, error) {
+	sql := `
+		UPDATE organization
+		SET name = $1, description = $2, type = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + organizationColumns + `
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Name, in.Description, in.Type, in.Id)
+	if err != nil {
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Update - Conn.Query: %w", err)
+	}
+
+	org, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Organization])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Organization{}, repoerrors.ErrNotFound
+		}
+		return e.Organization{}, fmt.Errorf("pgdb - OrganizationRepo.Update - CollectExactlyOneRow: %w", err)
+	}
+
+	return org, nil
+}
+
+func (r *OrganizationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := `
+		DELETE FROM organization
+		WHERE id = $1
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, id)
+	if err != nil {
+		return fmt.Errorf("pgdb - OrganizationRepo.Delete - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) Search(ctx context.Context, in rt.SearchOrganizationsInput) ([]e.Organization, error) {
+	sql, args, err := organizationsQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.Search - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.Search - Conn.Query: %w", err)
+	}
+
+	orgs, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Organization])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.Search - CollectRows: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// AddResponsible assigns responsible with viewer role
+func (r *OrganizationRepo) AddResponsible(ctx context.Context, orgId, userId uuid.UUID) error {
+	sql := `
+		WITH responsible AS (
+			INSERT INTO organization_responsible
+				(organization_id, user_id)
+			VALUES
+				($1, $2)
+			RETURNING organization_id, user_id
+		)
+		INSERT INTO organization_role
+			(organization_id, user_id, role)
+		SELECT organization_id, user_id, 'viewer' FROM responsible
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, orgId, userId); err != nil {
+		if isPgError(err, codeUniqueViolation) {
+			return repoerrors.ErrAlreadyExists
+		}
+		if isPgError(err, codeForeignKeyViolation) {
+			return repoerrors.ErrInvalidReference
+		}
+		return fmt.Errorf("pgdb - OrganizationRepo.AddResponsible - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) RemoveResponsible(ctx context.Context, orgId, userId uuid.UUID) error {
+	sql := `
+		DELETE FROM organization_responsible
+		WHERE organization_id = $1 AND user_id = $2
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, orgId, userId)
+	if err != nil {
+		return fmt.Errorf("pgdb - OrganizationRepo.RemoveResponsible - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) GetResponsibles(ctx context.Context, orgId uuid.UUID) ([]e.Employee, error) {
+	sql := `
+		SELECT employee.* FROM employee
+		JOIN organization_responsible ON organization_responsible.user_id = employee.id
+		WHERE organization_responsible.organization_id = $1
+		ORDER BY employee.username
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, orgId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.GetResponsibles - Conn.Query: %w", err)
+	}
+
+	employees, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Employee])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.GetResponsibles - CollectRows: %w", err)
+	}
+
+	return employees, nil
+}
+
+// SetRoles replaces roles of organization responsible
+func (r *OrganizationRepo) SetRoles(ctx context.Context, orgId, userId uuid.UUID, roles []string) error {
+	sql := `
+		WITH removed AS (
+			DELETE FROM organization_role
+			WHERE organization_id = $1
+			AND user_id = $2
+			AND role::text <> ALL($3::text[])
+		)
+		INSERT INTO organization_role
+			(organization_id, user_id, role)
+		SELECT $1, $2, unnest($3::text[])::organization_role_type
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, orgId, userId, roles); err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return repoerrors.ErrInvalidReference
+		}
+		return fmt.Errorf("pgdb - OrganizationRepo.SetRoles - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetRoleHolders returns ids of organization responsibles having role
+func (r *OrganizationRepo) GetRoleHolders(ctx context.Context, orgId uuid.UUID, role string) ([]uuid.UUID, error) {
+	sql := `
+		SELECT user_id FROM organization_role
+		WHERE organization_id = $1
+		AND role::text = $2
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, orgId, role)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.GetRoleHolders - Conn.Query: %w", err)
+	}
+
+	userIds, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OrganizationRepo.GetRoleHolders - CollectRows: %w", err)
+	}
+
+	return userIds, nil
+}
+
+// LockResponsibles locks responsibles of organization until end of
+// transaction, must be called in transaction. Transactions changing
+// responsibles or their roles after the lock see changes of each other
+func (r *OrganizationRepo) LockResponsibles(ctx context.Context, orgId uuid.UUID) error {
+	sql := `
+		SELECT user_id FROM organization_responsible
+		WHERE organization_id = $1
+		ORDER BY user_id
+		FOR UPDATE
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, orgId); err != nil {
+		return fmt.Errorf("pgdb - OrganizationRepo.LockResponsibles - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,190 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type OutboxRepo struct {
+	*postgres.Postgres
+}
+
+func NewOutboxRepo(pg *postgres.Postgres) *OutboxRepo {
+	return &OutboxRepo{pg}
+}
+
+// Add writes event in transaction of context, so it is stored only with the change
+func (r *OutboxRepo) Add(ctx context.Context, in rt.CreateOutboxEventInput) error {
+	sql := `
+		INSERT INTO outbox_event
+			(type, aggregate_type, aggregate_id, organization_id, payload)
+		VALUES
+			($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, in.Type, in.AggregateType, in.AggregateId, in.OrganizationId, in.Payload)
+	if err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.Add - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Claim returns due unprocessed events and postpones them by lease, other
+// dispatchers skip them until it expires. Events claimed by dispatcher which
+// stopped become due again after lease
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]e.OutboxEvent, error) {
+	sql := `
+		WITH claimed AS (
+			UPDATE outbox_event
+			SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM outbox_event
+				WHERE processed_at IS NULL
+				AND next_attempt_at <= CURRENT_TIMESTAMP
+				ORDER BY created_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT * FROM claimed
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OutboxRepo.Claim - Conn.Query: %w", err)
+	}
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.OutboxEvent])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OutboxRepo.Claim - CollectRows: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetDeliveredSinks returns names of sinks event was delivered to
+func (r *OutboxRepo) GetDeliveredSinks(ctx context.Context, eventId uuid.UUID) ([]string, error) {
+	sql := `
+		SELECT sink FROM outbox_delivery
+		WHERE event_id = $1 AND delivered_at IS NOT NULL
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, eventId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OutboxRepo.GetDeliveredSinks - Conn.Query: %w", err)
+	}
+
+	sinks, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - OutboxRepo.GetDeliveredSinks - CollectRows: %w", err)
+	}
+
+	return sinks, nil
+}
+
+// RecordDelivery stores attempt of delivering event to sink, empty lastError means success
+func (r *OutboxRepo) RecordDelivery(ctx context.Context, eventId uuid.UUID, sink, lastError string) error {
+	sql := `
+		INSERT INTO outbox_delivery
+			(event_id, sink, attempts, last_error, delivered_at)
+		VALUES
+			($1, $2, 1, $3, CASE WHEN $3 = '' THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (event_id, sink) DO UPDATE
+		SET attempts = outbox_delivery.attempts + 1,
+			last_error = EXCLUDED.last_error,
+			delivered_at = EXCLUDED.delivered_at,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, eventId, sink, lastError); err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.RecordDelivery - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) MarkProcessed(ctx context.Context, eventId uuid.UUID) error {
+	sql := `
+		UPDATE outbox_event
+		SET processed_at = CURRENT_TIMESTAMP, attempts = attempts + 1
+		WHERE id = $1
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, eventId); err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.MarkProcessed - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Reschedule postpones event after failed delivery
+func (r *OutboxRepo) Reschedule(ctx context.Context, eventId uuid.UUID, delay time.Duration) error {
+	sql := `
+		UPDATE outbox_event
+		SET attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		WHERE id = $1
+	`
+
+	if _, err := r.Conn(ctx).Exec(ctx, sql, eventId, delay.Seconds()); err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.Reschedule - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Listen passes events added to outbox by any instance to handle in commit
+// order, until ctx is done or connection fails. Events committed while no
+// one listens are not passed
+func (r *OutboxRepo) Listen(ctx context.Context, handle func(e.OutboxEvent)) error {
+	conn, err := r.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.Listen - Pool.Acquire: %w", err)
+	}
+	// Listening connection isn't returned to pool
+	defer func() {
+		conn.Hijack().Close(context.Background())
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN outbox_event"); err != nil {
+		return fmt.Errorf("pgdb - OutboxRepo.Listen - Conn.Exec: %w", err)
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("pgdb - OutboxRepo.Listen - WaitForNotification: %w", err)
+		}
+
+		id, err := uuid.Parse(n.Payload)
+		if err != nil {
+			return fmt.Errorf("pgdb - OutboxRepo.Listen - uuid.Parse: %w", err)
+		}
+
+		rows, err := conn.Query(ctx, "SELECT * FROM outbox_event WHERE id = $1", id)
+		if err != nil {
+			return fmt.Errorf("pgdb - OutboxRepo.Listen - Conn.Query: %w", err)
+		}
+		event, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[e.OutboxEvent])
+		if err != nil {
+			// Event is cleaned up already
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return fmt.Errorf("pgdb - OutboxRepo.Listen - CollectOneRow: %w", err)
+		}
+
+		handle(event)
+	}
+}
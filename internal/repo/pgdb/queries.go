@@ -0,0 +1,117 @@
+package pgdb
+
+import (
+	"app/internal/repo/pgdb/query"
+	rt "app/internal/repo/repotypes"
+
+	"github.com/google/uuid"
+)
+
+// versionQuery selects version of tender or bid, use repotypes.VersionLatest
+// if need latest version
+func versionQuery(table string, id uuid.UUID, version int) *query.Builder {
+	return query.Select("DISTINCT ON (id) *").
+		From(table).
+		Where("id = ?", id).
+		WhereIf(version != rt.VersionLatest, "version = ?", version).
+		OrderBy("id", "version DESC")
+}
+
+// publishedTendersQuery selects latest versions of published tenders viewer
+// can see. Public tenders are visible to everyone, Internal ones only to
+// responsibles of the tender organization, InviteOnly ones also to invited
+// organizations and employees
+func publishedTendersQuery(in rt.GetPublishedTendersInput) *query.Builder {
+	return query.Select("*").
+		From("(SELECT DISTINCT ON (id) * FROM tender ORDER BY id, version DESC) AS last_versions").
+		Where("status = 'Published'").
+		Where(`(
+			visibility = 'Public'
+			OR organization_id IN (
+				SELECT organization_id FROM organization_responsible
+				WHERE user_id = ?
+			)
+			OR (visibility = 'InviteOnly' AND id IN (
+				SELECT tender_id FROM tender_invitation
+				WHERE user_id = ?
+				OR organization_id IN (
+					SELECT organization_id FROM organization_responsible
+					WHERE user_id = ?
+				)
+			))
+		)`, in.ViewerId, in.ViewerId, in.ViewerId).
+		WhereIf(len(in.ServiceType) > 0, "type = ANY(?::service_type[])", in.ServiceType).
+		OrderBy("name").
+		Page(in.Limit, in.Offset)
+}
+
+// auditLogQuery selects entries of organization log, empty filters are skipped
+func auditLogQuery(in rt.GetAuditLogInput) *query.Builder {
+	return query.Select("*").
+		From("audit_log").
+		Where("organization_id = ?", in.OrganizationId).
+		WhereIf(in.EntityType != "", "entity_type = ?", in.EntityType).
+		WhereIf(in.EntityId != uuid.Nil, "entity_id = ?", in.EntityId).
+		WhereIf(in.Action != "", "action = ?", in.Action).
+		WhereIf(in.ActorName != "", "actor_name = ?", in.ActorName).
+		OrderBy("created_at DESC", "id").
+		Page(in.Limit, in.Offset)
+}
+
+// organizationsQuery selects organizations whose name or description contains
+// query
+func organizationsQuery(in rt.SearchOrganizationsInput) *query.Builder {
+	pattern := escapeLike(in.Query)
+	return query.Select(organizationColumns).
+		From("organization").
+		WhereIf(in.Query != "",
+			`(name ILIKE '%' || ? || '%' ESCAPE '\' OR description ILIKE '%' || ? || '%' ESCAPE '\')`,
+			pattern, pattern).
+		WhereIf(in.Type != "", "type::text = ?", in.Type).
+		OrderBy("name").
+		Page(in.Limit, in.Offset)
+}
+
+// employeesQuery selects employees whose username or name contains query
+func employeesQuery(in rt.SearchEmployeesInput) *query.Builder {
+	pattern := escapeLike(in.Query)
+	return query.Select("*").
+		From("employee").
+		WhereIf(in.Query != "",
+			`(username ILIKE '%' || ? || '%' ESCAPE '\' OR first_name ILIKE '%' || ? || '%' ESCAPE '\' OR last_name ILIKE '%' || ? || '%' ESCAPE '\')`,
+			pattern, pattern, pattern).
+		WhereIf(!in.IncludeInactive, "is_active").
+		OrderBy("username").
+		Page(in.Limit, in.Offset)
+}
+
+// webhookDeliveriesQuery selects deliveries of subscription, newest first
+func webhookDeliveriesQuery(in rt.GetWebhookDeliveriesInput) *query.Builder {
+	return query.Select("*").
+		From("webhook_delivery").
+		Where("subscription_id = ?", in.SubscriptionId).
+		WhereIf(in.Status != "", "status::text = ?", in.Status).
+		OrderBy("created_at DESC", "id").
+		Page(in.Limit, in.Offset)
+}
+
+// notificationsQuery selects notifications employee may still see, newest
+// first
+func notificationsQuery(in rt.GetNotificationsInput) *query.Builder {
+	return query.Select("*").
+		From("notification").
+		Where("employee_id = ?", in.EmployeeId).
+		WhereIf(in.UnreadOnly, "read_at IS NULL").
+		Where(visibleNotification).
+		OrderBy("created_at DESC", "id").
+		Page(in.Limit, in.Offset)
+}
+
+// loginAttemptsQuery selects login attempts, of one username if it is given
+func loginAttemptsQuery(in rt.GetLoginAttemptsInput) *query.Builder {
+	return query.Select("*").
+		From("login_attempt").
+		WhereIf(in.Username != "", "username = ?", in.Username).
+		OrderBy("created_at DESC").
+		Page(in.Limit, in.Offset)
+}
@@ -0,0 +1,227 @@
+package pgdb
+
+import (
+	rt "app/internal/repo/repotypes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestVersionQuery(t *testing.T) {
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		name    string
+		version int
+		sql     string
+		args    []any
+	}{
+		{
+			name:    "latest",
+			version: rt.VersionLatest,
+			sql:     "SELECT DISTINCT ON (id) * FROM tender WHERE id = $1 ORDER BY id, version DESC",
+			args:    []any{id},
+		},
+		{
+			name:    "given version",
+			version: 3,
+			sql:     "SELECT DISTINCT ON (id) * FROM tender WHERE id = $1 AND version = $2 ORDER BY id, version DESC",
+			args:    []any{id, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := versionQuery("tender", id, tt.version).Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestPublishedTendersQuery(t *testing.T) {
+	viewer := uuid.MustParse("550e8400-e29b-41d4-a716-446655440001")
+
+	tests := []struct {
+		name   string
+		in     rt.GetPublishedTendersInput
+		filter string
+		tail   string
+		args   []any
+	}{
+		{
+			name: "all service types",
+			in:   rt.GetPublishedTendersInput{Limit: 5, ViewerId: viewer},
+			tail: " ORDER BY name LIMIT $4",
+			args: []any{viewer, viewer, viewer, 5},
+		},
+		{
+			name: "service type filter",
+			in: rt.GetPublishedTendersInput{
+				Limit:       5,
+				Offset:      10,
+				ServiceType: []string{"Construction", "Delivery' OR '1'='1"},
+				ViewerId:    viewer,
+			},
+			filter: " AND type = ANY($4::service_type[])",
+			tail:   " ORDER BY name LIMIT $5 OFFSET $6",
+			args: []any{
+				viewer, viewer, viewer,
+				[]string{"Construction", "Delivery' OR '1'='1"},
+				5, 10,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := publishedTendersQuery(tt.in).Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if !strings.HasPrefix(sql, "SELECT * FROM (SELECT DISTINCT ON (id) * FROM tender ORDER BY id, version DESC) AS last_versions WHERE status = 'Published' AND (") {
+				t.Errorf("sql = %q, unexpected head", sql)
+			}
+			if !strings.HasSuffix(sql, ")"+tt.filter+tt.tail) {
+				t.Errorf("sql = %q, want suffix %q", sql, ")"+tt.filter+tt.tail)
+			}
+			if strings.Contains(sql, "Construction") || strings.Contains(sql, "Delivery") {
+				t.Errorf("sql = %q, service types must be arguments", sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestFilteredQueries(t *testing.T) {
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440002")
+	entity := uuid.MustParse("550e8400-e29b-41d4-a716-446655440003")
+
+	const (
+		orgMatch = `(name ILIKE '%' || ? || '%' ESCAPE '\' OR description ILIKE '%' || ? || '%' ESCAPE '\')`
+		empMatch = `(username ILIKE '%' || ? || '%' ESCAPE '\' OR first_name ILIKE '%' || ? || '%' ESCAPE '\' OR last_name ILIKE '%' || ? || '%' ESCAPE '\')`
+	)
+	numbered := func(cond string, from int) string {
+		for strings.Contains(cond, "?") {
+			cond = strings.Replace(cond, "?", "$"+strconv.Itoa(from), 1)
+			from++
+		}
+		return cond
+	}
+
+	tests := []struct {
+		name  string
+		query func() (string, []any, error)
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "audit log without filters",
+			query: auditLogQuery(rt.GetAuditLogInput{OrganizationId: id, Limit: 5}).Build,
+			sql:   "SELECT * FROM audit_log WHERE organization_id = $1 ORDER BY created_at DESC, id LIMIT $2",
+			args:  []any{id, 5},
+		},
+		{
+			name: "audit log with filters",
+			query: auditLogQuery(rt.GetAuditLogInput{
+				OrganizationId: id,
+				EntityType:     "tender",
+				EntityId:       entity,
+				Action:         "create",
+				ActorName:      "user1",
+				Limit:          5,
+				Offset:         10,
+			}).Build,
+			sql: "SELECT * FROM audit_log WHERE organization_id = $1 AND entity_type = $2 AND entity_id = $3" +
+				" AND action = $4 AND actor_name = $5 ORDER BY created_at DESC, id LIMIT $6 OFFSET $7",
+			args: []any{id, "tender", entity, "create", "user1", 5, 10},
+		},
+		{
+			name:  "organizations without filters",
+			query: organizationsQuery(rt.SearchOrganizationsInput{Limit: 5}).Build,
+			sql:   "SELECT " + organizationColumns + " FROM organization ORDER BY name LIMIT $1",
+			args:  []any{5},
+		},
+		{
+			name:  "organizations with filters",
+			query: organizationsQuery(rt.SearchOrganizationsInput{Query: "50%_off", Type: "LLC", Limit: 5}).Build,
+			sql:   "SELECT " + organizationColumns + " FROM organization WHERE " + numbered(orgMatch, 1) + " AND type::text = $3 ORDER BY name LIMIT $4",
+			args:  []any{`50\%\_off`, `50\%\_off`, "LLC", 5},
+		},
+		{
+			name:  "active employees",
+			query: employeesQuery(rt.SearchEmployeesInput{Limit: 5}).Build,
+			sql:   "SELECT * FROM employee WHERE is_active ORDER BY username LIMIT $1",
+			args:  []any{5},
+		},
+		{
+			name:  "employees with inactive ones",
+			query: employeesQuery(rt.SearchEmployeesInput{Query: "ann", IncludeInactive: true, Limit: 5, Offset: 10}).Build,
+			sql:   "SELECT * FROM employee WHERE " + numbered(empMatch, 1) + " ORDER BY username LIMIT $4 OFFSET $5",
+			args:  []any{"ann", "ann", "ann", 5, 10},
+		},
+		{
+			name:  "webhook deliveries",
+			query: webhookDeliveriesQuery(rt.GetWebhookDeliveriesInput{SubscriptionId: id, Limit: 5}).Build,
+			sql:   "SELECT * FROM webhook_delivery WHERE subscription_id = $1 ORDER BY created_at DESC, id LIMIT $2",
+			args:  []any{id, 5},
+		},
+		{
+			name:  "webhook deliveries with status",
+			query: webhookDeliveriesQuery(rt.GetWebhookDeliveriesInput{SubscriptionId: id, Status: "Failed", Limit: 5}).Build,
+			sql:   "SELECT * FROM webhook_delivery WHERE subscription_id = $1 AND status::text = $2 ORDER BY created_at DESC, id LIMIT $3",
+			args:  []any{id, "Failed", 5},
+		},
+		{
+			name:  "notifications",
+			query: notificationsQuery(rt.GetNotificationsInput{EmployeeId: id, Limit: 5}).Build,
+			sql:   "SELECT * FROM notification WHERE employee_id = $1 AND " + visibleNotification + " ORDER BY created_at DESC, id LIMIT $2",
+			args:  []any{id, 5},
+		},
+		{
+			name:  "unread notifications",
+			query: notificationsQuery(rt.GetNotificationsInput{EmployeeId: id, UnreadOnly: true, Limit: 5}).Build,
+			sql:   "SELECT * FROM notification WHERE employee_id = $1 AND read_at IS NULL AND " + visibleNotification + " ORDER BY created_at DESC, id LIMIT $2",
+			args:  []any{id, 5},
+		},
+		{
+			name:  "login attempts",
+			query: loginAttemptsQuery(rt.GetLoginAttemptsInput{Limit: 5}).Build,
+			sql:   "SELECT * FROM login_attempt ORDER BY created_at DESC LIMIT $1",
+			args:  []any{5},
+		},
+		{
+			name:  "login attempts of username",
+			query: loginAttemptsQuery(rt.GetLoginAttemptsInput{Username: "user1", Limit: 5}).Build,
+			sql:   "SELECT * FROM login_attempt WHERE username = $1 ORDER BY created_at DESC LIMIT $2",
+			args:  []any{"user1", 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.query()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
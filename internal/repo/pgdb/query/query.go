@@ -0,0 +1,143 @@
+// Package query builds dynamic SELECT statements for pgdb. Values are always
+// passed as arguments, SQL text comes from repository constants only. Sort
+// keys coming from callers are resolved through whitelist
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPlaceholders = errors.New("number of placeholders doesn't match number of arguments")
+	ErrSortKey      = errors.New("unknown sort key")
+)
+
+// SortColumns maps sort keys accepted from callers to expressions
+type SortColumns map[string]string
+
+// Builder builds SELECT statement. Conditions use ? placeholders, they are
+// numbered in order arguments are added
+type Builder struct {
+	columns string
+	from    string
+	where   []string
+	orderBy []string
+	limit   string
+	offset  string
+	args    []any
+	err     error
+}
+
+func Select(columns string) *Builder {
+	return &Builder{columns: columns}
+}
+
+func (b *Builder) From(from string) *Builder {
+	b.from = from
+	return b
+}
+
+// Where adds condition joined with AND as is, condition with OR must be
+// parenthesized
+func (b *Builder) Where(cond string, args ...any) *Builder {
+	b.where = append(b.where, b.bind(cond, args))
+	return b
+}
+
+// WhereIf adds condition only if ok is true
+func (b *Builder) WhereIf(ok bool, cond string, args ...any) *Builder {
+	if !ok {
+		return b
+	}
+	return b.Where(cond, args...)
+}
+
+// OrderBy adds trusted sort expressions
+func (b *Builder) OrderBy(exprs ...string) *Builder {
+	b.orderBy = append(b.orderBy, exprs...)
+	return b
+}
+
+// Sort adds sort expression key maps to, unknown key fails Build
+func (b *Builder) Sort(columns SortColumns, key string, desc bool) *Builder {
+	expr, ok := columns[key]
+	if !ok {
+		b.setErr(fmt.Errorf("%w %q", ErrSortKey, key))
+		return b
+	}
+	if desc {
+		expr += " DESC"
+	}
+	return b.OrderBy(expr)
+}
+
+// Page adds LIMIT and OFFSET, limit below 1 means no limit
+func (b *Builder) Page(limit, offset int) *Builder {
+	if limit > 0 {
+		b.limit = b.bind("?", []any{limit})
+	}
+	if offset > 0 {
+		b.offset = b.bind("?", []any{offset})
+	}
+	return b
+}
+
+// Build returns statement and its arguments
+func (b *Builder) Build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(b.columns)
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.from)
+	if len(b.where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(b.where, " AND "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+	if b.limit != "" {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(b.limit)
+	}
+	if b.offset != "" {
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(b.offset)
+	}
+
+	return sb.String(), b.args, nil
+}
+
+// bind replaces ? placeholders of expr with numbered ones and keeps args
+func (b *Builder) bind(expr string, args []any) string {
+	if strings.Count(expr, "?") != len(args) {
+		b.setErr(fmt.Errorf("%w: %q", ErrPlaceholders, expr))
+		return expr
+	}
+
+	var sb strings.Builder
+	for _, arg := range args {
+		i := strings.IndexByte(expr, '?')
+		b.args = append(b.args, arg)
+		sb.WriteString(expr[:i])
+		sb.WriteString("$" + strconv.Itoa(len(b.args)))
+		expr = expr[i+1:]
+	}
+	sb.WriteString(expr)
+
+	return sb.String()
+}
+
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
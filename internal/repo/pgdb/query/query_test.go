@@ -0,0 +1,129 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	columns := SortColumns{"name": "name", "created": "created_at"}
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		sql     string
+		args    []any
+	}{
+		{
+			name:    "no conditions",
+			builder: Select("*").From("tender"),
+			sql:     "SELECT * FROM tender",
+		},
+		{
+			name: "placeholders numbered in order",
+			builder: Select("id").From("bid").
+				Where("tender_id = ?", "t1").
+				Where("(status = ? OR author_id = ?)", "Published", "a1"),
+			sql:  "SELECT id FROM bid WHERE tender_id = $1 AND (status = $2 OR author_id = $3)",
+			args: []any{"t1", "Published", "a1"},
+		},
+		{
+			name: "skipped optional condition",
+			builder: Select("*").From("tender").
+				Where("id = ?", "t1").
+				WhereIf(false, "version = ?", 2).
+				WhereIf(true, "status = ?", "Closed"),
+			sql:  "SELECT * FROM tender WHERE id = $1 AND status = $2",
+			args: []any{"t1", "Closed"},
+		},
+		{
+			name: "sort and page",
+			builder: Select("*").From("tender").
+				Where("status = ?", "Published").
+				Sort(columns, "created", true).
+				OrderBy("id").
+				Page(10, 20),
+			sql:  "SELECT * FROM tender WHERE status = $1 ORDER BY created_at DESC, id LIMIT $2 OFFSET $3",
+			args: []any{"Published", 10, 20},
+		},
+		{
+			name:    "page without offset",
+			builder: Select("*").From("tender").Page(5, 0),
+			sql:     "SELECT * FROM tender LIMIT $1",
+			args:    []any{5},
+		},
+		{
+			name:    "page without limit",
+			builder: Select("*").From("tender").Page(0, 5),
+			sql:     "SELECT * FROM tender OFFSET $1",
+			args:    []any{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBuildKeepsInjectedValuesInArguments(t *testing.T) {
+	value := "x'); DROP TABLE tender; --"
+
+	sql, args, err := Select("*").From("tender").Where("name = ?", value).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if sql != "SELECT * FROM tender WHERE name = $1" {
+		t.Errorf("sql = %q", sql)
+	}
+	if !reflect.DeepEqual(args, []any{value}) {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		err     error
+	}{
+		{
+			name:    "unknown sort key",
+			builder: Select("*").From("tender").Sort(SortColumns{"name": "name"}, "name; DROP TABLE tender", false),
+			err:     ErrSortKey,
+		},
+		{
+			name:    "missing argument",
+			builder: Select("*").From("tender").Where("id = ? AND version = ?", "t1"),
+			err:     ErrPlaceholders,
+		},
+		{
+			name:    "extra argument",
+			builder: Select("*").From("tender").Where("id = ?", "t1", 2),
+			err:     ErrPlaceholders,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if sql != "" || args != nil {
+				t.Errorf("sql, args = %q, %v, want none", sql, args)
+			}
+		})
+	}
+}
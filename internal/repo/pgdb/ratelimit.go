@@ -0,0 +1,63 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type RateLimitRepo struct {
+	*postgres.Postgres
+}
+
+func NewRateLimitRepo(pg *postgres.Postgres) *RateLimitRepo {
+	return &RateLimitRepo{pg}
+}
+
+// Take refills bucket for time passed since last request and takes token if
+// there is one. Concurrent requests to one bucket are applied one by one
+func (r *RateLimitRepo) Take(ctx context.Context, in rt.TakeRateLimitTokenInput) (e.RateLimitBucket, error) {
+	refilled := `LEAST($2::float8, bucket.tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - bucket.updated_at)::float8 * $3::float8)`
+	sql := `
+		INSERT INTO rate_limit_bucket AS bucket (key, tokens, allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, TRUE, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE
+		SET
+			tokens = CASE WHEN ` + refilled + ` >= 1 THEN ` + refilled + ` - 1 ELSE ` + refilled + ` END,
+			allowed = ` + refilled + ` >= 1,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Key, in.Capacity, in.Rate)
+	if err != nil {
+		return e.RateLimitBucket{}, fmt.Errorf("pgdb - RateLimitRepo.Take - Conn.Query: %w", err)
+	}
+
+	b, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.RateLimitBucket])
+	if err != nil {
+		return e.RateLimitBucket{}, fmt.Errorf("pgdb - RateLimitRepo.Take - CollectExactlyOneRow: %w", err)
+	}
+
+	return b, nil
+}
+
+// DeleteIdle deletes buckets not used for age, they are full by then
+func (r *RateLimitRepo) DeleteIdle(ctx context.Context, age time.Duration) (int, error) {
+	sql := `
+		DELETE FROM rate_limit_bucket
+		WHERE updated_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, age.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("pgdb - RateLimitRepo.DeleteIdle - Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
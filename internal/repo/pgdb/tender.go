@@ -8,7 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -25,21 +25,23 @@ func NewTenderRepo(pg *postgres.Postgres) *TenderRepo {
 func (r *TenderRepo) CreateTender(ctx context.Context, in rt.CreateTenderInput) (e.Tender, error) {
 	sql := `
 		INSERT INTO tender
-			(name, description, type, organization_id, creator_username)
+			(name, description, type, organization_id, creator_username, visibility, deadline)
 		VALUES
-			($1, $2, $3, $4, $5)
+			($1, $2, $3, $4, $5, $6, $7)
 		RETURNING *
 	`
 
-	rows, err := r.Pool.Query(ctx, sql,
+	rows, err := r.Conn(ctx).Query(ctx, sql,
 		in.Name,
 		in.Description,
 		in.ServiceType,
 		in.OrganizationId,
 		in.CreatorUsername,
+		in.Visibility,
+		in.Deadline,
 	)
 	if err != nil {
-		return e.Tender{}, fmt.Errorf("pgdb - CreateTender - Pool.Query: %w", err)
+		return e.Tender{}, fmt.Errorf("pgdb - CreateTender - Conn.Query: %w", err)
 	}
 
 	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Tender])
@@ -62,9 +64,9 @@ func (r *TenderRepo) GetTendersByUsername(ctx context.Context, in rt.GetByUserna
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, in.Username, in.Limit, in.Offset)
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.Username, in.Limit, in.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("pgdb - GetTendersByUsername - Pool.Query: %w", err)
+		return nil, fmt.Errorf("pgdb - GetTendersByUsername - Conn.Query: %w", err)
 	}
 
 	tenders, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Tender])
@@ -76,30 +78,53 @@ func (r *TenderRepo) GetTendersByUsername(ctx context.Context, in rt.GetByUserna
 }
 
 func (r *TenderRepo) GetPublishedTenders(ctx context.Context, in rt.GetPublishedTendersInput) ([]e.Tender, error) {
+	sql, args, err := publishedTendersQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - GetPublishedTenders - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - GetPublishedTenders - Conn.Query: %w", err)
+	}
+
+	tenders, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Tender])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - GetPublishedTenders - CollectRows: %w", err)
+	}
+
+	return tenders, nil
+}
+
+func (r *TenderRepo) GetInvitedTenders(ctx context.Context, in rt.GetInvitedTendersInput) ([]e.Tender, error) {
 	sql := `
 		SELECT *
 		FROM (
 			SELECT DISTINCT ON (id) * FROM tender
 			ORDER BY id, version DESC
 		) AS last_versions
-		WHERE status='Published'
+		WHERE status = 'Published'
+		AND visibility <> 'Internal'
+		AND id IN (
+			SELECT tender_id FROM tender_invitation
+			WHERE user_id = $1
+			OR organization_id IN (
+				SELECT organization_id FROM organization_responsible
+				WHERE user_id = $1
+			)
+		)
+		ORDER BY name
+		LIMIT $2 OFFSET $3
 	`
-	if in.ServiceType != nil {
-		for i, s := range in.ServiceType {
-			in.ServiceType[i] = fmt.Sprintf("'%s'", s)
-		}
-		sql = fmt.Sprintf("%s\tAND type IN (%s)", sql, strings.Join(in.ServiceType, ","))
-	}
-	sql += "\n\t\tORDER BY name\n\t\tLIMIT $1 OFFSET $2"
 
-	rows, err := r.Pool.Query(ctx, sql, in.Limit, in.Offset)
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.UserId, in.Limit, in.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("pgdb - GetPublishedTenders - Pool.Query: %w", err)
+		return nil, fmt.Errorf("pgdb - GetInvitedTenders - Conn.Query: %w", err)
 	}
 
 	tenders, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Tender])
 	if err != nil {
-		return nil, fmt.Errorf("pgdb - GetPublishedTenders - CollectRows: %w", err)
+		return nil, fmt.Errorf("pgdb - GetInvitedTenders - CollectRows: %w", err)
 	}
 
 	return tenders, nil
@@ -107,18 +132,14 @@ func (r *TenderRepo) GetPublishedTenders(ctx context.Context, in rt.GetPublished
 
 // use repotypes.VersionLatest if need latest version
 func (r *TenderRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Tender, error) {
-	sql := `
-		SELECT DISTINCT ON (id) * FROM tender
-		WHERE id = $1
-	`
-	if version != rt.VersionLatest {
-		sql = fmt.Sprintf("%s AND version = %d", sql, version)
+	sql, args, err := versionQuery("tender", id, version).Build()
+	if err != nil {
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.Get - Build: %w", err)
 	}
-	sql += "\n\t\tORDER BY id, version DESC"
 
-	rows, err := r.Pool.Query(ctx, sql, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.Get - Pool.Query: %w", err)
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.Get - Conn.Query: %w", err)
 	}
 
 	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Tender])
@@ -146,9 +167,9 @@ func (r *TenderRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status stri
 		RETURNING *;
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, status, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, status, id)
 	if err != nil {
-		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.ChangeStatus - Pool.Query: %w", err)
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.ChangeStatus - Conn.Query: %w", err)
 	}
 
 	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Tender])
@@ -162,15 +183,45 @@ func (r *TenderRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status stri
 	return t, nil
 }
 
+func (r *TenderRepo) ChangeVisibility(ctx context.Context, id uuid.UUID, visibility string) (e.Tender, error) {
+	sql := `
+		UPDATE tender
+		SET visibility = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2
+			AND version = (
+				SELECT MAX(version)
+				FROM tender
+				WHERE id = $2
+			)
+		RETURNING *;
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, visibility, id)
+	if err != nil {
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.ChangeVisibility - Conn.Query: %w", err)
+	}
+
+	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Tender])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.Tender{}, repoerrors.ErrNotFound
+		}
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.ChangeVisibility - CollectExactlyOneRow: %w", err)
+	}
+
+	return t, nil
+}
+
 func (r *TenderRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedInput) (e.Tender, error) {
 	sql := `
 		INSERT INTO tender
-			(id, name, description, type, organization_id, version, creator_username, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			(id, name, description, type, organization_id, version, creator_username, status, visibility, deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING *
 	`
 
-	rows, err := r.Pool.Query(ctx, sql,
+	rows, err := r.Conn(ctx).Query(ctx, sql,
 		in.Id,
 		in.Name,
 		in.Description,
@@ -179,13 +230,19 @@ func (r *TenderRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedI
 		in.Version,
 		in.CreatorUsername,
 		in.Status,
+		in.Visibility,
+		in.Deadline,
 	)
 	if err != nil {
-		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.CreateSpecified - Pool.Query: %w", err)
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.CreateSpecified - Conn.Query: %w", err)
 	}
 
 	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.Tender])
 	if err != nil {
+		// Version was created by concurrent write
+		if isPgError(err, codeUniqueViolation) {
+			return e.Tender{}, repoerrors.ErrVersionConflict
+		}
 		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.CreateSpecified - pgx.CollectExactlyOneRow: %w", err)
 	}
 
@@ -199,9 +256,9 @@ func (r *TenderRepo) GetLatestVersion(ctx context.Context, id uuid.UUID) (int, e
 		ORDER BY id, version DESC
 	`
 
-	rows, err := r.Pool.Query(ctx, sql, id)
+	rows, err := r.Conn(ctx).Query(ctx, sql, id)
 	if err != nil {
-		return 0, fmt.Errorf("pgdb - TenderRepo.GetLatestVersion - Pool.Query: %w", err)
+		return 0, fmt.Errorf("pgdb - TenderRepo.GetLatestVersion - Conn.Query: %w", err)
 	}
 
 	latestVersion, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int])
@@ -211,3 +268,102 @@ func (r *TenderRepo) GetLatestVersion(ctx context.Context, id uuid.UUID) (int, e
 
 	return latestVersion, nil
 }
+
+// ClaimDeadlineReminders returns published tenders with deadline within window
+// which weren't reminded about yet and marks them reminded
+func (r *TenderRepo) ClaimDeadlineReminders(ctx context.Context, window time.Duration) ([]e.Tender, error) {
+	sql := `
+		WITH last_versions AS (
+			SELECT DISTINCT ON (id) * FROM tender
+			ORDER BY id, version DESC
+		), claimed AS (
+			INSERT INTO tender_deadline_reminder (tender_id, deadline)
+			SELECT id, deadline FROM last_versions
+			WHERE status = 'Published'
+			AND deadline > CURRENT_TIMESTAMP
+			AND deadline <= CURRENT_TIMESTAMP + make_interval(secs => $1)
+			ON CONFLICT (tender_id, deadline) DO NOTHING
+			RETURNING tender_id
+		)
+		SELECT last_versions.* FROM last_versions
+		JOIN claimed ON claimed.tender_id = last_versions.id
+		ORDER BY last_versions.deadline
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, window.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - TenderRepo.ClaimDeadlineReminders - Conn.Query: %w", err)
+	}
+
+	tenders, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.Tender])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - TenderRepo.ClaimDeadlineReminders - CollectRows: %w", err)
+	}
+
+	return tenders, nil
+}
+
+// Lock locks version of tender until end of transaction, must be called in
+// transaction. ErrVersionConflict means version was changed after updatedAt
+// or is not latest anymore
+func (r *TenderRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	sql := `
+		SELECT version FROM tender
+		WHERE id = $1 AND version = $2 AND updated_at = $3
+		FOR UPDATE
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id, version, updatedAt)
+	if err != nil {
+		return fmt.Errorf("pgdb - TenderRepo.Lock - Conn.Query: %w", err)
+	}
+
+	if _, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int]); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repoerrors.ErrVersionConflict
+		}
+		return fmt.Errorf("pgdb - TenderRepo.Lock - CollectExactlyOneRow: %w", err)
+	}
+
+	// Next statement sees versions committed before lock was granted
+	sql = `
+		SELECT MAX(version) FROM tender
+		WHERE id = $1
+	`
+
+	var latest int
+	if err := r.Conn(ctx).QueryRow(ctx, sql, id).Scan(&latest); err != nil {
+		return fmt.Errorf("pgdb - TenderRepo.Lock - QueryRow: %w", err)
+	}
+	if latest != version {
+		return repoerrors.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// GetForUpdate returns latest version of tender locked until end of transaction,
+// must be called in transaction. ErrVersionConflict is returned when tender is
+// changed before it is locked
+func (r *TenderRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Tender, error) {
+	t, err := r.Get(ctx, id, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Tender{}, err
+		}
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.GetForUpdate - %w", err)
+	}
+
+	// Version read may be changed before lock is granted. Lock checks
+	// updated_at of locked row and MAX(version), under read committed they
+	// see changes committed while waiting, so conflict is returned and caller
+	// runs transaction again instead of reading in same transaction
+	if err := r.Lock(ctx, t.Id, t.Version, t.UpdatedAt); err != nil {
+		if errors.Is(err, repoerrors.ErrVersionConflict) {
+			return e.Tender{}, err
+		}
+		return e.Tender{}, fmt.Errorf("pgdb - TenderRepo.GetForUpdate - %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,222 @@
+package pgdb
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"app/pkg/postgres"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type WebhookRepo struct {
+	*postgres.Postgres
+}
+
+func NewWebhookRepo(pg *postgres.Postgres) *WebhookRepo {
+	return &WebhookRepo{pg}
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, in rt.CreateWebhookInput) (e.WebhookSubscription, error) {
+	sql := `
+		INSERT INTO webhook_subscription
+			(organization_id, url, event_types, secret, created_by)
+		VALUES
+			($1, $2, $3, $4, $5)
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, in.OrganizationId, in.URL, in.EventTypes, in.Secret, in.CreatedBy)
+	if err != nil {
+		return e.WebhookSubscription{}, fmt.Errorf("pgdb - WebhookRepo.Create - Conn.Query: %w", err)
+	}
+
+	sub, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.WebhookSubscription])
+	if err != nil {
+		if isPgError(err, codeForeignKeyViolation) {
+			return e.WebhookSubscription{}, repoerrors.ErrInvalidReference
+		}
+		return e.WebhookSubscription{}, fmt.Errorf("pgdb - WebhookRepo.Create - CollectExactlyOneRow: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *WebhookRepo) Get(ctx context.Context, orgId, id uuid.UUID) (e.WebhookSubscription, error) {
+	sql := `
+		SELECT * FROM webhook_subscription
+		WHERE id = $1 AND organization_id = $2
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id, orgId)
+	if err != nil {
+		return e.WebhookSubscription{}, fmt.Errorf("pgdb - WebhookRepo.Get - Conn.Query: %w", err)
+	}
+
+	sub, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.WebhookSubscription])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.WebhookSubscription{}, repoerrors.ErrNotFound
+		}
+		return e.WebhookSubscription{}, fmt.Errorf("pgdb - WebhookRepo.Get - CollectExactlyOneRow: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *WebhookRepo) GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.WebhookSubscription, error) {
+	sql := `
+		SELECT * FROM webhook_subscription
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, orgId)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetByOrganization - Conn.Query: %w", err)
+	}
+
+	subs, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.WebhookSubscription])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetByOrganization - CollectRows: %w", err)
+	}
+
+	return subs, nil
+}
+
+// Delete removes subscription with its delivery log
+func (r *WebhookRepo) Delete(ctx context.Context, orgId, id uuid.UUID) error {
+	sql := `
+		DELETE FROM webhook_subscription
+		WHERE id = $1 AND organization_id = $2
+	`
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, id, orgId)
+	if err != nil {
+		return fmt.Errorf("pgdb - WebhookRepo.Delete - Conn.Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repoerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// AddDeliveries is idempotent, event is added to each subscription once
+func (r *WebhookRepo) AddDeliveries(ctx context.Context, in rt.AddWebhookDeliveriesInput) error {
+	sql := `
+		INSERT INTO webhook_delivery
+			(subscription_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3
+		FROM webhook_subscription
+		WHERE organization_id = ANY($4)
+		AND $2 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, in.EventId, in.EventType, in.Payload, in.OrganizationIds)
+	if err != nil {
+		return fmt.Errorf("pgdb - WebhookRepo.AddDeliveries - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, in rt.GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error) {
+	sql, args, err := webhookDeliveriesQuery(in).Build()
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetDeliveries - Build: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetDeliveries - Conn.Query: %w", err)
+	}
+
+	deliveries, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.WebhookDelivery])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetDeliveries - CollectRows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetPending locks due deliveries, must be called in transaction.
+// Deliveries locked by other workers are skipped
+func (r *WebhookRepo) GetPending(ctx context.Context, limit int) ([]e.WebhookDispatch, error) {
+	sql := `
+		SELECT webhook_delivery.*, webhook_subscription.url, webhook_subscription.secret
+		FROM webhook_delivery
+		JOIN webhook_subscription ON webhook_subscription.id = webhook_delivery.subscription_id
+		WHERE webhook_delivery.status = 'Pending'
+		AND webhook_delivery.next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY webhook_delivery.next_attempt_at, webhook_delivery.id
+		LIMIT $1
+		FOR UPDATE OF webhook_delivery SKIP LOCKED
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, limit)
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetPending - Conn.Query: %w", err)
+	}
+
+	dispatches, err := pgx.CollectRows(rows, pgx.RowToStructByName[e.WebhookDispatch])
+	if err != nil {
+		return nil, fmt.Errorf("pgdb - WebhookRepo.GetPending - CollectRows: %w", err)
+	}
+
+	return dispatches, nil
+}
+
+func (r *WebhookRepo) RecordAttempt(ctx context.Context, in rt.RecordWebhookAttemptInput) error {
+	sql := `
+		UPDATE webhook_delivery
+		SET attempts = attempts + 1,
+			status = $2::text::webhook_delivery_status,
+			last_status_code = NULLIF($3, 0),
+			last_error = $4,
+			delivered_at = CASE WHEN $2::text = 'Delivered' THEN CURRENT_TIMESTAMP END,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $5),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+
+	_, err := r.Conn(ctx).Exec(ctx, sql, in.DeliveryId, in.Status, in.StatusCode, in.Error, in.Delay.Seconds())
+	if err != nil {
+		return fmt.Errorf("pgdb - WebhookRepo.RecordAttempt - Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Redeliver puts delivery back to queue with fresh attempts budget
+func (r *WebhookRepo) Redeliver(ctx context.Context, subscriptionId, id uuid.UUID) (e.WebhookDelivery, error) {
+	sql := `
+		UPDATE webhook_delivery
+		SET status = 'Pending',
+			attempts = 0,
+			next_attempt_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND subscription_id = $2
+		RETURNING *
+	`
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, id, subscriptionId)
+	if err != nil {
+		return e.WebhookDelivery{}, fmt.Errorf("pgdb - WebhookRepo.Redeliver - Conn.Query: %w", err)
+	}
+
+	delivery, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[e.WebhookDelivery])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return e.WebhookDelivery{}, repoerrors.ErrNotFound
+		}
+		return e.WebhookDelivery{}, fmt.Errorf("pgdb - WebhookRepo.Redeliver - CollectExactlyOneRow: %w", err)
+	}
+
+	return delivery, nil
+}
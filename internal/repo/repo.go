@@ -2,10 +2,12 @@ package repo
 
 import (
 	e "app/internal/entity"
+	"app/internal/repo/memdb"
 	"app/internal/repo/pgdb"
 	rt "app/internal/repo/repotypes"
 	"app/pkg/postgres"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,14 +20,23 @@ type Tender interface {
 	GetTendersByUsername(ctx context.Context, in rt.GetByUsernameInput) ([]e.Tender, error)
 	GetPublishedTenders(ctx context.Context, in rt.GetPublishedTendersInput) ([]e.Tender, error)
 	GetLatestVersion(ctx context.Context, id uuid.UUID) (int, error)
+	GetInvitedTenders(ctx context.Context, in rt.GetInvitedTendersInput) ([]e.Tender, error)
+	ClaimDeadlineReminders(ctx context.Context, window time.Duration) ([]e.Tender, error)
+	ChangeVisibility(ctx context.Context, id uuid.UUID, visibility string) (e.Tender, error)
+	Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error
+	GetForUpdate(ctx context.Context, id uuid.UUID) (e.Tender, error)
 }
 
 type Employee interface {
 	IsResponsible(ctx context.Context, orgId, userId uuid.UUID) (bool, error)
-	IsResponsibleSimplified(ctx context.Context, userId uuid.UUID) (bool, error)
+	GetRoles(ctx context.Context, orgId, userId uuid.UUID) ([]string, error)
 	GetByUsername(ctx context.Context, username string) (e.Employee, error)
 	GetById(ctx context.Context, id uuid.UUID) (e.Employee, error)
-	GetOrgIdFromResponsible(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	Create(ctx context.Context, in rt.CreateEmployeeInput) (e.Employee, error)
+	Update(ctx context.Context, in rt.UpdateEmployeeInput) (e.Employee, error)
+	SetActive(ctx context.Context, id uuid.UUID, active bool) (e.Employee, error)
+	SetAdmin(ctx context.Context, id uuid.UUID, admin bool) (e.Employee, error)
+	Search(ctx context.Context, in rt.SearchEmployeesInput) ([]e.Employee, error)
 }
 
 type Bid interface {
@@ -33,18 +44,184 @@ type Bid interface {
 	Create(ctx context.Context, in rt.CreateBidInput) (e.Bid, error)
 	CreateSpecified(ctx context.Context, in rt.CreateSpecifiedBidInput) (e.Bid, error)
 	ChangeStatus(ctx context.Context, id uuid.UUID, status string) (e.Bid, error)
+	GetBidders(ctx context.Context, tenderId uuid.UUID) (rt.TenderBidders, error)
+	Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error
+	GetForUpdate(ctx context.Context, id uuid.UUID) (e.Bid, error)
+}
+
+type Invitation interface {
+	Create(ctx context.Context, in rt.CreateInvitationInput) (e.TenderInvitation, error)
+	Delete(ctx context.Context, tenderId, invitationId uuid.UUID) error
+	GetByTender(ctx context.Context, tenderId uuid.UUID) ([]e.TenderInvitation, error)
+	IsInvited(ctx context.Context, tenderId, userId uuid.UUID) (bool, error)
+}
+
+type Organization interface {
+	Create(ctx context.Context, in rt.CreateOrganizationInput) (e.Organization, error)
+	Get(ctx context.Context, id uuid.UUID) (e.Organization, error)
+	Update(ctx context.Context, in rt.UpdateOrganizationInput) (e.Organization, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, in rt.SearchOrganizationsInput) ([]e.Organization, error)
+	AddResponsible(ctx context.Context, orgId, userId uuid.UUID) error
+	RemoveResponsible(ctx context.Context, orgId, userId uuid.UUID) error
+	GetResponsibles(ctx context.Context, orgId uuid.UUID) ([]e.Employee, error)
+	SetRoles(ctx context.Context, orgId, userId uuid.UUID, roles []string) error
+	GetRoleHolders(ctx context.Context, orgId uuid.UUID, role string) ([]uuid.UUID, error)
+	LockResponsibles(ctx context.Context, orgId uuid.UUID) error
+}
+
+type Credential interface {
+	Get(ctx context.Context, employeeId uuid.UUID) (e.Credential, error)
+	SetPassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error
+	CreatePassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error
+	RegisterFailure(ctx context.Context, employeeId uuid.UUID, maxAttempts int, lockout time.Duration) (e.Credential, error)
+	ResetFailures(ctx context.Context, employeeId uuid.UUID) error
+	CreateResetToken(ctx context.Context, in rt.CreateResetTokenInput) (time.Time, error)
+	UseResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	AddLoginAttempt(ctx context.Context, in rt.CreateLoginAttemptInput) error
+	GetLoginAttempts(ctx context.Context, in rt.GetLoginAttemptsInput) ([]e.LoginAttempt, error)
+}
+
+type APIKey interface {
+	Create(ctx context.Context, in rt.CreateAPIKeyInput) (e.APIKey, error)
+	GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.APIKey, error)
+	Revoke(ctx context.Context, orgId, id uuid.UUID) error
+	Use(ctx context.Context, secretHash string) (e.APIKey, error)
+}
+
+type Audit interface {
+	Add(ctx context.Context, in rt.CreateAuditEntryInput) error
+	GetOrganizationLog(ctx context.Context, in rt.GetAuditLogInput) ([]e.AuditEntry, error)
+	Seal(ctx context.Context) error
+	GetRange(ctx context.Context, from, to time.Time) ([]e.AuditEntry, error)
+}
+
+type Outbox interface {
+	Add(ctx context.Context, in rt.CreateOutboxEventInput) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]e.OutboxEvent, error)
+	GetDeliveredSinks(ctx context.Context, eventId uuid.UUID) ([]string, error)
+	RecordDelivery(ctx context.Context, eventId uuid.UUID, sink, lastError string) error
+	MarkProcessed(ctx context.Context, eventId uuid.UUID) error
+	Reschedule(ctx context.Context, eventId uuid.UUID, delay time.Duration) error
+}
+
+// OutboxFeed passes events added to outbox by any instance
+type OutboxFeed interface {
+	Listen(ctx context.Context, handle func(e.OutboxEvent)) error
+}
+
+type Webhook interface {
+	Create(ctx context.Context, in rt.CreateWebhookInput) (e.WebhookSubscription, error)
+	Get(ctx context.Context, orgId, id uuid.UUID) (e.WebhookSubscription, error)
+	GetByOrganization(ctx context.Context, orgId uuid.UUID) ([]e.WebhookSubscription, error)
+	Delete(ctx context.Context, orgId, id uuid.UUID) error
+	AddDeliveries(ctx context.Context, in rt.AddWebhookDeliveriesInput) error
+	GetDeliveries(ctx context.Context, in rt.GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error)
+	GetPending(ctx context.Context, limit int) ([]e.WebhookDispatch, error)
+	RecordAttempt(ctx context.Context, in rt.RecordWebhookAttemptInput) error
+	Redeliver(ctx context.Context, subscriptionId, id uuid.UUID) (e.WebhookDelivery, error)
+}
+
+type Email interface {
+	GetPreferences(ctx context.Context, employeeId uuid.UUID) ([]string, error)
+	SetPreferences(ctx context.Context, employeeId uuid.UUID, kinds []string) error
+	GetRecipients(ctx context.Context, in rt.GetEmailRecipientsInput) ([]e.Employee, error)
+	AddMessage(ctx context.Context, in rt.CreateEmailMessageInput) error
+	GetPending(ctx context.Context, limit int) ([]e.EmailMessage, error)
+	RecordAttempt(ctx context.Context, in rt.RecordEmailAttemptInput) error
+}
+
+type Notification interface {
+	Add(ctx context.Context, in rt.AddNotificationsInput) (int, error)
+	GetByEmployee(ctx context.Context, in rt.GetNotificationsInput) ([]e.Notification, error)
+	CountUnread(ctx context.Context, employeeId uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, employeeId, id uuid.UUID) (e.Notification, error)
+	MarkAllRead(ctx context.Context, employeeId uuid.UUID) (int, error)
+	DeleteOlderThan(ctx context.Context, age time.Duration) (int, error)
+}
+
+type Idempotency interface {
+	Claim(ctx context.Context, in rt.ClaimIdempotencyKeyInput) (bool, error)
+	Get(ctx context.Context, caller, key string) (e.IdempotencyKey, error)
+	Complete(ctx context.Context, in rt.CompleteIdempotencyKeyInput) error
+	Extend(ctx context.Context, caller, key string, lease time.Duration) error
+	Release(ctx context.Context, caller, key string) error
+	DeleteOlderThan(ctx context.Context, age time.Duration) (int, error)
+}
+
+type RateLimit interface {
+	Take(ctx context.Context, in rt.TakeRateLimitTokenInput) (e.RateLimitBucket, error)
+	DeleteIdle(ctx context.Context, age time.Duration) (int, error)
+}
+
+// Transactor runs fn in transaction, repositories called with fn context join it.
+// Nested call runs fn in savepoint
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// InTxRetry repeats transaction lost to serialization conflict or deadlock,
+	// fn must not have effects outside database
+	InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Repositories struct {
 	Tender
 	Employee
 	Bid
+	Invitation
+	Organization
+	Credential
+	APIKey
+	Audit
+	Outbox
+	Webhook
+	Email
+	Notification
+	Idempotency
+	RateLimit
+	Transactor
+
+	// OutboxFeed is nil for memory repos, they serve single instance
+	OutboxFeed OutboxFeed
+}
+
+func NewPostgresRepo(pg *postgres.Postgres, tx *postgres.TxManager) *Repositories {
+	return &Repositories{
+		Tender:       pgdb.NewTenderRepo(pg),
+		Employee:     pgdb.NewEmployeeRepo(pg),
+		Bid:          pgdb.NewBidRepo(pg),
+		Invitation:   pgdb.NewInvitationRepo(pg),
+		Organization: pgdb.NewOrganizationRepo(pg),
+		Credential:   pgdb.NewCredentialRepo(pg),
+		APIKey:       pgdb.NewAPIKeyRepo(pg),
+		Audit:        pgdb.NewAuditRepo(pg),
+		Outbox:       pgdb.NewOutboxRepo(pg),
+		Webhook:      pgdb.NewWebhookRepo(pg),
+		Email:        pgdb.NewEmailRepo(pg),
+		Notification: pgdb.NewNotificationRepo(pg),
+		Idempotency:  pgdb.NewIdempotencyRepo(pg),
+		RateLimit:    pgdb.NewRateLimitRepo(pg),
+		Transactor:   tx,
+		OutboxFeed:   pgdb.NewOutboxRepo(pg),
+	}
 }
 
-func NewPostgresRepo(pg *postgres.Postgres) *Repositories {
+// NewMemoryRepo keeps data in process memory, it is lost on restart
+func NewMemoryRepo(s *memdb.Store) *Repositories {
 	return &Repositories{
-		Tender:   pgdb.NewTenderRepo(pg),
-		Employee: pgdb.NewEmployeeRepo(pg),
-		Bid:      pgdb.NewBidRepo(pg),
+		Tender:       memdb.NewTenderRepo(s),
+		Employee:     memdb.NewEmployeeRepo(s),
+		Bid:          memdb.NewBidRepo(s),
+		Invitation:   memdb.NewInvitationRepo(s),
+		Organization: memdb.NewOrganizationRepo(s),
+		Credential:   memdb.NewCredentialRepo(s),
+		APIKey:       memdb.NewAPIKeyRepo(s),
+		Audit:        memdb.NewAuditRepo(s),
+		Outbox:       memdb.NewOutboxRepo(s),
+		Webhook:      memdb.NewWebhookRepo(s),
+		Email:        memdb.NewEmailRepo(s),
+		Notification: memdb.NewNotificationRepo(s),
+		Idempotency:  memdb.NewIdempotencyRepo(s),
+		RateLimit:    memdb.NewRateLimitRepo(s),
+		Transactor:   s,
 	}
 }
@@ -3,5 +3,9 @@ package repoerrors
 import "errors"
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrInvalidReference = errors.New("referenced entity doesn't exist")
+	ErrEmailTaken       = errors.New("email is already used")
+	ErrVersionConflict  = errors.New("version was changed concurrently")
 )
@@ -0,0 +1,605 @@
+// Package repotest is contract test suite of repositories, every implementation
+// of repo.Repositories must pass it
+package repotest
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Harness runs test with repositories under test. Rows written by test must not
+// be seen by other tests, database may already have rows of its own
+type Harness func(t *testing.T, test func(ctx context.Context, t *testing.T, repos *repo.Repositories))
+
+// Run runs contract tests of Tender, Employee and Bid repositories
+func Run(t *testing.T, h Harness) {
+	tests := []struct {
+		name string
+		test func(ctx context.Context, t *testing.T, repos *repo.Repositories)
+	}{
+		{"TenderNotFound", testTenderNotFound},
+		{"TenderVersions", testTenderVersions},
+		{"TenderChangeLatest", testTenderChangeLatest},
+		{"TenderLock", testTenderLock},
+		{"TendersByUsername", testTendersByUsername},
+		{"PublishedTenders", testPublishedTenders},
+		{"EmployeeCreate", testEmployeeCreate},
+		{"EmployeeUpdate", testEmployeeUpdate},
+		{"EmployeeRoles", testEmployeeRoles},
+		{"BidNotFound", testBidNotFound},
+		{"BidVersions", testBidVersions},
+		{"BidLock", testBidLock},
+		{"Bidders", testBidders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h(t, tt.test)
+		})
+	}
+}
+
+// attempt runs call expected to fail in nested transaction, so failed statement
+// doesn't break transaction of test
+func attempt(ctx context.Context, repos *repo.Repositories, call func(ctx context.Context) error) error {
+	return repos.Transactor.InTx(ctx, call)
+}
+
+func wantErr(t *testing.T, what string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: err = %v, want %v", what, err, want)
+	}
+}
+
+func must(t *testing.T, what string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+}
+
+// uniqueName keeps names of test rows apart from rows of other tests
+func uniqueName(prefix string) string {
+	return prefix + "-" + uuid.NewString()[:8]
+}
+
+func newEmployee(ctx context.Context, t *testing.T, repos *repo.Repositories) e.Employee {
+	t.Helper()
+	emp, err := repos.Employee.Create(ctx, rt.CreateEmployeeInput{
+		Username:  uniqueName("user"),
+		FirstName: "First",
+		LastName:  "Last",
+	})
+	must(t, "Employee.Create", err)
+	return emp
+}
+
+func newOrganization(ctx context.Context, t *testing.T, repos *repo.Repositories, responsibleId uuid.UUID) e.Organization {
+	t.Helper()
+	org, err := repos.Organization.Create(ctx, rt.CreateOrganizationInput{
+		Name:          uniqueName("org"),
+		Description:   "Description",
+		Type:          "LLC",
+		ResponsibleId: responsibleId,
+	})
+	must(t, "Organization.Create", err)
+	return org
+}
+
+func tenderInput(orgId uuid.UUID, creator, name, visibility string) rt.CreateTenderInput {
+	return rt.CreateTenderInput{
+		Name:            name,
+		Description:     "Description",
+		ServiceType:     "Construction",
+		OrganizationId:  orgId,
+		CreatorUsername: creator,
+		Visibility:      visibility,
+	}
+}
+
+// newTender creates tender of new organization with given visibility
+func newTender(ctx context.Context, t *testing.T, repos *repo.Repositories, visibility string) (e.Tender, e.Employee) {
+	t.Helper()
+	emp := newEmployee(ctx, t, repos)
+	org := newOrganization(ctx, t, repos, emp.Id)
+	tender, err := repos.Tender.CreateTender(ctx, tenderInput(org.Id, emp.Username, uniqueName("tender"), visibility))
+	must(t, "Tender.CreateTender", err)
+	return tender, emp
+}
+
+// newTenderVersion creates next version of tender named name
+func newTenderVersion(ctx context.Context, t *testing.T, repos *repo.Repositories, prev e.Tender, name string) e.Tender {
+	t.Helper()
+	in := tenderInput(prev.OrganizationId, prev.CreatorUsername, name, prev.Visibility)
+	in.Status = prev.Status
+	tender, err := repos.Tender.CreateSpecified(ctx, rt.CreateSpecifiedInput{
+		Id:                prev.Id,
+		Version:           prev.Version + 1,
+		CreateTenderInput: in,
+	})
+	must(t, "Tender.CreateSpecified", err)
+	return tender
+}
+
+func testTenderNotFound(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	tender, _ := newTender(ctx, t, repos, "Public")
+
+	_, err := repos.Tender.Get(ctx, uuid.New(), rt.VersionLatest)
+	wantErr(t, "Get unknown tender", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Tender.Get(ctx, tender.Id, 2)
+	wantErr(t, "Get unknown version", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Tender.GetLatestVersion(ctx, uuid.New())
+	wantErr(t, "GetLatestVersion", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Tender.ChangeStatus(ctx, uuid.New(), "Published")
+	wantErr(t, "ChangeStatus", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Tender.ChangeVisibility(ctx, uuid.New(), "Internal")
+	wantErr(t, "ChangeVisibility", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Tender.GetForUpdate(ctx, uuid.New())
+	wantErr(t, "GetForUpdate", err, repoerrors.ErrNotFound)
+}
+
+func testTenderVersions(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	v1, _ := newTender(ctx, t, repos, "Public")
+	if v1.Version != 1 || v1.Status != "Created" {
+		t.Fatalf("created tender version = %d, status = %q, want 1, Created", v1.Version, v1.Status)
+	}
+
+	v2 := newTenderVersion(ctx, t, repos, v1, "renamed")
+	if v2.Id != v1.Id || v2.Version != 2 {
+		t.Fatalf("new version = %v/%d, want %v/2", v2.Id, v2.Version, v1.Id)
+	}
+
+	latest, err := repos.Tender.Get(ctx, v1.Id, rt.VersionLatest)
+	must(t, "Get latest", err)
+	if latest.Version != 2 || latest.Name != "renamed" {
+		t.Errorf("latest = %d %q, want 2 renamed", latest.Version, latest.Name)
+	}
+
+	old, err := repos.Tender.Get(ctx, v1.Id, 1)
+	must(t, "Get version 1", err)
+	if old.Name != v1.Name {
+		t.Errorf("version 1 name = %q, want %q", old.Name, v1.Name)
+	}
+
+	version, err := repos.Tender.GetLatestVersion(ctx, v1.Id)
+	must(t, "GetLatestVersion", err)
+	if version != 2 {
+		t.Errorf("GetLatestVersion = %d, want 2", version)
+	}
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		_, err := repos.Tender.CreateSpecified(ctx, rt.CreateSpecifiedInput{
+			Id:                v1.Id,
+			Version:           2,
+			CreateTenderInput: tenderInput(v1.OrganizationId, v1.CreatorUsername, "concurrent", "Public"),
+		})
+		return err
+	})
+	wantErr(t, "CreateSpecified existing version", err, repoerrors.ErrVersionConflict)
+
+	latest, err = repos.Tender.Get(ctx, v1.Id, rt.VersionLatest)
+	must(t, "Get latest", err)
+	if latest.Name != "renamed" {
+		t.Errorf("latest name after conflict = %q, want renamed", latest.Name)
+	}
+}
+
+func testTenderChangeLatest(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	v1, _ := newTender(ctx, t, repos, "Public")
+	newTenderVersion(ctx, t, repos, v1, "renamed")
+
+	tender, err := repos.Tender.ChangeStatus(ctx, v1.Id, "Published")
+	must(t, "ChangeStatus", err)
+	if tender.Version != 2 || tender.Status != "Published" {
+		t.Errorf("ChangeStatus = %d %q, want 2 Published", tender.Version, tender.Status)
+	}
+
+	tender, err = repos.Tender.ChangeVisibility(ctx, v1.Id, "Internal")
+	must(t, "ChangeVisibility", err)
+	if tender.Version != 2 || tender.Visibility != "Internal" || tender.Status != "Published" {
+		t.Errorf("ChangeVisibility = %d %q %q, want 2 Internal Published", tender.Version, tender.Visibility, tender.Status)
+	}
+
+	old, err := repos.Tender.Get(ctx, v1.Id, 1)
+	must(t, "Get version 1", err)
+	if old.Status != "Created" || old.Visibility != "Public" {
+		t.Errorf("version 1 = %q %q, want Created Public", old.Status, old.Visibility)
+	}
+}
+
+func testTenderLock(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	v1, _ := newTender(ctx, t, repos, "Public")
+	v2 := newTenderVersion(ctx, t, repos, v1, "renamed")
+
+	must(t, "Lock latest", repos.Tender.Lock(ctx, v2.Id, v2.Version, v2.UpdatedAt))
+
+	err := attempt(ctx, repos, func(ctx context.Context) error {
+		return repos.Tender.Lock(ctx, v1.Id, v1.Version, v1.UpdatedAt)
+	})
+	wantErr(t, "Lock stale version", err, repoerrors.ErrVersionConflict)
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		return repos.Tender.Lock(ctx, v2.Id, v2.Version, v2.UpdatedAt.Add(-time.Second))
+	})
+	wantErr(t, "Lock changed version", err, repoerrors.ErrVersionConflict)
+
+	tender, err := repos.Tender.GetForUpdate(ctx, v1.Id)
+	must(t, "GetForUpdate", err)
+	if tender.Version != 2 {
+		t.Errorf("GetForUpdate version = %d, want 2", tender.Version)
+	}
+}
+
+func testTendersByUsername(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	emp := newEmployee(ctx, t, repos)
+	org := newOrganization(ctx, t, repos, emp.Id)
+
+	for _, name := range []string{"b", "a"} {
+		_, err := repos.Tender.CreateTender(ctx, tenderInput(org.Id, emp.Username, name, "Public"))
+		must(t, "CreateTender", err)
+	}
+	tenders, err := repos.Tender.GetTendersByUsername(ctx, rt.GetByUsernameInput{Limit: 10, Username: emp.Username})
+	must(t, "GetTendersByUsername", err)
+	if len(tenders) != 2 {
+		t.Fatalf("GetTendersByUsername returned %d tenders, want 2", len(tenders))
+	}
+	// Renamed version of "a" takes place after "b"
+	newTenderVersion(ctx, t, repos, tenders[0], "c")
+
+	tenders, err = repos.Tender.GetTendersByUsername(ctx, rt.GetByUsernameInput{Limit: 10, Username: emp.Username})
+	must(t, "GetTendersByUsername", err)
+	if got := tenderNames(tenders); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("GetTendersByUsername = %v, want [b c]", got)
+	}
+
+	tenders, err = repos.Tender.GetTendersByUsername(ctx, rt.GetByUsernameInput{Limit: 1, Offset: 1, Username: emp.Username})
+	must(t, "GetTendersByUsername", err)
+	if got := tenderNames(tenders); !slices.Equal(got, []string{"c"}) {
+		t.Errorf("GetTendersByUsername second page = %v, want [c]", got)
+	}
+}
+
+func testPublishedTenders(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	outsider := newEmployee(ctx, t, repos)
+	responsible := newEmployee(ctx, t, repos)
+	org := newOrganization(ctx, t, repos, responsible.Id)
+
+	tenders := map[string]uuid.UUID{}
+	for _, visibility := range []string{"Public", "InviteOnly", "Internal"} {
+		tender, err := repos.Tender.CreateTender(ctx, tenderInput(org.Id, responsible.Username, uniqueName(visibility), visibility))
+		must(t, "CreateTender", err)
+		_, err = repos.Tender.ChangeStatus(ctx, tender.Id, "Published")
+		must(t, "ChangeStatus", err)
+		tenders[visibility] = tender.Id
+	}
+	unpublished, err := repos.Tender.CreateTender(ctx, tenderInput(org.Id, responsible.Username, uniqueName("unpublished"), "Public"))
+	must(t, "CreateTender", err)
+	tenders["Unpublished"] = unpublished.Id
+
+	// visible reports which of test tenders viewer sees among published ones
+	visible := func(viewerId uuid.UUID) []string {
+		t.Helper()
+		published, err := repos.Tender.GetPublishedTenders(ctx, rt.GetPublishedTendersInput{ViewerId: viewerId})
+		must(t, "GetPublishedTenders", err)
+
+		var seen []string
+		for _, name := range []string{"Public", "InviteOnly", "Internal", "Unpublished"} {
+			for _, tender := range published {
+				if tender.Id == tenders[name] {
+					seen = append(seen, name)
+				}
+			}
+		}
+		return seen
+	}
+
+	if got := visible(uuid.Nil); !slices.Equal(got, []string{"Public"}) {
+		t.Errorf("anonymous sees %v, want [Public]", got)
+	}
+	if got := visible(outsider.Id); !slices.Equal(got, []string{"Public"}) {
+		t.Errorf("outsider sees %v, want [Public]", got)
+	}
+	if got := visible(responsible.Id); !slices.Equal(got, []string{"Public", "InviteOnly", "Internal"}) {
+		t.Errorf("responsible sees %v, want [Public InviteOnly Internal]", got)
+	}
+
+	for _, name := range []string{"InviteOnly", "Internal"} {
+		_, err := repos.Invitation.Create(ctx, rt.CreateInvitationInput{
+			TenderId: tenders[name],
+			UserId:   uuid.NullUUID{UUID: outsider.Id, Valid: true},
+		})
+		must(t, "Invitation.Create", err)
+	}
+	if got := visible(outsider.Id); !slices.Equal(got, []string{"Public", "InviteOnly"}) {
+		t.Errorf("invited outsider sees %v, want [Public InviteOnly]", got)
+	}
+}
+
+func testEmployeeCreate(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	email := uniqueName("mail") + "@example.com"
+	emp, err := repos.Employee.Create(ctx, rt.CreateEmployeeInput{
+		Username:  uniqueName("user"),
+		FirstName: "First",
+		LastName:  "Last",
+		Email:     &email,
+	})
+	must(t, "Create", err)
+	if !emp.IsActive || emp.Email == nil || *emp.Email != email {
+		t.Errorf("created employee = %+v, want active with email %q", emp, email)
+	}
+
+	byId, err := repos.Employee.GetById(ctx, emp.Id)
+	must(t, "GetById", err)
+	byUsername, err := repos.Employee.GetByUsername(ctx, emp.Username)
+	must(t, "GetByUsername", err)
+	if byId.Username != emp.Username || byUsername.Id != emp.Id {
+		t.Errorf("GetById = %v, GetByUsername = %v, want %v", byId.Username, byUsername.Id, emp.Id)
+	}
+
+	_, err = repos.Employee.GetById(ctx, uuid.New())
+	wantErr(t, "GetById unknown", err, repoerrors.ErrNotFound)
+	_, err = repos.Employee.GetByUsername(ctx, uniqueName("unknown"))
+	wantErr(t, "GetByUsername unknown", err, repoerrors.ErrNotFound)
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		_, err := repos.Employee.Create(ctx, rt.CreateEmployeeInput{Username: emp.Username})
+		return err
+	})
+	wantErr(t, "Create taken username", err, repoerrors.ErrAlreadyExists)
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		upper := strings.ToUpper(email)
+		_, err := repos.Employee.Create(ctx, rt.CreateEmployeeInput{Username: uniqueName("user"), Email: &upper})
+		return err
+	})
+	wantErr(t, "Create taken email", err, repoerrors.ErrEmailTaken)
+}
+
+func testEmployeeUpdate(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	emp := newEmployee(ctx, t, repos)
+	other := newEmployee(ctx, t, repos)
+
+	email := uniqueName("mail") + "@example.com"
+	updated, err := repos.Employee.Update(ctx, rt.UpdateEmployeeInput{Id: emp.Id, FirstName: "New", LastName: "Name", Email: &email})
+	must(t, "Update", err)
+	if updated.FirstName != "New" || updated.LastName != "Name" || updated.Email == nil || *updated.Email != email {
+		t.Errorf("Update = %+v, want New Name %q", updated, email)
+	}
+
+	_, err = repos.Employee.Update(ctx, rt.UpdateEmployeeInput{Id: uuid.New()})
+	wantErr(t, "Update unknown", err, repoerrors.ErrNotFound)
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		_, err := repos.Employee.Update(ctx, rt.UpdateEmployeeInput{Id: other.Id, Email: &email})
+		return err
+	})
+	wantErr(t, "Update to taken email", err, repoerrors.ErrEmailTaken)
+
+	deactivated, err := repos.Employee.SetActive(ctx, emp.Id, false)
+	must(t, "SetActive", err)
+	if deactivated.IsActive {
+		t.Error("SetActive(false) left employee active")
+	}
+	_, err = repos.Employee.SetActive(ctx, uuid.New(), false)
+	wantErr(t, "SetActive unknown", err, repoerrors.ErrNotFound)
+
+	admin, err := repos.Employee.SetAdmin(ctx, emp.Id, true)
+	must(t, "SetAdmin", err)
+	if !admin.IsAdmin {
+		t.Error("SetAdmin(true) left employee without admin")
+	}
+	_, err = repos.Employee.SetAdmin(ctx, uuid.New(), true)
+	wantErr(t, "SetAdmin unknown", err, repoerrors.ErrNotFound)
+
+	search := func(includeInactive bool) []e.Employee {
+		t.Helper()
+		employees, err := repos.Employee.Search(ctx, rt.SearchEmployeesInput{Query: emp.Username, IncludeInactive: includeInactive})
+		must(t, "Search", err)
+		return employees
+	}
+	if found := search(false); len(found) != 0 {
+		t.Errorf("Search found %d inactive employees, want 0", len(found))
+	}
+	if found := search(true); len(found) != 1 || found[0].Id != emp.Id {
+		t.Errorf("Search with inactive = %v, want only %v", found, emp.Id)
+	}
+}
+
+func testEmployeeRoles(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	responsible := newEmployee(ctx, t, repos)
+	outsider := newEmployee(ctx, t, repos)
+	org := newOrganization(ctx, t, repos, responsible.Id)
+
+	isResponsible, err := repos.Employee.IsResponsible(ctx, org.Id, responsible.Id)
+	must(t, "IsResponsible", err)
+	if !isResponsible {
+		t.Error("creator of organization isn't its responsible")
+	}
+	roles, err := repos.Employee.GetRoles(ctx, org.Id, responsible.Id)
+	must(t, "GetRoles", err)
+	if want := []string{"viewer", "editor", "publisher", "approver", "admin"}; !slices.Equal(roles, want) {
+		t.Errorf("GetRoles = %v, want %v", roles, want)
+	}
+
+	isResponsible, err = repos.Employee.IsResponsible(ctx, org.Id, outsider.Id)
+	must(t, "IsResponsible", err)
+	if isResponsible {
+		t.Error("outsider is responsible of organization")
+	}
+	roles, err = repos.Employee.GetRoles(ctx, org.Id, outsider.Id)
+	must(t, "GetRoles", err)
+	if len(roles) != 0 {
+		t.Errorf("GetRoles of outsider = %v, want none", roles)
+	}
+}
+
+func bidInput(tender e.Tender, author e.Employee, name string) rt.CreateBidInput {
+	return rt.CreateBidInput{
+		Name:        name,
+		Description: "Description",
+		TenderId:    tender.Id,
+		AuthorType:  "User",
+		AuthorId:    author.Id,
+	}
+}
+
+// newBid creates bid of new employee on new tender
+func newBid(ctx context.Context, t *testing.T, repos *repo.Repositories) e.Bid {
+	t.Helper()
+	tender, _ := newTender(ctx, t, repos, "Public")
+	bid, err := repos.Bid.Create(ctx, bidInput(tender, newEmployee(ctx, t, repos), uniqueName("bid")))
+	must(t, "Bid.Create", err)
+	return bid
+}
+
+func newBidVersion(ctx context.Context, t *testing.T, repos *repo.Repositories, prev e.Bid, name string) e.Bid {
+	t.Helper()
+	bid, err := repos.Bid.CreateSpecified(ctx, rt.CreateSpecifiedBidInput{
+		Id:             prev.Id,
+		Name:           name,
+		Description:    prev.Description,
+		AuthorType:     prev.AuthorType,
+		AuthorId:       prev.AuthorId,
+		Status:         prev.Status,
+		Version:        prev.Version + 1,
+		TenderId:       prev.TenderId,
+		OrganizationId: prev.OrganizationId,
+	})
+	must(t, "Bid.CreateSpecified", err)
+	return bid
+}
+
+func testBidNotFound(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	bid := newBid(ctx, t, repos)
+
+	_, err := repos.Bid.Get(ctx, uuid.New(), rt.VersionLatest)
+	wantErr(t, "Get unknown bid", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Bid.Get(ctx, bid.Id, 2)
+	wantErr(t, "Get unknown version", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Bid.ChangeStatus(ctx, uuid.New(), "Published")
+	wantErr(t, "ChangeStatus", err, repoerrors.ErrNotFound)
+
+	_, err = repos.Bid.GetForUpdate(ctx, uuid.New())
+	wantErr(t, "GetForUpdate", err, repoerrors.ErrNotFound)
+}
+
+func testBidVersions(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	v1 := newBid(ctx, t, repos)
+	if v1.Version != 1 || v1.Status != "Created" {
+		t.Fatalf("created bid version = %d, status = %q, want 1, Created", v1.Version, v1.Status)
+	}
+	newBidVersion(ctx, t, repos, v1, "renamed")
+
+	latest, err := repos.Bid.Get(ctx, v1.Id, rt.VersionLatest)
+	must(t, "Get latest", err)
+	if latest.Version != 2 || latest.Name != "renamed" {
+		t.Errorf("latest = %d %q, want 2 renamed", latest.Version, latest.Name)
+	}
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		_, err := repos.Bid.CreateSpecified(ctx, rt.CreateSpecifiedBidInput{
+			Id:         v1.Id,
+			Name:       "concurrent",
+			AuthorType: v1.AuthorType,
+			AuthorId:   v1.AuthorId,
+			Status:     v1.Status,
+			Version:    2,
+			TenderId:   v1.TenderId,
+		})
+		return err
+	})
+	wantErr(t, "CreateSpecified existing version", err, repoerrors.ErrVersionConflict)
+
+	bid, err := repos.Bid.ChangeStatus(ctx, v1.Id, "Published")
+	must(t, "ChangeStatus", err)
+	if bid.Version != 2 || bid.Status != "Published" || bid.Name != "renamed" {
+		t.Errorf("ChangeStatus = %d %q %q, want 2 Published renamed", bid.Version, bid.Status, bid.Name)
+	}
+
+	old, err := repos.Bid.Get(ctx, v1.Id, 1)
+	must(t, "Get version 1", err)
+	if old.Status != "Created" || old.Name != v1.Name {
+		t.Errorf("version 1 = %q %q, want Created %q", old.Status, old.Name, v1.Name)
+	}
+}
+
+func testBidLock(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	v1 := newBid(ctx, t, repos)
+	v2 := newBidVersion(ctx, t, repos, v1, "renamed")
+
+	must(t, "Lock latest", repos.Bid.Lock(ctx, v2.Id, v2.Version, v2.UpdatedAt))
+
+	err := attempt(ctx, repos, func(ctx context.Context) error {
+		return repos.Bid.Lock(ctx, v1.Id, v1.Version, v1.UpdatedAt)
+	})
+	wantErr(t, "Lock stale version", err, repoerrors.ErrVersionConflict)
+
+	err = attempt(ctx, repos, func(ctx context.Context) error {
+		return repos.Bid.Lock(ctx, v2.Id, v2.Version, v2.UpdatedAt.Add(-time.Second))
+	})
+	wantErr(t, "Lock changed version", err, repoerrors.ErrVersionConflict)
+
+	bid, err := repos.Bid.GetForUpdate(ctx, v1.Id)
+	must(t, "GetForUpdate", err)
+	if bid.Version != 2 {
+		t.Errorf("GetForUpdate version = %d, want 2", bid.Version)
+	}
+}
+
+func testBidders(ctx context.Context, t *testing.T, repos *repo.Repositories) {
+	tender, _ := newTender(ctx, t, repos, "Public")
+	user := newEmployee(ctx, t, repos)
+	responsible := newEmployee(ctx, t, repos)
+	org := newOrganization(ctx, t, repos, responsible.Id)
+
+	userBid, err := repos.Bid.Create(ctx, bidInput(tender, user, "user bid"))
+	must(t, "Create user bid", err)
+	newBidVersion(ctx, t, repos, userBid, "user bid v2")
+
+	in := bidInput(tender, responsible, "organization bid")
+	in.AuthorType = "Organization"
+	in.OrganizationId = uuid.NullUUID{UUID: org.Id, Valid: true}
+	_, err = repos.Bid.Create(ctx, in)
+	must(t, "Create organization bid", err)
+
+	bidders, err := repos.Bid.GetBidders(ctx, tender.Id)
+	must(t, "GetBidders", err)
+	if len(bidders.EmployeeIds) != 1 || bidders.EmployeeIds[0] != user.Id {
+		t.Errorf("bidding employees = %v, want [%v]", bidders.EmployeeIds, user.Id)
+	}
+	if len(bidders.OrganizationIds) != 1 || bidders.OrganizationIds[0] != org.Id {
+		t.Errorf("bidding organizations = %v, want [%v]", bidders.OrganizationIds, org.Id)
+	}
+
+	bidders, err = repos.Bid.GetBidders(ctx, uuid.New())
+	must(t, "GetBidders", err)
+	if len(bidders.EmployeeIds)+len(bidders.OrganizationIds) != 0 {
+		t.Errorf("bidders of unknown tender = %+v, want none", bidders)
+	}
+}
+
+func tenderNames(tenders []e.Tender) []string {
+	names := make([]string, 0, len(tenders))
+	for _, tender := range tenders {
+		names = append(names, tender.Name)
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+package repotypes
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Zero TTL means key never expires
+type CreateAPIKeyInput struct {
+	OrganizationId uuid.UUID
+	Name           string
+	Prefix         string
+	SecretHash     string
+	Scopes         []string
+	TTL            time.Duration
+	CreatedBy      uuid.UUID
+}
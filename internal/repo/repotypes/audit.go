@@ -0,0 +1,29 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+type CreateAuditEntryInput struct {
+	OrganizationId uuid.NullUUID
+	ActorType      string
+	ActorId        uuid.UUID
+	ActorName      string
+	Action         string
+	EntityType     string
+	EntityId       uuid.UUID
+	EntityVersion  int
+	Before         []byte
+	After          []byte
+	RequestId      string
+	ClientIP       string
+}
+
+// Empty filters match everything
+type GetAuditLogInput struct {
+	Limit          int
+	Offset         int
+	OrganizationId uuid.UUID
+	EntityType     string
+	EntityId       uuid.UUID
+	Action         string
+	ActorName      string
+}
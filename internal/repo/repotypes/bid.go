@@ -3,20 +3,29 @@ package repotypes
 import "github.com/google/uuid"
 
 type CreateBidInput struct {
-	Name        string
-	Description string
-	TenderId    uuid.UUID
-	AuthorType  string
-	AuthorId    uuid.UUID
+	Name           string
+	Description    string
+	TenderId       uuid.UUID
+	AuthorType     string
+	AuthorId       uuid.UUID
+	OrganizationId uuid.NullUUID
 }
 
 type CreateSpecifiedBidInput struct {
-	Id          uuid.UUID
-	Name        string
-	Description string
-	AuthorType  string
-	AuthorId    uuid.UUID
-	Status      string
-	Version     int
-	TenderId    uuid.UUID
+	Id             uuid.UUID
+	Name           string
+	Description    string
+	AuthorType     string
+	AuthorId       uuid.UUID
+	Status         string
+	Version        int
+	TenderId       uuid.UUID
+	OrganizationId uuid.NullUUID
+}
+
+// TenderBidders are authors of bids on tender: organizations and employees
+// bidding on their own
+type TenderBidders struct {
+	OrganizationIds []uuid.UUID
+	EmployeeIds     []uuid.UUID
 }
@@ -0,0 +1,29 @@
+package repotypes
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateResetTokenInput struct {
+	EmployeeId uuid.UUID
+	TokenHash  string
+	TTL        time.Duration
+	CreatedBy  uuid.UUID
+}
+
+type CreateLoginAttemptInput struct {
+	EmployeeId uuid.NullUUID
+	Username   string
+	ClientIP   string
+	Success    bool
+	Reason     string
+}
+
+// Empty Username means attempts of all employees
+type GetLoginAttemptsInput struct {
+	Limit    int
+	Offset   int
+	Username string
+}
@@ -0,0 +1,33 @@
+package repotypes
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetEmailRecipientsInput selects active employees with address opted in to
+// Kind: listed employees and responsibles of listed organizations
+type GetEmailRecipientsInput struct {
+	Kind            string
+	OrganizationIds []uuid.UUID
+	EmployeeIds     []uuid.UUID
+}
+
+type CreateEmailMessageInput struct {
+	EmployeeId uuid.UUID
+	EventId    uuid.UUID
+	Kind       string
+	ToAddress  string
+	Subject    string
+	TextBody   string
+	HTMLBody   string
+}
+
+// Delay is used for Pending status only
+type RecordEmailAttemptInput struct {
+	MessageId uuid.UUID
+	Status    string
+	Error     string
+	Delay     time.Duration
+}
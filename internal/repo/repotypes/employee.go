@@ -0,0 +1,26 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+// Nil Email means employee has no address
+type CreateEmployeeInput struct {
+	Username  string
+	FirstName string
+	LastName  string
+	Email     *string
+}
+
+type UpdateEmployeeInput struct {
+	Id        uuid.UUID
+	FirstName string
+	LastName  string
+	Email     *string
+}
+
+// Empty Query means no filtering by name
+type SearchEmployeesInput struct {
+	Limit           int
+	Offset          int
+	Query           string
+	IncludeInactive bool
+}
@@ -0,0 +1,21 @@
+package repotypes
+
+import "time"
+
+// ClaimIdempotencyKeyInput claims key for Lease. Key older than TTL is claimed
+// as new one
+type ClaimIdempotencyKeyInput struct {
+	Caller      string
+	Key         string
+	RequestHash []byte
+	Lease       time.Duration
+	TTL         time.Duration
+}
+
+type CompleteIdempotencyKeyInput struct {
+	Caller      string
+	Key         string
+	Status      int
+	ContentType string
+	Body        []byte
+}
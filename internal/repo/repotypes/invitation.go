@@ -0,0 +1,9 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+type CreateInvitationInput struct {
+	TenderId       uuid.UUID
+	OrganizationId uuid.NullUUID
+	UserId         uuid.NullUUID
+}
@@ -0,0 +1,22 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+// AddNotificationsInput fans notification out to active responsibles of
+// OrganizationIds and to EmployeeIds personally
+type AddNotificationsInput struct {
+	EventId         uuid.UUID
+	Kind            string
+	OrganizationIds []uuid.UUID
+	EmployeeIds     []uuid.UUID
+	TenderId        uuid.UUID
+	BidId           uuid.NullUUID
+	Title           string
+}
+
+type GetNotificationsInput struct {
+	Limit      int
+	Offset     int
+	EmployeeId uuid.UUID
+	UnreadOnly bool
+}
@@ -0,0 +1,25 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+type CreateOrganizationInput struct {
+	Name          string
+	Description   string
+	Type          string
+	ResponsibleId uuid.UUID
+}
+
+type UpdateOrganizationInput struct {
+	Id          uuid.UUID
+	Name        string
+	Description string
+	Type        string
+}
+
+// Empty Query and Type mean no filtering
+type SearchOrganizationsInput struct {
+	Limit  int
+	Offset int
+	Query  string
+	Type   string
+}
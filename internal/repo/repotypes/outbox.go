@@ -0,0 +1,11 @@
+package repotypes
+
+import "github.com/google/uuid"
+
+type CreateOutboxEventInput struct {
+	Type           string
+	AggregateType  string
+	AggregateId    uuid.UUID
+	OrganizationId uuid.NullUUID
+	Payload        []byte
+}
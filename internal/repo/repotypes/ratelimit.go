@@ -0,0 +1,9 @@
+package repotypes
+
+// TakeRateLimitTokenInput takes token from bucket holding up to Capacity
+// tokens and refilled by Rate tokens per second
+type TakeRateLimitTokenInput struct {
+	Key      string
+	Capacity int
+	Rate     float64
+}
@@ -1,6 +1,10 @@
 package repotypes
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 const (
 	VersionLatest = 0
@@ -13,6 +17,8 @@ type CreateTenderInput struct {
 	OrganizationId  uuid.UUID
 	CreatorUsername string
 	Status          string
+	Visibility      string
+	Deadline        *time.Time
 }
 
 type GetByUsernameInput struct {
@@ -21,10 +27,18 @@ type GetByUsernameInput struct {
 	Username string
 }
 
+// ViewerId = uuid.Nil means anonymous viewer (public tenders only)
 type GetPublishedTendersInput struct {
 	Limit       int
 	Offset      int
 	ServiceType []string
+	ViewerId    uuid.UUID
+}
+
+type GetInvitedTendersInput struct {
+	Limit  int
+	Offset int
+	UserId uuid.UUID
 }
 
 type CreateSpecifiedInput struct {
@@ -0,0 +1,42 @@
+package repotypes
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateWebhookInput struct {
+	OrganizationId uuid.UUID
+	URL            string
+	EventTypes     []string
+	Secret         string
+	CreatedBy      uuid.UUID
+}
+
+// AddWebhookDeliveriesInput fans event out to subscriptions of organizations
+// which listen to its type
+type AddWebhookDeliveriesInput struct {
+	EventId         uuid.UUID
+	EventType       string
+	Payload         []byte
+	OrganizationIds []uuid.UUID
+}
+
+// Status is empty if deliveries of all statuses are requested
+type GetWebhookDeliveriesInput struct {
+	Limit          int
+	Offset         int
+	SubscriptionId uuid.UUID
+	Status         string
+}
+
+// RecordWebhookAttemptInput stores result of delivery attempt. StatusCode is
+// zero if receiver didn't respond, Delay is used for Pending status only
+type RecordWebhookAttemptInput struct {
+	DeliveryId uuid.UUID
+	Status     string
+	StatusCode int
+	Error      string
+	Delay      time.Duration
+}
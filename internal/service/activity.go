@@ -0,0 +1,185 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/stream"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// activityEvents are events streamed to tender activity subscribers
+var activityEvents = map[string]bool{
+	EventTenderStatusChanged:  true,
+	EventTenderVersionCreated: true,
+	EventBidCreated:           true,
+}
+
+type ActivityService struct {
+	broker         *stream.Broker
+	employeeRepo   repo.Employee
+	invitationRepo repo.Invitation
+	authz          *authz.Authorizer
+}
+
+func NewActivityService(broker *stream.Broker, eRepo repo.Employee, iRepo repo.Invitation, az *authz.Authorizer) *ActivityService {
+	return &ActivityService{
+		broker:         broker,
+		employeeRepo:   eRepo,
+		invitationRepo: iRepo,
+		authz:          az,
+	}
+}
+
+// SubscribeActivity starts stream of tender activity caller may see, resuming
+// after lastEventId if it is still in history
+func (s *ActivityService) SubscribeActivity(ctx context.Context, username, lastEventId string) (*ActivityStream, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "ActivityService.SubscribeActivity", username)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, backlog, resumed, err := s.broker.Subscribe(lastEventId)
+	if err != nil {
+		if errors.Is(err, stream.ErrClosed) {
+			return nil, ErrStreamClosed
+		}
+		log.Errorf("ActivityService.SubscribeActivity - broker.Subscribe: %v", err)
+		return nil, ErrSubscribeActivity
+	}
+
+	return &ActivityStream{
+		Resync:  !resumed,
+		service: s,
+		caller:  c,
+		sub:     sub,
+		backlog: backlog,
+	}, nil
+}
+
+// ActivityStream yields events visible to its caller. Resync is set if
+// requested resume point is lost and caller should reload state
+type ActivityStream struct {
+	Resync bool
+
+	service *ActivityService
+	caller  caller
+	sub     *stream.Subscription
+	backlog []e.OutboxEvent
+}
+
+// Next returns next visible event. Ok is false if nothing came in idle time.
+// ErrStreamClosed means subscription ended, caller may resume from last event
+func (a *ActivityStream) Next(ctx context.Context, idle time.Duration) (event e.OutboxEvent, ok bool, err error) {
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+
+	for {
+		if len(a.backlog) > 0 {
+			event, a.backlog = a.backlog[0], a.backlog[1:]
+		} else {
+			var open bool
+			select {
+			case <-ctx.Done():
+				return e.OutboxEvent{}, false, ctx.Err()
+			case <-timer.C:
+				return e.OutboxEvent{}, false, nil
+			case event, open = <-a.sub.C:
+				if !open {
+					return e.OutboxEvent{}, false, ErrStreamClosed
+				}
+			}
+		}
+
+		if a.service.visible(ctx, a.caller, event) {
+			return event, true, nil
+		}
+	}
+}
+
+func (a *ActivityStream) Close() {
+	a.sub.Close()
+}
+
+// visible mirrors tender and bid read rules. Tender is visible if caller sees
+// it before or after the change, so tenders leaving view are reported too
+func (s *ActivityService) visible(ctx context.Context, c caller, event e.OutboxEvent) bool {
+	if !activityEvents[event.Type] {
+		return false
+	}
+
+	switch event.Type {
+	case EventBidCreated:
+		var payload bidEventPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Errorf("ActivityService.visible - json.Unmarshal: %v", err)
+			return false
+		}
+		bid := e.Bid{
+			Id:         payload.BidId,
+			TenderId:   payload.TenderId,
+			AuthorType: payload.AuthorType,
+			AuthorId:   payload.AuthorId,
+		}
+		if payload.OrganizationId != nil {
+			bid.OrganizationId = uuid.NullUUID{UUID: *payload.OrganizationId, Valid: true}
+		}
+		tender := e.Tender{Id: payload.TenderId, OrganizationId: event.OrganizationId.UUID}
+		return s.allowed(s.authz.CanViewBid(ctx, c.actor, bid, tender)) ||
+			s.allowed(s.authz.CanDecideOnBid(ctx, c.actor, bid, tender))
+	default:
+		var payload tenderChangePayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Errorf("ActivityService.visible - json.Unmarshal: %v", err)
+			return false
+		}
+		tender := e.Tender{
+			Id:             payload.TenderId,
+			OrganizationId: payload.OrganizationId,
+			Status:         payload.Status,
+			Visibility:     payload.Visibility,
+		}
+		if s.canSeeTender(ctx, c, tender) {
+			return true
+		}
+		tender.Status, tender.Visibility = payload.PreviousStatus, payload.PreviousVisibility
+		return s.canSeeTender(ctx, c, tender)
+	}
+}
+
+// canSeeTender follows GetTenders for published tenders and GetTender otherwise
+func (s *ActivityService) canSeeTender(ctx context.Context, c caller, tender e.Tender) bool {
+	if tender.Status == "Published" {
+		switch tender.Visibility {
+		case "Public":
+			return true
+		case "InviteOnly":
+			if !c.actor.IsAPIKey() {
+				invited, err := s.invitationRepo.IsInvited(ctx, tender.Id, c.actor.Id)
+				if err != nil {
+					log.Errorf("ActivityService.canSeeTender - invitationRepo.IsInvited: %v", err)
+				}
+				if invited {
+					return true
+				}
+			}
+		}
+	}
+
+	return s.allowed(s.authz.CanViewTender(ctx, c.actor, tender))
+}
+
+func (s *ActivityService) allowed(d authz.Decision, err error) bool {
+	if err != nil {
+		log.Errorf("ActivityService.allowed - authz: %v", err)
+		return false
+	}
+	return d.Allowed
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Keys look like tnd_<prefix>_<secret>, prefix identifies key in listings and logs
+const apiKeyType = "tnd"
+
+type APIKeyService struct {
+	apiKeyRepo       repo.APIKey
+	employeeRepo     repo.Employee
+	organizationRepo repo.Organization
+	authz            *authz.Authorizer
+}
+
+func NewAPIKeyService(kRepo repo.APIKey, eRepo repo.Employee, oRepo repo.Organization, az *authz.Authorizer) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:       kRepo,
+		employeeRepo:     eRepo,
+		organizationRepo: oRepo,
+		authz:            az,
+	}
+}
+
+// Create issues API key for organization. Secret is returned only once
+func (s *APIKeyService) Create(ctx context.Context, in CreateAPIKeyInput) (e.APIKey, string, error) {
+	// Check if organization exists and user can manage it
+	c, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "APIKeyService.Create",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return e.APIKey{}, "", err
+	}
+
+	// Generate key, only secret hash is stored
+	prefix, secret, err := newAPIKeySecret()
+	if err != nil {
+		log.Errorf("APIKeyService.Create - newAPIKeySecret: %v", err)
+		return e.APIKey{}, "", ErrCreateAPIKey
+	}
+	key, err := s.apiKeyRepo.Create(ctx, rt.CreateAPIKeyInput{
+		OrganizationId: in.OrganizationId,
+		Name:           in.Name,
+		Prefix:         prefix,
+		SecretHash:     hashToken(secret),
+		Scopes:         in.Scopes,
+		TTL:            time.Duration(in.ExpiresInDays) * 24 * time.Hour,
+		CreatedBy:      c.employee.Id,
+	})
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrInvalidReference) {
+			return e.APIKey{}, "", ErrNotFoundOrganization
+		}
+		log.Errorf("APIKeyService.Create - apiKeyRepo.Create: %v", err)
+		return e.APIKey{}, "", ErrCreateAPIKey
+	}
+
+	return key, secret, nil
+}
+
+func (s *APIKeyService) GetByOrganization(ctx context.Context, orgId uuid.UUID, username string) ([]e.APIKey, error) {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "APIKeyService.GetByOrganization",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.GetByOrganization(ctx, orgId)
+	if err != nil {
+		log.Errorf("APIKeyService.GetByOrganization - apiKeyRepo.GetByOrganization: %v", err)
+		return nil, ErrGetAPIKeys
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, orgId, keyId uuid.UUID, username string) error {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "APIKeyService.Revoke",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	if err := s.apiKeyRepo.Revoke(ctx, orgId, keyId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundAPIKey
+		}
+		log.Errorf("APIKeyService.Revoke - apiKeyRepo.Revoke: %v", err)
+		return ErrRevokeAPIKey
+	}
+
+	return nil
+}
+
+// Authenticate resolves API key to service identity of its organization
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (identity.Service, error) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyType {
+		return identity.Service{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.Use(ctx, hashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return identity.Service{}, ErrInvalidAPIKey
+		}
+		log.Errorf("APIKeyService.Authenticate - apiKeyRepo.Use: %v", err)
+		return identity.Service{}, ErrGetAPIKey
+	}
+
+	return identity.Service{
+		KeyId:          key.Id,
+		OrganizationId: key.OrganizationId,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		Scopes:         key.Scopes,
+	}, nil
+}
+
+// newAPIKeySecret returns public key prefix and full key containing it
+func newAPIKeySecret() (prefix, key string, err error) {
+	b := make([]byte, 36)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(b[:4])
+	key = apiKeyType + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(b[4:])
+	return prefix, key, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	AuditEntityTender = "tender"
+	AuditEntityBid    = "bid"
+
+	AuditActionCreate           = "create"
+	AuditActionEdit             = "edit"
+	AuditActionChangeStatus     = "change_status"
+	AuditActionChangeVisibility = "change_visibility"
+	AuditActionRollback         = "rollback"
+	AuditActionSubmitDecision   = "submit_decision"
+)
+
+type AuditService struct {
+	auditRepo        repo.Audit
+	employeeRepo     repo.Employee
+	organizationRepo repo.Organization
+	authz            *authz.Authorizer
+}
+
+func NewAuditService(aRepo repo.Audit, eRepo repo.Employee, oRepo repo.Organization, az *authz.Authorizer) *AuditService {
+	return &AuditService{
+		auditRepo:        aRepo,
+		employeeRepo:     eRepo,
+		organizationRepo: oRepo,
+		authz:            az,
+	}
+}
+
+func (s *AuditService) GetOrganizationLog(ctx context.Context, in GetAuditLogInput) ([]e.AuditEntry, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "AuditService.GetOrganizationLog", in.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if organization exists
+	if _, err := s.organizationRepo.Get(ctx, in.OrganizationId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return nil, ErrNotFoundOrganization
+		}
+		log.Errorf("AuditService.GetOrganizationLog - organizationRepo.Get: %v", err)
+		return nil, ErrGetOrganization
+	}
+
+	// Check rights
+	if err := checkDecision(s.authz.CanViewAuditLog(ctx, c.actor, in.OrganizationId)); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditRepo.GetOrganizationLog(ctx, rt.GetAuditLogInput{
+		Limit:          in.Limit,
+		Offset:         in.Offset,
+		OrganizationId: in.OrganizationId,
+		EntityType:     in.EntityType,
+		EntityId:       in.EntityId,
+		Action:         in.Action,
+		ActorName:      in.ActorName,
+	})
+	if err != nil {
+		log.Errorf("AuditService.GetOrganizationLog - auditRepo.GetOrganizationLog: %v", err)
+		return nil, ErrGetAuditLog
+	}
+
+	return entries, nil
+}
+
+// auditEntry describes mutation, Before is empty for created entities
+type auditEntry struct {
+	OrganizationId uuid.NullUUID
+	Action         string
+	EntityType     string
+	EntityId       uuid.UUID
+	Version        int
+	Before         any
+	After          any
+}
+
+// decisionSnapshot is audit state of bid after decision, bid itself doesn't change
+type decisionSnapshot struct {
+	Decision string
+	Bid      e.Bid
+}
+
+func tenderAuditEntry(action string, before *e.Tender, after e.Tender) auditEntry {
+	entry := auditEntry{
+		OrganizationId: uuid.NullUUID{UUID: after.OrganizationId, Valid: true},
+		Action:         action,
+		EntityType:     AuditEntityTender,
+		EntityId:       after.Id,
+		Version:        after.Version,
+		After:          after,
+	}
+	if before != nil {
+		entry.Before = *before
+	}
+	return entry
+}
+
+func bidAuditEntry(action string, before *e.Bid, after e.Bid) auditEntry {
+	entry := auditEntry{
+		OrganizationId: after.OrganizationId,
+		Action:         action,
+		EntityType:     AuditEntityBid,
+		EntityId:       after.Id,
+		Version:        after.Version,
+		After:          after,
+	}
+	if before != nil {
+		entry.Before = *before
+	}
+	return entry
+}
+
+// recordAudit writes entry on behalf of caller, call it in transaction of the mutation
+func recordAudit(ctx context.Context, aRepo repo.Audit, method string, c caller, entry auditEntry) error {
+	before, err := marshalSnapshot(entry.Before)
+	if err != nil {
+		log.Errorf("%s - marshalSnapshot: %v", method, err)
+		return ErrWriteAudit
+	}
+	after, err := marshalSnapshot(entry.After)
+	if err != nil {
+		log.Errorf("%s - marshalSnapshot: %v", method, err)
+		return ErrWriteAudit
+	}
+
+	request := identity.RequestFromContext(ctx)
+	err = aRepo.Add(ctx, rt.CreateAuditEntryInput{
+		OrganizationId: entry.OrganizationId,
+		ActorType:      string(c.actor.Kind),
+		ActorId:        c.actor.Id,
+		ActorName:      c.actor.Name,
+		Action:         entry.Action,
+		EntityType:     entry.EntityType,
+		EntityId:       entry.EntityId,
+		EntityVersion:  entry.Version,
+		Before:         before,
+		After:          after,
+		RequestId:      request.Id,
+		ClientIP:       request.ClientIP,
+	})
+	if err != nil {
+		log.Errorf("%s - auditRepo.Add: %v", method, err)
+		return ErrWriteAudit
+	}
+
+	return nil
+}
+
+func marshalSnapshot(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// inTx runs fn in transaction, which is repeated on serialization conflict, so
+// fn must only touch database. Service errors returned by fn are passed as is,
+// transaction failures are logged
+func inTx(ctx context.Context, tx repo.Transactor, method string, fn func(ctx context.Context) error) error {
+	var fnErr error
+	err := tx.InTxRetry(ctx, func(ctx context.Context) error {
+		fnErr = fn(ctx)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		log.Errorf("%s - InTx: %v", method, err)
+		return ErrTransaction
+	}
+	return nil
+}
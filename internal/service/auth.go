@@ -0,0 +1,368 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Login attempt reasons stored in login_attempt table
+const (
+	loginReasonOK           = "ok"
+	loginReasonPasswordless = "passwordless"
+	loginReasonUnknownUser  = "unknown_user"
+	loginReasonNoPassword   = "no_password"
+	loginReasonBadPassword  = "bad_password"
+	loginReasonLocked       = "locked"
+	loginReasonDeactivated  = "deactivated"
+)
+
+type tokenClaims struct {
+	jwt.StandardClaims
+	Username string `json:"username"`
+}
+
+type AuthService struct {
+	employeeRepo   repo.Employee
+	credentialRepo repo.Credential
+	cfg            AuthConfig
+	signKey        []byte
+	// Compared against when user is unknown so response time doesn't reveal it
+	dummyHash []byte
+}
+
+func NewAuthService(eRepo repo.Employee, cRepo repo.Credential, cfg AuthConfig) *AuthService {
+	if cfg.BcryptCost == 0 {
+		cfg.BcryptCost = bcrypt.DefaultCost
+	}
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("dummy password"), cfg.BcryptCost)
+	if err != nil {
+		log.Fatal(fmt.Errorf("service - NewAuthService - bcrypt.GenerateFromPassword: %w", err))
+	}
+
+	return &AuthService{
+		employeeRepo:   eRepo,
+		credentialRepo: cRepo,
+		cfg:            cfg,
+		signKey:        []byte(cfg.SignKey),
+		dummyHash:      dummyHash,
+	}
+}
+
+// Login checks password and issues access token for active employee.
+// Every attempt is recorded, repeated failures lock the account
+func (s *AuthService) Login(ctx context.Context, in LoginInput) (Token, error) {
+	attempt := rt.CreateLoginAttemptInput{Username: in.Username, ClientIP: in.ClientIP}
+
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(in.Password))
+			s.recordAttempt(ctx, attempt, loginReasonUnknownUser)
+			return Token{}, ErrInvalidCredentials
+		}
+		log.Errorf("AuthService.Login - employeeRepo.GetByUsername: %v", err)
+		return Token{}, ErrGetEmployeeByUsername
+	}
+	attempt.EmployeeId = uuid.NullUUID{UUID: user.Id, Valid: true}
+
+	// Check password. Employee without one may log in with empty password in
+	// development only, such session can't set password, reset token is needed
+	reason, err := s.checkPassword(ctx, "Login", user.Id, in.Password)
+	if reason == loginReasonNoPassword && s.cfg.DevAllowPasswordless && in.Password == "" {
+		reason, err = loginReasonPasswordless, nil
+	}
+	if err != nil {
+		s.recordAttempt(ctx, attempt, reason)
+		return Token{}, err
+	}
+	if !user.IsActive {
+		s.recordAttempt(ctx, attempt, loginReasonDeactivated)
+		return Token{}, ErrEmployeeDeactivated
+	}
+	attempt.Success = true
+	s.recordAttempt(ctx, attempt, reason)
+
+	// Sign token
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.TokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.Id.String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+		Username: user.Username,
+	})
+	signed, err := token.SignedString(s.signKey)
+	if err != nil {
+		log.Errorf("AuthService.Login - token.SignedString: %v", err)
+		return Token{}, ErrSignToken
+	}
+
+	return Token{AccessToken: signed, ExpiresAt: expiresAt}, nil
+}
+
+// Authenticate validates access token and returns its subject
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (e.Employee, error) {
+	// Parse token
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return s.signKey, nil
+	})
+	if err != nil {
+		return e.Employee{}, ErrInvalidToken
+	}
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return e.Employee{}, ErrInvalidToken
+	}
+
+	// Check if token subject still exists and is active
+	user, err := s.employeeRepo.GetById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrInvalidToken
+		}
+		log.Errorf("AuthService.Authenticate - employeeRepo.GetById: %v", err)
+		return e.Employee{}, ErrGetEmployeeById
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	return user, nil
+}
+
+// SetPassword sets first password of employee who has none. Caller holds
+// bootstrap token from config, so fresh deployment can get its first login
+func (s *AuthService) SetPassword(ctx context.Context, in SetPasswordInput) error {
+	// Check bootstrap token
+	if s.cfg.BootstrapToken == "" || subtle.ConstantTimeCompare([]byte(in.BootstrapToken), []byte(s.cfg.BootstrapToken)) != 1 {
+		return ErrInvalidBootstrapToken
+	}
+
+	// Check if employee exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundEmployee
+		}
+		log.Errorf("AuthService.SetPassword - employeeRepo.GetByUsername: %v", err)
+		return ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return ErrEmployeeDeactivated
+	}
+
+	// Store password unless employee has one, token can't take accounts over
+	hash, err := s.hashPassword("SetPassword", in.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.credentialRepo.CreatePassword(ctx, user.Id, hash); err != nil {
+		if errors.Is(err, repoerrors.ErrAlreadyExists) {
+			return ErrPasswordAlreadySet
+		}
+		log.Errorf("AuthService.SetPassword - credentialRepo.CreatePassword: %v", err)
+		return ErrSetPassword
+	}
+
+	return nil
+}
+
+// ChangePassword replaces password after checking the current one. Wrong
+// current password counts as failed login
+func (s *AuthService) ChangePassword(ctx context.Context, in ChangePasswordInput) error {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "AuthService.ChangePassword", in.Username)
+	if err != nil {
+		return err
+	}
+
+	// Check current password
+	if _, err := s.checkPassword(ctx, "ChangePassword", user.Id, in.OldPassword); err != nil {
+		return err
+	}
+
+	return s.storePassword(ctx, "ChangePassword", user.Id, in.NewPassword)
+}
+
+// CreateResetToken issues one-time password reset token. Only administrators
+// can issue tokens, token is handed over to employee out of band
+func (s *AuthService) CreateResetToken(ctx context.Context, employeeId uuid.UUID, username string) (ResetToken, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "AuthService.CreateResetToken", username)
+	if err != nil {
+		return ResetToken{}, err
+	}
+
+	// Check rights
+	if !user.IsAdmin {
+		return ResetToken{}, ErrForbidden
+	}
+
+	// Check if employee exists
+	if _, err := s.employeeRepo.GetById(ctx, employeeId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ResetToken{}, ErrNotFoundEmployee
+		}
+		log.Errorf("AuthService.CreateResetToken - employeeRepo.GetById: %v", err)
+		return ResetToken{}, ErrGetEmployeeById
+	}
+
+	// Create token, only its hash is stored
+	token, hash, err := newResetToken()
+	if err != nil {
+		log.Errorf("AuthService.CreateResetToken - newResetToken: %v", err)
+		return ResetToken{}, ErrCreateResetToken
+	}
+	expiresAt, err := s.credentialRepo.CreateResetToken(ctx, rt.CreateResetTokenInput{
+		EmployeeId: employeeId,
+		TokenHash:  hash,
+		TTL:        s.cfg.ResetTokenTTL,
+		CreatedBy:  user.Id,
+	})
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrInvalidReference) {
+			return ResetToken{}, ErrNotFoundEmployee
+		}
+		log.Errorf("AuthService.CreateResetToken - credentialRepo.CreateResetToken: %v", err)
+		return ResetToken{}, ErrCreateResetToken
+	}
+
+	return ResetToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// ResetPassword sets new password by reset token and lifts lockout
+func (s *AuthService) ResetPassword(ctx context.Context, in ResetPasswordInput) error {
+	// Check password before token is spent
+	if err := s.cfg.PasswordPolicy.validate(in.NewPassword); err != nil {
+		return err
+	}
+
+	employeeId, err := s.credentialRepo.UseResetToken(ctx, hashToken(in.ResetToken))
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		log.Errorf("AuthService.ResetPassword - credentialRepo.UseResetToken: %v", err)
+		return ErrResetPassword
+	}
+
+	return s.storePassword(ctx, "ResetPassword", employeeId, in.NewPassword)
+}
+
+// GetLoginAttempts is available to administrators only
+func (s *AuthService) GetLoginAttempts(ctx context.Context, in GetLoginAttemptsInput) ([]e.LoginAttempt, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "AuthService.GetLoginAttempts", in.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check rights
+	if !user.IsAdmin {
+		return nil, ErrForbidden
+	}
+
+	attempts, err := s.credentialRepo.GetLoginAttempts(ctx, rt.GetLoginAttemptsInput{
+		Limit:    in.Limit,
+		Offset:   in.Offset,
+		Username: in.TargetUsername,
+	})
+	if err != nil {
+		log.Errorf("AuthService.GetLoginAttempts - credentialRepo.GetLoginAttempts: %v", err)
+		return nil, ErrGetLoginAttempts
+	}
+
+	return attempts, nil
+}
+
+// checkPassword compares password with stored hash and registers failure on
+// mismatch. Returned reason is recorded as login attempt reason
+func (s *AuthService) checkPassword(ctx context.Context, method string, employeeId uuid.UUID, password string) (string, error) {
+	cred, err := s.credentialRepo.Get(ctx, employeeId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+			return loginReasonNoPassword, ErrInvalidCredentials
+		}
+		log.Errorf("AuthService.%s - credentialRepo.Get: %v", method, err)
+		return "", ErrGetCredential
+	}
+	if cred.IsLocked {
+		return loginReasonLocked, ErrAccountLocked
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(cred.PasswordHash), []byte(password)); err != nil {
+		if s.cfg.MaxFailedLogins > 0 {
+			cred, err = s.credentialRepo.RegisterFailure(ctx, employeeId, s.cfg.MaxFailedLogins, s.cfg.LockoutDuration)
+			if err != nil {
+				log.Errorf("AuthService.%s - credentialRepo.RegisterFailure: %v", method, err)
+			} else if cred.IsLocked {
+				return loginReasonBadPassword, ErrAccountLocked
+			}
+		}
+		return loginReasonBadPassword, ErrInvalidCredentials
+	}
+
+	if cred.FailedAttempts > 0 {
+		if err := s.credentialRepo.ResetFailures(ctx, employeeId); err != nil {
+			log.Errorf("AuthService.%s - credentialRepo.ResetFailures: %v", method, err)
+		}
+	}
+
+	return loginReasonOK, nil
+}
+
+func (s *AuthService) storePassword(ctx context.Context, method string, employeeId uuid.UUID, password string) error {
+	hash, err := s.hashPassword(method, password)
+	if err != nil {
+		return err
+	}
+	if err := s.credentialRepo.SetPassword(ctx, employeeId, hash); err != nil {
+		log.Errorf("AuthService.%s - credentialRepo.SetPassword: %v", method, err)
+		return ErrSetPassword
+	}
+
+	return nil
+}
+
+// hashPassword checks password against policy before hashing it
+func (s *AuthService) hashPassword(method, password string) (string, error) {
+	if err := s.cfg.PasswordPolicy.validate(password); err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cfg.BcryptCost)
+	if err != nil {
+		log.Errorf("AuthService.%s - bcrypt.GenerateFromPassword: %v", method, err)
+		return "", ErrHashPassword
+	}
+
+	return string(hash), nil
+}
+
+// recordAttempt doesn't fail login, attempts log is best effort
+func (s *AuthService) recordAttempt(ctx context.Context, in rt.CreateLoginAttemptInput, reason string) {
+	in.Reason = reason
+	if err := s.credentialRepo.AddLoginAttempt(ctx, in); err != nil {
+		log.Errorf("AuthService.Login - credentialRepo.AddLoginAttempt: %v", err)
+	}
+}
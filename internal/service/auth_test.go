@@ -0,0 +1,207 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/memdb"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeCredentialRepo stores password hashes, lockout isn't modelled
+type fakeCredentialRepo struct {
+	repo.Credential
+	hashes   map[uuid.UUID]string
+	attempts []rt.CreateLoginAttemptInput
+}
+
+func (r *fakeCredentialRepo) Get(ctx context.Context, employeeId uuid.UUID) (e.Credential, error) {
+	hash, ok := r.hashes[employeeId]
+	if !ok {
+		return e.Credential{}, repoerrors.ErrNotFound
+	}
+	return e.Credential{EmployeeId: employeeId, PasswordHash: hash}, nil
+}
+
+func (r *fakeCredentialRepo) SetPassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	r.hashes[employeeId] = passwordHash
+	return nil
+}
+
+func (r *fakeCredentialRepo) CreatePassword(ctx context.Context, employeeId uuid.UUID, passwordHash string) error {
+	if _, ok := r.hashes[employeeId]; ok {
+		return repoerrors.ErrAlreadyExists
+	}
+	r.hashes[employeeId] = passwordHash
+	return nil
+}
+
+func (r *fakeCredentialRepo) AddLoginAttempt(ctx context.Context, in rt.CreateLoginAttemptInput) error {
+	r.attempts = append(r.attempts, in)
+	return nil
+}
+
+func TestAuthServicePasswordless(t *testing.T) {
+	tests := []struct {
+		name        string
+		passwordSet bool
+		devAllow    bool
+		password    string
+		wantErr     error
+		wantReason  string
+	}{
+		{name: "no password", wantErr: ErrInvalidCredentials, wantReason: loginReasonNoPassword},
+		{name: "no password in development", devAllow: true, wantReason: loginReasonPasswordless},
+		{name: "guessed password in development", devAllow: true, password: "guess", wantErr: ErrInvalidCredentials, wantReason: loginReasonNoPassword},
+		{name: "empty password when set", passwordSet: true, devAllow: true, wantErr: ErrInvalidCredentials, wantReason: loginReasonBadPassword},
+		{name: "password when set", passwordSet: true, password: "secret", wantReason: loginReasonOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employees := &fakeEmployeeRepo{}
+			alice := employees.add("alice", true)
+			creds := &fakeCredentialRepo{hashes: map[uuid.UUID]string{}}
+			if tt.passwordSet {
+				hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+				creds.hashes[alice.Id] = string(hash)
+			}
+			s := NewAuthService(employees, creds, AuthConfig{
+				SignKey:              "key",
+				BcryptCost:           bcrypt.MinCost,
+				DevAllowPasswordless: tt.devAllow,
+			})
+
+			_, err := s.Login(context.Background(), LoginInput{Username: "alice", Password: tt.password})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(creds.attempts) != 1 || creds.attempts[0].Reason != tt.wantReason {
+				t.Errorf("attempts = %+v, want one with reason %q", creds.attempts, tt.wantReason)
+			}
+		})
+	}
+}
+
+// Passwordless session must not be enough to take account over
+func TestAuthServicePasswordlessCantSetPassword(t *testing.T) {
+	employees := &fakeEmployeeRepo{}
+	alice := employees.add("alice", true)
+	creds := &fakeCredentialRepo{hashes: map[uuid.UUID]string{}}
+	s := NewAuthService(employees, creds, AuthConfig{
+		SignKey:              "key",
+		BcryptCost:           bcrypt.MinCost,
+		DevAllowPasswordless: true,
+	})
+
+	err := s.ChangePassword(context.Background(), ChangePasswordInput{Username: "alice", NewPassword: "correct horse 1"})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("ChangePassword() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, ok := creds.hashes[alice.Id]; ok {
+		t.Errorf("password is set")
+	}
+}
+
+func TestAuthServiceSetPassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfgToken    string
+		token       string
+		username    string
+		passwordSet bool
+		password    string
+		wantErr     error
+	}{
+		{name: "first password", cfgToken: "bootstrap", token: "bootstrap", username: "alice"},
+		{name: "wrong token", cfgToken: "bootstrap", token: "guess", username: "alice", wantErr: ErrInvalidBootstrapToken},
+		{name: "disabled", token: "", username: "alice", wantErr: ErrInvalidBootstrapToken},
+		{name: "password already set", cfgToken: "bootstrap", token: "bootstrap", username: "alice", passwordSet: true, wantErr: ErrPasswordAlreadySet},
+		{name: "unknown employee", cfgToken: "bootstrap", token: "bootstrap", username: "ghost", wantErr: ErrNotFoundEmployee},
+		{name: "deactivated employee", cfgToken: "bootstrap", token: "bootstrap", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "weak password", cfgToken: "bootstrap", token: "bootstrap", username: "alice", password: "short", wantErr: ErrWeakPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employees := &fakeEmployeeRepo{}
+			alice := employees.add("alice", true)
+			employees.add("inactive", false)
+			creds := &fakeCredentialRepo{hashes: map[uuid.UUID]string{}}
+			if tt.passwordSet {
+				creds.hashes[alice.Id] = "old hash"
+			}
+			s := NewAuthService(employees, creds, AuthConfig{
+				SignKey:        "key",
+				BcryptCost:     bcrypt.MinCost,
+				BootstrapToken: tt.cfgToken,
+				PasswordPolicy: PasswordPolicy{MinLength: 8},
+			})
+			password := tt.password
+			if password == "" {
+				password = "correct horse 1"
+			}
+
+			err := s.SetPassword(context.Background(), SetPasswordInput{
+				Username:       tt.username,
+				BootstrapToken: tt.token,
+				NewPassword:    password,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if tt.passwordSet && creds.hashes[alice.Id] != "old hash" {
+					t.Errorf("existing password replaced")
+				}
+				return
+			}
+			if bcrypt.CompareHashAndPassword([]byte(creds.hashes[alice.Id]), []byte(password)) != nil {
+				t.Errorf("stored hash doesn't match password")
+			}
+		})
+	}
+}
+
+// Fresh deployment gets its first login without passwordless development mode
+func TestAuthServiceFirstLogin(t *testing.T) {
+	ctx := context.Background()
+	store := memdb.NewStore()
+	store.Seed()
+	repos := repo.NewMemoryRepo(store)
+	s := NewAuthService(repos.Employee, repos.Credential, AuthConfig{
+		SignKey:        "key",
+		BcryptCost:     bcrypt.MinCost,
+		BootstrapToken: "bootstrap",
+		PasswordPolicy: PasswordPolicy{MinLength: 8},
+	})
+
+	if _, err := s.Login(ctx, LoginInput{Username: "user1"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() without password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	in := SetPasswordInput{Username: "user1", BootstrapToken: "bootstrap", NewPassword: "correct horse 1"}
+	if err := s.SetPassword(ctx, in); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	token, err := s.Login(ctx, LoginInput{Username: "user1", Password: "correct horse 1"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	user, err := s.Authenticate(ctx, token.AccessToken)
+	if err != nil || user.Username != "user1" {
+		t.Fatalf("Authenticate() = %s, %v, want user1", user.Username, err)
+	}
+
+	// Token holder can't take account over once password is set
+	in.NewPassword = "stolen horse 1"
+	if err := s.SetPassword(ctx, in); !errors.Is(err, ErrPasswordAlreadySet) {
+		t.Errorf("second SetPassword() error = %v, want %v", err, ErrPasswordAlreadySet)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"app/internal/authz"
 	e "app/internal/entity"
 	"app/internal/repo"
 	"app/internal/repo/repoerrors"
@@ -13,22 +14,32 @@ import (
 )
 
 type BidService struct {
-	tenderRepo   repo.Tender
-	employeeRepo repo.Employee
-	bidRepo      repo.Bid
+	tenderRepo     repo.Tender
+	employeeRepo   repo.Employee
+	bidRepo        repo.Bid
+	invitationRepo repo.Invitation
+	auditRepo      repo.Audit
+	outboxRepo     repo.Outbox
+	tx             repo.Transactor
+	authz          *authz.Authorizer
 }
 
-func NewBidService(tRepo repo.Tender, eRepo repo.Employee, bRepo repo.Bid) *BidService {
+func NewBidService(tRepo repo.Tender, eRepo repo.Employee, bRepo repo.Bid, iRepo repo.Invitation, aRepo repo.Audit, obRepo repo.Outbox, tx repo.Transactor, az *authz.Authorizer) *BidService {
 	return &BidService{
-		tenderRepo:   tRepo,
-		employeeRepo: eRepo,
-		bidRepo:      bRepo,
+		tenderRepo:     tRepo,
+		employeeRepo:   eRepo,
+		bidRepo:        bRepo,
+		invitationRepo: iRepo,
+		auditRepo:      aRepo,
+		outboxRepo:     obRepo,
+		tx:             tx,
+		authz:          az,
 	}
 }
 
 func (s *BidService) CreateBid(ctx context.Context, in CreateBidInput) (e.Bid, error) {
 	// Check if user exists
-	_, err := s.employeeRepo.GetById(ctx, in.AuthorId)
+	employee, err := s.employeeRepo.GetById(ctx, in.AuthorId)
 	if err != nil {
 		if errors.Is(err, repoerrors.ErrNotFound) {
 			return e.Bid{}, ErrUsername
@@ -36,6 +47,9 @@ func (s *BidService) CreateBid(ctx context.Context, in CreateBidInput) (e.Bid, e
 		log.Errorf("BidService.Create - employeeRepo.GetByid: %v", err)
 		return e.Bid{}, ErrGetEmployeeById
 	}
+	if !employee.IsActive {
+		return e.Bid{}, ErrEmployeeDeactivated
+	}
 
 	// Check if tender exists and published
 	tender, err := s.tenderRepo.Get(ctx, in.TenderId, rt.VersionLatest)
@@ -50,113 +64,199 @@ func (s *BidService) CreateBid(ctx context.Context, in CreateBidInput) (e.Bid, e
 		return e.Bid{}, ErrNotFoundTender
 	}
 
-	// Check resposibility in case when AuthorType = "Organization"
-	if in.AuthorType == "Organization" {
-		isResponsible, err := s.employeeRepo.IsResponsibleSimplified(ctx, in.AuthorId)
-		if err != nil {
-			log.Errorf("BidService.Create - employeeRepo.IsResponsibleSimplified: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
+	// Check if tender is visible to author
+	isVisible, err := isTenderVisible(ctx, s.employeeRepo, s.invitationRepo, tender, in.AuthorId)
+	if err != nil {
+		log.Errorf("BidService.Create - isTenderVisible: %v", err)
+		return e.Bid{}, ErrCheckVisibility
+	}
+	if !isVisible {
+		return e.Bid{}, ErrNotFoundTender
+	}
+
+	// Organization bid is made on behalf of organization author is responsible for
+	var orgId uuid.NullUUID
+	switch in.AuthorType {
+	case "Organization":
+		if in.OrganizationId == uuid.Nil {
+			return e.Bid{}, ErrBidOrganization
 		}
-		if !isResponsible {
-			return e.Bid{}, ErrForbidden
+		if err := checkDecision(s.authz.CanCreateOrganizationBid(ctx, employeeCaller(employee).actor, in.OrganizationId)); err != nil {
+			return e.Bid{}, err
+		}
+		orgId = uuid.NullUUID{UUID: in.OrganizationId, Valid: true}
+	case "User":
+		if in.OrganizationId != uuid.Nil {
+			return e.Bid{}, ErrBidOrganization
 		}
 	}
 
-	// Create bid
-	bid, err := s.bidRepo.Create(ctx, rt.CreateBidInput{
-		Name:        in.Name,
-		Description: in.Description,
-		AuthorType:  in.AuthorType,
-		AuthorId:    in.AuthorId,
-		TenderId:    in.TenderId,
+	// Create bid and record it in audit log
+	var bid e.Bid
+	err = inTx(ctx, s.tx, "BidService.CreateBid", func(ctx context.Context) error {
+		var err error
+		bid, err = s.bidRepo.Create(ctx, rt.CreateBidInput{
+			Name:           in.Name,
+			Description:    in.Description,
+			AuthorType:     in.AuthorType,
+			AuthorId:       in.AuthorId,
+			TenderId:       in.TenderId,
+			OrganizationId: orgId,
+		})
+		if err != nil {
+			log.Errorf("BidService - CreateBid - bidRepo.Create: %v", err)
+			return ErrCreateBid
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, "BidService.CreateBid", employeeCaller(employee), bidAuditEntry(AuditActionCreate, nil, bid)); err != nil {
+			return err
+		}
+
+		return publishEvent(ctx, s.outboxRepo, "BidService.CreateBid", rt.CreateOutboxEventInput{
+			Type:           EventBidCreated,
+			AggregateType:  AuditEntityBid,
+			AggregateId:    bid.Id,
+			OrganizationId: uuid.NullUUID{UUID: tender.OrganizationId, Valid: true},
+		}, newBidEventPayload(bid, tender))
 	})
 	if err != nil {
-		log.Errorf("BidService - CreateBid - bidRepo.Create: %v", err)
-		return e.Bid{}, ErrCreateBid
+		return e.Bid{}, err
 	}
 
 	return bid, nil
 }
 
 func (s *BidService) SubmitDecision(ctx context.Context, bidId uuid.UUID, username string, decision string) (e.Bid, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "BidService.SubmitDecision", username)
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrUsername
-		}
-		log.Errorf("BidService.SubmitDecision - employeeRepo.GetByUsername: %v", err)
-		return e.Bid{}, ErrGetEmployeeById
-	}
+		return e.Bid{}, err
+	}
+
+	// Checks, tender close, audit and event share one transaction, so decision
+	// is made on the state it was checked against. Transaction is run again
+	// when tender or bid changes before it is locked
+	var bid e.Bid
+	err = retryConflicts(Precondition{}, func() error {
+		return inTx(ctx, s.tx, "BidService.SubmitDecision", func(ctx context.Context) error {
+			// Find tender of bid, it never changes
+			var err error
+			bid, err = s.bidRepo.Get(ctx, bidId, rt.VersionLatest)
+			if err != nil {
+				if errors.Is(err, repoerrors.ErrNotFound) {
+					return ErrNotFoundBid
+				}
+				log.Errorf("BidService.SubmitDecision - bidRepo.Get: %v", err)
+				return ErrGetBid
+			}
 
-	// Check if bid exists and published
-	bid, err := s.bidRepo.Get(ctx, bidId, rt.VersionLatest)
-	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrNotFoundBid
-		}
-		log.Errorf("BidService.SubmitDecision - bidRepo.Get: %v", err)
-		return e.Bid{}, ErrGetBid
-	}
-	if bid.Status != "Published" {
-		return e.Bid{}, ErrNotFoundBid
-	}
+			// Check if tender exists and published. Lock keeps concurrent
+			// decisions on its bids from both closing it
+			tender, err := s.tenderRepo.GetForUpdate(ctx, bid.TenderId)
+			if err != nil {
+				if errors.Is(err, repoerrors.ErrNotFound) {
+					return ErrNotFoundTender
+				}
+				return versionError("BidService.SubmitDecision", "tenderRepo.GetForUpdate", err, ErrGetTender)
+			}
+			if tender.Status != "Published" {
+				return ErrNotFoundTender
+			}
 
-	// Check if tender exists and published
-	tender, err := s.tenderRepo.Get(ctx, bid.TenderId, rt.VersionLatest)
-	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrNotFoundTender
-		}
-		log.Errorf("BidService.SubmitDecision - tenderRepo.Get: %v", err)
-		return e.Bid{}, ErrGetTender
-	}
-	if tender.Status != "Published" {
-		return e.Bid{}, ErrNotFoundTender
-	}
+			// Check if bid is still published, locks are taken tender first
+			bid, err = s.bidRepo.GetForUpdate(ctx, bidId)
+			if err != nil {
+				if errors.Is(err, repoerrors.ErrNotFound) {
+					return ErrNotFoundBid
+				}
+				return versionError("BidService.SubmitDecision", "bidRepo.GetForUpdate", err, ErrGetBid)
+			}
+			if bid.Status != "Published" {
+				return ErrNotFoundBid
+			}
 
-	// Check responsibility
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, tender.OrganizationId, user.Id)
+			// Check rights
+			if err := checkDecision(s.authz.CanDecideOnBid(ctx, c.actor, bid, tender)); err != nil {
+				return err
+			}
+
+			// Make decision, approval closes tender
+			tenderAfter := tender
+			if decision == "Approved" {
+				tenderAfter, err = s.tenderRepo.ChangeStatus(ctx, tender.Id, "Closed")
+				if err != nil {
+					if errors.Is(err, repoerrors.ErrNotFound) {
+						return ErrNotFoundTender
+					}
+					log.Errorf("BidService.SubmitDecision - tenderRepo.ChangeStatus: %v", err)
+					return ErrGetTender
+				}
+				entry := tenderAuditEntry(AuditActionChangeStatus, &tender, tenderAfter)
+				if err := recordAudit(ctx, s.auditRepo, "BidService.SubmitDecision", c, entry); err != nil {
+					return err
+				}
+				if err := publishTenderChange(ctx, s.outboxRepo, "BidService.SubmitDecision", EventTenderStatusChanged, tender, tenderAfter); err != nil {
+					return err
+				}
+			}
+
+			err = recordAudit(ctx, s.auditRepo, "BidService.SubmitDecision", c, auditEntry{
+				OrganizationId: uuid.NullUUID{UUID: tender.OrganizationId, Valid: true},
+				Action:         AuditActionSubmitDecision,
+				EntityType:     AuditEntityBid,
+				EntityId:       bid.Id,
+				Version:        bid.Version,
+				Before:         bid,
+				After:          decisionSnapshot{Decision: decision, Bid: bid},
+			})
+			if err != nil {
+				return err
+			}
+
+			return publishEvent(ctx, s.outboxRepo, "BidService.SubmitDecision", rt.CreateOutboxEventInput{
+				Type:           EventBidDecisionSubmitted,
+				AggregateType:  AuditEntityBid,
+				AggregateId:    bid.Id,
+				OrganizationId: uuid.NullUUID{UUID: tender.OrganizationId, Valid: true},
+			}, decisionEventPayload{
+				bidEventPayload: newBidEventPayload(bid, tender),
+				Decision:        decision,
+				TenderStatus:    tenderAfter.Status,
+			})
+		})
+	})
 	if err != nil {
-		log.Errorf("BidService.SubmitDecision - employeeRepo.IsResponsible: %v", err)
-		return e.Bid{}, ErrCheckResponsibility
-	}
-	if !isResponsible {
-		return e.Bid{}, ErrForbidden
+		return e.Bid{}, err
 	}
 
-	// Make decision
-	var resBid e.Bid
-	switch decision {
-	case "Rejected":
-		resBid = bid
-	case "Approved":
-		if _, err := s.tenderRepo.ChangeStatus(ctx, tender.Id, "Closed"); err != nil {
-			if errors.Is(err, repoerrors.ErrNotFound) {
-				return e.Bid{}, ErrNotFoundTender
-			}
-			log.Errorf("BidService.ChangeStatus - tenderRepo.ChangeStatus: %v", err)
-			return e.Bid{}, ErrGetTender
-		}
-		resBid = bid
+	return bid, nil
+}
+
+// ChangeStatus is repeated when it loses race to concurrent write, unless caller
+// set precondition
+func (s *BidService) ChangeStatus(ctx context.Context, in ChangeBidStatusInput) (e.Bid, error) {
+	var b e.Bid
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		b, err = s.changeStatus(ctx, in)
+		return err
+	})
+	if err != nil {
+		return e.Bid{}, err
 	}
 
-	return resBid, nil
+	return b, nil
 }
 
-func (s *BidService) ChangeStatus(ctx context.Context, bidId uuid.UUID, status, username string) (e.Bid, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, username)
+func (s *BidService) changeStatus(ctx context.Context, in ChangeBidStatusInput) (e.Bid, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "BidService.ChangeStatus", in.Username)
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrUsername
-		}
-		log.Errorf("BidService.ChangeStatus - employeeRepo.GetByUsername: %v", err)
-		return e.Bid{}, ErrGetEmployeeById
+		return e.Bid{}, err
 	}
 
 	// Check if bid exists
-	bid, err := s.bidRepo.Get(ctx, bidId, rt.VersionLatest)
+	bid, err := s.bidRepo.Get(ctx, in.BidId, rt.VersionLatest)
 	if err != nil {
 		if errors.Is(err, repoerrors.ErrNotFound) {
 			return e.Bid{}, ErrNotFoundBid
@@ -165,49 +265,46 @@ func (s *BidService) ChangeStatus(ctx context.Context, bidId uuid.UUID, status,
 		return e.Bid{}, ErrGetBid
 	}
 
-	// Check responsibility
-	var isResponsible bool
-	switch bid.AuthorType {
-	case "User":
-		isResponsible = bid.AuthorId == user.Id
-	case "Organization":
-		orgId, err := s.employeeRepo.GetOrgIdFromResponsible(ctx, bid.AuthorId)
-		if err != nil {
-			log.Errorf("BidService.ChangeStatus - employeeRepo.GetOrgIdFromResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
+	// Check rights
+	if err := checkDecision(s.authz.CanEditBid(ctx, c.actor, bid)); err != nil {
+		return e.Bid{}, err
+	}
+	if err := in.Precondition.check(bid.Version, bid.UpdatedAt); err != nil {
+		return e.Bid{}, err
+	}
+
+	// Change status and record it in audit log, version read above must
+	// still be latest
+	var b e.Bid
+	err = inTx(ctx, s.tx, "BidService.ChangeStatus", func(ctx context.Context) error {
+		if err := s.bidRepo.Lock(ctx, bid.Id, bid.Version, bid.UpdatedAt); err != nil {
+			return versionError("BidService.ChangeStatus", "bidRepo.Lock", err, ErrGetBid)
 		}
-		isResponsible, err = s.employeeRepo.IsResponsible(ctx, orgId, user.Id)
+
+		var err error
+		b, err = s.bidRepo.ChangeStatus(ctx, in.BidId, in.Status)
 		if err != nil {
-			log.Errorf("BidService.ChangeStatus - employeeRepo.IsResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				return ErrNotFoundBid
+			}
+			log.Errorf("BidService.ChangeStatus - bidRepo.ChangeStatus: %v", err)
+			return ErrGetBid
 		}
-	}
-	if !isResponsible {
-		return e.Bid{}, ErrForbidden
-	}
 
-	// Change status
-	b, err := s.bidRepo.ChangeStatus(ctx, bidId, status)
+		return recordAudit(ctx, s.auditRepo, "BidService.ChangeStatus", c, bidAuditEntry(AuditActionChangeStatus, &bid, b))
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrNotFoundBid
-		}
-		log.Errorf("BidService.ChangeStatus - bidRepo.ChangeStatus: %v", err)
-		return e.Bid{}, ErrGetBid
+		return e.Bid{}, err
 	}
 
 	return b, nil
 }
 
 func (s *BidService) Get(ctx context.Context, bidId uuid.UUID, username string) (e.Bid, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "BidService.Get", username)
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrUsername
-		}
-		log.Errorf("BidService.Get - employeeRepo.GetByUsername: %v", err)
-		return e.Bid{}, ErrGetEmployeeById
+		return e.Bid{}, err
 	}
 
 	// Check if bid exists
@@ -220,39 +317,45 @@ func (s *BidService) Get(ctx context.Context, bidId uuid.UUID, username string)
 		return e.Bid{}, ErrGetBid
 	}
 
-	// Check responsibility
-	var isResponsible bool
-	switch bid.AuthorType {
-	case "User":
-		isResponsible = bid.AuthorId == user.Id
-	case "Organization":
-		orgId, err := s.employeeRepo.GetOrgIdFromResponsible(ctx, bid.AuthorId)
-		if err != nil {
-			log.Errorf("BidService.Get - employeeRepo.GetOrgIdFromResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
-		}
-		isResponsible, err = s.employeeRepo.IsResponsible(ctx, orgId, user.Id)
-		if err != nil {
-			log.Errorf("BidService.Get - employeeRepo.IsResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
+	// Check if tender exists
+	tender, err := s.tenderRepo.Get(ctx, bid.TenderId, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Bid{}, ErrNotFoundTender
 		}
+		log.Errorf("BidService.Get - tenderRepo.Get: %v", err)
+		return e.Bid{}, ErrGetTender
 	}
-	if !isResponsible {
-		return e.Bid{}, ErrForbidden
+
+	// Check rights, API keys read bids on tenders of their organization
+	if err := checkDecision(s.authz.CanViewBid(ctx, c.actor, bid, tender)); err != nil {
+		return e.Bid{}, err
 	}
 
 	return bid, nil
 }
 
+// Edit is repeated when it loses race to concurrent write, unless caller
+// set precondition
 func (s *BidService) Edit(ctx context.Context, in EditBidInput) (e.Bid, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	var b e.Bid
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		b, err = s.edit(ctx, in)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrUsername
-		}
-		log.Errorf("BidService.Edit - employeeRepo.GetByUsername: %v", err)
-		return e.Bid{}, ErrGetEmployeeById
+		return e.Bid{}, err
+	}
+
+	return b, nil
+}
+
+func (s *BidService) edit(ctx context.Context, in EditBidInput) (e.Bid, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "BidService.Edit", in.Username)
+	if err != nil {
+		return e.Bid{}, err
 	}
 
 	// Check if bid exists
@@ -265,37 +368,25 @@ func (s *BidService) Edit(ctx context.Context, in EditBidInput) (e.Bid, error) {
 		return e.Bid{}, ErrGetBid
 	}
 
-	// Check responsibility
-	var isResponsible bool
-	switch bid.AuthorType {
-	case "User":
-		isResponsible = bid.AuthorId == user.Id
-	case "Organization":
-		orgId, err := s.employeeRepo.GetOrgIdFromResponsible(ctx, bid.AuthorId)
-		if err != nil {
-			log.Errorf("BidService.Edit - employeeRepo.GetOrgIdFromResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
-		}
-		isResponsible, err = s.employeeRepo.IsResponsible(ctx, orgId, user.Id)
-		if err != nil {
-			log.Errorf("BidService.Edit - employeeRepo.IsResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
-		}
+	// Check rights
+	if err := checkDecision(s.authz.CanEditBid(ctx, c.actor, bid)); err != nil {
+		return e.Bid{}, err
 	}
-	if !isResponsible {
-		return e.Bid{}, ErrForbidden
+	if err := in.Precondition.check(bid.Version, bid.UpdatedAt); err != nil {
+		return e.Bid{}, err
 	}
 
 	// Create edited version
 	input := rt.CreateSpecifiedBidInput{
-		Id:          in.BidId,
-		Name:        in.Name,
-		Description: in.Description,
-		AuthorType:  bid.AuthorType,
-		AuthorId:    bid.AuthorId,
-		Status:      bid.Status,
-		Version:     bid.Version + 1,
-		TenderId:    bid.TenderId,
+		Id:             in.BidId,
+		Name:           in.Name,
+		Description:    in.Description,
+		AuthorType:     bid.AuthorType,
+		AuthorId:       bid.AuthorId,
+		Status:         bid.Status,
+		Version:        bid.Version + 1,
+		TenderId:       bid.TenderId,
+		OrganizationId: bid.OrganizationId,
 	}
 	if in.Name == "" {
 		input.Name = bid.Name
@@ -303,28 +394,52 @@ func (s *BidService) Edit(ctx context.Context, in EditBidInput) (e.Bid, error) {
 	if in.Description == "" {
 		input.Description = bid.Description
 	}
-	b, err := s.bidRepo.CreateSpecified(ctx, input)
+	var b e.Bid
+	err = inTx(ctx, s.tx, "BidService.Edit", func(ctx context.Context) error {
+		if err := s.bidRepo.Lock(ctx, bid.Id, bid.Version, bid.UpdatedAt); err != nil {
+			return versionError("BidService.Edit", "bidRepo.Lock", err, ErrGetBid)
+		}
+
+		var err error
+		b, err = s.bidRepo.CreateSpecified(ctx, input)
+		if err != nil {
+			return versionError("BidService.Edit", "bidRepo.CreateSpecified", err, ErrCreateBid)
+		}
+
+		return recordAudit(ctx, s.auditRepo, "BidService.Edit", c, bidAuditEntry(AuditActionEdit, &bid, b))
+	})
 	if err != nil {
-		log.Errorf("BidService.Edit - bidRepo.CreateSpecified: %v", err)
-		return e.Bid{}, ErrCreateBid
+		return e.Bid{}, err
 	}
 
 	return b, nil
 }
 
-func (s *BidService) Rollback(ctx context.Context, bidId uuid.UUID, verison int, username string) (e.Bid, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, username)
+// Rollback is repeated when it loses race to concurrent write, unless caller
+// set precondition
+func (s *BidService) Rollback(ctx context.Context, in RollbackBidInput) (e.Bid, error) {
+	var b e.Bid
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		b, err = s.rollback(ctx, in)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Bid{}, ErrUsername
-		}
-		log.Errorf("BidService.Rollback - employeeRepo.GetByUsername: %v", err)
-		return e.Bid{}, ErrGetEmployeeById
+		return e.Bid{}, err
+	}
+
+	return b, nil
+}
+
+func (s *BidService) rollback(ctx context.Context, in RollbackBidInput) (e.Bid, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "BidService.Rollback", in.Username)
+	if err != nil {
+		return e.Bid{}, err
 	}
 
 	// Check if bid exists
-	bidToRollback, err := s.bidRepo.Get(ctx, bidId, verison)
+	bidToRollback, err := s.bidRepo.Get(ctx, in.BidId, in.Version)
 	if err != nil {
 		if errors.Is(err, repoerrors.ErrNotFound) {
 			return e.Bid{}, ErrNotFoundBid
@@ -333,48 +448,48 @@ func (s *BidService) Rollback(ctx context.Context, bidId uuid.UUID, verison int,
 		return e.Bid{}, ErrGetBid
 	}
 
-	// Check responsibility
-	var isResponsible bool
-	switch bidToRollback.AuthorType {
-	case "User":
-		isResponsible = bidToRollback.AuthorId == user.Id
-	case "Organization":
-		orgId, err := s.employeeRepo.GetOrgIdFromResponsible(ctx, bidToRollback.AuthorId)
-		if err != nil {
-			log.Errorf("BidService.Rollback - employeeRepo.GetOrgIdFromResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
-		}
-		isResponsible, err = s.employeeRepo.IsResponsible(ctx, orgId, user.Id)
-		if err != nil {
-			log.Errorf("BidService.Rollback - employeeRepo.IsResponsible: %v", err)
-			return e.Bid{}, ErrCheckResponsibility
-		}
-	}
-	if !isResponsible {
-		return e.Bid{}, ErrForbidden
+	// Check rights
+	if err := checkDecision(s.authz.CanEditBid(ctx, c.actor, bidToRollback)); err != nil {
+		return e.Bid{}, err
 	}
 
 	// Get latest version bid
-	latestVersionBid, err := s.bidRepo.Get(ctx, bidId, rt.VersionLatest)
+	latestVersionBid, err := s.bidRepo.Get(ctx, in.BidId, rt.VersionLatest)
 	if err != nil {
 		log.Errorf("BidService.Rollback - bidRepo.Get: %v", err)
 		return e.Bid{}, ErrGetBid
 	}
+	if err := in.Precondition.check(latestVersionBid.Version, latestVersionBid.UpdatedAt); err != nil {
+		return e.Bid{}, err
+	}
+
+	// Rollback bid and record it in audit log
+	var b e.Bid
+	err = inTx(ctx, s.tx, "BidService.Rollback", func(ctx context.Context) error {
+		if err := s.bidRepo.Lock(ctx, latestVersionBid.Id, latestVersionBid.Version, latestVersionBid.UpdatedAt); err != nil {
+			return versionError("BidService.Rollback", "bidRepo.Lock", err, ErrGetBid)
+		}
+
+		var err error
+		b, err = s.bidRepo.CreateSpecified(ctx, rt.CreateSpecifiedBidInput{
+			Id:             in.BidId,
+			Name:           bidToRollback.Name,
+			Version:        latestVersionBid.Version + 1,
+			Description:    bidToRollback.Description,
+			AuthorType:     bidToRollback.AuthorType,
+			AuthorId:       bidToRollback.AuthorId,
+			Status:         bidToRollback.Status,
+			TenderId:       bidToRollback.TenderId,
+			OrganizationId: bidToRollback.OrganizationId,
+		})
+		if err != nil {
+			return versionError("BidService.Rollback", "bidRepo.CreateSpecified", err, ErrCreateBid)
+		}
 
-	// Rollback bid
-	b, err := s.bidRepo.CreateSpecified(ctx, rt.CreateSpecifiedBidInput{
-		Id: bidId,
-		Name: bidToRollback.Name,
-		Version: latestVersionBid.Version + 1,
-		Description: bidToRollback.Description,
-		AuthorType: bidToRollback.AuthorType,
-		AuthorId: bidToRollback.AuthorId,
-		Status: bidToRollback.Status,
-		TenderId: bidToRollback.TenderId,
+		return recordAudit(ctx, s.auditRepo, "BidService.Rollback", c, bidAuditEntry(AuditActionRollback, &latestVersionBid, b))
 	})
 	if err != nil {
-		log.Errorf("BidService.Rollback - bidRepo.CreateSpecified: %v", err)
-		return e.Bid{}, ErrCreateBid
+		return e.Bid{}, err
 	}
 
 	return b, nil
@@ -0,0 +1,631 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/uuid"
+)
+
+func TestBidServiceCreateBid(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(f *fixture)
+		in      func(f *fixture) CreateBidInput
+		wantErr error
+		check   func(t *testing.T, f *fixture, bid e.Bid)
+	}{
+		{
+			name: "user bid",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{Name: "Bid", AuthorType: "User", AuthorId: f.outsider.Id}
+			},
+			check: func(t *testing.T, f *fixture, bid e.Bid) {
+				if bid.Version != 1 || bid.Status != "Created" || bid.OrganizationId.Valid {
+					t.Errorf("bid = %+v, want version 1 Created user bid", bid)
+				}
+				if got := f.outbox.types(); !slices.Equal(got, []string{EventBidCreated}) {
+					t.Errorf("events = %v, want [%s]", got, EventBidCreated)
+				}
+				if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionCreate {
+					t.Errorf("audit = %v, want one create entry", f.audit.entries)
+				}
+			},
+		},
+		{
+			name: "organization bid",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{AuthorType: "Organization", AuthorId: f.rival.Id, OrganizationId: f.rivalOrg}
+			},
+			check: func(t *testing.T, f *fixture, bid e.Bid) {
+				if bid.OrganizationId != (uuid.NullUUID{UUID: f.rivalOrg, Valid: true}) {
+					t.Errorf("OrganizationId = %v, want %v", bid.OrganizationId, f.rivalOrg)
+				}
+			},
+		},
+		{
+			name:  "internal tender by responsible",
+			setup: func(f *fixture) { f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInternal },
+			in:    func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.viewer.Id} },
+		},
+		{
+			name:    "internal tender by outsider",
+			setup:   func(f *fixture) { f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInternal },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrNotFoundTender,
+		},
+		{
+			name: "invite-only tender by invitee",
+			setup: func(f *fixture) {
+				f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInviteOnly
+				f.invitations.Create(context.Background(), rt.CreateInvitationInput{
+					TenderId: f.tender.Id,
+					UserId:   uuid.NullUUID{UUID: f.outsider.Id, Valid: true},
+				})
+			},
+			in: func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+		},
+		{
+			name:    "invite-only tender without invitation",
+			setup:   func(f *fixture) { f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInviteOnly },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrNotFoundTender,
+		},
+		{
+			name: "visibility check fails",
+			setup: func(f *fixture) {
+				f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInternal
+				f.employees.rolesErr = errRepo
+			},
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrCheckVisibility,
+		},
+		{
+			name:    "tender not published",
+			setup:   func(f *fixture) { f.tenders.versions[f.tender.Id][0].Status = "Created" },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrNotFoundTender,
+		},
+		{
+			name:    "tender closed",
+			setup:   func(f *fixture) { f.tenders.versions[f.tender.Id][0].Status = "Closed" },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrNotFoundTender,
+		},
+		{
+			name: "tender not found",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{TenderId: uuid.New(), AuthorType: "User", AuthorId: f.outsider.Id}
+			},
+			wantErr: ErrNotFoundTender,
+		},
+		{
+			name:    "tender repo fails",
+			setup:   func(f *fixture) { f.tenders.err = errRepo },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrGetTender,
+		},
+		{
+			name:    "unknown author",
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: uuid.New()} },
+			wantErr: ErrUsername,
+		},
+		{
+			name:    "employee lookup fails",
+			setup:   func(f *fixture) { f.employees.err = errRepo },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrGetEmployeeById,
+		},
+		{
+			name:    "deactivated author",
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.inactive.Id} },
+			wantErr: ErrEmployeeDeactivated,
+		},
+		{
+			name: "organization bid without organization",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{AuthorType: "Organization", AuthorId: f.rival.Id}
+			},
+			wantErr: ErrBidOrganization,
+		},
+		{
+			name: "user bid with organization",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{AuthorType: "User", AuthorId: f.rival.Id, OrganizationId: f.rivalOrg}
+			},
+			wantErr: ErrBidOrganization,
+		},
+		{
+			name: "organization bid by outsider",
+			in: func(f *fixture) CreateBidInput {
+				return CreateBidInput{AuthorType: "Organization", AuthorId: f.outsider.Id, OrganizationId: f.rivalOrg}
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "bid repo fails",
+			setup:   func(f *fixture) { f.bids.err = errRepo },
+			in:      func(f *fixture) CreateBidInput { return CreateBidInput{AuthorType: "User", AuthorId: f.outsider.Id} },
+			wantErr: ErrCreateBid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			in := tt.in(f)
+			if in.TenderId == uuid.Nil {
+				in.TenderId = f.tender.Id
+			}
+
+			bid, err := f.bidService().CreateBid(context.Background(), in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				if len(f.bids.versions) != 0 {
+					t.Errorf("bids = %v, want none", f.bids.versions)
+				}
+				return
+			}
+			if bid.TenderId != f.tender.Id || bid.AuthorId != in.AuthorId {
+				t.Errorf("bid = %+v, want bid of %v on tender", bid, in.AuthorId)
+			}
+			if tt.check != nil {
+				tt.check(t, f, bid)
+			}
+		})
+	}
+}
+
+func TestBidServiceSubmitDecision(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(f *fixture)
+		username   string
+		decision   string
+		missing    bool
+		wantErr    error
+		wantStatus string
+		wantEvents []string
+	}{
+		{
+			name:       "approval closes tender",
+			username:   "admin",
+			decision:   "Approved",
+			wantStatus: "Closed",
+			wantEvents: []string{EventTenderStatusChanged, EventBidDecisionSubmitted},
+		},
+		{
+			name:       "rejection keeps tender published",
+			username:   "admin",
+			decision:   "Rejected",
+			wantStatus: "Published",
+			wantEvents: []string{EventBidDecisionSubmitted},
+		},
+		{name: "unknown username", username: "ghost", decision: "Approved", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", decision: "Approved", wantErr: ErrEmployeeDeactivated},
+		{name: "bid author", username: "outsider", decision: "Approved", wantErr: ErrForbidden},
+		{name: "viewer can't decide", username: "viewer", decision: "Approved", wantErr: ErrForbidden},
+		{name: "other organization", username: "rival", decision: "Approved", wantErr: ErrForbidden},
+		{name: "roles lookup fails", setup: func(f *fixture) { f.employees.rolesErr = errRepo }, username: "admin", decision: "Approved", wantErr: ErrCheckResponsibility},
+		{name: "bid not found", username: "admin", decision: "Approved", missing: true, wantErr: ErrNotFoundBid},
+		{name: "bid repo fails", setup: func(f *fixture) { f.bids.err = errRepo }, username: "admin", decision: "Approved", wantErr: ErrGetBid},
+		{
+			name: "bid not published",
+			setup: func(f *fixture) {
+				for id := range f.bids.versions {
+					f.bids.versions[id][0].Status = "Created"
+				}
+			},
+			username: "admin",
+			decision: "Approved",
+			wantErr:  ErrNotFoundBid,
+		},
+		{
+			name:     "tender not published",
+			setup:    func(f *fixture) { f.tenders.versions[f.tender.Id][0].Status = "Closed" },
+			username: "admin",
+			decision: "Approved",
+			wantErr:  ErrNotFoundTender,
+		},
+		{
+			name:     "tender not found",
+			setup:    func(f *fixture) { delete(f.tenders.versions, f.tender.Id) },
+			username: "admin",
+			decision: "Approved",
+			wantErr:  ErrNotFoundTender,
+		},
+		{name: "tender repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", decision: "Approved", wantErr: ErrGetTender},
+		{
+			name:       "tender changed before lock",
+			setup:      func(f *fixture) { f.tenders.conflicts = 1 },
+			username:   "admin",
+			decision:   "Approved",
+			wantStatus: "Closed",
+			wantEvents: []string{EventTenderStatusChanged, EventBidDecisionSubmitted},
+		},
+		{
+			name:       "bid changed before lock",
+			setup:      func(f *fixture) { f.bids.conflicts = 2 },
+			username:   "admin",
+			decision:   "Rejected",
+			wantStatus: "Published",
+			wantEvents: []string{EventBidDecisionSubmitted},
+		},
+		{
+			name:     "tender keeps changing",
+			setup:    func(f *fixture) { f.tenders.conflicts = maxConflictRetries },
+			username: "admin",
+			decision: "Approved",
+			wantErr:  ErrConcurrentUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			bid := f.newBid("Published")
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			bidId := bid.Id
+			if tt.missing {
+				bidId = uuid.New()
+			}
+
+			got, err := f.bidService().SubmitDecision(context.Background(), bidId, tt.username, tt.decision)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				if len(f.outbox.events) != 0 || len(f.audit.entries) != 0 {
+					t.Errorf("events, audit = %v, %v, want none", f.outbox.events, f.audit.entries)
+				}
+				return
+			}
+			if got.Id != bid.Id {
+				t.Errorf("Id = %v, want %v", got.Id, bid.Id)
+			}
+			if tender, _ := f.tenders.latest(f.tender.Id); tender.Status != tt.wantStatus {
+				t.Errorf("tender Status = %q, want %q", tender.Status, tt.wantStatus)
+			}
+			if got := f.outbox.types(); !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+
+	t.Run("API key", func(t *testing.T) {
+		f := newFixture()
+		bid := f.newBid("Published")
+		ctx := apiKeyContext(f.orgId, authz.ScopeTendersWrite, authz.ScopeBidsRead)
+
+		_, err := f.bidService().SubmitDecision(ctx, bid.Id, "", "Approved")
+		checkErr(t, err, ErrForbidden)
+	})
+}
+
+func TestBidServiceChangeStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		pre      Precondition
+		missing  bool
+		wantErr  error
+	}{
+		{name: "author", username: "outsider"},
+		{name: "matching precondition", username: "outsider", pre: Precondition{Version: 1}},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "other user", username: "rival", wantErr: ErrForbidden},
+		{name: "tender responsible", username: "admin", wantErr: ErrForbidden},
+		{name: "bid not found", username: "outsider", missing: true, wantErr: ErrNotFoundBid},
+		{name: "repo fails", setup: func(f *fixture) { f.bids.err = errRepo }, username: "outsider", wantErr: ErrGetBid},
+		{name: "stale precondition", username: "outsider", pre: Precondition{Version: 3}, wantErr: ErrVersionMismatch},
+		{
+			name:     "lost race",
+			setup:    func(f *fixture) { f.bids.lockErr = repoerrors.ErrVersionConflict },
+			username: "outsider",
+			wantErr:  ErrConcurrentUpdate,
+		},
+		{
+			name:     "lost race with precondition",
+			setup:    func(f *fixture) { f.bids.lockErr = repoerrors.ErrVersionConflict },
+			username: "outsider",
+			pre:      Precondition{Version: 1},
+			wantErr:  ErrVersionMismatch,
+		},
+		{name: "lock fails", setup: func(f *fixture) { f.bids.lockErr = errRepo }, username: "outsider", wantErr: ErrGetBid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			bid := f.newBid("Created")
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			bidId := bid.Id
+			if tt.missing {
+				bidId = uuid.New()
+			}
+
+			got, err := f.bidService().ChangeStatus(context.Background(), ChangeBidStatusInput{
+				BidId:        bidId,
+				Status:       "Published",
+				Username:     tt.username,
+				Precondition: tt.pre,
+			})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Status != "Published" || got.Version != 1 {
+				t.Errorf("Status, Version = %q, %d, want Published, 1", got.Status, got.Version)
+			}
+			if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionChangeStatus {
+				t.Errorf("audit = %v, want one change_status entry", f.audit.entries)
+			}
+		})
+	}
+
+	t.Run("organization bid", func(t *testing.T) {
+		f := newFixture()
+		bid := f.bids.add(e.Bid{
+			Id:             uuid.New(),
+			AuthorType:     "Organization",
+			AuthorId:       f.rival.Id,
+			Status:         "Created",
+			TenderId:       f.tender.Id,
+			OrganizationId: uuid.NullUUID{UUID: f.rivalOrg, Valid: true},
+		})
+		other := f.employees.add("colleague", true)
+		f.employees.setRoles(f.rivalOrg, other.Id, authz.RoleEditor)
+		s := f.bidService()
+
+		if _, err := s.ChangeStatus(context.Background(), ChangeBidStatusInput{BidId: bid.Id, Status: "Published", Username: "colleague"}); err != nil {
+			t.Errorf("editor of bid organization: err = %v, want nil", err)
+		}
+		f.employees.setRoles(f.rivalOrg, other.Id, authz.RoleViewer)
+		_, err := s.ChangeStatus(context.Background(), ChangeBidStatusInput{BidId: bid.Id, Status: "Canceled", Username: "colleague"})
+		checkErr(t, err, ErrForbidden)
+	})
+}
+
+func TestBidServiceGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(f *fixture) context.Context
+		setup    func(f *fixture)
+		username string
+		missing  bool
+		wantErr  error
+	}{
+		{name: "author", username: "outsider"},
+		{name: "API key of tender organization", ctx: func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeBidsRead) }},
+		{
+			name:    "API key without scope",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersRead) },
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "API key of other organization",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.rivalOrg, authz.ScopeBidsRead) },
+			wantErr: ErrForbidden,
+		},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "other user", username: "rival", wantErr: ErrForbidden},
+		{name: "bid not found", username: "outsider", missing: true, wantErr: ErrNotFoundBid},
+		{name: "bid repo fails", setup: func(f *fixture) { f.bids.err = errRepo }, username: "outsider", wantErr: ErrGetBid},
+		{name: "tender not found", setup: func(f *fixture) { delete(f.tenders.versions, f.tender.Id) }, username: "outsider", wantErr: ErrNotFoundTender},
+		{name: "tender repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "outsider", wantErr: ErrGetTender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			bid := f.newBid("Published")
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			bidId := bid.Id
+			if tt.missing {
+				bidId = uuid.New()
+			}
+
+			got, err := f.bidService().Get(ctx, bidId, tt.username)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && got.Id != bid.Id {
+				t.Errorf("Id = %v, want %v", got.Id, bid.Id)
+			}
+		})
+	}
+}
+
+func TestBidServiceEdit(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		in       EditBidInput
+		missing  bool
+		wantErr  error
+		wantName string
+		wantDesc string
+	}{
+		{name: "all fields", in: EditBidInput{Username: "outsider", Name: "Renamed", Description: "Changed"}, wantName: "Renamed", wantDesc: "Changed"},
+		{name: "only name", in: EditBidInput{Username: "outsider", Name: "Renamed"}, wantName: "Renamed", wantDesc: "Description"},
+		{name: "only description", in: EditBidInput{Username: "outsider", Description: "Changed"}, wantName: "Bid", wantDesc: "Changed"},
+		{name: "empty edit keeps everything", in: EditBidInput{Username: "outsider"}, wantName: "Bid", wantDesc: "Description"},
+		{name: "unknown username", in: EditBidInput{Username: "ghost"}, wantErr: ErrUsername},
+		{name: "deactivated employee", in: EditBidInput{Username: "inactive"}, wantErr: ErrEmployeeDeactivated},
+		{name: "other user", in: EditBidInput{Username: "rival"}, wantErr: ErrForbidden},
+		{name: "bid not found", in: EditBidInput{Username: "outsider"}, missing: true, wantErr: ErrNotFoundBid},
+		{name: "repo fails", setup: func(f *fixture) { f.bids.err = errRepo }, in: EditBidInput{Username: "outsider"}, wantErr: ErrGetBid},
+		{name: "stale precondition", in: EditBidInput{Username: "outsider", Precondition: Precondition{Version: 2}}, wantErr: ErrVersionMismatch},
+		{
+			name:    "version taken",
+			setup:   func(f *fixture) { f.bids.createErr = repoerrors.ErrVersionConflict },
+			in:      EditBidInput{Username: "outsider"},
+			wantErr: ErrConcurrentUpdate,
+		},
+		{name: "create fails", setup: func(f *fixture) { f.bids.createErr = errRepo }, in: EditBidInput{Username: "outsider"}, wantErr: ErrCreateBid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			bid := f.newBid("Published")
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tt.in.BidId = bid.Id
+			if tt.missing {
+				tt.in.BidId = uuid.New()
+			}
+
+			got, err := f.bidService().Edit(context.Background(), tt.in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				if n := len(f.bids.versions[bid.Id]); n != 1 {
+					t.Errorf("versions = %d, want 1", n)
+				}
+				return
+			}
+			if got.Version != 2 || got.Name != tt.wantName || got.Description != tt.wantDesc {
+				t.Errorf("Version, Name, Description = %d, %q, %q, want 2, %q, %q", got.Version, got.Name, got.Description, tt.wantName, tt.wantDesc)
+			}
+			want := bid
+			want.Name, want.Description = tt.wantName, tt.wantDesc
+			if !sameBid(got, want) {
+				t.Errorf("bid = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBidServiceRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(f *fixture)
+		in      RollbackBidInput
+		missing bool
+		wantErr error
+	}{
+		{name: "first version", in: RollbackBidInput{Version: 1, Username: "outsider"}},
+		{name: "latest version", in: RollbackBidInput{Version: 3, Username: "outsider"}},
+		{name: "matching precondition", in: RollbackBidInput{Version: 2, Username: "outsider", Precondition: Precondition{Version: 3}}},
+		{name: "unknown username", in: RollbackBidInput{Version: 1, Username: "ghost"}, wantErr: ErrUsername},
+		{name: "deactivated employee", in: RollbackBidInput{Version: 1, Username: "inactive"}, wantErr: ErrEmployeeDeactivated},
+		{name: "other user", in: RollbackBidInput{Version: 1, Username: "rival"}, wantErr: ErrForbidden},
+		{name: "version not found", in: RollbackBidInput{Version: 4, Username: "outsider"}, wantErr: ErrNotFoundBid},
+		{name: "bid not found", in: RollbackBidInput{Version: 1, Username: "outsider"}, missing: true, wantErr: ErrNotFoundBid},
+		{name: "repo fails", setup: func(f *fixture) { f.bids.err = errRepo }, in: RollbackBidInput{Version: 1, Username: "outsider"}, wantErr: ErrGetBid},
+		{
+			name:    "stale precondition",
+			in:      RollbackBidInput{Version: 1, Username: "outsider", Precondition: Precondition{Version: 1}},
+			wantErr: ErrVersionMismatch,
+		},
+		{
+			name:    "lost race",
+			setup:   func(f *fixture) { f.bids.lockErr = repoerrors.ErrVersionConflict },
+			in:      RollbackBidInput{Version: 1, Username: "outsider"},
+			wantErr: ErrConcurrentUpdate,
+		},
+		{name: "create fails", setup: func(f *fixture) { f.bids.createErr = errRepo }, in: RollbackBidInput{Version: 1, Username: "outsider"}, wantErr: ErrCreateBid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			bid := f.newBid("Created")
+			edited := bid
+			edited.Name, edited.Status = "Second", "Published"
+			f.bids.add(edited)
+			edited.Name = "Third"
+			f.bids.add(edited)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tt.in.BidId = bid.Id
+			if tt.missing {
+				tt.in.BidId = uuid.New()
+			}
+
+			got, err := f.bidService().Rollback(context.Background(), tt.in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Version != 4 {
+				t.Errorf("Version = %d, want 4", got.Version)
+			}
+			target := f.bids.versions[bid.Id][tt.in.Version-1]
+			if !sameBid(got, target) {
+				t.Errorf("bid = %+v, want %+v", got, target)
+			}
+			if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionRollback {
+				t.Errorf("audit = %v, want one rollback entry", f.audit.entries)
+			}
+		})
+	}
+}
+
+// TestBidRollbackVersion is TestTenderRollbackVersion for bids
+func TestBidRollbackVersion(t *testing.T) {
+	property := func(seed int64) bool {
+		rnd := rand.New(rand.NewSource(seed))
+		f := newFixture()
+		bid := f.newBid("Created")
+		s := f.bidService()
+		ctx := context.Background()
+
+		steps := 1 + rnd.Intn(10)
+		for step := 0; step < steps; step++ {
+			latest, _ := f.bids.latest(bid.Id)
+
+			var got e.Bid
+			var target e.Bid
+			var err error
+			if rnd.Intn(2) == 0 {
+				target = latest
+				target.Description = uuid.NewString()
+				got, err = s.Edit(ctx, EditBidInput{BidId: bid.Id, Username: "outsider", Description: target.Description})
+			} else {
+				target = f.bids.versions[bid.Id][rnd.Intn(latest.Version)]
+				got, err = s.Rollback(ctx, RollbackBidInput{BidId: bid.Id, Version: target.Version, Username: "outsider"})
+			}
+			if err != nil {
+				t.Logf("seed %d step %d: %v", seed, step, err)
+				return false
+			}
+			if got.Version != latest.Version+1 || !sameBid(got, target) {
+				t.Logf("seed %d step %d: got %+v after version %d, want fields of %+v", seed, step, got, latest.Version, target)
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// sameBid compares bids except version and timestamps
+func sameBid(a, b e.Bid) bool {
+	return a.Id == b.Id && a.Name == b.Name && a.Description == b.Description && a.AuthorType == b.AuthorType &&
+		a.AuthorId == b.AuthorId && a.Status == b.Status && a.TenderId == b.TenderId && a.OrganizationId == b.OrganizationId
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// caller is whoever request is made on behalf of: employee or organization API key
+type caller struct {
+	employee e.Employee
+	actor    authz.Actor
+}
+
+func (c caller) username() string {
+	return c.actor.Name
+}
+
+func employeeCaller(employee e.Employee) caller {
+	return caller{
+		employee: employee,
+		actor:    authz.EmployeeActor(employee.Id, employee.Username),
+	}
+}
+
+// resolveCaller returns API key from context if request was authenticated with
+// one, otherwise active employee with username
+func resolveCaller(ctx context.Context, eRepo repo.Employee, method, username string) (caller, error) {
+	if apiKey, ok := identity.ServiceFromContext(ctx); ok {
+		return caller{
+			actor: authz.APIKeyActor(apiKey.KeyId, apiKey.OrganizationId, apiKey.Username(), apiKey.Scopes),
+		}, nil
+	}
+
+	user, err := eRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return caller{}, ErrUsername
+		}
+		log.Errorf("%s - employeeRepo.GetByUsername: %v", method, err)
+		return caller{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return caller{}, ErrEmployeeDeactivated
+	}
+
+	return employeeCaller(user), nil
+}
+
+// resolveEmployee is resolveCaller for actions of employee's own account, API
+// keys don't have one
+func resolveEmployee(ctx context.Context, eRepo repo.Employee, method, username string) (e.Employee, error) {
+	c, err := resolveCaller(ctx, eRepo, method, username)
+	if err != nil {
+		return e.Employee{}, err
+	}
+	if c.actor.IsAPIKey() {
+		return e.Employee{}, ErrForbidden
+	}
+
+	return c.employee, nil
+}
+
+// orgPolicy is authz policy on organization as a whole
+type orgPolicy func(ctx context.Context, actor authz.Actor, orgId uuid.UUID) (authz.Decision, error)
+
+// authorizeOrganization resolves caller and returns organization if it exists
+// and policy allows caller in it
+func authorizeOrganization(ctx context.Context, eRepo repo.Employee, oRepo repo.Organization, method string,
+	orgId uuid.UUID, username string, policy orgPolicy) (caller, e.Organization, error) {
+	c, err := resolveCaller(ctx, eRepo, method, username)
+	if err != nil {
+		return caller{}, e.Organization{}, err
+	}
+
+	// Check if organization exists
+	org, err := oRepo.Get(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return caller{}, e.Organization{}, ErrNotFoundOrganization
+		}
+		log.Errorf("%s - organizationRepo.Get: %v", method, err)
+		return caller{}, e.Organization{}, ErrGetOrganization
+	}
+
+	// Check rights
+	if err := checkDecision(policy(ctx, c.actor, orgId)); err != nil {
+		return caller{}, e.Organization{}, err
+	}
+
+	return c, org, nil
+}
+
+// checkDecision turns policy outcome into service error, policies log details themselves
+func checkDecision(d authz.Decision, err error) error {
+	if err != nil {
+		return ErrCheckResponsibility
+	}
+	if !d.Allowed {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorizeOrganization(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(f *fixture) context.Context
+		username func(f *fixture) string
+		orgId    func(f *fixture) uuid.UUID
+		orgErr   error
+		policy   func(az *authz.Authorizer) orgPolicy
+		wantErr  error
+	}{
+		{name: "admin"},
+		{
+			name:     "viewer",
+			username: func(f *fixture) string { return f.viewer.Username },
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "viewer with view policy",
+			username: func(f *fixture) string { return f.viewer.Username },
+			policy:   func(az *authz.Authorizer) orgPolicy { return az.CanViewOrganization },
+		},
+		{
+			name:     "admin of other organization",
+			username: func(f *fixture) string { return f.rival.Username },
+			wantErr:  ErrForbidden,
+		},
+		{
+			name:     "unknown user",
+			username: func(f *fixture) string { return "nobody" },
+			wantErr:  ErrUsername,
+		},
+		{
+			name:     "deactivated",
+			username: func(f *fixture) string { return f.inactive.Username },
+			wantErr:  ErrEmployeeDeactivated,
+		},
+		{
+			name:    "unknown organization",
+			orgId:   func(f *fixture) uuid.UUID { return uuid.New() },
+			wantErr: ErrNotFoundOrganization,
+		},
+		{
+			name:    "organization lookup failure",
+			orgErr:  errRepo,
+			wantErr: ErrGetOrganization,
+		},
+		{
+			name:    "api key can't manage",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersWrite) },
+			wantErr: ErrForbidden,
+		},
+		{
+			name:   "api key with scope",
+			ctx:    func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersWrite) },
+			policy: func(az *authz.Authorizer) orgPolicy { return az.CanCreateTender },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			orgs := &fakeOrganizationRepo{
+				orgs: map[uuid.UUID]e.Organization{f.orgId: {Id: f.orgId, Name: "Org"}},
+				err:  tt.orgErr,
+			}
+			az := authz.New(f.employees)
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+			username := f.admin.Username
+			if tt.username != nil {
+				username = tt.username(f)
+			}
+			orgId := f.orgId
+			if tt.orgId != nil {
+				orgId = tt.orgId(f)
+			}
+			policy := az.CanManageOrganization
+			if tt.policy != nil {
+				policy = tt.policy(az)
+			}
+
+			c, org, err := authorizeOrganization(ctx, f.employees, orgs, "Test", orgId, username, policy)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if org.Id != f.orgId {
+				t.Errorf("organization %s, want %s", org.Id, f.orgId)
+			}
+			if !c.actor.IsAPIKey() && c.employee.Username != username {
+				t.Errorf("caller %s, want %s", c.employee.Username, username)
+			}
+		})
+	}
+}
+
+func TestResolveEmployee(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(f *fixture) context.Context
+		username string
+		wantErr  error
+	}{
+		{name: "active", username: "viewer"},
+		{name: "unknown", username: "nobody", wantErr: ErrUsername},
+		{name: "deactivated", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{
+			name:     "api key",
+			ctx:      func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersRead) },
+			username: "viewer",
+			wantErr:  ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+
+			user, err := resolveEmployee(ctx, f.employees, "Test", tt.username)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && user.Id != f.viewer.Id {
+				t.Errorf("employee %s, want %s", user.Username, f.viewer.Username)
+			}
+		})
+	}
+}
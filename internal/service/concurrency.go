@@ -0,0 +1,58 @@
+package service
+
+import (
+	"app/internal/repo/repoerrors"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxConflictRetries limits how many times write losing race to concurrent
+// write is repeated
+const maxConflictRetries = 3
+
+// errVersionConflict means write lost race to concurrent write, it never
+// leaves service
+var errVersionConflict = errors.New("version conflict")
+
+func (p Precondition) isSet() bool {
+	return p.Version != 0 || !p.UpdatedAt.IsZero()
+}
+
+func (p Precondition) check(version int, updatedAt time.Time) error {
+	if p.Version != 0 && p.Version != version {
+		return ErrVersionMismatch
+	}
+	if !p.UpdatedAt.IsZero() && !p.UpdatedAt.Equal(updatedAt) {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// retryConflicts runs write again while it loses race to concurrent write.
+// Write must read state it changes on each run. Write with precondition is
+// not repeated, state caller based it on is gone
+func retryConflicts(pre Precondition, write func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if !errors.Is(err, errVersionConflict) {
+			return err
+		}
+		if pre.isSet() {
+			return ErrVersionMismatch
+		}
+		if attempt == maxConflictRetries {
+			return ErrConcurrentUpdate
+		}
+	}
+}
+
+// versionError turns repo error of versioned write into service error
+func versionError(method, call string, err, fallback error) error {
+	if errors.Is(err, repoerrors.ErrVersionConflict) {
+		return errVersionConflict
+	}
+	log.Errorf("%s - %s: %v", method, call, err)
+	return fallback
+}
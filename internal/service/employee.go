@@ -0,0 +1,262 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type EmployeeService struct {
+	employeeRepo repo.Employee
+}
+
+func NewEmployeeService(eRepo repo.Employee) *EmployeeService {
+	return &EmployeeService{
+		employeeRepo: eRepo,
+	}
+}
+
+// Only administrators can create employees
+func (s *EmployeeService) Create(ctx context.Context, in CreateEmployeeInput) (e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrUsername
+		}
+		log.Errorf("EmployeeService.Create - employeeRepo.GetByUsername: %v", err)
+		return e.Employee{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	// Check rights
+	if !user.IsAdmin {
+		return e.Employee{}, ErrForbidden
+	}
+
+	// Create employee
+	employee, err := s.employeeRepo.Create(ctx, rt.CreateEmployeeInput{
+		Username:  in.NewUsername,
+		FirstName: in.FirstName,
+		LastName:  in.LastName,
+		Email:     optionalString(in.Email),
+	})
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrEmailTaken) {
+			return e.Employee{}, ErrEmailTaken
+		}
+		if errors.Is(err, repoerrors.ErrAlreadyExists) {
+			return e.Employee{}, ErrEmployeeExists
+		}
+		log.Errorf("EmployeeService.Create - employeeRepo.Create: %v", err)
+		return e.Employee{}, ErrCreateEmployee
+	}
+
+	return employee, nil
+}
+
+func (s *EmployeeService) Get(ctx context.Context, employeeId uuid.UUID, username string) (e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrUsername
+		}
+		log.Errorf("EmployeeService.Get - employeeRepo.GetByUsername: %v", err)
+		return e.Employee{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	// Check if employee exists, deactivated ones are visible to administrators only
+	employee, err := s.employeeRepo.GetById(ctx, employeeId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrNotFoundEmployee
+		}
+		log.Errorf("EmployeeService.Get - employeeRepo.GetById: %v", err)
+		return e.Employee{}, ErrGetEmployeeById
+	}
+	if !employee.IsActive && !user.IsAdmin {
+		return e.Employee{}, ErrNotFoundEmployee
+	}
+
+	return employee, nil
+}
+
+// Employees can edit themselves, administrators can edit anyone
+func (s *EmployeeService) Edit(ctx context.Context, in EditEmployeeInput) (e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrUsername
+		}
+		log.Errorf("EmployeeService.Edit - employeeRepo.GetByUsername: %v", err)
+		return e.Employee{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	// Check rights
+	if user.Id != in.EmployeeId && !user.IsAdmin {
+		return e.Employee{}, ErrForbidden
+	}
+
+	// Check if employee exists
+	employee, err := s.employeeRepo.GetById(ctx, in.EmployeeId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrNotFoundEmployee
+		}
+		log.Errorf("EmployeeService.Edit - employeeRepo.GetById: %v", err)
+		return e.Employee{}, ErrGetEmployeeById
+	}
+
+	// Update changed fields only
+	input := rt.UpdateEmployeeInput{
+		Id:        employee.Id,
+		FirstName: in.FirstName,
+		LastName:  in.LastName,
+		Email:     optionalString(in.Email),
+	}
+	if in.FirstName == "" {
+		input.FirstName = employee.FirstName
+	}
+	if in.LastName == "" {
+		input.LastName = employee.LastName
+	}
+	if in.Email == "" {
+		input.Email = employee.Email
+	}
+	updated, err := s.employeeRepo.Update(ctx, input)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrNotFoundEmployee
+		}
+		if errors.Is(err, repoerrors.ErrEmailTaken) {
+			return e.Employee{}, ErrEmailTaken
+		}
+		log.Errorf("EmployeeService.Edit - employeeRepo.Update: %v", err)
+		return e.Employee{}, ErrUpdateEmployee
+	}
+
+	return updated, nil
+}
+
+// Only administrators can (de)activate employees
+func (s *EmployeeService) SetActive(ctx context.Context, employeeId uuid.UUID, active bool, username string) (e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrUsername
+		}
+		log.Errorf("EmployeeService.SetActive - employeeRepo.GetByUsername: %v", err)
+		return e.Employee{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	// Check rights
+	if !user.IsAdmin {
+		return e.Employee{}, ErrForbidden
+	}
+	if user.Id == employeeId && !active {
+		return e.Employee{}, ErrDeactivateSelf
+	}
+
+	employee, err := s.employeeRepo.SetActive(ctx, employeeId, active)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrNotFoundEmployee
+		}
+		log.Errorf("EmployeeService.SetActive - employeeRepo.SetActive: %v", err)
+		return e.Employee{}, ErrUpdateEmployee
+	}
+
+	return employee, nil
+}
+
+// Only administrators can grant and revoke admin rights. Own rights can't be
+// revoked, so there is always administrator left to grant them
+func (s *EmployeeService) SetAdmin(ctx context.Context, employeeId uuid.UUID, admin bool, username string) (e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrUsername
+		}
+		log.Errorf("EmployeeService.SetAdmin - employeeRepo.GetByUsername: %v", err)
+		return e.Employee{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Employee{}, ErrEmployeeDeactivated
+	}
+
+	// Check rights
+	if !user.IsAdmin {
+		return e.Employee{}, ErrForbidden
+	}
+	if user.Id == employeeId && !admin {
+		return e.Employee{}, ErrRevokeOwnAdmin
+	}
+
+	employee, err := s.employeeRepo.SetAdmin(ctx, employeeId, admin)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Employee{}, ErrNotFoundEmployee
+		}
+		log.Errorf("EmployeeService.SetAdmin - employeeRepo.SetAdmin: %v", err)
+		return e.Employee{}, ErrUpdateEmployee
+	}
+
+	return employee, nil
+}
+
+// Deactivated employees are listed for administrators only
+func (s *EmployeeService) Search(ctx context.Context, in SearchEmployeesInput) ([]e.Employee, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return nil, ErrUsername
+		}
+		log.Errorf("EmployeeService.Search - employeeRepo.GetByUsername: %v", err)
+		return nil, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return nil, ErrEmployeeDeactivated
+	}
+
+	employees, err := s.employeeRepo.Search(ctx, rt.SearchEmployeesInput{
+		Limit:           in.Limit,
+		Offset:          in.Offset,
+		Query:           in.Query,
+		IncludeInactive: in.IncludeInactive && user.IsAdmin,
+	})
+	if err != nil {
+		log.Errorf("EmployeeService.Search - employeeRepo.Search: %v", err)
+		return nil, ErrSearchEmployees
+	}
+
+	return employees, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
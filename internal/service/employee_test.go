@@ -0,0 +1,78 @@
+package service
+
+import (
+	"app/internal/repo"
+	"app/internal/repo/memdb"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEmployeeServiceSetAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		target   string
+		admin    bool
+		missing  bool
+		wantErr  error
+	}{
+		{name: "grant", username: "root", target: "alice", admin: true},
+		{name: "revoke", username: "root", target: "bob", admin: false},
+		{name: "not admin", username: "alice", target: "alice", admin: true, wantErr: ErrForbidden},
+		{name: "deactivated admin", username: "retired", target: "alice", admin: true, wantErr: ErrEmployeeDeactivated},
+		{name: "unknown username", username: "ghost", target: "alice", admin: true, wantErr: ErrUsername},
+		{name: "revoke own", username: "root", target: "root", admin: false, wantErr: ErrRevokeOwnAdmin},
+		{name: "employee not found", username: "root", missing: true, admin: true, wantErr: ErrNotFoundEmployee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employees := &fakeEmployeeRepo{}
+			for _, username := range []string{"root", "retired"} {
+				emp := employees.add(username, username == "root")
+				employees.SetAdmin(context.Background(), emp.Id, true)
+			}
+			employees.add("alice", true)
+			bob := employees.add("bob", true)
+			employees.SetAdmin(context.Background(), bob.Id, true)
+
+			targetId := uuid.New()
+			if !tt.missing {
+				target, _ := employees.GetByUsername(context.Background(), tt.target)
+				targetId = target.Id
+			}
+
+			got, err := NewEmployeeService(employees).SetAdmin(context.Background(), targetId, tt.admin, tt.username)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && (got.Id != targetId || got.IsAdmin != tt.admin) {
+				t.Errorf("SetAdmin() = %+v, want %v with admin %t", got, targetId, tt.admin)
+			}
+		})
+	}
+}
+
+// Seeded administrator can manage employees and make more administrators
+func TestEmployeeServiceSeededAdmin(t *testing.T) {
+	ctx := context.Background()
+	store := memdb.NewStore()
+	store.Seed()
+	s := NewEmployeeService(repo.NewMemoryRepo(store).Employee)
+
+	carol, err := s.Create(ctx, CreateEmployeeInput{Username: "user1", NewUsername: "carol"})
+	if err != nil {
+		t.Fatalf("Create() by seeded admin error = %v", err)
+	}
+	if _, err := s.Create(ctx, CreateEmployeeInput{Username: "carol", NewUsername: "dave"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Create() by employee error = %v, want %v", err, ErrForbidden)
+	}
+
+	if _, err := s.SetAdmin(ctx, carol.Id, true, "user1"); err != nil {
+		t.Fatalf("SetAdmin() error = %v", err)
+	}
+	if _, err := s.Create(ctx, CreateEmployeeInput{Username: "carol", NewUsername: "dave"}); err != nil {
+		t.Errorf("Create() by granted admin error = %v", err)
+	}
+}
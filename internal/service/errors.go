@@ -17,4 +17,85 @@ var (
 	ErrCreateBid              = errors.New("cannot create tender (or newer version)")
 	ErrNotFoundBid            = errors.New("bid not found (or exact bid version)")
 	ErrGetBid                 = errors.New("cannot get bid (or exact bid version)")
+	ErrBidOrganization        = errors.New("organizationId is required for organization bids and not allowed for user bids")
+	ErrCheckVisibility        = errors.New("cannot check tender visibility")
+	ErrInvitee                = errors.New("invited organization or employee doesn't exist")
+	ErrInvitationExists       = errors.New("organization or employee is already invited")
+	ErrCreateInvitation       = errors.New("cannot create invitation")
+	ErrGetInvitations         = errors.New("cannot get invitations")
+	ErrNotFoundInvitation     = errors.New("invitation not found")
+	ErrDeleteInvitation       = errors.New("cannot delete invitation")
+	ErrGetInvitedTenders      = errors.New("cannot get tenders user is invited to")
+	ErrNotFoundOrganization   = errors.New("organization not found")
+	ErrCreateOrganization     = errors.New("cannot create organization")
+	ErrGetOrganization        = errors.New("cannot get organization")
+	ErrGetOrganizations       = errors.New("cannot get organizations")
+	ErrUpdateOrganization     = errors.New("cannot update organization")
+	ErrDeleteOrganization     = errors.New("cannot delete organization")
+	ErrGetResponsibles        = errors.New("cannot get organization responsibles")
+	ErrResponsibleExists      = errors.New("employee is already responsible for organization")
+	ErrNotFoundResponsible    = errors.New("employee is not responsible for organization")
+	ErrLastResponsible        = errors.New("cannot unassign the last responsible of organization")
+	ErrAssignResponsible      = errors.New("cannot assign responsible")
+	ErrUnassignResponsible    = errors.New("cannot unassign responsible")
+	ErrNotFoundEmployee       = errors.New("employee not found")
+	ErrEmployeeDeactivated    = errors.New("employee is deactivated")
+	ErrEmployeeExists         = errors.New("employee with this username already exists")
+	ErrCreateEmployee         = errors.New("cannot create employee")
+	ErrUpdateEmployee         = errors.New("cannot update employee")
+	ErrSearchEmployees        = errors.New("cannot search employees")
+	ErrDeactivateSelf         = errors.New("employee cannot deactivate themselves")
+	ErrRevokeOwnAdmin         = errors.New("administrator cannot revoke their own admin rights")
+	ErrSignToken              = errors.New("cannot sign access token")
+	ErrInvalidToken           = errors.New("access token is invalid or expired")
+	ErrInvalidCredentials     = errors.New("username or password is incorrect")
+	ErrAccountLocked          = errors.New("account is temporarily locked after repeated failed logins")
+	ErrWeakPassword           = errors.New("password doesn't satisfy password policy")
+	ErrPasswordAlreadySet     = errors.New("password is already set, use change or reset instead")
+	ErrInvalidBootstrapToken  = errors.New("bootstrap token is invalid or first passwords are disabled")
+	ErrInvalidResetToken      = errors.New("reset token is invalid, expired or already used")
+	ErrGetCredential          = errors.New("cannot get employee credential")
+	ErrHashPassword           = errors.New("cannot hash password")
+	ErrSetPassword            = errors.New("cannot set password")
+	ErrCreateResetToken       = errors.New("cannot create reset token")
+	ErrResetPassword          = errors.New("cannot reset password")
+	ErrGetLoginAttempts       = errors.New("cannot get login attempts")
+	ErrCreateAPIKey           = errors.New("cannot create API key")
+	ErrGetAPIKeys             = errors.New("cannot get API keys")
+	ErrGetAPIKey              = errors.New("cannot get API key")
+	ErrNotFoundAPIKey         = errors.New("API key not found or already revoked")
+	ErrRevokeAPIKey           = errors.New("cannot revoke API key")
+	ErrInvalidAPIKey          = errors.New("API key is invalid, expired or revoked")
+	ErrGetRoles               = errors.New("cannot get organization roles")
+	ErrSetRoles               = errors.New("cannot set organization roles")
+	ErrLastAdmin              = errors.New("organization must keep at least one admin")
+	ErrTransaction            = errors.New("cannot complete transaction")
+	ErrWriteAudit             = errors.New("cannot write audit log")
+	ErrGetAuditLog            = errors.New("cannot get audit log")
+	ErrWriteEvent             = errors.New("cannot write domain event")
+	ErrCreateWebhook          = errors.New("cannot create webhook")
+	ErrGetWebhooks            = errors.New("cannot get webhooks")
+	ErrNotFoundWebhook        = errors.New("webhook not found")
+	ErrDeleteWebhook          = errors.New("cannot delete webhook")
+	ErrGetWebhookDeliveries   = errors.New("cannot get webhook deliveries")
+	ErrNotFoundDelivery       = errors.New("webhook delivery not found")
+	ErrRedeliverWebhook       = errors.New("cannot redeliver webhook")
+	ErrStreamClosed           = errors.New("activity stream is closed")
+	ErrSubscribeActivity      = errors.New("cannot subscribe to activity stream")
+	ErrEmailTaken             = errors.New("email is already used by another employee")
+	ErrTenderDeadline         = errors.New("tender deadline must be in the future")
+	ErrEmailRequired          = errors.New("employee has no email address")
+	ErrGetEmailPreferences    = errors.New("cannot get email preferences")
+	ErrSetEmailPreferences    = errors.New("cannot set email preferences")
+	ErrRemindDeadlines        = errors.New("cannot send deadline reminders")
+	ErrGetNotifications       = errors.New("cannot get notifications")
+	ErrNotFoundNotification   = errors.New("notification not found")
+	ErrMarkNotificationRead   = errors.New("cannot mark notification read")
+	ErrPruneNotifications     = errors.New("cannot prune notifications")
+	ErrVersionMismatch        = errors.New("version was modified, get it again and retry")
+	ErrConcurrentUpdate       = errors.New("version is being modified concurrently, retry later")
+	ErrIdempotencyMismatch    = errors.New("idempotency key was used with different request")
+	ErrIdempotencyInProgress  = errors.New("request with this idempotency key is in progress")
+	ErrIdempotencyKey         = errors.New("cannot process idempotency key")
+	ErrPruneIdempotencyKeys   = errors.New("cannot prune idempotency keys")
 )
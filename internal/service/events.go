@@ -0,0 +1,122 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Domain event types written to outbox
+const (
+	EventTenderPublished      = "tender.published"
+	EventTenderStatusChanged  = "tender.status_changed"
+	EventTenderVersionCreated = "tender.version_created"
+	// EventTenderDeadlineApproaching is published once per deadline by reminder job
+	EventTenderDeadlineApproaching = "tender.deadline_approaching"
+	EventBidCreated                = "bid.created"
+	EventBidDecisionSubmitted      = "bid.decision_submitted"
+)
+
+type tenderEventPayload struct {
+	TenderId       uuid.UUID  `json:"tenderId"`
+	Name           string     `json:"name"`
+	ServiceType    string     `json:"serviceType"`
+	Status         string     `json:"status"`
+	Visibility     string     `json:"visibility"`
+	OrganizationId uuid.UUID  `json:"organizationId"`
+	Version        int        `json:"version"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+}
+
+// tenderChangePayload keeps previous state, so consumers notice tender leaving
+// or entering their view
+type tenderChangePayload struct {
+	tenderEventPayload
+	PreviousStatus     string `json:"previousStatus"`
+	PreviousVisibility string `json:"previousVisibility"`
+}
+
+type bidEventPayload struct {
+	BidId          uuid.UUID  `json:"bidId"`
+	TenderId       uuid.UUID  `json:"tenderId"`
+	TenderName     string     `json:"tenderName"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	AuthorType     string     `json:"authorType"`
+	AuthorId       uuid.UUID  `json:"authorId"`
+	OrganizationId *uuid.UUID `json:"organizationId,omitempty"`
+	Version        int        `json:"version"`
+}
+
+type decisionEventPayload struct {
+	bidEventPayload
+	Decision     string `json:"decision"`
+	TenderStatus string `json:"tenderStatus"`
+}
+
+func newTenderEventPayload(t e.Tender) tenderEventPayload {
+	return tenderEventPayload{
+		TenderId:       t.Id,
+		Name:           t.Name,
+		ServiceType:    t.Type,
+		Status:         t.Status,
+		Visibility:     t.Visibility,
+		OrganizationId: t.OrganizationId,
+		Version:        t.Version,
+		Deadline:       t.Deadline,
+	}
+}
+
+func newBidEventPayload(b e.Bid, t e.Tender) bidEventPayload {
+	p := bidEventPayload{
+		BidId:      b.Id,
+		TenderId:   b.TenderId,
+		TenderName: t.Name,
+		Name:       b.Name,
+		Status:     b.Status,
+		AuthorType: b.AuthorType,
+		AuthorId:   b.AuthorId,
+		Version:    b.Version,
+	}
+	if b.OrganizationId.Valid {
+		p.OrganizationId = &b.OrganizationId.UUID
+	}
+	return p
+}
+
+// publishTenderChange writes change event of tender, call it in transaction of the change
+func publishTenderChange(ctx context.Context, oRepo repo.Outbox, method, eventType string, before, after e.Tender) error {
+	return publishEvent(ctx, oRepo, method, rt.CreateOutboxEventInput{
+		Type:           eventType,
+		AggregateType:  AuditEntityTender,
+		AggregateId:    after.Id,
+		OrganizationId: uuid.NullUUID{UUID: after.OrganizationId, Valid: true},
+	}, tenderChangePayload{
+		tenderEventPayload: newTenderEventPayload(after),
+		PreviousStatus:     before.Status,
+		PreviousVisibility: before.Visibility,
+	})
+}
+
+// publishEvent writes event to outbox, call it in transaction of the change
+func publishEvent(ctx context.Context, oRepo repo.Outbox, method string, in rt.CreateOutboxEventInput, payload any) error {
+	var err error
+	in.Payload, err = json.Marshal(payload)
+	if err != nil {
+		log.Errorf("%s - json.Marshal: %v", method, err)
+		return ErrWriteEvent
+	}
+
+	if err := oRepo.Add(ctx, in); err != nil {
+		log.Errorf("%s - outboxRepo.Add: %v", method, err)
+		return ErrWriteEvent
+	}
+
+	return nil
+}
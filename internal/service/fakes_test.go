@@ -0,0 +1,617 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	// Services log every repo failure, tests make them on purpose
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// errRepo is repo failure other than its sentinel errors, services must hide it
+var errRepo = errors.New("connection lost")
+
+// fakeEmployeeRepo knows employees and roles of organization responsibles.
+// err fails every call, rolesErr fails role lookups only
+type fakeEmployeeRepo struct {
+	repo.Employee
+	employees []e.Employee
+	roles     map[[2]uuid.UUID][]string
+	err       error
+	rolesErr  error
+}
+
+func (r *fakeEmployeeRepo) add(username string, active bool) e.Employee {
+	emp := e.Employee{Id: uuid.New(), Username: username, IsActive: active}
+	r.employees = append(r.employees, emp)
+	return emp
+}
+
+func (r *fakeEmployeeRepo) setRoles(orgId, userId uuid.UUID, roles ...string) {
+	if r.roles == nil {
+		r.roles = map[[2]uuid.UUID][]string{}
+	}
+	r.roles[[2]uuid.UUID{orgId, userId}] = roles
+}
+
+func (r *fakeEmployeeRepo) GetByUsername(ctx context.Context, username string) (e.Employee, error) {
+	if r.err != nil {
+		return e.Employee{}, r.err
+	}
+	for _, emp := range r.employees {
+		if emp.Username == username {
+			return emp, nil
+		}
+	}
+	return e.Employee{}, repoerrors.ErrNotFound
+}
+
+func (r *fakeEmployeeRepo) GetById(ctx context.Context, id uuid.UUID) (e.Employee, error) {
+	if r.err != nil {
+		return e.Employee{}, r.err
+	}
+	for _, emp := range r.employees {
+		if emp.Id == id {
+			return emp, nil
+		}
+	}
+	return e.Employee{}, repoerrors.ErrNotFound
+}
+
+func (r *fakeEmployeeRepo) SetAdmin(ctx context.Context, id uuid.UUID, admin bool) (e.Employee, error) {
+	if r.err != nil {
+		return e.Employee{}, r.err
+	}
+	for i := range r.employees {
+		if r.employees[i].Id == id {
+			r.employees[i].IsAdmin = admin
+			return r.employees[i], nil
+		}
+	}
+	return e.Employee{}, repoerrors.ErrNotFound
+}
+
+func (r *fakeEmployeeRepo) IsResponsible(ctx context.Context, orgId, userId uuid.UUID) (bool, error) {
+	roles, err := r.GetRoles(ctx, orgId, userId)
+	return len(roles) > 0, err
+}
+
+func (r *fakeEmployeeRepo) GetRoles(ctx context.Context, orgId, userId uuid.UUID) ([]string, error) {
+	if r.rolesErr != nil {
+		return nil, r.rolesErr
+	}
+	return r.roles[[2]uuid.UUID{orgId, userId}], nil
+}
+
+// fakeTenderRepo keeps versions of tenders in order. err fails every call,
+// lockErr fails Lock and createErr fails CreateSpecified, GetForUpdate
+// conflicts as many times as conflicts says
+type fakeTenderRepo struct {
+	repo.Tender
+	versions  map[uuid.UUID][]e.Tender
+	err       error
+	lockErr   error
+	createErr error
+	conflicts int
+	// Inputs of list calls
+	published rt.GetPublishedTendersInput
+	invited   rt.GetInvitedTendersInput
+}
+
+// add stores tender as its next version
+func (r *fakeTenderRepo) add(t e.Tender) e.Tender {
+	if r.versions == nil {
+		r.versions = map[uuid.UUID][]e.Tender{}
+	}
+	t.Version = len(r.versions[t.Id]) + 1
+	t.UpdatedAt = time.Now()
+	r.versions[t.Id] = append(r.versions[t.Id], t)
+	return t
+}
+
+func (r *fakeTenderRepo) latest(id uuid.UUID) (e.Tender, bool) {
+	versions := r.versions[id]
+	if len(versions) == 0 {
+		return e.Tender{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+func (r *fakeTenderRepo) update(id uuid.UUID, change func(t *e.Tender)) (e.Tender, error) {
+	if r.err != nil {
+		return e.Tender{}, r.err
+	}
+	t, ok := r.latest(id)
+	if !ok {
+		return e.Tender{}, repoerrors.ErrNotFound
+	}
+	change(&t)
+	t.UpdatedAt = time.Now()
+	r.versions[id][t.Version-1] = t
+	return t, nil
+}
+
+func (r *fakeTenderRepo) CreateTender(ctx context.Context, in rt.CreateTenderInput) (e.Tender, error) {
+	if r.err != nil {
+		return e.Tender{}, r.err
+	}
+	return r.add(e.Tender{
+		Id:              uuid.New(),
+		Name:            in.Name,
+		Description:     in.Description,
+		Type:            in.ServiceType,
+		Status:          "Created",
+		Visibility:      in.Visibility,
+		OrganizationId:  in.OrganizationId,
+		CreatorUsername: in.CreatorUsername,
+		Deadline:        in.Deadline,
+	}), nil
+}
+
+func (r *fakeTenderRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Tender, error) {
+	if r.err != nil {
+		return e.Tender{}, r.err
+	}
+	if version == rt.VersionLatest {
+		if t, ok := r.latest(id); ok {
+			return t, nil
+		}
+		return e.Tender{}, repoerrors.ErrNotFound
+	}
+	if versions := r.versions[id]; version > 0 && version <= len(versions) {
+		return versions[version-1], nil
+	}
+	return e.Tender{}, repoerrors.ErrNotFound
+}
+
+func (r *fakeTenderRepo) GetTendersByUsername(ctx context.Context, in rt.GetByUsernameInput) ([]e.Tender, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var tenders []e.Tender
+	for id := range r.versions {
+		if t, _ := r.latest(id); t.CreatorUsername == in.Username {
+			tenders = append(tenders, t)
+		}
+	}
+	return tenders, nil
+}
+
+func (r *fakeTenderRepo) GetPublishedTenders(ctx context.Context, in rt.GetPublishedTendersInput) ([]e.Tender, error) {
+	r.published = in
+	if r.err != nil {
+		return nil, r.err
+	}
+	var tenders []e.Tender
+	for id := range r.versions {
+		if t, _ := r.latest(id); t.Status == "Published" {
+			tenders = append(tenders, t)
+		}
+	}
+	return tenders, nil
+}
+
+func (r *fakeTenderRepo) GetInvitedTenders(ctx context.Context, in rt.GetInvitedTendersInput) ([]e.Tender, error) {
+	r.invited = in
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []e.Tender{}, nil
+}
+
+func (r *fakeTenderRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string) (e.Tender, error) {
+	return r.update(id, func(t *e.Tender) { t.Status = status })
+}
+
+func (r *fakeTenderRepo) ChangeVisibility(ctx context.Context, id uuid.UUID, visibility string) (e.Tender, error) {
+	return r.update(id, func(t *e.Tender) { t.Visibility = visibility })
+}
+
+func (r *fakeTenderRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedInput) (e.Tender, error) {
+	if r.err != nil {
+		return e.Tender{}, r.err
+	}
+	if r.createErr != nil {
+		return e.Tender{}, r.createErr
+	}
+	if in.Version != len(r.versions[in.Id])+1 {
+		return e.Tender{}, repoerrors.ErrVersionConflict
+	}
+	return r.add(e.Tender{
+		Id:              in.Id,
+		Name:            in.Name,
+		Description:     in.Description,
+		Type:            in.ServiceType,
+		Status:          in.Status,
+		Visibility:      in.Visibility,
+		OrganizationId:  in.OrganizationId,
+		CreatorUsername: in.CreatorUsername,
+		Deadline:        in.Deadline,
+	}), nil
+}
+
+func (r *fakeTenderRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.lockErr != nil {
+		return r.lockErr
+	}
+	t, ok := r.latest(id)
+	if !ok || t.Version != version || !t.UpdatedAt.Equal(updatedAt) {
+		return repoerrors.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *fakeTenderRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Tender, error) {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return e.Tender{}, repoerrors.ErrVersionConflict
+	}
+	return r.Get(ctx, id, rt.VersionLatest)
+}
+
+// fakeBidRepo keeps versions of bids in order, errors work as in fakeTenderRepo
+type fakeBidRepo struct {
+	repo.Bid
+	versions  map[uuid.UUID][]e.Bid
+	err       error
+	lockErr   error
+	createErr error
+	conflicts int
+}
+
+// add stores bid as its next version
+func (r *fakeBidRepo) add(b e.Bid) e.Bid {
+	if r.versions == nil {
+		r.versions = map[uuid.UUID][]e.Bid{}
+	}
+	b.Version = len(r.versions[b.Id]) + 1
+	b.UpdatedAt = time.Now()
+	r.versions[b.Id] = append(r.versions[b.Id], b)
+	return b
+}
+
+func (r *fakeBidRepo) latest(id uuid.UUID) (e.Bid, bool) {
+	versions := r.versions[id]
+	if len(versions) == 0 {
+		return e.Bid{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+func (r *fakeBidRepo) Get(ctx context.Context, id uuid.UUID, version int) (e.Bid, error) {
+	if r.err != nil {
+		return e.Bid{}, r.err
+	}
+	if version == rt.VersionLatest {
+		if b, ok := r.latest(id); ok {
+			return b, nil
+		}
+		return e.Bid{}, repoerrors.ErrNotFound
+	}
+	if versions := r.versions[id]; version > 0 && version <= len(versions) {
+		return versions[version-1], nil
+	}
+	return e.Bid{}, repoerrors.ErrNotFound
+}
+
+func (r *fakeBidRepo) Create(ctx context.Context, in rt.CreateBidInput) (e.Bid, error) {
+	if r.err != nil {
+		return e.Bid{}, r.err
+	}
+	return r.add(e.Bid{
+		Id:             uuid.New(),
+		Name:           in.Name,
+		Description:    in.Description,
+		AuthorType:     in.AuthorType,
+		AuthorId:       in.AuthorId,
+		Status:         "Created",
+		TenderId:       in.TenderId,
+		OrganizationId: in.OrganizationId,
+	}), nil
+}
+
+func (r *fakeBidRepo) CreateSpecified(ctx context.Context, in rt.CreateSpecifiedBidInput) (e.Bid, error) {
+	if r.err != nil {
+		return e.Bid{}, r.err
+	}
+	if r.createErr != nil {
+		return e.Bid{}, r.createErr
+	}
+	if in.Version != len(r.versions[in.Id])+1 {
+		return e.Bid{}, repoerrors.ErrVersionConflict
+	}
+	return r.add(e.Bid{
+		Id:             in.Id,
+		Name:           in.Name,
+		Description:    in.Description,
+		AuthorType:     in.AuthorType,
+		AuthorId:       in.AuthorId,
+		Status:         in.Status,
+		TenderId:       in.TenderId,
+		OrganizationId: in.OrganizationId,
+	}), nil
+}
+
+func (r *fakeBidRepo) ChangeStatus(ctx context.Context, id uuid.UUID, status string) (e.Bid, error) {
+	if r.err != nil {
+		return e.Bid{}, r.err
+	}
+	b, ok := r.latest(id)
+	if !ok {
+		return e.Bid{}, repoerrors.ErrNotFound
+	}
+	b.Status = status
+	b.UpdatedAt = time.Now()
+	r.versions[id][b.Version-1] = b
+	return b, nil
+}
+
+func (r *fakeBidRepo) Lock(ctx context.Context, id uuid.UUID, version int, updatedAt time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.lockErr != nil {
+		return r.lockErr
+	}
+	b, ok := r.latest(id)
+	if !ok || b.Version != version || !b.UpdatedAt.Equal(updatedAt) {
+		return repoerrors.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *fakeBidRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (e.Bid, error) {
+	if r.conflicts > 0 {
+		r.conflicts--
+		return e.Bid{}, repoerrors.ErrVersionConflict
+	}
+	return r.Get(ctx, id, rt.VersionLatest)
+}
+
+// fakeInvitationRepo stores invitations, err fails every call
+type fakeInvitationRepo struct {
+	repo.Invitation
+	invitations []e.TenderInvitation
+	err         error
+}
+
+func (r *fakeInvitationRepo) Create(ctx context.Context, in rt.CreateInvitationInput) (e.TenderInvitation, error) {
+	if r.err != nil {
+		return e.TenderInvitation{}, r.err
+	}
+	inv := e.TenderInvitation{
+		Id:             uuid.New(),
+		TenderId:       in.TenderId,
+		OrganizationId: in.OrganizationId,
+		UserId:         in.UserId,
+	}
+	r.invitations = append(r.invitations, inv)
+	return inv, nil
+}
+
+func (r *fakeInvitationRepo) Delete(ctx context.Context, tenderId, invitationId uuid.UUID) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, inv := range r.invitations {
+		if inv.Id == invitationId && inv.TenderId == tenderId {
+			r.invitations = append(r.invitations[:i], r.invitations[i+1:]...)
+			return nil
+		}
+	}
+	return repoerrors.ErrNotFound
+}
+
+func (r *fakeInvitationRepo) GetByTender(ctx context.Context, tenderId uuid.UUID) ([]e.TenderInvitation, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var invitations []e.TenderInvitation
+	for _, inv := range r.invitations {
+		if inv.TenderId == tenderId {
+			invitations = append(invitations, inv)
+		}
+	}
+	return invitations, nil
+}
+
+// IsInvited counts personal invitations only
+func (r *fakeInvitationRepo) IsInvited(ctx context.Context, tenderId, userId uuid.UUID) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	for _, inv := range r.invitations {
+		if inv.TenderId == tenderId && inv.UserId.Valid && inv.UserId.UUID == userId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeOrganizationRepo knows organizations by id, err fails every call
+type fakeOrganizationRepo struct {
+	repo.Organization
+	orgs map[uuid.UUID]e.Organization
+	err  error
+}
+
+func (r *fakeOrganizationRepo) Get(ctx context.Context, id uuid.UUID) (e.Organization, error) {
+	if r.err != nil {
+		return e.Organization{}, r.err
+	}
+	org, ok := r.orgs[id]
+	if !ok {
+		return e.Organization{}, repoerrors.ErrNotFound
+	}
+	return org, nil
+}
+
+type fakeAuditRepo struct {
+	repo.Audit
+	entries []rt.CreateAuditEntryInput
+}
+
+func (r *fakeAuditRepo) Add(ctx context.Context, in rt.CreateAuditEntryInput) error {
+	r.entries = append(r.entries, in)
+	return nil
+}
+
+type fakeOutboxRepo struct {
+	repo.Outbox
+	events []rt.CreateOutboxEventInput
+}
+
+func (r *fakeOutboxRepo) Add(ctx context.Context, in rt.CreateOutboxEventInput) error {
+	r.events = append(r.events, in)
+	return nil
+}
+
+func (r *fakeOutboxRepo) types() []string {
+	types := make([]string, 0, len(r.events))
+	for _, ev := range r.events {
+		types = append(types, ev.Type)
+	}
+	return types
+}
+
+// fakeIdempotencyRepo records leases keys are extended for, Extend is called
+// from background goroutine
+type fakeIdempotencyRepo struct {
+	repo.Idempotency
+	mu       sync.Mutex
+	extended []time.Duration
+	err      error
+}
+
+func (r *fakeIdempotencyRepo) Extend(ctx context.Context, caller, key string, lease time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extended = append(r.extended, lease)
+	return r.err
+}
+
+func (r *fakeIdempotencyRepo) extends() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.extended)
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTransactor) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fixture is organization with published public tender and employees in every
+// position towards it
+type fixture struct {
+	employees   *fakeEmployeeRepo
+	tenders     *fakeTenderRepo
+	bids        *fakeBidRepo
+	invitations *fakeInvitationRepo
+	audit       *fakeAuditRepo
+	outbox      *fakeOutboxRepo
+
+	orgId uuid.UUID
+	// Admin of organization
+	admin e.Employee
+	// Viewer of organization
+	viewer e.Employee
+	// Employee without organization, bids on their own
+	outsider e.Employee
+	// Admin of other organization, bids on its behalf
+	rival    e.Employee
+	rivalOrg uuid.UUID
+	inactive e.Employee
+	tender   e.Tender
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		employees:   &fakeEmployeeRepo{},
+		tenders:     &fakeTenderRepo{},
+		bids:        &fakeBidRepo{},
+		invitations: &fakeInvitationRepo{},
+		audit:       &fakeAuditRepo{},
+		outbox:      &fakeOutboxRepo{},
+		orgId:       uuid.New(),
+		rivalOrg:    uuid.New(),
+	}
+
+	f.admin = f.employees.add("admin", true)
+	f.viewer = f.employees.add("viewer", true)
+	f.outsider = f.employees.add("outsider", true)
+	f.rival = f.employees.add("rival", true)
+	f.inactive = f.employees.add("inactive", false)
+	f.employees.setRoles(f.orgId, f.admin.Id, authz.RoleAdmin)
+	f.employees.setRoles(f.orgId, f.viewer.Id, authz.RoleViewer)
+	f.employees.setRoles(f.orgId, f.inactive.Id, authz.RoleAdmin)
+	f.employees.setRoles(f.rivalOrg, f.rival.Id, authz.RoleAdmin)
+
+	f.tender = f.newTender("Published", VisibilityPublic)
+	return f
+}
+
+func (f *fixture) newTender(status, visibility string) e.Tender {
+	return f.tenders.add(e.Tender{
+		Id:              uuid.New(),
+		Name:            "Tender",
+		Description:     "Description",
+		Type:            "Construction",
+		Status:          status,
+		Visibility:      visibility,
+		OrganizationId:  f.orgId,
+		CreatorUsername: f.admin.Username,
+	})
+}
+
+// newBid adds user bid of outsider on fixture tender
+func (f *fixture) newBid(status string) e.Bid {
+	return f.bids.add(e.Bid{
+		Id:          uuid.New(),
+		Name:        "Bid",
+		Description: "Description",
+		AuthorType:  "User",
+		AuthorId:    f.outsider.Id,
+		Status:      status,
+		TenderId:    f.tender.Id,
+	})
+}
+
+func (f *fixture) tenderService() *TenderService {
+	return NewTenderService(f.tenders, f.employees, f.invitations, f.audit, f.outbox, fakeTransactor{}, authz.New(f.employees))
+}
+
+func (f *fixture) bidService() *BidService {
+	return NewBidService(f.tenders, f.employees, f.bids, f.invitations, f.audit, f.outbox, fakeTransactor{}, authz.New(f.employees))
+}
+
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type IdempotencyService struct {
+	idempotencyRepo repo.Idempotency
+	cfg             IdempotencyConfig
+}
+
+func NewIdempotencyService(iRepo repo.Idempotency, cfg IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: iRepo,
+		cfg:             cfg,
+	}
+}
+
+// BeginIdempotentRequest returns stored response if request was made before,
+// otherwise it claims key and caller must complete or release it. Request
+// repeated while first one is in progress waits for its response up to Wait,
+// then ErrIdempotencyInProgress is returned
+func (s *IdempotencyService) BeginIdempotentRequest(ctx context.Context, in BeginIdempotentRequestInput) (IdempotentResponse, bool, error) {
+	deadline := time.Now().Add(s.cfg.Wait)
+	for {
+		// Claim key, fails if it is taken
+		claimed, err := s.idempotencyRepo.Claim(ctx, rt.ClaimIdempotencyKeyInput{
+			Caller:      in.Caller,
+			Key:         in.Key,
+			RequestHash: in.RequestHash,
+			Lease:       s.cfg.Lease,
+			TTL:         s.cfg.TTL,
+		})
+		if err != nil {
+			log.Errorf("IdempotencyService.BeginIdempotentRequest - idempotencyRepo.Claim: %v", err)
+			return IdempotentResponse{}, false, ErrIdempotencyKey
+		}
+		if claimed {
+			return IdempotentResponse{}, false, nil
+		}
+
+		// Get request holding key, it may be pruned meanwhile
+		k, err := s.idempotencyRepo.Get(ctx, in.Caller, in.Key)
+		if err != nil {
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				continue
+			}
+			log.Errorf("IdempotencyService.BeginIdempotentRequest - idempotencyRepo.Get: %v", err)
+			return IdempotentResponse{}, false, ErrIdempotencyKey
+		}
+		if !bytes.Equal(k.RequestHash, in.RequestHash) {
+			return IdempotentResponse{}, false, ErrIdempotencyMismatch
+		}
+		if k.ResponseStatus != nil {
+			resp := IdempotentResponse{
+				Status: *k.ResponseStatus,
+				Body:   k.ResponseBody,
+			}
+			if k.ResponseContentType != nil {
+				resp.ContentType = *k.ResponseContentType
+			}
+			return resp, true, nil
+		}
+
+		// Wait for first request
+		if time.Now().After(deadline) {
+			return IdempotentResponse{}, false, ErrIdempotencyInProgress
+		}
+		select {
+		case <-ctx.Done():
+			return IdempotentResponse{}, false, ErrIdempotencyInProgress
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+func (s *IdempotencyService) CompleteIdempotentRequest(ctx context.Context, in CompleteIdempotentRequestInput) error {
+	err := s.idempotencyRepo.Complete(ctx, rt.CompleteIdempotencyKeyInput{
+		Caller:      in.Caller,
+		Key:         in.Key,
+		Status:      in.Response.Status,
+		ContentType: in.Response.ContentType,
+		Body:        in.Response.Body,
+	})
+	if err != nil {
+		log.Errorf("IdempotencyService.CompleteIdempotentRequest - idempotencyRepo.Complete: %v", err)
+		return ErrIdempotencyKey
+	}
+
+	return nil
+}
+
+// KeepIdempotentRequest extends lease of claimed key until stop is called, so
+// request running longer than lease doesn't let key be claimed again
+func (s *IdempotencyService) KeepIdempotentRequest(ctx context.Context, caller, key string) func() {
+	if s.cfg.Lease <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.cfg.Lease / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.idempotencyRepo.Extend(ctx, caller, key, s.cfg.Lease); err != nil {
+					log.Errorf("IdempotencyService.KeepIdempotentRequest - idempotencyRepo.Extend: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// ReleaseIdempotentRequest lets request with key be made again, used when
+// request failed without changing anything
+func (s *IdempotencyService) ReleaseIdempotentRequest(ctx context.Context, caller, key string) error {
+	if err := s.idempotencyRepo.Release(ctx, caller, key); err != nil {
+		log.Errorf("IdempotencyService.ReleaseIdempotentRequest - idempotencyRepo.Release: %v", err)
+		return ErrIdempotencyKey
+	}
+
+	return nil
+}
+
+func (s *IdempotencyService) PruneIdempotencyKeys(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := s.idempotencyRepo.DeleteOlderThan(ctx, retention)
+	if err != nil {
+		log.Errorf("IdempotencyService.PruneIdempotencyKeys - idempotencyRepo.DeleteOlderThan: %v", err)
+		return 0, ErrPruneIdempotencyKeys
+	}
+
+	return n, nil
+}
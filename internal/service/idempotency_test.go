@@ -0,0 +1,119 @@
+package service
+
+import (
+	"app/internal/repo"
+	"app/internal/repo/memdb"
+	"context"
+	"testing"
+	"time"
+)
+
+// Duplicate made while first request is in progress waits for its response
+func TestIdempotencyServiceBeginIdempotentRequest(t *testing.T) {
+	const wait = 200 * time.Millisecond
+	first := BeginIdempotentRequestInput{Caller: "employee:1", Key: "k1", RequestHash: []byte("tender")}
+	stored := IdempotentResponse{Status: 200, ContentType: "application/json", Body: []byte(`{"id":"1"}`)}
+
+	tests := []struct {
+		name      string
+		duplicate BeginIdempotentRequestInput
+		// First request completes after delay, never if zero
+		completeAfter time.Duration
+		wantErr       error
+		wantReplay    bool
+	}{
+		{name: "replayed after first completes", duplicate: first, completeAfter: wait / 4, wantReplay: true},
+		{name: "first still in progress", duplicate: first, wantErr: ErrIdempotencyInProgress},
+		{
+			name:      "different request",
+			duplicate: BeginIdempotentRequestInput{Caller: first.Caller, Key: first.Key, RequestHash: []byte("bid")},
+			wantErr:   ErrIdempotencyMismatch,
+		},
+		{
+			name:      "other caller",
+			duplicate: BeginIdempotentRequestInput{Caller: "employee:2", Key: first.Key, RequestHash: first.RequestHash},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewIdempotencyService(repo.NewMemoryRepo(memdb.NewStore()).Idempotency, IdempotencyConfig{
+				TTL:          time.Hour,
+				Lease:        time.Minute,
+				Wait:         wait,
+				PollInterval: wait / 20,
+			})
+
+			if _, replay, err := s.BeginIdempotentRequest(ctx, first); err != nil || replay {
+				t.Fatalf("first BeginIdempotentRequest() = %t, %v", replay, err)
+			}
+			if tt.completeAfter > 0 {
+				timer := time.AfterFunc(tt.completeAfter, func() {
+					s.CompleteIdempotentRequest(ctx, CompleteIdempotentRequestInput{Caller: first.Caller, Key: first.Key, Response: stored})
+				})
+				defer timer.Stop()
+			}
+
+			resp, replay, err := s.BeginIdempotentRequest(ctx, tt.duplicate)
+			checkErr(t, err, tt.wantErr)
+			if replay != tt.wantReplay {
+				t.Fatalf("replay = %t, want %t", replay, tt.wantReplay)
+			}
+			if replay && (resp.Status != stored.Status || string(resp.Body) != string(stored.Body)) {
+				t.Errorf("replayed %+v, want %+v", resp, stored)
+			}
+		})
+	}
+}
+
+func TestIdempotencyServiceKeepIdempotentRequest(t *testing.T) {
+	const lease = 20 * time.Millisecond
+
+	t.Run("lease extended until stopped", func(t *testing.T) {
+		r := &fakeIdempotencyRepo{}
+		s := NewIdempotencyService(r, IdempotencyConfig{Lease: lease})
+
+		stop := s.KeepIdempotentRequest(context.Background(), "employee:1", "k1")
+		// Handler runs for several leases
+		time.Sleep(3 * lease)
+		stop()
+
+		n := r.extends()
+		if n < 2 {
+			t.Fatalf("extended %d times in 3 leases, want at least 2", n)
+		}
+		for _, got := range r.extended {
+			if got != lease {
+				t.Errorf("extended for %s, want %s", got, lease)
+			}
+		}
+		time.Sleep(2 * lease)
+		if r.extends() != n {
+			t.Errorf("lease extended after stop")
+		}
+	})
+
+	t.Run("failed extend keeps trying", func(t *testing.T) {
+		r := &fakeIdempotencyRepo{err: errRepo}
+		s := NewIdempotencyService(r, IdempotencyConfig{Lease: lease})
+
+		stop := s.KeepIdempotentRequest(context.Background(), "employee:1", "k1")
+		time.Sleep(3 * lease)
+		stop()
+
+		if n := r.extends(); n < 2 {
+			t.Errorf("extended %d times in 3 leases, want at least 2", n)
+		}
+	})
+
+	t.Run("no lease", func(t *testing.T) {
+		r := &fakeIdempotencyRepo{}
+		s := NewIdempotencyService(r, IdempotencyConfig{})
+
+		s.KeepIdempotentRequest(context.Background(), "employee:1", "k1")()
+		if r.extends() != 0 {
+			t.Errorf("key without lease extended")
+		}
+	})
+}
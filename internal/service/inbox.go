@@ -0,0 +1,118 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// In-app notification kinds
+const (
+	NotificationBidReceived   = "bid_received"
+	NotificationBidDecided    = "bid_decided"
+	NotificationTenderAmended = "tender_amended"
+)
+
+type InboxService struct {
+	notificationRepo repo.Notification
+	employeeRepo     repo.Employee
+}
+
+func NewInboxService(nRepo repo.Notification, eRepo repo.Employee) *InboxService {
+	return &InboxService{
+		notificationRepo: nRepo,
+		employeeRepo:     eRepo,
+	}
+}
+
+func (s *InboxService) GetNotifications(ctx context.Context, in GetNotificationsInput) ([]e.Notification, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "InboxService.GetNotifications", in.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.notificationRepo.GetByEmployee(ctx, rt.GetNotificationsInput{
+		Limit:      in.Limit,
+		Offset:     in.Offset,
+		EmployeeId: user.Id,
+		UnreadOnly: in.UnreadOnly,
+	})
+	if err != nil {
+		log.Errorf("InboxService.GetNotifications - notificationRepo.GetByEmployee: %v", err)
+		return nil, ErrGetNotifications
+	}
+
+	return notifications, nil
+}
+
+func (s *InboxService) GetUnreadCount(ctx context.Context, username string) (int, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "InboxService.GetUnreadCount", username)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.notificationRepo.CountUnread(ctx, user.Id)
+	if err != nil {
+		log.Errorf("InboxService.GetUnreadCount - notificationRepo.CountUnread: %v", err)
+		return 0, ErrGetNotifications
+	}
+
+	return count, nil
+}
+
+func (s *InboxService) MarkNotificationRead(ctx context.Context, notificationId uuid.UUID, username string) (e.Notification, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "InboxService.MarkNotificationRead", username)
+	if err != nil {
+		return e.Notification{}, err
+	}
+
+	// Other employees' notifications are reported as missing
+	notification, err := s.notificationRepo.MarkRead(ctx, user.Id, notificationId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Notification{}, ErrNotFoundNotification
+		}
+		log.Errorf("InboxService.MarkNotificationRead - notificationRepo.MarkRead: %v", err)
+		return e.Notification{}, ErrMarkNotificationRead
+	}
+
+	return notification, nil
+}
+
+// MarkAllNotificationsRead returns number of notifications marked
+func (s *InboxService) MarkAllNotificationsRead(ctx context.Context, username string) (int, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "InboxService.MarkAllNotificationsRead", username)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.notificationRepo.MarkAllRead(ctx, user.Id)
+	if err != nil {
+		log.Errorf("InboxService.MarkAllNotificationsRead - notificationRepo.MarkAllRead: %v", err)
+		return 0, ErrMarkNotificationRead
+	}
+
+	return n, nil
+}
+
+// PruneNotifications deletes notifications older than retention, read or not
+func (s *InboxService) PruneNotifications(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := s.notificationRepo.DeleteOlderThan(ctx, retention)
+	if err != nil {
+		log.Errorf("InboxService.PruneNotifications - notificationRepo.DeleteOlderThan: %v", err)
+		return 0, ErrPruneNotifications
+	}
+
+	return n, nil
+}
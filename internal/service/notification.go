@@ -0,0 +1,115 @@
+package service
+
+import (
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Email notification kinds employees can opt in to
+const (
+	EmailKindBidReceived         = "bid_received"
+	EmailKindBidDecided          = "bid_decided"
+	EmailKindTenderAmended       = "tender_amended"
+	EmailKindDeadlineApproaching = "deadline_approaching"
+)
+
+type NotificationService struct {
+	emailRepo    repo.Email
+	employeeRepo repo.Employee
+	tenderRepo   repo.Tender
+	outboxRepo   repo.Outbox
+	tx           repo.Transactor
+}
+
+func NewNotificationService(mRepo repo.Email, eRepo repo.Employee, tRepo repo.Tender, obRepo repo.Outbox, tx repo.Transactor) *NotificationService {
+	return &NotificationService{
+		emailRepo:    mRepo,
+		employeeRepo: eRepo,
+		tenderRepo:   tRepo,
+		outboxRepo:   obRepo,
+		tx:           tx,
+	}
+}
+
+// GetEmailPreferences returns address of employee and kinds employee opted in to
+func (s *NotificationService) GetEmailPreferences(ctx context.Context, username string) (string, []string, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "NotificationService.GetEmailPreferences", username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	kinds, err := s.emailRepo.GetPreferences(ctx, user.Id)
+	if err != nil {
+		log.Errorf("NotificationService.GetEmailPreferences - emailRepo.GetPreferences: %v", err)
+		return "", nil, ErrGetEmailPreferences
+	}
+
+	return optionalValue(user.Email), kinds, nil
+}
+
+// SetEmailPreferences replaces kinds employee opted in to, empty kinds opt out
+// of everything
+func (s *NotificationService) SetEmailPreferences(ctx context.Context, username string, kinds []string) (string, []string, error) {
+	// Check if user exists
+	user, err := resolveEmployee(ctx, s.employeeRepo, "NotificationService.SetEmailPreferences", username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Opting in makes no sense without address
+	if len(kinds) > 0 && user.Email == nil {
+		return "", nil, ErrEmailRequired
+	}
+
+	if err := s.emailRepo.SetPreferences(ctx, user.Id, kinds); err != nil {
+		log.Errorf("NotificationService.SetEmailPreferences - emailRepo.SetPreferences: %v", err)
+		return "", nil, ErrSetEmailPreferences
+	}
+
+	return s.GetEmailPreferences(ctx, username)
+}
+
+// RemindDeadlines publishes deadline event for every published tender which
+// closes within window. Each deadline is reminded once
+func (s *NotificationService) RemindDeadlines(ctx context.Context, window time.Duration) (int, error) {
+	var n int
+	err := inTx(ctx, s.tx, "NotificationService.RemindDeadlines", func(ctx context.Context) error {
+		tenders, err := s.tenderRepo.ClaimDeadlineReminders(ctx, window)
+		if err != nil {
+			log.Errorf("NotificationService.RemindDeadlines - tenderRepo.ClaimDeadlineReminders: %v", err)
+			return ErrRemindDeadlines
+		}
+
+		for _, tender := range tenders {
+			err := publishEvent(ctx, s.outboxRepo, "NotificationService.RemindDeadlines", rt.CreateOutboxEventInput{
+				Type:           EventTenderDeadlineApproaching,
+				AggregateType:  AuditEntityTender,
+				AggregateId:    tender.Id,
+				OrganizationId: uuid.NullUUID{UUID: tender.OrganizationId, Valid: true},
+			}, newTenderEventPayload(tender))
+			if err != nil {
+				return err
+			}
+		}
+		n = len(tenders)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func optionalValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
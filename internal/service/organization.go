@@ -0,0 +1,317 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type OrganizationService struct {
+	organizationRepo repo.Organization
+	employeeRepo     repo.Employee
+	tx               repo.Transactor
+	authz            *authz.Authorizer
+}
+
+func NewOrganizationService(oRepo repo.Organization, eRepo repo.Employee, tx repo.Transactor, az *authz.Authorizer) *OrganizationService {
+	return &OrganizationService{
+		organizationRepo: oRepo,
+		employeeRepo:     eRepo,
+		tx:               tx,
+		authz:            az,
+	}
+}
+
+func (s *OrganizationService) Create(ctx context.Context, in CreateOrganizationInput) (e.Organization, error) {
+	// Check if user exists
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Organization{}, ErrUsername
+		}
+		log.Errorf("OrganizationService.Create - employeeRepo.GetByUsername: %v", err)
+		return e.Organization{}, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return e.Organization{}, ErrEmployeeDeactivated
+	}
+
+	// Create organization, creator becomes its first responsible
+	org, err := s.organizationRepo.Create(ctx, rt.CreateOrganizationInput{
+		Name:          in.Name,
+		Description:   in.Description,
+		Type:          in.Type,
+		ResponsibleId: user.Id,
+	})
+	if err != nil {
+		log.Errorf("OrganizationService.Create - organizationRepo.Create: %v", err)
+		return e.Organization{}, ErrCreateOrganization
+	}
+
+	return org, nil
+}
+
+func (s *OrganizationService) Get(ctx context.Context, orgId uuid.UUID) (e.Organization, error) {
+	org, err := s.organizationRepo.Get(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Organization{}, ErrNotFoundOrganization
+		}
+		log.Errorf("OrganizationService.Get - organizationRepo.Get: %v", err)
+		return e.Organization{}, ErrGetOrganization
+	}
+
+	return org, nil
+}
+
+func (s *OrganizationService) GetOrganizations(ctx context.Context, in GetOrganizationsInput) ([]e.Organization, error) {
+	orgs, err := s.organizationRepo.Search(ctx, rt.SearchOrganizationsInput{
+		Limit:  in.Limit,
+		Offset: in.Offset,
+		Query:  in.Query,
+		Type:   in.Type,
+	})
+	if err != nil {
+		log.Errorf("OrganizationService.GetOrganizations - organizationRepo.Search: %v", err)
+		return nil, ErrGetOrganizations
+	}
+
+	return orgs, nil
+}
+
+func (s *OrganizationService) Edit(ctx context.Context, in EditOrganizationInput) (e.Organization, error) {
+	// Check if organization exists and user is responsible for it
+	_, org, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.Edit",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return e.Organization{}, err
+	}
+
+	// Update changed fields only
+	input := rt.UpdateOrganizationInput{
+		Id:          org.Id,
+		Name:        in.Name,
+		Description: in.Description,
+		Type:        in.Type,
+	}
+	if in.Name == "" {
+		input.Name = org.Name
+	}
+	if in.Description == "" {
+		input.Description = org.Description
+	}
+	if in.Type == "" {
+		input.Type = org.Type
+	}
+	updated, err := s.organizationRepo.Update(ctx, input)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Organization{}, ErrNotFoundOrganization
+		}
+		log.Errorf("OrganizationService.Edit - organizationRepo.Update: %v", err)
+		return e.Organization{}, ErrUpdateOrganization
+	}
+
+	return updated, nil
+}
+
+func (s *OrganizationService) Delete(ctx context.Context, orgId uuid.UUID, username string) error {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.Delete",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	if err := s.organizationRepo.Delete(ctx, orgId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundOrganization
+		}
+		log.Errorf("OrganizationService.Delete - organizationRepo.Delete: %v", err)
+		return ErrDeleteOrganization
+	}
+
+	return nil
+}
+
+func (s *OrganizationService) GetResponsibles(ctx context.Context, orgId uuid.UUID, username string) ([]e.Employee, error) {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.GetResponsibles",
+		orgId, username, s.authz.CanViewOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	employees, err := s.organizationRepo.GetResponsibles(ctx, orgId)
+	if err != nil {
+		log.Errorf("OrganizationService.GetResponsibles - organizationRepo.GetResponsibles: %v", err)
+		return nil, ErrGetResponsibles
+	}
+
+	return employees, nil
+}
+
+func (s *OrganizationService) AssignResponsible(ctx context.Context, in ResponsibleInput) error {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.AssignResponsible",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	// Check if assigned employee exists
+	employee, err := s.employeeRepo.GetById(ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundEmployee
+		}
+		log.Errorf("OrganizationService.AssignResponsible - employeeRepo.GetById: %v", err)
+		return ErrGetEmployeeById
+	}
+	if !employee.IsActive {
+		return ErrNotFoundEmployee
+	}
+
+	if err := s.organizationRepo.AddResponsible(ctx, in.OrganizationId, in.UserId); err != nil {
+		if errors.Is(err, repoerrors.ErrAlreadyExists) {
+			return ErrResponsibleExists
+		}
+		log.Errorf("OrganizationService.AssignResponsible - organizationRepo.AddResponsible: %v", err)
+		return ErrAssignResponsible
+	}
+
+	return nil
+}
+
+func (s *OrganizationService) UnassignResponsible(ctx context.Context, in ResponsibleInput) error {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.UnassignResponsible",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	// Checks and removal run under lock of responsibles, so concurrent
+	// changes can't both pass checks
+	return inTx(ctx, s.tx, "OrganizationService.UnassignResponsible", func(ctx context.Context) error {
+		if err := s.organizationRepo.LockResponsibles(ctx, in.OrganizationId); err != nil {
+			log.Errorf("OrganizationService.UnassignResponsible - organizationRepo.LockResponsibles: %v", err)
+			return ErrUnassignResponsible
+		}
+
+		// Organization must keep at least one responsible
+		responsibles, err := s.organizationRepo.GetResponsibles(ctx, in.OrganizationId)
+		if err != nil {
+			log.Errorf("OrganizationService.UnassignResponsible - organizationRepo.GetResponsibles: %v", err)
+			return ErrGetResponsibles
+		}
+		if len(responsibles) <= 1 {
+			return ErrLastResponsible
+		}
+
+		// Organization must keep at least one admin
+		if err := s.checkNotLastAdmin(ctx, "UnassignResponsible", in.OrganizationId, in.UserId); err != nil {
+			return err
+		}
+
+		if err := s.organizationRepo.RemoveResponsible(ctx, in.OrganizationId, in.UserId); err != nil {
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				return ErrNotFoundResponsible
+			}
+			log.Errorf("OrganizationService.UnassignResponsible - organizationRepo.RemoveResponsible: %v", err)
+			return ErrUnassignResponsible
+		}
+
+		return nil
+	})
+}
+
+func (s *OrganizationService) GetRoles(ctx context.Context, in ResponsibleInput) ([]string, error) {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.GetRoles",
+		in.OrganizationId, in.Username, s.authz.CanViewOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.employeeRepo.GetRoles(ctx, in.OrganizationId, in.UserId)
+	if err != nil {
+		log.Errorf("OrganizationService.GetRoles - employeeRepo.GetRoles: %v", err)
+		return nil, ErrGetRoles
+	}
+	if len(roles) == 0 {
+		return nil, ErrNotFoundResponsible
+	}
+
+	return roles, nil
+}
+
+// SetRoles replaces roles of organization responsible, at least one role is required
+func (s *OrganizationService) SetRoles(ctx context.Context, in SetRolesInput) ([]string, error) {
+	// Check if organization exists and user is responsible for it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "OrganizationService.SetRoles",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check and change run under lock of responsibles, so concurrent changes
+	// can't both pass check
+	err = inTx(ctx, s.tx, "OrganizationService.SetRoles", func(ctx context.Context) error {
+		if err := s.organizationRepo.LockResponsibles(ctx, in.OrganizationId); err != nil {
+			log.Errorf("OrganizationService.SetRoles - organizationRepo.LockResponsibles: %v", err)
+			return ErrSetRoles
+		}
+
+		// Organization must keep at least one admin
+		if !slices.Contains(in.Roles, authz.RoleAdmin) {
+			if err := s.checkNotLastAdmin(ctx, "SetRoles", in.OrganizationId, in.UserId); err != nil {
+				return err
+			}
+		}
+
+		if err := s.organizationRepo.SetRoles(ctx, in.OrganizationId, in.UserId, in.Roles); err != nil {
+			if errors.Is(err, repoerrors.ErrInvalidReference) {
+				return ErrNotFoundResponsible
+			}
+			log.Errorf("OrganizationService.SetRoles - organizationRepo.SetRoles: %v", err)
+			return ErrSetRoles
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.employeeRepo.GetRoles(ctx, in.OrganizationId, in.UserId)
+	if err != nil {
+		log.Errorf("OrganizationService.SetRoles - employeeRepo.GetRoles: %v", err)
+		return nil, ErrGetRoles
+	}
+
+	return roles, nil
+}
+
+// checkNotLastAdmin fails if userId is the only admin of organization, must
+// be called under lock of responsibles
+func (s *OrganizationService) checkNotLastAdmin(ctx context.Context, method string, orgId, userId uuid.UUID) error {
+	admins, err := s.organizationRepo.GetRoleHolders(ctx, orgId, authz.RoleAdmin)
+	if err != nil {
+		log.Errorf("OrganizationService.%s - organizationRepo.GetRoleHolders: %v", method, err)
+		return ErrGetRoles
+	}
+	if len(admins) == 1 && admins[0] == userId {
+		return ErrLastAdmin
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"app/internal/authz"
+	"app/internal/repo"
+	"app/internal/repo/memdb"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type recordingTxKey struct{}
+
+// recordingTransactor marks context of transaction for recordingOrganizationRepo
+type recordingTransactor struct {
+	repo.Transactor
+}
+
+func (t recordingTransactor) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.Transactor.InTxRetry(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, recordingTxKey{}, true))
+	})
+}
+
+// recordingOrganizationRepo logs calls checking or changing responsibles,
+// calls made outside of transaction are marked
+type recordingOrganizationRepo struct {
+	repo.Organization
+	log []string
+}
+
+func (r *recordingOrganizationRepo) record(ctx context.Context, call string) {
+	if ctx.Value(recordingTxKey{}) == nil {
+		call += " outside tx"
+	}
+	r.log = append(r.log, call)
+}
+
+func (r *recordingOrganizationRepo) LockResponsibles(ctx context.Context, orgId uuid.UUID) error {
+	r.record(ctx, "LockResponsibles")
+	return r.Organization.LockResponsibles(ctx, orgId)
+}
+
+func (r *recordingOrganizationRepo) GetRoleHolders(ctx context.Context, orgId uuid.UUID, role string) ([]uuid.UUID, error) {
+	r.record(ctx, "GetRoleHolders")
+	return r.Organization.GetRoleHolders(ctx, orgId, role)
+}
+
+func (r *recordingOrganizationRepo) SetRoles(ctx context.Context, orgId, userId uuid.UUID, roles []string) error {
+	r.record(ctx, "SetRoles")
+	return r.Organization.SetRoles(ctx, orgId, userId, roles)
+}
+
+func (r *recordingOrganizationRepo) RemoveResponsible(ctx context.Context, orgId, userId uuid.UUID) error {
+	r.record(ctx, "RemoveResponsible")
+	return r.Organization.RemoveResponsible(ctx, orgId, userId)
+}
+
+// Last admin check and change of roles run in one transaction under lock of
+// responsibles
+func TestOrganizationServiceKeepsAdmin(t *testing.T) {
+	// Seeded organization with user1, user2 and user3 having all roles
+	orgId := uuid.MustParse("550e8400-e29b-41d4-a716-446655440020")
+	userId := func(n int) uuid.UUID {
+		return uuid.MustParse(fmt.Sprintf("550e8400-e29b-41d4-a716-4466554400%02x", n))
+	}
+	viewer := []string{authz.RoleViewer}
+
+	tests := []struct {
+		name    string
+		demoted []int
+		call    func(s *OrganizationService) error
+		wantErr error
+		want    []string
+	}{
+		{
+			name: "set roles",
+			call: func(s *OrganizationService) error {
+				_, err := s.SetRoles(context.Background(), SetRolesInput{OrganizationId: orgId, UserId: userId(2), Username: "user1", Roles: viewer})
+				return err
+			},
+			want: []string{"LockResponsibles", "GetRoleHolders", "SetRoles"},
+		},
+		{
+			name:    "revoke last admin",
+			demoted: []int{2, 3},
+			call: func(s *OrganizationService) error {
+				_, err := s.SetRoles(context.Background(), SetRolesInput{OrganizationId: orgId, UserId: userId(1), Username: "user1", Roles: viewer})
+				return err
+			},
+			wantErr: ErrLastAdmin,
+			want:    []string{"LockResponsibles", "GetRoleHolders"},
+		},
+		{
+			name: "unassign",
+			call: func(s *OrganizationService) error {
+				return s.UnassignResponsible(context.Background(), ResponsibleInput{OrganizationId: orgId, UserId: userId(2), Username: "user1"})
+			},
+			want: []string{"LockResponsibles", "GetRoleHolders", "RemoveResponsible"},
+		},
+		{
+			name:    "unassign last admin",
+			demoted: []int{2, 3},
+			call: func(s *OrganizationService) error {
+				return s.UnassignResponsible(context.Background(), ResponsibleInput{OrganizationId: orgId, UserId: userId(1), Username: "user1"})
+			},
+			wantErr: ErrLastAdmin,
+			want:    []string{"LockResponsibles", "GetRoleHolders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memdb.NewStore()
+			store.Seed()
+			repos := repo.NewMemoryRepo(store)
+			for _, n := range tt.demoted {
+				if err := repos.Organization.SetRoles(context.Background(), orgId, userId(n), viewer); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			orgs := &recordingOrganizationRepo{Organization: repos.Organization}
+			s := NewOrganizationService(orgs, repos.Employee, recordingTransactor{store}, authz.New(repos.Employee))
+			checkErr(t, tt.call(s), tt.wantErr)
+			if !slices.Equal(orgs.log, tt.want) {
+				t.Errorf("calls %q, want %q", orgs.log, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode"
+)
+
+// bcrypt ignores everything after 72 bytes
+const maxPasswordBytes = 72
+
+func (p PasswordPolicy) validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters long", ErrWeakPassword, p.MinLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: must be at most %d bytes long", ErrWeakPassword, maxPasswordBytes)
+	}
+
+	var hasLetter, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+	if p.RequireLetter && !hasLetter {
+		return fmt.Errorf("%w: must contain a letter", ErrWeakPassword)
+	}
+	if p.RequireDigit && !hasDigit {
+		return fmt.Errorf("%w: must contain a digit", ErrWeakPassword)
+	}
+	if p.RequireSpecial && !hasSpecial {
+		return fmt.Errorf("%w: must contain a special character", ErrWeakPassword)
+	}
+
+	return nil
+}
+
+// newResetToken returns random token given to employee and its hash stored in database
+func newResetToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+// hashToken is used for high entropy secrets only, they don't need slow hashing
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,19 +1,32 @@
 package service
 
 import (
+	"app/internal/authz"
 	e "app/internal/entity"
+	"app/internal/identity"
 	"app/internal/repo"
+	"app/internal/stream"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Precondition guards write against lost update, it must match latest version.
+// Zero fields match any version
+type Precondition struct {
+	Version   int
+	UpdatedAt time.Time
+}
+
 type CreateTenderInput struct {
 	Name            string
 	Description     string
 	ServiceType     string
 	OrganizationId  uuid.UUID
 	CreatorUsername string
+	Visibility      string
+	Deadline        *time.Time
 }
 
 type GetByUsernameInput struct {
@@ -22,30 +35,56 @@ type GetByUsernameInput struct {
 	Username string
 }
 
+// Username is optional, anonymous callers see public tenders only
 type GetTendersInput struct {
 	Limit       int
 	Offset      int
 	ServiceType []string
+	Username    string
 }
 
 type ChangeTenderStatusInput struct {
-	TenderId uuid.UUID
-	Status   string
-	Username string
+	TenderId     uuid.UUID
+	Status       string
+	Username     string
+	Precondition Precondition
 }
 
 type EditTenderInput struct {
-	TenderId    uuid.UUID
-	Username    string
-	Name        string
-	Description string
-	ServiceType string
+	TenderId     uuid.UUID
+	Username     string
+	Name         string
+	Description  string
+	ServiceType  string
+	Deadline     *time.Time
+	Precondition Precondition
 }
 
 type RollbackTenderInput struct {
-	TenderId uuid.UUID
-	Version  int
-	Username string
+	TenderId     uuid.UUID
+	Version      int
+	Username     string
+	Precondition Precondition
+}
+
+type ChangeTenderVisibilityInput struct {
+	TenderId   uuid.UUID
+	Visibility string
+	Username   string
+}
+
+// Exactly one of OrganizationId and UserId must be set
+type InviteToTenderInput struct {
+	TenderId       uuid.UUID
+	Username       string
+	OrganizationId uuid.NullUUID
+	UserId         uuid.NullUUID
+}
+
+type RevokeInvitationInput struct {
+	TenderId     uuid.UUID
+	InvitationId uuid.UUID
+	Username     string
 }
 
 type Tender interface {
@@ -56,6 +95,11 @@ type Tender interface {
 	GetTendersByUsername(ctx context.Context, in GetByUsernameInput) ([]e.Tender, error)
 	GetTenders(ctx context.Context, in GetTendersInput) ([]e.Tender, error)
 	GetTender(ctx context.Context, tenderId uuid.UUID, username string) (e.Tender, error)
+	ChangeVisibility(ctx context.Context, in ChangeTenderVisibilityInput) (e.Tender, error)
+	Invite(ctx context.Context, in InviteToTenderInput) (e.TenderInvitation, error)
+	GetInvitations(ctx context.Context, tenderId uuid.UUID, username string) ([]e.TenderInvitation, error)
+	RevokeInvitation(ctx context.Context, in RevokeInvitationInput) error
+	GetInvitedTenders(ctx context.Context, in GetByUsernameInput) ([]e.Tender, error)
 }
 
 type CreateBidInput struct {
@@ -64,36 +108,351 @@ type CreateBidInput struct {
 	TenderId    uuid.UUID
 	AuthorType  string
 	AuthorId    uuid.UUID
+	// Required for organization bids
+	OrganizationId uuid.UUID
 }
 
 type EditBidInput struct {
-	BidId       uuid.UUID
-	Username    string
-	Name        string
-	Description string
+	BidId        uuid.UUID
+	Username     string
+	Name         string
+	Description  string
+	Precondition Precondition
+}
+
+type ChangeBidStatusInput struct {
+	BidId        uuid.UUID
+	Status       string
+	Username     string
+	Precondition Precondition
+}
+
+type RollbackBidInput struct {
+	BidId        uuid.UUID
+	Version      int
+	Username     string
+	Precondition Precondition
 }
 
 type Bid interface {
 	CreateBid(ctx context.Context, in CreateBidInput) (e.Bid, error)
 	SubmitDecision(ctx context.Context, bidId uuid.UUID, username string, decision string) (e.Bid, error)
-	ChangeStatus(ctx context.Context, bidId uuid.UUID, status, username string) (e.Bid, error)
+	ChangeStatus(ctx context.Context, in ChangeBidStatusInput) (e.Bid, error)
 	Get(ctx context.Context, bidId uuid.UUID, username string) (e.Bid, error)
 	Edit(ctx context.Context, in EditBidInput) (e.Bid, error)
-	Rollback(ctx context.Context, bidId uuid.UUID, version int, username string) (e.Bid, error)
+	Rollback(ctx context.Context, in RollbackBidInput) (e.Bid, error)
+}
+
+type CreateOrganizationInput struct {
+	Name        string
+	Description string
+	Type        string
+	Username    string
+}
+
+type GetOrganizationsInput struct {
+	Limit  int
+	Offset int
+	Query  string
+	Type   string
+}
+
+// Empty fields are left unchanged
+type EditOrganizationInput struct {
+	OrganizationId uuid.UUID
+	Username       string
+	Name           string
+	Description    string
+	Type           string
+}
+
+type SetRolesInput struct {
+	OrganizationId uuid.UUID
+	UserId         uuid.UUID
+	Username       string
+	Roles          []string
+}
+
+type ResponsibleInput struct {
+	OrganizationId uuid.UUID
+	UserId         uuid.UUID
+	Username       string
+}
+
+type Organization interface {
+	Create(ctx context.Context, in CreateOrganizationInput) (e.Organization, error)
+	Get(ctx context.Context, orgId uuid.UUID) (e.Organization, error)
+	GetOrganizations(ctx context.Context, in GetOrganizationsInput) ([]e.Organization, error)
+	Edit(ctx context.Context, in EditOrganizationInput) (e.Organization, error)
+	Delete(ctx context.Context, orgId uuid.UUID, username string) error
+	GetResponsibles(ctx context.Context, orgId uuid.UUID, username string) ([]e.Employee, error)
+	AssignResponsible(ctx context.Context, in ResponsibleInput) error
+	UnassignResponsible(ctx context.Context, in ResponsibleInput) error
+	GetRoles(ctx context.Context, in ResponsibleInput) ([]string, error)
+	SetRoles(ctx context.Context, in SetRolesInput) ([]string, error)
+}
+
+// Empty Email means employee has no address
+type CreateEmployeeInput struct {
+	Username    string
+	NewUsername string
+	FirstName   string
+	LastName    string
+	Email       string
+}
+
+// Empty fields are left unchanged
+type EditEmployeeInput struct {
+	EmployeeId uuid.UUID
+	Username   string
+	FirstName  string
+	LastName   string
+	Email      string
+}
+
+type SearchEmployeesInput struct {
+	Limit           int
+	Offset          int
+	Username        string
+	Query           string
+	IncludeInactive bool
+}
+
+type Employee interface {
+	Create(ctx context.Context, in CreateEmployeeInput) (e.Employee, error)
+	Get(ctx context.Context, employeeId uuid.UUID, username string) (e.Employee, error)
+	Edit(ctx context.Context, in EditEmployeeInput) (e.Employee, error)
+	SetActive(ctx context.Context, employeeId uuid.UUID, active bool, username string) (e.Employee, error)
+	SetAdmin(ctx context.Context, employeeId uuid.UUID, admin bool, username string) (e.Employee, error)
+	Search(ctx context.Context, in SearchEmployeesInput) ([]e.Employee, error)
+}
+
+type LoginInput struct {
+	Username string
+	Password string
+	ClientIP string
+}
+
+type SetPasswordInput struct {
+	Username       string
+	BootstrapToken string
+	NewPassword    string
+}
+
+type ChangePasswordInput struct {
+	Username    string
+	OldPassword string
+	NewPassword string
+}
+
+type ResetPasswordInput struct {
+	ResetToken  string
+	NewPassword string
+}
+
+type ResetToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+type GetLoginAttemptsInput struct {
+	Limit          int
+	Offset         int
+	Username       string
+	TargetUsername string
+}
+
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+type Auth interface {
+	Login(ctx context.Context, in LoginInput) (Token, error)
+	Authenticate(ctx context.Context, accessToken string) (e.Employee, error)
+	SetPassword(ctx context.Context, in SetPasswordInput) error
+	ChangePassword(ctx context.Context, in ChangePasswordInput) error
+	CreateResetToken(ctx context.Context, employeeId uuid.UUID, username string) (ResetToken, error)
+	ResetPassword(ctx context.Context, in ResetPasswordInput) error
+	GetLoginAttempts(ctx context.Context, in GetLoginAttemptsInput) ([]e.LoginAttempt, error)
+}
+
+type CreateAPIKeyInput struct {
+	OrganizationId uuid.UUID
+	Username       string
+	Name           string
+	Scopes         []string
+	ExpiresInDays  int
+}
+
+type APIKey interface {
+	Create(ctx context.Context, in CreateAPIKeyInput) (e.APIKey, string, error)
+	GetByOrganization(ctx context.Context, orgId uuid.UUID, username string) ([]e.APIKey, error)
+	Revoke(ctx context.Context, orgId, keyId uuid.UUID, username string) error
+	Authenticate(ctx context.Context, rawKey string) (identity.Service, error)
+}
+
+type GetAuditLogInput struct {
+	Limit          int
+	Offset         int
+	OrganizationId uuid.UUID
+	Username       string
+	EntityType     string
+	EntityId       uuid.UUID
+	Action         string
+	ActorName      string
+}
+
+type Audit interface {
+	GetOrganizationLog(ctx context.Context, in GetAuditLogInput) ([]e.AuditEntry, error)
+}
+
+// Secret is generated if empty
+type CreateWebhookInput struct {
+	OrganizationId uuid.UUID
+	Username       string
+	URL            string
+	EventTypes     []string
+	Secret         string
+}
+
+// Status is optional filter
+type GetWebhookDeliveriesInput struct {
+	Limit          int
+	Offset         int
+	OrganizationId uuid.UUID
+	WebhookId      uuid.UUID
+	Username       string
+	Status         string
+}
+
+type Webhook interface {
+	CreateWebhook(ctx context.Context, in CreateWebhookInput) (e.WebhookSubscription, string, error)
+	GetWebhooks(ctx context.Context, orgId uuid.UUID, username string) ([]e.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, orgId, webhookId uuid.UUID, username string) error
+	GetWebhookDeliveries(ctx context.Context, in GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, orgId, webhookId, deliveryId uuid.UUID, username string) (e.WebhookDelivery, error)
+}
+
+type Activity interface {
+	SubscribeActivity(ctx context.Context, username, lastEventId string) (*ActivityStream, error)
+}
+
+type Notification interface {
+	GetEmailPreferences(ctx context.Context, username string) (string, []string, error)
+	SetEmailPreferences(ctx context.Context, username string, kinds []string) (string, []string, error)
+	RemindDeadlines(ctx context.Context, window time.Duration) (int, error)
+}
+
+// UnreadOnly hides notifications already read
+type GetNotificationsInput struct {
+	Limit      int
+	Offset     int
+	Username   string
+	UnreadOnly bool
+}
+
+type Inbox interface {
+	GetNotifications(ctx context.Context, in GetNotificationsInput) ([]e.Notification, error)
+	GetUnreadCount(ctx context.Context, username string) (int, error)
+	MarkNotificationRead(ctx context.Context, notificationId uuid.UUID, username string) (e.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, username string) (int, error)
+	PruneNotifications(ctx context.Context, retention time.Duration) (int, error)
+}
+
+type BeginIdempotentRequestInput struct {
+	Caller      string
+	Key         string
+	RequestHash []byte
+}
+
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+type CompleteIdempotentRequestInput struct {
+	Caller   string
+	Key      string
+	Response IdempotentResponse
+}
+
+type Idempotency interface {
+	BeginIdempotentRequest(ctx context.Context, in BeginIdempotentRequestInput) (IdempotentResponse, bool, error)
+	CompleteIdempotentRequest(ctx context.Context, in CompleteIdempotentRequestInput) error
+	KeepIdempotentRequest(ctx context.Context, caller, key string) (stop func())
+	ReleaseIdempotentRequest(ctx context.Context, caller, key string) error
+	PruneIdempotencyKeys(ctx context.Context, retention time.Duration) (int, error)
 }
 
 type Services struct {
 	Tender
 	Bid
+	Organization
+	Employee
+	Auth
+	APIKey
+	Audit
+	Webhook
+	Activity
+	Notification
+	Inbox
+	Idempotency
+}
+
+type PasswordPolicy struct {
+	MinLength      int
+	RequireLetter  bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+type AuthConfig struct {
+	SignKey  string
+	TokenTTL time.Duration
+	// Development only, see config
+	DevAllowPasswordless bool
+	// Lets employee without password set first one, empty disables
+	BootstrapToken  string
+	PasswordPolicy  PasswordPolicy
+	BcryptCost      int
+	ResetTokenTTL   time.Duration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+}
+
+// IdempotencyConfig sets how long responses are stored and how long repeated
+// request waits for first one
+type IdempotencyConfig struct {
+	TTL          time.Duration
+	Lease        time.Duration
+	Wait         time.Duration
+	PollInterval time.Duration
 }
 
 type ServicesDependencies struct {
-	Repos *repo.Repositories
+	Repos       *repo.Repositories
+	Broker      *stream.Broker
+	Auth        AuthConfig
+	Idempotency IdempotencyConfig
 }
 
 func NewServices(d ServicesDependencies) *Services {
+	az := authz.New(d.Repos.Employee)
+
 	return &Services{
-		Tender: NewTenderService(d.Repos.Tender, d.Repos.Employee),
-		Bid:    NewBidService(d.Repos.Tender, d.Repos.Employee, d.Repos.Bid),
+		Tender:       NewTenderService(d.Repos.Tender, d.Repos.Employee, d.Repos.Invitation, d.Repos.Audit, d.Repos.Outbox, d.Repos.Transactor, az),
+		Bid:          NewBidService(d.Repos.Tender, d.Repos.Employee, d.Repos.Bid, d.Repos.Invitation, d.Repos.Audit, d.Repos.Outbox, d.Repos.Transactor, az),
+		Organization: NewOrganizationService(d.Repos.Organization, d.Repos.Employee, d.Repos.Transactor, az),
+		Employee:     NewEmployeeService(d.Repos.Employee),
+		Auth:         NewAuthService(d.Repos.Employee, d.Repos.Credential, d.Auth),
+		APIKey:       NewAPIKeyService(d.Repos.APIKey, d.Repos.Employee, d.Repos.Organization, az),
+		Audit:        NewAuditService(d.Repos.Audit, d.Repos.Employee, d.Repos.Organization, az),
+		Webhook:      NewWebhookService(d.Repos.Webhook, d.Repos.Employee, d.Repos.Organization, az),
+		Activity:     NewActivityService(d.Broker, d.Repos.Employee, d.Repos.Invitation, az),
+		Notification: NewNotificationService(d.Repos.Email, d.Repos.Employee, d.Repos.Tender, d.Repos.Outbox, d.Repos.Transactor),
+		Inbox:        NewInboxService(d.Repos.Notification, d.Repos.Employee),
+		Idempotency:  NewIdempotencyService(d.Repos.Idempotency, d.Idempotency),
 	}
 }
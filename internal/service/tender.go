@@ -1,65 +1,90 @@
 package service
 
 import (
+	"app/internal/authz"
 	e "app/internal/entity"
 	"app/internal/repo"
 	"app/internal/repo/repoerrors"
 	rt "app/internal/repo/repotypes"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
 type TenderService struct {
-	tenderRepo   repo.Tender
-	employeeRepo repo.Employee
+	tenderRepo     repo.Tender
+	employeeRepo   repo.Employee
+	invitationRepo repo.Invitation
+	auditRepo      repo.Audit
+	outboxRepo     repo.Outbox
+	tx             repo.Transactor
+	authz          *authz.Authorizer
 }
 
-func NewTenderService(tRepo repo.Tender, eRepo repo.Employee) *TenderService {
+func NewTenderService(tRepo repo.Tender, eRepo repo.Employee, iRepo repo.Invitation, aRepo repo.Audit, obRepo repo.Outbox, tx repo.Transactor, az *authz.Authorizer) *TenderService {
 	return &TenderService{
-		tenderRepo:   tRepo,
-		employeeRepo: eRepo,
+		tenderRepo:     tRepo,
+		employeeRepo:   eRepo,
+		invitationRepo: iRepo,
+		auditRepo:      aRepo,
+		outboxRepo:     obRepo,
+		tx:             tx,
+		authz:          az,
 	}
 }
 
 func (s *TenderService) CreateTender(ctx context.Context, in CreateTenderInput) (e.Tender, error) {
-	user, err := s.employeeRepo.GetByUsername(ctx, in.CreatorUsername)
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.CreateTender", in.CreatorUsername)
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrUsername
-		}
-		log.Errorf("TenderService.CreateTender - employeeRepo.GetByUsername: %v", err)
-		return e.Tender{}, ErrGetEmployeeByUsername
+		return e.Tender{}, err
 	}
 
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, in.OrganizationId, user.Id)
-	if err != nil {
-		log.Errorf("TenderService.CreateTender - employeeRepo.IsResponsible: %v", err)
-		return e.Tender{}, ErrCheckResponsibility
+	// Check rights
+	if err := checkDecision(s.authz.CanCreateTender(ctx, c.actor, in.OrganizationId)); err != nil {
+		return e.Tender{}, err
+	}
+
+	visibility := in.Visibility
+	if visibility == "" {
+		visibility = VisibilityPublic
 	}
-	if !isResponsible {
-		return e.Tender{}, ErrForbidden
+	if in.Deadline != nil && !in.Deadline.After(time.Now()) {
+		return e.Tender{}, ErrTenderDeadline
 	}
 
-	tender, err := s.tenderRepo.CreateTender(ctx, rt.CreateTenderInput{
-		Name:            in.Name,
-		Description:     in.Description,
-		ServiceType:     in.ServiceType,
-		OrganizationId:  in.OrganizationId,
-		CreatorUsername: in.CreatorUsername,
+	// Create tender and record it in audit log
+	var tender e.Tender
+	err = inTx(ctx, s.tx, "TenderService.CreateTender", func(ctx context.Context) error {
+		var err error
+		tender, err = s.tenderRepo.CreateTender(ctx, rt.CreateTenderInput{
+			Name:            in.Name,
+			Description:     in.Description,
+			ServiceType:     in.ServiceType,
+			OrganizationId:  in.OrganizationId,
+			CreatorUsername: c.username(),
+			Visibility:      visibility,
+			Deadline:        utcTime(in.Deadline),
+		})
+		if err != nil {
+			log.Errorf("TenderService.CreateTender - tenderRepo.CreateTender: %v", err)
+			return ErrCreateTender
+		}
+
+		return recordAudit(ctx, s.auditRepo, "TenderService.CreateTender", c, tenderAuditEntry(AuditActionCreate, nil, tender))
 	})
 	if err != nil {
-		log.Errorf("TenderService.CreateTender - tenderRepo.CreateTender: %v", err)
-		return e.Tender{}, ErrCreateTender
+		return e.Tender{}, err
 	}
 
 	return tender, nil
 }
 
 func (s *TenderService) GetTendersByUsername(ctx context.Context, in GetByUsernameInput) ([]e.Tender, error) {
-	_, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	employee, err := s.employeeRepo.GetByUsername(ctx, in.Username)
 	if err != nil {
 		if errors.Is(err, repoerrors.ErrNotFound) {
 			return nil, ErrUsername
@@ -67,6 +92,9 @@ func (s *TenderService) GetTendersByUsername(ctx context.Context, in GetByUserna
 		log.Errorf("TenderService.GetTendersByUsername - employeeRepo.GetByUsername: %v", err)
 		return nil, ErrGetEmployeeByUsername
 	}
+	if !employee.IsActive {
+		return nil, ErrEmployeeDeactivated
+	}
 
 	tenders, err := s.tenderRepo.GetTendersByUsername(ctx, rt.GetByUsernameInput{
 		Limit:    in.Limit,
@@ -82,10 +110,28 @@ func (s *TenderService) GetTendersByUsername(ctx context.Context, in GetByUserna
 }
 
 func (s *TenderService) GetTenders(ctx context.Context, in GetTendersInput) ([]e.Tender, error) {
+	// Resolve viewer if specified
+	var viewerId uuid.UUID
+	if in.Username != "" {
+		user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+		if err != nil {
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				return nil, ErrUsername
+			}
+			log.Errorf("TenderService.GetTenders - employeeRepo.GetByUsername: %v", err)
+			return nil, ErrGetEmployeeByUsername
+		}
+		if !user.IsActive {
+			return nil, ErrEmployeeDeactivated
+		}
+		viewerId = user.Id
+	}
+
 	tenders, err := s.tenderRepo.GetPublishedTenders(ctx, rt.GetPublishedTendersInput{
 		Limit:       in.Limit,
 		Offset:      in.Offset,
 		ServiceType: in.ServiceType,
+		ViewerId:    viewerId,
 	})
 	if err != nil {
 		log.Errorf("TenderService.GetTenders - tenderRepo.GetPublishedTenders: %v", err)
@@ -95,15 +141,27 @@ func (s *TenderService) GetTenders(ctx context.Context, in GetTendersInput) ([]e
 	return tenders, nil
 }
 
+// ChangeStatus is repeated when it loses race to concurrent write, unless caller
+// set precondition
 func (s *TenderService) ChangeStatus(ctx context.Context, in ChangeTenderStatusInput) (e.Tender, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	var t e.Tender
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		t, err = s.changeStatus(ctx, in)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrUsername
-		}
-		log.Errorf("TenderService.ChangeStatus - employeeRepo.GetByUsername: %v", err)
-		return e.Tender{}, ErrGetEmployeeByUsername
+		return e.Tender{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TenderService) changeStatus(ctx context.Context, in ChangeTenderStatusInput) (e.Tender, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.ChangeStatus", in.Username)
+	if err != nil {
+		return e.Tender{}, err
 	}
 
 	// Check if tender exists
@@ -117,37 +175,79 @@ func (s *TenderService) ChangeStatus(ctx context.Context, in ChangeTenderStatusI
 	}
 
 	// Check rights
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, tender.OrganizationId, user.Id)
-	if err != nil {
-		log.Errorf("TenderService.ChangeStatus - employeeRepo.IsResponsible: %v", err)
-		return e.Tender{}, ErrCheckResponsibility
+	if err := checkDecision(s.authz.CanPublishTender(ctx, c.actor, tender)); err != nil {
+		return e.Tender{}, err
 	}
-	if !isResponsible {
-		return e.Tender{}, ErrForbidden
+	if err := in.Precondition.check(tender.Version, tender.UpdatedAt); err != nil {
+		return e.Tender{}, err
 	}
 
-	// Change status
-	t, err := s.tenderRepo.ChangeStatus(ctx, in.TenderId, in.Status)
-	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrNotFoundTender
+	// Change status and record it in audit log, publication is domain event.
+	// Version read above must still be latest
+	var t e.Tender
+	err = inTx(ctx, s.tx, "TenderService.ChangeStatus", func(ctx context.Context) error {
+		if err := s.tenderRepo.Lock(ctx, tender.Id, tender.Version, tender.UpdatedAt); err != nil {
+			return versionError("TenderService.ChangeStatus", "tenderRepo.Lock", err, ErrGetTender)
 		}
-		log.Errorf("TenderService.ChangeStatus - tenderRepo.ChangeStatus: %v", err)
-		return e.Tender{}, ErrGetTender
+
+		var err error
+		t, err = s.tenderRepo.ChangeStatus(ctx, in.TenderId, in.Status)
+		if err != nil {
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				return ErrNotFoundTender
+			}
+			log.Errorf("TenderService.ChangeStatus - tenderRepo.ChangeStatus: %v", err)
+			return ErrGetTender
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, "TenderService.ChangeStatus", c, tenderAuditEntry(AuditActionChangeStatus, &tender, t)); err != nil {
+			return err
+		}
+
+		if t.Status == tender.Status {
+			return nil
+		}
+		if err := publishTenderChange(ctx, s.outboxRepo, "TenderService.ChangeStatus", EventTenderStatusChanged, tender, t); err != nil {
+			return err
+		}
+		if t.Status != "Published" {
+			return nil
+		}
+		return publishEvent(ctx, s.outboxRepo, "TenderService.ChangeStatus", rt.CreateOutboxEventInput{
+			Type:           EventTenderPublished,
+			AggregateType:  AuditEntityTender,
+			AggregateId:    t.Id,
+			OrganizationId: uuid.NullUUID{UUID: t.OrganizationId, Valid: true},
+		}, newTenderEventPayload(t))
+	})
+	if err != nil {
+		return e.Tender{}, err
 	}
 
 	return t, nil
 }
 
+// Edit is repeated when it loses race to concurrent write, unless caller
+// set precondition
 func (s *TenderService) Edit(ctx context.Context, in EditTenderInput) (e.Tender, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	var t e.Tender
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		t, err = s.edit(ctx, in)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrUsername
-		}
-		log.Errorf("TenderService.Edit - employeeRepo.GetByUsername: %v", err)
-		return e.Tender{}, ErrGetEmployeeByUsername
+		return e.Tender{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TenderService) edit(ctx context.Context, in EditTenderInput) (e.Tender, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.Edit", in.Username)
+	if err != nil {
+		return e.Tender{}, err
 	}
 
 	// Check if tender exists
@@ -161,13 +261,15 @@ func (s *TenderService) Edit(ctx context.Context, in EditTenderInput) (e.Tender,
 	}
 
 	// Check rights
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, tender.OrganizationId, user.Id)
-	if err != nil {
-		log.Errorf("TenderService.Edit - employeeRepo.IsResponsible: %v", err)
-		return e.Tender{}, ErrCheckResponsibility
+	if err := checkDecision(s.authz.CanEditTender(ctx, c.actor, tender)); err != nil {
+		return e.Tender{}, err
+	}
+	if err := in.Precondition.check(tender.Version, tender.UpdatedAt); err != nil {
+		return e.Tender{}, err
 	}
-	if !isResponsible {
-		return e.Tender{}, ErrForbidden
+
+	if in.Deadline != nil && !in.Deadline.After(time.Now()) {
+		return e.Tender{}, ErrTenderDeadline
 	}
 
 	// Create edited version
@@ -181,6 +283,8 @@ func (s *TenderService) Edit(ctx context.Context, in EditTenderInput) (e.Tender,
 			OrganizationId:  tender.OrganizationId,
 			CreatorUsername: tender.CreatorUsername,
 			Status:          tender.Status,
+			Visibility:      tender.Visibility,
+			Deadline:        utcTime(in.Deadline),
 		},
 	}
 	if in.Name == "" {
@@ -192,24 +296,55 @@ func (s *TenderService) Edit(ctx context.Context, in EditTenderInput) (e.Tender,
 	if in.ServiceType == "" {
 		input.ServiceType = tender.Type
 	}
-	t, err := s.tenderRepo.CreateSpecified(ctx, input)
+	if in.Deadline == nil {
+		input.Deadline = tender.Deadline
+	}
+	var t e.Tender
+	err = inTx(ctx, s.tx, "TenderService.Edit", func(ctx context.Context) error {
+		if err := s.tenderRepo.Lock(ctx, tender.Id, tender.Version, tender.UpdatedAt); err != nil {
+			return versionError("TenderService.Edit", "tenderRepo.Lock", err, ErrGetTender)
+		}
+
+		var err error
+		t, err = s.tenderRepo.CreateSpecified(ctx, input)
+		if err != nil {
+			return versionError("TenderService.Edit", "tenderRepo.CreateSpecified", err, ErrCreateTender)
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, "TenderService.Edit", c, tenderAuditEntry(AuditActionEdit, &tender, t)); err != nil {
+			return err
+		}
+
+		return publishTenderChange(ctx, s.outboxRepo, "TenderService.Edit", EventTenderVersionCreated, tender, t)
+	})
 	if err != nil {
-		log.Errorf("TenderService.Edit - tenderRepo.CreateSpecified: %v", err)
-		return e.Tender{}, ErrCreateTender
+		return e.Tender{}, err
 	}
 
 	return t, nil
 }
 
+// Rollback is repeated when it loses race to concurrent write, unless caller
+// set precondition
 func (s *TenderService) Rollback(ctx context.Context, in RollbackTenderInput) (e.Tender, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	var t e.Tender
+	err := retryConflicts(in.Precondition, func() error {
+		var err error
+		t, err = s.rollback(ctx, in)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrUsername
-		}
-		log.Errorf("TenderService.Rollback - employeeRepo.GetByUsername: %v", err)
-		return e.Tender{}, ErrGetEmployeeByUsername
+		return e.Tender{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TenderService) rollback(ctx context.Context, in RollbackTenderInput) (e.Tender, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.Rollback", in.Username)
+	if err != nil {
+		return e.Tender{}, err
 	}
 
 	// Check if tender exists
@@ -223,50 +358,66 @@ func (s *TenderService) Rollback(ctx context.Context, in RollbackTenderInput) (e
 	}
 
 	// Check rights
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, tenderToRollback.OrganizationId, user.Id)
-	if err != nil {
-		log.Errorf("TenderService.Rollback - employeeRepo.IsResponsible: %v", err)
-		return e.Tender{}, ErrCheckResponsibility
-	}
-	if !isResponsible {
-		return e.Tender{}, ErrForbidden
+	if err := checkDecision(s.authz.CanEditTender(ctx, c.actor, tenderToRollback)); err != nil {
+		return e.Tender{}, err
 	}
 
-	// Create rollback version
-	latestVersion, err := s.tenderRepo.GetLatestVersion(ctx, in.TenderId)
+	// Get latest version, it is audit snapshot before rollback
+	latest, err := s.tenderRepo.Get(ctx, in.TenderId, rt.VersionLatest)
 	if err != nil {
-		log.Errorf("TenderService.Rollback - tenderRepo.GetLatestVersion: %v", err)
+		log.Errorf("TenderService.Rollback - tenderRepo.Get: %v", err)
 		return e.Tender{}, ErrGetTenderLatestVersion
 	}
-	t, err := s.tenderRepo.CreateSpecified(ctx, rt.CreateSpecifiedInput{
-		Id:      in.TenderId,
-		Version: latestVersion + 1,
-		CreateTenderInput: rt.CreateTenderInput{
-			Name:            tenderToRollback.Name,
-			Description:     tenderToRollback.Description,
-			ServiceType:     tenderToRollback.Type,
-			OrganizationId:  tenderToRollback.OrganizationId,
-			CreatorUsername: tenderToRollback.CreatorUsername,
-			Status:          tenderToRollback.Status,
-		},
+	if err := in.Precondition.check(latest.Version, latest.UpdatedAt); err != nil {
+		return e.Tender{}, err
+	}
+
+	// Create rollback version and record it in audit log
+	var t e.Tender
+	err = inTx(ctx, s.tx, "TenderService.Rollback", func(ctx context.Context) error {
+		if err := s.tenderRepo.Lock(ctx, latest.Id, latest.Version, latest.UpdatedAt); err != nil {
+			return versionError("TenderService.Rollback", "tenderRepo.Lock", err, ErrGetTender)
+		}
+
+		// Visibility isn't versioned, it is changed in place on latest version,
+		// so rollback must not bring old one back
+		var err error
+		t, err = s.tenderRepo.CreateSpecified(ctx, rt.CreateSpecifiedInput{
+			Id:      in.TenderId,
+			Version: latest.Version + 1,
+			CreateTenderInput: rt.CreateTenderInput{
+				Name:            tenderToRollback.Name,
+				Description:     tenderToRollback.Description,
+				ServiceType:     tenderToRollback.Type,
+				OrganizationId:  tenderToRollback.OrganizationId,
+				CreatorUsername: tenderToRollback.CreatorUsername,
+				Status:          tenderToRollback.Status,
+				Visibility:      latest.Visibility,
+				Deadline:        tenderToRollback.Deadline,
+			},
+		})
+		if err != nil {
+			return versionError("TenderService.Rollback", "tenderRepo.CreateSpecified", err, ErrCreateTender)
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, "TenderService.Rollback", c, tenderAuditEntry(AuditActionRollback, &latest, t)); err != nil {
+			return err
+		}
+
+		return publishTenderChange(ctx, s.outboxRepo, "TenderService.Rollback", EventTenderVersionCreated, latest, t)
 	})
 	if err != nil {
-		log.Errorf("TenderService.Rollback - tenderRepo.CreateSpecified: %v", err)
-		return e.Tender{}, ErrCreateTender
+		return e.Tender{}, err
 	}
 
 	return t, nil
 }
 
 func (s *TenderService) GetTender(ctx context.Context, tenderId uuid.UUID, username string) (e.Tender, error) {
-	// Check if user exists
-	user, err := s.employeeRepo.GetByUsername(ctx, username)
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.GetTender", username)
 	if err != nil {
-		if errors.Is(err, repoerrors.ErrNotFound) {
-			return e.Tender{}, ErrUsername
-		}
-		log.Errorf("TenderService.GetTender - employeeRepo.GetByUsername: %v", err)
-		return e.Tender{}, ErrGetEmployeeByUsername
+		return e.Tender{}, err
 	}
 
 	// Check if tender exists
@@ -280,14 +431,194 @@ func (s *TenderService) GetTender(ctx context.Context, tenderId uuid.UUID, usern
 	}
 
 	// Check rights
-	isResponsible, err := s.employeeRepo.IsResponsible(ctx, tender.OrganizationId, user.Id)
+	if err := checkDecision(s.authz.CanViewTender(ctx, c.actor, tender)); err != nil {
+		return e.Tender{}, err
+	}
+
+	return tender, nil
+}
+
+func (s *TenderService) ChangeVisibility(ctx context.Context, in ChangeTenderVisibilityInput) (e.Tender, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.ChangeVisibility", in.Username)
 	if err != nil {
-		log.Errorf("TenderService.GetTender - employeeRepo.IsResponsible: %v", err)
-		return e.Tender{}, ErrCheckResponsibility
+		return e.Tender{}, err
 	}
-	if !isResponsible {
-		return e.Tender{}, ErrForbidden
+
+	// Check if tender exists
+	tender, err := s.tenderRepo.Get(ctx, in.TenderId, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.Tender{}, ErrNotFoundTender
+		}
+		log.Errorf("TenderService.ChangeVisibility - tenderRepo.Get: %v", err)
+		return e.Tender{}, ErrGetTender
 	}
 
-	return tender, nil
+	// Check rights
+	if err := checkDecision(s.authz.CanPublishTender(ctx, c.actor, tender)); err != nil {
+		return e.Tender{}, err
+	}
+
+	// Change visibility and record it in audit log
+	var t e.Tender
+	err = inTx(ctx, s.tx, "TenderService.ChangeVisibility", func(ctx context.Context) error {
+		var err error
+		t, err = s.tenderRepo.ChangeVisibility(ctx, in.TenderId, in.Visibility)
+		if err != nil {
+			if errors.Is(err, repoerrors.ErrNotFound) {
+				return ErrNotFoundTender
+			}
+			log.Errorf("TenderService.ChangeVisibility - tenderRepo.ChangeVisibility: %v", err)
+			return ErrGetTender
+		}
+
+		return recordAudit(ctx, s.auditRepo, "TenderService.ChangeVisibility", c, tenderAuditEntry(AuditActionChangeVisibility, &tender, t))
+	})
+	if err != nil {
+		return e.Tender{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TenderService) Invite(ctx context.Context, in InviteToTenderInput) (e.TenderInvitation, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.Invite", in.Username)
+	if err != nil {
+		return e.TenderInvitation{}, err
+	}
+
+	// Check if tender exists
+	tender, err := s.tenderRepo.Get(ctx, in.TenderId, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.TenderInvitation{}, ErrNotFoundTender
+		}
+		log.Errorf("TenderService.Invite - tenderRepo.Get: %v", err)
+		return e.TenderInvitation{}, ErrGetTender
+	}
+
+	// Check rights
+	if err := checkDecision(s.authz.CanEditTender(ctx, c.actor, tender)); err != nil {
+		return e.TenderInvitation{}, err
+	}
+
+	// Create invitation
+	invitation, err := s.invitationRepo.Create(ctx, rt.CreateInvitationInput{
+		TenderId:       in.TenderId,
+		OrganizationId: in.OrganizationId,
+		UserId:         in.UserId,
+	})
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrAlreadyExists) {
+			return e.TenderInvitation{}, ErrInvitationExists
+		}
+		if errors.Is(err, repoerrors.ErrInvalidReference) {
+			return e.TenderInvitation{}, ErrInvitee
+		}
+		log.Errorf("TenderService.Invite - invitationRepo.Create: %v", err)
+		return e.TenderInvitation{}, ErrCreateInvitation
+	}
+
+	return invitation, nil
+}
+
+func (s *TenderService) GetInvitations(ctx context.Context, tenderId uuid.UUID, username string) ([]e.TenderInvitation, error) {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.GetInvitations", username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if tender exists
+	tender, err := s.tenderRepo.Get(ctx, tenderId, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return nil, ErrNotFoundTender
+		}
+		log.Errorf("TenderService.GetInvitations - tenderRepo.Get: %v", err)
+		return nil, ErrGetTender
+	}
+
+	// Check rights
+	if err := checkDecision(s.authz.CanViewTender(ctx, c.actor, tender)); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.GetByTender(ctx, tenderId)
+	if err != nil {
+		log.Errorf("TenderService.GetInvitations - invitationRepo.GetByTender: %v", err)
+		return nil, ErrGetInvitations
+	}
+
+	return invitations, nil
+}
+
+func (s *TenderService) RevokeInvitation(ctx context.Context, in RevokeInvitationInput) error {
+	// Check if caller exists
+	c, err := resolveCaller(ctx, s.employeeRepo, "TenderService.RevokeInvitation", in.Username)
+	if err != nil {
+		return err
+	}
+
+	// Check if tender exists
+	tender, err := s.tenderRepo.Get(ctx, in.TenderId, rt.VersionLatest)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundTender
+		}
+		log.Errorf("TenderService.RevokeInvitation - tenderRepo.Get: %v", err)
+		return ErrGetTender
+	}
+
+	// Check rights
+	if err := checkDecision(s.authz.CanEditTender(ctx, c.actor, tender)); err != nil {
+		return err
+	}
+
+	if err := s.invitationRepo.Delete(ctx, in.TenderId, in.InvitationId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundInvitation
+		}
+		log.Errorf("TenderService.RevokeInvitation - invitationRepo.Delete: %v", err)
+		return ErrDeleteInvitation
+	}
+
+	return nil
+}
+
+func (s *TenderService) GetInvitedTenders(ctx context.Context, in GetByUsernameInput) ([]e.Tender, error) {
+	user, err := s.employeeRepo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return nil, ErrUsername
+		}
+		log.Errorf("TenderService.GetInvitedTenders - employeeRepo.GetByUsername: %v", err)
+		return nil, ErrGetEmployeeByUsername
+	}
+	if !user.IsActive {
+		return nil, ErrEmployeeDeactivated
+	}
+
+	tenders, err := s.tenderRepo.GetInvitedTenders(ctx, rt.GetInvitedTendersInput{
+		Limit:  in.Limit,
+		Offset: in.Offset,
+		UserId: user.Id,
+	})
+	if err != nil {
+		log.Errorf("TenderService.GetInvitedTenders - tenderRepo.GetInvitedTenders: %v", err)
+		return nil, ErrGetInvitedTenders
+	}
+
+	return tenders, nil
+}
+
+// utcTime normalizes time for TIMESTAMP columns, which keep no time zone
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
 }
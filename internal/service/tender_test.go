@@ -0,0 +1,875 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/identity"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyContext authenticates request with API key of organization
+func apiKeyContext(orgId uuid.UUID, scopes ...string) context.Context {
+	return identity.WithService(context.Background(), identity.Service{
+		KeyId:          uuid.New(),
+		OrganizationId: orgId,
+		Name:           "ci",
+		Prefix:         "ci0000",
+		Scopes:         scopes,
+	})
+}
+
+func TestTenderServiceCreateTender(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		ctx     func(f *fixture) context.Context
+		setup   func(f *fixture)
+		in      func(f *fixture) CreateTenderInput
+		wantErr error
+		check   func(t *testing.T, f *fixture, tender e.Tender)
+	}{
+		{
+			name: "public by default",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{Name: "New", OrganizationId: f.orgId, CreatorUsername: f.admin.Username, Deadline: &future}
+			},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if tender.Visibility != VisibilityPublic {
+					t.Errorf("Visibility = %q, want %q", tender.Visibility, VisibilityPublic)
+				}
+				if tender.Version != 1 || tender.Status != "Created" {
+					t.Errorf("Version, Status = %d, %q, want 1, Created", tender.Version, tender.Status)
+				}
+				if tender.Deadline.Location() != time.UTC {
+					t.Errorf("Deadline location = %v, want UTC", tender.Deadline.Location())
+				}
+				if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionCreate {
+					t.Errorf("audit = %v, want one create entry", f.audit.entries)
+				}
+			},
+		},
+		{
+			name: "keeps visibility",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.admin.Username, Visibility: VisibilityInternal}
+			},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if tender.Visibility != VisibilityInternal {
+					t.Errorf("Visibility = %q, want %q", tender.Visibility, VisibilityInternal)
+				}
+			},
+		},
+		{
+			name: "API key is creator",
+			ctx:  func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersWrite) },
+			in:   func(f *fixture) CreateTenderInput { return CreateTenderInput{OrganizationId: f.orgId} },
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if tender.CreatorUsername != "api-key:ci0000" {
+					t.Errorf("CreatorUsername = %q, want api-key:ci0000", tender.CreatorUsername)
+				}
+			},
+		},
+		{
+			name:    "API key without scope",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersRead) },
+			in:      func(f *fixture) CreateTenderInput { return CreateTenderInput{OrganizationId: f.orgId} },
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "unknown username",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: "ghost"}
+			},
+			wantErr: ErrUsername,
+		},
+		{
+			name:  "employee lookup fails",
+			setup: func(f *fixture) { f.employees.err = errRepo },
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: "admin"}
+			},
+			wantErr: ErrGetEmployeeByUsername,
+		},
+		{
+			name: "deactivated employee",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.inactive.Username}
+			},
+			wantErr: ErrEmployeeDeactivated,
+		},
+		{
+			name: "not responsible",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.outsider.Username}
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "viewer can't create",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.viewer.Username}
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:  "roles lookup fails",
+			setup: func(f *fixture) { f.employees.rolesErr = errRepo },
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.admin.Username}
+			},
+			wantErr: ErrCheckResponsibility,
+		},
+		{
+			name: "deadline in past",
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.admin.Username, Deadline: &past}
+			},
+			wantErr: ErrTenderDeadline,
+		},
+		{
+			name:  "repo fails",
+			setup: func(f *fixture) { f.tenders.err = errRepo },
+			in: func(f *fixture) CreateTenderInput {
+				return CreateTenderInput{OrganizationId: f.orgId, CreatorUsername: f.admin.Username}
+			},
+			wantErr: ErrCreateTender,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			tender, err := f.tenderService().CreateTender(ctx, tt.in(f))
+			checkErr(t, err, tt.wantErr)
+			if tt.check != nil {
+				tt.check(t, f, tender)
+			}
+		})
+	}
+}
+
+func TestTenderServiceGetTendersByUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		wantErr  error
+		wantLen  int
+	}{
+		{name: "own tenders", username: "admin", wantLen: 1},
+		{name: "no tenders", username: "viewer", wantLen: 0},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{
+			name:     "employee lookup fails",
+			setup:    func(f *fixture) { f.employees.err = errRepo },
+			username: "admin",
+			wantErr:  ErrGetEmployeeByUsername,
+		},
+		{
+			name:     "repo fails",
+			setup:    func(f *fixture) { f.tenders.err = errRepo },
+			username: "admin",
+			wantErr:  ErrGetTendersByUsername,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			tenders, err := f.tenderService().GetTendersByUsername(context.Background(), GetByUsernameInput{Limit: 5, Username: tt.username})
+			checkErr(t, err, tt.wantErr)
+			if len(tenders) != tt.wantLen {
+				t.Errorf("len(tenders) = %d, want %d", len(tenders), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestTenderServiceGetTenders(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(f *fixture)
+		username     string
+		wantErr      error
+		wantViewerId func(f *fixture) uuid.UUID
+	}{
+		{name: "anonymous", wantViewerId: func(f *fixture) uuid.UUID { return uuid.Nil }},
+		{name: "viewer", username: "outsider", wantViewerId: func(f *fixture) uuid.UUID { return f.outsider.Id }},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{
+			name:     "employee lookup fails",
+			setup:    func(f *fixture) { f.employees.err = errRepo },
+			username: "admin",
+			wantErr:  ErrGetEmployeeByUsername,
+		},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, wantErr: ErrGetTenders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			in := GetTendersInput{Limit: 5, Offset: 1, ServiceType: []string{"Construction"}, Username: tt.username}
+			_, err := f.tenderService().GetTenders(context.Background(), in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantViewerId == nil {
+				return
+			}
+			got := f.tenders.published
+			if got.ViewerId != tt.wantViewerId(f) || got.Limit != 5 || got.Offset != 1 || !slices.Equal(got.ServiceType, in.ServiceType) {
+				t.Errorf("GetPublishedTenders input = %+v", got)
+			}
+		})
+	}
+}
+
+func TestTenderServiceChangeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        func(f *fixture) context.Context
+		setup      func(f *fixture)
+		username   string
+		status     string
+		pre        Precondition
+		missing    bool
+		wantErr    error
+		wantEvents []string
+	}{
+		{
+			name:       "close",
+			username:   "admin",
+			status:     "Closed",
+			wantEvents: []string{EventTenderStatusChanged},
+		},
+		{
+			name:       "publish",
+			setup:      func(f *fixture) { f.tenders.versions[f.tender.Id][0].Status = "Created" },
+			username:   "admin",
+			status:     "Published",
+			wantEvents: []string{EventTenderStatusChanged, EventTenderPublished},
+		},
+		{name: "same status", username: "admin", status: "Published", wantEvents: []string{}},
+		{name: "matching precondition", username: "admin", status: "Closed", pre: Precondition{Version: 1}, wantEvents: []string{EventTenderStatusChanged}},
+		{
+			name:       "API key",
+			ctx:        func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersWrite) },
+			status:     "Closed",
+			wantEvents: []string{EventTenderStatusChanged},
+		},
+		{
+			name:    "API key of other organization",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.rivalOrg, authz.ScopeTendersWrite) },
+			status:  "Closed",
+			wantErr: ErrForbidden,
+		},
+		{name: "unknown username", username: "ghost", status: "Closed", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", status: "Closed", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", status: "Closed", wantErr: ErrForbidden},
+		{name: "viewer can't publish", username: "viewer", status: "Closed", wantErr: ErrForbidden},
+		{name: "tender not found", username: "admin", status: "Closed", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", status: "Closed", wantErr: ErrGetTender},
+		{name: "stale precondition", username: "admin", status: "Closed", pre: Precondition{Version: 2}, wantErr: ErrVersionMismatch},
+		{
+			name:     "lost race",
+			setup:    func(f *fixture) { f.tenders.lockErr = repoerrors.ErrVersionConflict },
+			username: "admin",
+			status:   "Closed",
+			wantErr:  ErrConcurrentUpdate,
+		},
+		{
+			name:     "lost race with precondition",
+			setup:    func(f *fixture) { f.tenders.lockErr = repoerrors.ErrVersionConflict },
+			username: "admin",
+			status:   "Closed",
+			pre:      Precondition{Version: 1},
+			wantErr:  ErrVersionMismatch,
+		},
+		{
+			name:     "lock fails",
+			setup:    func(f *fixture) { f.tenders.lockErr = errRepo },
+			username: "admin",
+			status:   "Closed",
+			wantErr:  ErrGetTender,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tenderId := f.tender.Id
+			if tt.missing {
+				tenderId = uuid.New()
+			}
+
+			tender, err := f.tenderService().ChangeStatus(ctx, ChangeTenderStatusInput{
+				TenderId:     tenderId,
+				Status:       tt.status,
+				Username:     tt.username,
+				Precondition: tt.pre,
+			})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if tender.Status != tt.status || tender.Version != 1 {
+				t.Errorf("Status, Version = %q, %d, want %q, 1", tender.Status, tender.Version, tt.status)
+			}
+			if got := f.outbox.types(); !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestTenderServiceEdit(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		setup   func(f *fixture)
+		in      EditTenderInput
+		missing bool
+		wantErr error
+		check   func(t *testing.T, f *fixture, tender e.Tender)
+	}{
+		{
+			name: "all fields",
+			in:   EditTenderInput{Username: "admin", Name: "Renamed", Description: "Changed", ServiceType: "Delivery", Deadline: &future},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if tender.Name != "Renamed" || tender.Description != "Changed" || tender.Type != "Delivery" {
+					t.Errorf("tender = %+v, want all fields edited", tender)
+				}
+				if tender.Deadline == nil || !tender.Deadline.Equal(future) {
+					t.Errorf("Deadline = %v, want %v", tender.Deadline, future)
+				}
+			},
+		},
+		{
+			name: "only name",
+			in:   EditTenderInput{Username: "admin", Name: "Renamed"},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				want := f.tender
+				want.Name = "Renamed"
+				if !sameTender(tender, want) {
+					t.Errorf("tender = %+v, want %+v", tender, want)
+				}
+			},
+		},
+		{
+			name: "empty edit keeps everything",
+			setup: func(f *fixture) {
+				f.tenders.versions[f.tender.Id][0].Deadline = &future
+				f.tender.Deadline = &future
+			},
+			in: EditTenderInput{Username: "admin"},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if !sameTender(tender, f.tender) {
+					t.Errorf("tender = %+v, want %+v", tender, f.tender)
+				}
+			},
+		},
+		{
+			name: "keeps status and visibility",
+			setup: func(f *fixture) {
+				f.tenders.versions[f.tender.Id][0].Status = "Closed"
+				f.tenders.versions[f.tender.Id][0].Visibility = VisibilityInviteOnly
+			},
+			in: EditTenderInput{Username: "admin", Description: "Changed"},
+			check: func(t *testing.T, f *fixture, tender e.Tender) {
+				if tender.Status != "Closed" || tender.Visibility != VisibilityInviteOnly {
+					t.Errorf("Status, Visibility = %q, %q, want Closed, InviteOnly", tender.Status, tender.Visibility)
+				}
+			},
+		},
+		{name: "unknown username", in: EditTenderInput{Username: "ghost"}, wantErr: ErrUsername},
+		{name: "deactivated employee", in: EditTenderInput{Username: "inactive"}, wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", in: EditTenderInput{Username: "outsider"}, wantErr: ErrForbidden},
+		{name: "viewer can't edit", in: EditTenderInput{Username: "viewer"}, wantErr: ErrForbidden},
+		{name: "tender not found", in: EditTenderInput{Username: "admin"}, missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, in: EditTenderInput{Username: "admin"}, wantErr: ErrGetTender},
+		{name: "deadline in past", in: EditTenderInput{Username: "admin", Deadline: &past}, wantErr: ErrTenderDeadline},
+		{
+			name:    "stale precondition",
+			in:      EditTenderInput{Username: "admin", Precondition: Precondition{UpdatedAt: time.Now().Add(time.Minute)}},
+			wantErr: ErrVersionMismatch,
+		},
+		{
+			name:    "version taken",
+			setup:   func(f *fixture) { f.tenders.createErr = repoerrors.ErrVersionConflict },
+			in:      EditTenderInput{Username: "admin"},
+			wantErr: ErrConcurrentUpdate,
+		},
+		{
+			name:    "create fails",
+			setup:   func(f *fixture) { f.tenders.createErr = errRepo },
+			in:      EditTenderInput{Username: "admin"},
+			wantErr: ErrCreateTender,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tt.in.TenderId = f.tender.Id
+			if tt.missing {
+				tt.in.TenderId = uuid.New()
+			}
+
+			tender, err := f.tenderService().Edit(context.Background(), tt.in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				if n := len(f.tenders.versions[f.tender.Id]); n != 1 {
+					t.Errorf("versions = %d, want 1", n)
+				}
+				return
+			}
+			if tender.Version != 2 {
+				t.Errorf("Version = %d, want 2", tender.Version)
+			}
+			if got := f.outbox.types(); !slices.Equal(got, []string{EventTenderVersionCreated}) {
+				t.Errorf("events = %v, want [%s]", got, EventTenderVersionCreated)
+			}
+			tt.check(t, f, tender)
+		})
+	}
+}
+
+func TestTenderServiceRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(f *fixture)
+		in      RollbackTenderInput
+		missing bool
+		wantErr error
+	}{
+		{name: "first version", in: RollbackTenderInput{Version: 1, Username: "admin"}},
+		{name: "latest version", in: RollbackTenderInput{Version: 3, Username: "admin"}},
+		{name: "matching precondition", in: RollbackTenderInput{Version: 2, Username: "admin", Precondition: Precondition{Version: 3}}},
+		{
+			name: "keeps current visibility",
+			setup: func(f *fixture) {
+				if _, err := f.tenders.ChangeVisibility(context.Background(), f.tender.Id, VisibilityInternal); err != nil {
+					panic(err)
+				}
+			},
+			in: RollbackTenderInput{Version: 1, Username: "admin"},
+		},
+		{name: "unknown username", in: RollbackTenderInput{Version: 1, Username: "ghost"}, wantErr: ErrUsername},
+		{name: "deactivated employee", in: RollbackTenderInput{Version: 1, Username: "inactive"}, wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", in: RollbackTenderInput{Version: 1, Username: "outsider"}, wantErr: ErrForbidden},
+		{name: "viewer can't roll back", in: RollbackTenderInput{Version: 1, Username: "viewer"}, wantErr: ErrForbidden},
+		{name: "version not found", in: RollbackTenderInput{Version: 4, Username: "admin"}, wantErr: ErrNotFoundTender},
+		{name: "tender not found", in: RollbackTenderInput{Version: 1, Username: "admin"}, missing: true, wantErr: ErrNotFoundTender},
+		{
+			name:    "repo fails",
+			setup:   func(f *fixture) { f.tenders.err = errRepo },
+			in:      RollbackTenderInput{Version: 1, Username: "admin"},
+			wantErr: ErrGetTender,
+		},
+		{
+			name:    "stale precondition",
+			in:      RollbackTenderInput{Version: 1, Username: "admin", Precondition: Precondition{Version: 2}},
+			wantErr: ErrVersionMismatch,
+		},
+		{
+			name:    "lost race",
+			setup:   func(f *fixture) { f.tenders.lockErr = repoerrors.ErrVersionConflict },
+			in:      RollbackTenderInput{Version: 1, Username: "admin"},
+			wantErr: ErrConcurrentUpdate,
+		},
+		{
+			name:    "create fails",
+			setup:   func(f *fixture) { f.tenders.createErr = errRepo },
+			in:      RollbackTenderInput{Version: 1, Username: "admin"},
+			wantErr: ErrCreateTender,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			edited := f.tender
+			edited.Name = "Second"
+			f.tenders.add(edited)
+			edited.Name = "Third"
+			f.tenders.add(edited)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tt.in.TenderId = f.tender.Id
+			if tt.missing {
+				tt.in.TenderId = uuid.New()
+			}
+			latest, _ := f.tenders.latest(f.tender.Id)
+
+			tender, err := f.tenderService().Rollback(context.Background(), tt.in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if tender.Version != 4 {
+				t.Errorf("Version = %d, want 4", tender.Version)
+			}
+			target := f.tenders.versions[f.tender.Id][tt.in.Version-1]
+			target.Visibility = latest.Visibility
+			if !sameTender(tender, target) {
+				t.Errorf("tender = %+v, want %+v", tender, target)
+			}
+			if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionRollback {
+				t.Errorf("audit = %v, want one rollback entry", f.audit.entries)
+			}
+		})
+	}
+}
+
+func TestTenderServiceGetTender(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      func(f *fixture) context.Context
+		setup    func(f *fixture)
+		username string
+		missing  bool
+		wantErr  error
+	}{
+		{name: "admin", username: "admin"},
+		{name: "viewer", username: "viewer"},
+		{name: "API key", ctx: func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeTendersRead) }},
+		{
+			name:    "API key without scope",
+			ctx:     func(f *fixture) context.Context { return apiKeyContext(f.orgId, authz.ScopeBidsRead) },
+			wantErr: ErrForbidden,
+		},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", wantErr: ErrForbidden},
+		{name: "roles lookup fails", setup: func(f *fixture) { f.employees.rolesErr = errRepo }, username: "admin", wantErr: ErrCheckResponsibility},
+		{name: "tender not found", username: "admin", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", wantErr: ErrGetTender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(f)
+			}
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tenderId := f.tender.Id
+			if tt.missing {
+				tenderId = uuid.New()
+			}
+
+			tender, err := f.tenderService().GetTender(ctx, tenderId, tt.username)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && tender.Id != f.tender.Id {
+				t.Errorf("Id = %v, want %v", tender.Id, f.tender.Id)
+			}
+		})
+	}
+}
+
+func TestTenderServiceChangeVisibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		missing  bool
+		wantErr  error
+	}{
+		{name: "admin", username: "admin"},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", wantErr: ErrForbidden},
+		{name: "viewer can't publish", username: "viewer", wantErr: ErrForbidden},
+		{name: "tender not found", username: "admin", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", wantErr: ErrGetTender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tenderId := f.tender.Id
+			if tt.missing {
+				tenderId = uuid.New()
+			}
+
+			tender, err := f.tenderService().ChangeVisibility(context.Background(), ChangeTenderVisibilityInput{
+				TenderId:   tenderId,
+				Visibility: VisibilityInternal,
+				Username:   tt.username,
+			})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			if tender.Visibility != VisibilityInternal || tender.Version != 1 {
+				t.Errorf("Visibility, Version = %q, %d, want Internal, 1", tender.Visibility, tender.Version)
+			}
+			if len(f.audit.entries) != 1 || f.audit.entries[0].Action != AuditActionChangeVisibility {
+				t.Errorf("audit = %v, want one change_visibility entry", f.audit.entries)
+			}
+		})
+	}
+}
+
+func TestTenderServiceInvite(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		missing  bool
+		wantErr  error
+	}{
+		{name: "admin", username: "admin"},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", wantErr: ErrForbidden},
+		{name: "viewer can't invite", username: "viewer", wantErr: ErrForbidden},
+		{name: "tender not found", username: "admin", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", wantErr: ErrGetTender},
+		{name: "already invited", setup: func(f *fixture) { f.invitations.err = repoerrors.ErrAlreadyExists }, username: "admin", wantErr: ErrInvitationExists},
+		{name: "unknown invitee", setup: func(f *fixture) { f.invitations.err = repoerrors.ErrInvalidReference }, username: "admin", wantErr: ErrInvitee},
+		{name: "invitation repo fails", setup: func(f *fixture) { f.invitations.err = errRepo }, username: "admin", wantErr: ErrCreateInvitation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tenderId := f.tender.Id
+			if tt.missing {
+				tenderId = uuid.New()
+			}
+
+			invitation, err := f.tenderService().Invite(context.Background(), InviteToTenderInput{
+				TenderId: tenderId,
+				Username: tt.username,
+				UserId:   uuid.NullUUID{UUID: f.outsider.Id, Valid: true},
+			})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && (invitation.TenderId != f.tender.Id || invitation.UserId.UUID != f.outsider.Id) {
+				t.Errorf("invitation = %+v, want outsider invited to tender", invitation)
+			}
+		})
+	}
+}
+
+func TestTenderServiceGetInvitations(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		missing  bool
+		wantErr  error
+		wantLen  int
+	}{
+		{name: "viewer", username: "viewer", wantLen: 1},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", wantErr: ErrForbidden},
+		{name: "tender not found", username: "admin", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", wantErr: ErrGetTender},
+		{name: "invitation repo fails", setup: func(f *fixture) { f.invitations.err = errRepo }, username: "admin", wantErr: ErrGetInvitations},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			f.invitations.Create(context.Background(), rt.CreateInvitationInput{
+				TenderId: f.tender.Id,
+				UserId:   uuid.NullUUID{UUID: f.outsider.Id, Valid: true},
+			})
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			tenderId := f.tender.Id
+			if tt.missing {
+				tenderId = uuid.New()
+			}
+
+			invitations, err := f.tenderService().GetInvitations(context.Background(), tenderId, tt.username)
+			checkErr(t, err, tt.wantErr)
+			if len(invitations) != tt.wantLen {
+				t.Errorf("len(invitations) = %d, want %d", len(invitations), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestTenderServiceRevokeInvitation(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(f *fixture)
+		username      string
+		missing       bool
+		unknownInvite bool
+		wantErr       error
+	}{
+		{name: "admin", username: "admin"},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "not responsible", username: "outsider", wantErr: ErrForbidden},
+		{name: "viewer can't revoke", username: "viewer", wantErr: ErrForbidden},
+		{name: "tender not found", username: "admin", missing: true, wantErr: ErrNotFoundTender},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "admin", wantErr: ErrGetTender},
+		{name: "invitation not found", username: "admin", unknownInvite: true, wantErr: ErrNotFoundInvitation},
+		{name: "invitation repo fails", setup: func(f *fixture) { f.invitations.err = errRepo }, username: "admin", wantErr: ErrDeleteInvitation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			invitation, _ := f.invitations.Create(context.Background(), rt.CreateInvitationInput{
+				TenderId: f.tender.Id,
+				UserId:   uuid.NullUUID{UUID: f.outsider.Id, Valid: true},
+			})
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			in := RevokeInvitationInput{TenderId: f.tender.Id, InvitationId: invitation.Id, Username: tt.username}
+			if tt.missing {
+				in.TenderId = uuid.New()
+			}
+			if tt.unknownInvite {
+				in.InvitationId = uuid.New()
+			}
+
+			err := f.tenderService().RevokeInvitation(context.Background(), in)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr == nil && len(f.invitations.invitations) != 0 {
+				t.Errorf("invitations = %v, want none", f.invitations.invitations)
+			}
+		})
+	}
+}
+
+func TestTenderServiceGetInvitedTenders(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *fixture)
+		username string
+		wantErr  error
+	}{
+		{name: "outsider", username: "outsider"},
+		{name: "unknown username", username: "ghost", wantErr: ErrUsername},
+		{name: "deactivated employee", username: "inactive", wantErr: ErrEmployeeDeactivated},
+		{name: "employee lookup fails", setup: func(f *fixture) { f.employees.err = errRepo }, username: "outsider", wantErr: ErrGetEmployeeByUsername},
+		{name: "repo fails", setup: func(f *fixture) { f.tenders.err = errRepo }, username: "outsider", wantErr: ErrGetInvitedTenders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			_, err := f.tenderService().GetInvitedTenders(context.Background(), GetByUsernameInput{Limit: 5, Offset: 2, Username: tt.username})
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			want := rt.GetInvitedTendersInput{Limit: 5, Offset: 2, UserId: f.outsider.Id}
+			if f.tenders.invited != want {
+				t.Errorf("GetInvitedTenders input = %+v, want %+v", f.tenders.invited, want)
+			}
+		})
+	}
+}
+
+// TestTenderRollbackVersion checks that any sequence of edits and rollbacks
+// keeps versions dense: rollback always creates latest+1 with fields of target
+func TestTenderRollbackVersion(t *testing.T) {
+	property := func(seed int64) bool {
+		rnd := rand.New(rand.NewSource(seed))
+		f := newFixture()
+		s := f.tenderService()
+		ctx := context.Background()
+
+		steps := 1 + rnd.Intn(10)
+		for step := 0; step < steps; step++ {
+			latest, _ := f.tenders.latest(f.tender.Id)
+
+			var got e.Tender
+			var target e.Tender
+			var err error
+			if rnd.Intn(2) == 0 {
+				target = latest
+				target.Name = uuid.NewString()
+				got, err = s.Edit(ctx, EditTenderInput{TenderId: f.tender.Id, Username: "admin", Name: target.Name})
+			} else {
+				target = f.tenders.versions[f.tender.Id][rnd.Intn(latest.Version)]
+				got, err = s.Rollback(ctx, RollbackTenderInput{TenderId: f.tender.Id, Version: target.Version, Username: "admin"})
+			}
+			if err != nil {
+				t.Logf("seed %d step %d: %v", seed, step, err)
+				return false
+			}
+			if got.Version != latest.Version+1 || !sameTender(got, target) {
+				t.Logf("seed %d step %d: got %+v after version %d, want fields of %+v", seed, step, got, latest.Version, target)
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// sameTender compares tenders except version and timestamps
+func sameTender(a, b e.Tender) bool {
+	sameDeadline := a.Deadline == nil && b.Deadline == nil ||
+		a.Deadline != nil && b.Deadline != nil && a.Deadline.Equal(*b.Deadline)
+	return a.Id == b.Id && a.Name == b.Name && a.Description == b.Description && a.Type == b.Type &&
+		a.Status == b.Status && a.Visibility == b.Visibility && a.OrganizationId == b.OrganizationId &&
+		a.CreatorUsername == b.CreatorUsername && sameDeadline
+}
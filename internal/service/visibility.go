@@ -0,0 +1,39 @@
+package service
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VisibilityPublic     = "Public"
+	VisibilityInviteOnly = "InviteOnly"
+	VisibilityInternal   = "Internal"
+)
+
+// isTenderVisible reports whether employee may see (and bid on) the tender.
+// Responsibles of the tender organization always see it
+func isTenderVisible(ctx context.Context, eRepo repo.Employee, iRepo repo.Invitation, tender e.Tender, userId uuid.UUID) (bool, error) {
+	if tender.Visibility == VisibilityPublic {
+		return true, nil
+	}
+
+	isResponsible, err := eRepo.IsResponsible(ctx, tender.OrganizationId, userId)
+	if err != nil {
+		return false, fmt.Errorf("employeeRepo.IsResponsible: %w", err)
+	}
+	if isResponsible || tender.Visibility == VisibilityInternal {
+		return isResponsible, nil
+	}
+
+	isInvited, err := iRepo.IsInvited(ctx, tender.Id, userId)
+	if err != nil {
+		return false, fmt.Errorf("invitationRepo.IsInvited: %w", err)
+	}
+
+	return isInvited, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"app/internal/authz"
+	e "app/internal/entity"
+	"app/internal/repo"
+	"app/internal/repo/repoerrors"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Generated secrets look like whsec_<random>
+const webhookSecretType = "whsec"
+
+type WebhookService struct {
+	webhookRepo      repo.Webhook
+	employeeRepo     repo.Employee
+	organizationRepo repo.Organization
+	authz            *authz.Authorizer
+}
+
+func NewWebhookService(wRepo repo.Webhook, eRepo repo.Employee, oRepo repo.Organization, az *authz.Authorizer) *WebhookService {
+	return &WebhookService{
+		webhookRepo:      wRepo,
+		employeeRepo:     eRepo,
+		organizationRepo: oRepo,
+		authz:            az,
+	}
+}
+
+// CreateWebhook subscribes organization to events. Secret is generated if
+// not given and is returned only once
+func (s *WebhookService) CreateWebhook(ctx context.Context, in CreateWebhookInput) (e.WebhookSubscription, string, error) {
+	// Check if organization exists and user can manage it
+	c, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "WebhookService.CreateWebhook",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return e.WebhookSubscription{}, "", err
+	}
+
+	secret := in.Secret
+	if secret == "" {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			log.Errorf("WebhookService.CreateWebhook - newWebhookSecret: %v", err)
+			return e.WebhookSubscription{}, "", ErrCreateWebhook
+		}
+	}
+
+	sub, err := s.webhookRepo.Create(ctx, rt.CreateWebhookInput{
+		OrganizationId: in.OrganizationId,
+		URL:            in.URL,
+		EventTypes:     in.EventTypes,
+		Secret:         secret,
+		CreatedBy:      c.employee.Id,
+	})
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrInvalidReference) {
+			return e.WebhookSubscription{}, "", ErrNotFoundOrganization
+		}
+		log.Errorf("WebhookService.CreateWebhook - webhookRepo.Create: %v", err)
+		return e.WebhookSubscription{}, "", ErrCreateWebhook
+	}
+
+	return sub, secret, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, orgId uuid.UUID, username string) ([]e.WebhookSubscription, error) {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "WebhookService.GetWebhooks",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.webhookRepo.GetByOrganization(ctx, orgId)
+	if err != nil {
+		log.Errorf("WebhookService.GetWebhooks - webhookRepo.GetByOrganization: %v", err)
+		return nil, ErrGetWebhooks
+	}
+
+	return subs, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, orgId, webhookId uuid.UUID, username string) error {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "WebhookService.DeleteWebhook",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, orgId, webhookId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundWebhook
+		}
+		log.Errorf("WebhookService.DeleteWebhook - webhookRepo.Delete: %v", err)
+		return ErrDeleteWebhook
+	}
+
+	return nil
+}
+
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, in GetWebhookDeliveriesInput) ([]e.WebhookDelivery, error) {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "WebhookService.GetWebhookDeliveries",
+		in.OrganizationId, in.Username, s.authz.CanManageOrganization)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if webhook belongs to organization
+	if err := s.checkWebhook(ctx, "GetWebhookDeliveries", in.OrganizationId, in.WebhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveries(ctx, rt.GetWebhookDeliveriesInput{
+		Limit:          in.Limit,
+		Offset:         in.Offset,
+		SubscriptionId: in.WebhookId,
+		Status:         in.Status,
+	})
+	if err != nil {
+		log.Errorf("WebhookService.GetWebhookDeliveries - webhookRepo.GetDeliveries: %v", err)
+		return nil, ErrGetWebhookDeliveries
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhook queues delivery again, dead-lettered ones included
+func (s *WebhookService) RedeliverWebhook(ctx context.Context, orgId, webhookId, deliveryId uuid.UUID, username string) (e.WebhookDelivery, error) {
+	// Check if organization exists and user can manage it
+	_, _, err := authorizeOrganization(ctx, s.employeeRepo, s.organizationRepo, "WebhookService.RedeliverWebhook",
+		orgId, username, s.authz.CanManageOrganization)
+	if err != nil {
+		return e.WebhookDelivery{}, err
+	}
+
+	// Check if webhook belongs to organization
+	if err := s.checkWebhook(ctx, "RedeliverWebhook", orgId, webhookId); err != nil {
+		return e.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.webhookRepo.Redeliver(ctx, webhookId, deliveryId)
+	if err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return e.WebhookDelivery{}, ErrNotFoundDelivery
+		}
+		log.Errorf("WebhookService.RedeliverWebhook - webhookRepo.Redeliver: %v", err)
+		return e.WebhookDelivery{}, ErrRedeliverWebhook
+	}
+
+	return delivery, nil
+}
+
+func (s *WebhookService) checkWebhook(ctx context.Context, method string, orgId, webhookId uuid.UUID) error {
+	if _, err := s.webhookRepo.Get(ctx, orgId, webhookId); err != nil {
+		if errors.Is(err, repoerrors.ErrNotFound) {
+			return ErrNotFoundWebhook
+		}
+		log.Errorf("WebhookService.%s - webhookRepo.Get: %v", method, err)
+		return ErrGetWebhooks
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretType + "_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,169 @@
+// Package stream fans domain events out to live subscribers and keeps bounded
+// history of recent events, so reconnecting subscribers can resume
+package stream
+
+import (
+	e "app/internal/entity"
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("stream broker is closed")
+
+const (
+	defaultHistorySize = 1000
+	defaultBufferSize  = 64
+)
+
+// Broker publishes events to subscribers of this instance. With several
+// instances it is fed by Follower, single instance may use it as outbox sink.
+// Events are held in memory only, after restart subscribers resync
+type Broker struct {
+	mu      sync.Mutex
+	history []e.OutboxEvent
+	next    int
+	full    bool
+	subs    map[*Subscription]struct{}
+	closed  bool
+
+	bufferSize int
+}
+
+func NewBroker(opts ...Option) *Broker {
+	b := &Broker{
+		history:    make([]e.OutboxEvent, defaultHistorySize),
+		subs:       make(map[*Subscription]struct{}),
+		bufferSize: defaultBufferSize,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+func (b *Broker) Name() string {
+	return "stream"
+}
+
+func (b *Broker) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	b.Publish(event)
+	return nil
+}
+
+// Publish appends event to history and sends it to subscribers. Subscriber
+// which doesn't keep up is dropped, it can resume from history
+func (b *Broker) Publish(event e.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.history[b.next] = event
+	b.next = (b.next + 1) % len(b.history)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.c <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// Subscribe returns new subscription and events published after lastEventId.
+// Resumed is false if lastEventId is set but not in history anymore, then
+// subscriber may have missed events
+func (b *Broker) Subscribe(lastEventId string) (sub *Subscription, backlog []e.OutboxEvent, resumed bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, false, ErrClosed
+	}
+
+	resumed = true
+	if lastEventId != "" {
+		backlog, resumed = b.since(lastEventId)
+	}
+
+	c := make(chan e.OutboxEvent, b.bufferSize)
+	sub = &Subscription{C: c, c: c, broker: b}
+	b.subs[sub] = struct{}{}
+
+	return sub, backlog, resumed, nil
+}
+
+// Reset forgets history and ends all subscriptions, so subscribers resync
+// after events may have been missed
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.history)
+	b.next = 0
+	b.full = false
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+// Close ends all subscriptions, later subscriptions fail with ErrClosed
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+// since returns events after one with id in publication order
+func (b *Broker) since(id string) ([]e.OutboxEvent, bool) {
+	events := b.ordered()
+	for i, event := range events {
+		if event.Id.String() == id {
+			return events[i+1:], true
+		}
+	}
+	return nil, false
+}
+
+func (b *Broker) ordered() []e.OutboxEvent {
+	if !b.full {
+		return append([]e.OutboxEvent(nil), b.history[:b.next]...)
+	}
+	return append(append([]e.OutboxEvent(nil), b.history[b.next:]...), b.history[:b.next]...)
+}
+
+// drop must be called with mu held
+func (b *Broker) drop(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.c)
+}
+
+// Subscription receives events on C, C is closed when subscriber is dropped
+// or broker is closed
+type Subscription struct {
+	C      <-chan e.OutboxEvent
+	c      chan e.OutboxEvent
+	broker *Broker
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.drop(s)
+}
@@ -0,0 +1,73 @@
+package stream
+
+import (
+	e "app/internal/entity"
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Feed passes events added to outbox by any instance until ctx is done or it
+// fails
+type Feed interface {
+	Listen(ctx context.Context, handle func(e.OutboxEvent)) error
+}
+
+// Follower publishes events of feed to broker, so subscribers of every
+// instance get all events, not only ones outbox dispatcher of their instance
+// claimed. After feed fails it is listened again in retry
+type Follower struct {
+	broker *Broker
+	feed   Feed
+	retry  time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewFollower(broker *Broker, feed Feed, retry time.Duration) *Follower {
+	return &Follower{
+		broker: broker,
+		feed:   feed,
+		retry:  retry,
+	}
+}
+
+func (f *Follower) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.run(ctx)
+	}()
+}
+
+func (f *Follower) Stop() {
+	if f.cancel == nil {
+		return
+	}
+	f.cancel()
+	f.wg.Wait()
+}
+
+func (f *Follower) run(ctx context.Context) {
+	for {
+		err := f.feed.Listen(ctx, f.broker.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		// Events published while feed is down are missed, subscribers resync
+		log.Errorf("stream - Follower.run - Listen: %v", err)
+		f.broker.Reset()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.retry):
+		}
+	}
+}
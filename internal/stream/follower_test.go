@@ -0,0 +1,74 @@
+package stream
+
+import (
+	e "app/internal/entity"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeFeed passes events of one batch per Listen call, then fails, or blocks
+// until ctx is done when batches are over
+type fakeFeed struct {
+	batches [][]e.OutboxEvent
+	n       int
+	calls   chan int
+}
+
+func (f *fakeFeed) Listen(ctx context.Context, handle func(e.OutboxEvent)) error {
+	call := f.n
+	f.n++
+	f.calls <- call
+	if call >= len(f.batches) {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	for _, event := range f.batches[call] {
+		handle(event)
+	}
+	return errors.New("connection lost")
+}
+
+func TestFollower(t *testing.T) {
+	first := e.OutboxEvent{Id: uuid.New()}
+	second := e.OutboxEvent{Id: uuid.New()}
+
+	broker := NewBroker()
+	sub, _, _, err := broker.Subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := &fakeFeed{batches: [][]e.OutboxEvent{{first}, {second}}, calls: make(chan int, 3)}
+	f := NewFollower(broker, feed, time.Millisecond)
+	f.Start()
+	defer f.Stop()
+
+	// Feed is listened again after every failure
+	for want := range 3 {
+		select {
+		case call := <-feed.calls:
+			if call != want {
+				t.Fatalf("Listen call %d, want %d", call, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Listen isn't called again")
+		}
+	}
+
+	// Subscriber got event and was dropped when feed failed
+	if event := <-sub.C; event.Id != first.Id {
+		t.Errorf("event %v, want %v", event.Id, first.Id)
+	}
+	if _, ok := <-sub.C; ok {
+		t.Errorf("subscription isn't ended after feed failed")
+	}
+
+	// History before failure is forgotten, so resuming subscriber resyncs
+	if _, _, resumed, _ := broker.Subscribe(first.Id.String()); resumed {
+		t.Errorf("resumed from event published before feed failed")
+	}
+}
@@ -0,0 +1,19 @@
+package stream
+
+import e "app/internal/entity"
+
+type Option func(*Broker)
+
+// HistorySize is number of recent events available for resume
+func HistorySize(size int) Option {
+	return func(b *Broker) {
+		b.history = make([]e.OutboxEvent, size)
+	}
+}
+
+// BufferSize is number of events subscriber may lag behind before it is dropped
+func BufferSize(size int) Option {
+	return func(b *Broker) {
+		b.bufferSize = size
+	}
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client posts deliveries to subscription URLs
+type Client struct {
+	http *http.Client
+	now  func() time.Time
+}
+
+func NewClient(httpClient *http.Client) *Client {
+	return &Client{http: httpClient, now: time.Now}
+}
+
+// Send returns response status code (zero if there was no response).
+// Any status except 2xx is error
+func (c *Client) Send(ctx context.Context, d e.WebhookDispatch) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("webhook - Client.Send - http.NewRequest: %w", err)
+	}
+
+	timestamp := c.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tenders-webhook/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.Id.String())
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook - Client.Send - http.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain small response so connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook - Client.Send: unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
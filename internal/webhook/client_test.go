@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestClientSendSignsRequest(t *testing.T) {
+	const secret = "whsec_test_secret"
+	body := []byte(`{"id":"1","type":"bid.created","data":{}}`)
+
+	var got struct {
+		body      []byte
+		event     string
+		delivery  string
+		timestamp string
+		signature string
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.body, _ = io.ReadAll(r.Body)
+		got.event = r.Header.Get(HeaderEvent)
+		got.delivery = r.Header.Get(HeaderDelivery)
+		got.timestamp = r.Header.Get(HeaderTimestamp)
+		got.signature = r.Header.Get(HeaderSignature)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d := newDispatch(srv.URL, secret, body)
+	code, err := NewClient(srv.Client()).Send(context.Background(), d)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Fatalf("status code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if string(got.body) != string(body) {
+		t.Errorf("body = %s, want %s", got.body, body)
+	}
+	if got.event != "bid.created" || got.delivery != d.Id.String() {
+		t.Errorf("event/delivery headers = %q/%q", got.event, got.delivery)
+	}
+	timestamp, err := strconv.ParseInt(got.timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header %q: %v", got.timestamp, err)
+	}
+	if !Verify(secret, timestamp, got.body, got.signature) {
+		t.Errorf("signature %q doesn't verify", got.signature)
+	}
+	if Verify("other_secret", timestamp, got.body, got.signature) {
+		t.Errorf("signature verifies with wrong secret")
+	}
+}
+
+func TestClientSendRejectsNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	code, err := NewClient(srv.Client()).Send(context.Background(), newDispatch(srv.URL, "secret", []byte(`{}`)))
+	if err == nil {
+		t.Fatal("Send: want error for 500 response")
+	}
+	if code != http.StatusInternalServerError {
+		t.Errorf("status code = %d, want %d", code, http.StatusInternalServerError)
+	}
+}
+
+func newDispatch(url, secret string, body []byte) e.WebhookDispatch {
+	return e.WebhookDispatch{
+		WebhookDelivery: e.WebhookDelivery{
+			Id:        uuid.New(),
+			EventId:   uuid.New(),
+			EventType: "bid.created",
+			Payload:   body,
+			Status:    StatusPending,
+		},
+		URL:    url,
+		Secret: secret,
+	}
+}
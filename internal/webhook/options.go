@@ -0,0 +1,42 @@
+package webhook
+
+import "time"
+
+type Option func(*Worker)
+
+func PollInterval(interval time.Duration) Option {
+	return func(w *Worker) {
+		w.pollInterval = interval
+	}
+}
+
+func BatchSize(size int) Option {
+	return func(w *Worker) {
+		w.batchSize = size
+	}
+}
+
+// MaxAttempts is number of attempts before delivery is dead-lettered
+func MaxAttempts(attempts int) Option {
+	return func(w *Worker) {
+		w.maxAttempts = attempts
+	}
+}
+
+func MaxBackoff(backoff time.Duration) Option {
+	return func(w *Worker) {
+		w.maxBackoff = backoff
+	}
+}
+
+func InitialBackoff(backoff time.Duration) Option {
+	return func(w *Worker) {
+		w.initialBackoff = backoff
+	}
+}
+
+func RequestTimeout(timeout time.Duration) Option {
+	return func(w *Worker) {
+		w.requestTimeout = timeout
+	}
+}
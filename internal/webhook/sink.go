@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sink is outbox sink queueing events for webhook subscriptions. Queueing
+// joins outbox transaction, so event is never queued twice
+type Sink struct {
+	webhookRepo repo.Webhook
+}
+
+func NewSink(wRepo repo.Webhook) *Sink {
+	return &Sink{webhookRepo: wRepo}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+// envelope is request body of delivery
+type envelope struct {
+	Id        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func (s *Sink) Deliver(ctx context.Context, event e.OutboxEvent) error {
+	body, err := json.Marshal(envelope{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt.UTC().Format(time.RFC3339),
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook - Sink.Deliver - json.Marshal: %w", err)
+	}
+
+	err = s.webhookRepo.AddDeliveries(ctx, rt.AddWebhookDeliveriesInput{
+		EventId:         event.Id,
+		EventType:       event.Type,
+		Payload:         body,
+		OrganizationIds: recipients(event),
+	})
+	if err != nil {
+		return fmt.Errorf("webhook - Sink.Deliver - webhookRepo.AddDeliveries: %w", err)
+	}
+
+	return nil
+}
+
+// recipients returns organizations interested in event: organization of event
+// (tender owner) and organization named in payload (bid author)
+func recipients(event e.OutboxEvent) []uuid.UUID {
+	var orgIds []uuid.UUID
+	if event.OrganizationId.Valid {
+		orgIds = append(orgIds, event.OrganizationId.UUID)
+	}
+
+	var payload struct {
+		OrganizationId *uuid.UUID `json:"organizationId"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err == nil && payload.OrganizationId != nil {
+		if !slices.Contains(orgIds, *payload.OrganizationId) {
+			orgIds = append(orgIds, *payload.OrganizationId)
+		}
+	}
+
+	return orgIds
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRecipients(t *testing.T) {
+	tenderOrg, bidOrg := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []uuid.UUID
+	}{
+		{name: "bid of other organization", payload: `{"organizationId":"` + bidOrg.String() + `"}`, want: []uuid.UUID{tenderOrg, bidOrg}},
+		{name: "same organization", payload: `{"organizationId":"` + tenderOrg.String() + `"}`, want: []uuid.UUID{tenderOrg}},
+		{name: "user bid", payload: `{"authorType":"User"}`, want: []uuid.UUID{tenderOrg}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recipients(e.OutboxEvent{
+				OrganizationId: uuid.NullUUID{UUID: tenderOrg, Valid: true},
+				Payload:        []byte(tt.payload),
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("recipients = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("recipients = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Delivery statuses
+const (
+	StatusPending    = "Pending"
+	StatusDelivered  = "Delivered"
+	StatusDeadLetter = "DeadLetter"
+)
+
+const (
+	defaultPollInterval   = time.Second
+	defaultBatchSize      = 20
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 10 * time.Second
+	defaultMaxBackoff     = time.Hour
+	defaultRequestTimeout = 10 * time.Second
+)
+
+// Worker sends pending deliveries, retries failed ones with exponential
+// backoff and dead-letters them after MaxAttempts
+type Worker struct {
+	webhookRepo repo.Webhook
+	tx          repo.Transactor
+	client      *Client
+
+	pollInterval   time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	requestTimeout time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorker(wRepo repo.Webhook, tx repo.Transactor, opts ...Option) *Worker {
+	w := &Worker{
+		webhookRepo:    wRepo,
+		tx:             tx,
+		pollInterval:   defaultPollInterval,
+		batchSize:      defaultBatchSize,
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		requestTimeout: defaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.client = NewClient(&http.Client{Timeout: w.requestTimeout})
+
+	return w
+}
+
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.run(ctx)
+	}()
+}
+
+// Stop cancels current batch and waits for worker to exit. Deliveries of
+// cancelled batch stay pending
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Worker) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := w.sendBatch(ctx)
+		if err != nil {
+			log.Errorf("webhook - Worker.run - sendBatch: %v", err)
+		}
+
+		// Full batch means there are more pending deliveries
+		if err == nil && n == w.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(w.pollInterval)
+		}
+	}
+}
+
+// sendBatch locks batch of due deliveries while sending them, so several
+// workers never send same delivery concurrently
+func (w *Worker) sendBatch(ctx context.Context) (int, error) {
+	var n int
+	err := w.tx.InTx(ctx, func(ctx context.Context) error {
+		dispatches, err := w.webhookRepo.GetPending(ctx, w.batchSize)
+		if err != nil {
+			return err
+		}
+		n = len(dispatches)
+
+		for _, d := range dispatches {
+			if err := w.webhookRepo.RecordAttempt(ctx, w.send(ctx, d)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// send makes one attempt and returns its result
+func (w *Worker) send(ctx context.Context, d e.WebhookDispatch) rt.RecordWebhookAttemptInput {
+	statusCode, err := w.client.Send(ctx, d)
+	if err == nil {
+		return rt.RecordWebhookAttemptInput{
+			DeliveryId: d.Id,
+			Status:     StatusDelivered,
+			StatusCode: statusCode,
+		}
+	}
+
+	result := rt.RecordWebhookAttemptInput{
+		DeliveryId: d.Id,
+		Status:     StatusPending,
+		StatusCode: statusCode,
+		Error:      err.Error(),
+		Delay:      w.backoff(d.Attempts),
+	}
+	if d.Attempts+1 >= w.maxAttempts {
+		log.Warnf("webhook - Worker.send - delivery %s dead-lettered: %v", d.Id, err)
+		result.Status = StatusDeadLetter
+		result.Delay = 0
+	}
+
+	return result
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+	backoff := w.initialBackoff
+	for i := 0; i < attempts && backoff < w.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, w.maxBackoff)
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	e "app/internal/entity"
+	"app/internal/repo"
+	rt "app/internal/repo/repotypes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeWebhookRepo serves fixed pending deliveries and records attempts
+type fakeWebhookRepo struct {
+	repo.Webhook
+	pending  []e.WebhookDispatch
+	attempts []rt.RecordWebhookAttemptInput
+}
+
+func (r *fakeWebhookRepo) GetPending(ctx context.Context, limit int) ([]e.WebhookDispatch, error) {
+	pending := r.pending
+	r.pending = nil
+	return pending, nil
+}
+
+func (r *fakeWebhookRepo) RecordAttempt(ctx context.Context, in rt.RecordWebhookAttemptInput) error {
+	r.attempts = append(r.attempts, in)
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTransactor) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestWorker(r repo.Webhook) *Worker {
+	return NewWorker(r, fakeTransactor{},
+		MaxAttempts(3),
+		InitialBackoff(time.Second),
+		MaxBackoff(3*time.Second),
+		RequestTimeout(time.Second),
+	)
+}
+
+func TestWorkerDelivers(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	d := newDispatch(srv.URL, "secret", []byte(`{}`))
+	r := &fakeWebhookRepo{pending: []e.WebhookDispatch{d}}
+	if _, err := newTestWorker(r).sendBatch(context.Background()); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+
+	if hits.Load() != 1 {
+		t.Fatalf("receiver hits = %d, want 1", hits.Load())
+	}
+	assertAttempt(t, r, rt.RecordWebhookAttemptInput{DeliveryId: d.Id, Status: StatusDelivered, StatusCode: http.StatusOK})
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{attempts: 0, delay: time.Second},
+		{attempts: 1, delay: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		d := newDispatch(srv.URL, "secret", []byte(`{}`))
+		d.Attempts = tt.attempts
+		r := &fakeWebhookRepo{pending: []e.WebhookDispatch{d}}
+		if _, err := newTestWorker(r).sendBatch(context.Background()); err != nil {
+			t.Fatalf("sendBatch: %v", err)
+		}
+		assertAttempt(t, r, rt.RecordWebhookAttemptInput{
+			DeliveryId: d.Id,
+			Status:     StatusPending,
+			StatusCode: http.StatusServiceUnavailable,
+			Delay:      tt.delay,
+		})
+	}
+}
+
+func TestWorkerDeadLettersAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	d := newDispatch(srv.URL, "secret", []byte(`{}`))
+	d.Attempts = 2
+	r := &fakeWebhookRepo{pending: []e.WebhookDispatch{d}}
+	if _, err := newTestWorker(r).sendBatch(context.Background()); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+
+	assertAttempt(t, r, rt.RecordWebhookAttemptInput{DeliveryId: d.Id, Status: StatusDeadLetter, StatusCode: http.StatusBadGateway})
+}
+
+func TestWorkerRetriesUnreachableReceiver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+
+	d := newDispatch(srv.URL, "secret", []byte(`{}`))
+	r := &fakeWebhookRepo{pending: []e.WebhookDispatch{d}}
+	if _, err := newTestWorker(r).sendBatch(context.Background()); err != nil {
+		t.Fatalf("sendBatch: %v", err)
+	}
+
+	assertAttempt(t, r, rt.RecordWebhookAttemptInput{DeliveryId: d.Id, Status: StatusPending, Delay: time.Second})
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	w := newTestWorker(&fakeWebhookRepo{})
+	if got := w.backoff(10); got != 3*time.Second {
+		t.Errorf("backoff(10) = %v, want %v", got, 3*time.Second)
+	}
+}
+
+// assertAttempt compares single recorded attempt ignoring error text
+func assertAttempt(t *testing.T, r *fakeWebhookRepo, want rt.RecordWebhookAttemptInput) {
+	t.Helper()
+	if len(r.attempts) != 1 {
+		t.Fatalf("recorded attempts = %d, want 1", len(r.attempts))
+	}
+	got := r.attempts[0]
+	if (got.Error != "") != (want.Status != StatusDelivered) {
+		t.Errorf("error = %q for status %s", got.Error, want.Status)
+	}
+	got.Error = ""
+	if got != want {
+		t.Errorf("attempt = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tender_invitation;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS visibility;
+
+DROP TYPE IF EXISTS tender_visibility;
@@ -0,0 +1,26 @@
+CREATE TYPE tender_visibility AS ENUM (
+    'Public',
+    'InviteOnly',
+    'Internal'
+);
+
+ALTER TABLE tender
+    ADD COLUMN visibility tender_visibility NOT NULL DEFAULT 'Public';
+
+CREATE TABLE tender_invitation (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL,
+    organization_id UUID NULL REFERENCES organization(id) ON DELETE CASCADE,
+    user_id UUID NULL REFERENCES employee(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT tender_invitation_invitee_check CHECK (
+        (organization_id IS NULL) <> (user_id IS NULL)
+    ),
+    CONSTRAINT tender_invitation_organization_key UNIQUE (tender_id, organization_id),
+    CONSTRAINT tender_invitation_user_key UNIQUE (tender_id, user_id)
+);
+
+CREATE INDEX idx_tender_invitation_tender_id_hash ON tender_invitation USING HASH (tender_id);
+CREATE INDEX idx_tender_invitation_organization_id_hash ON tender_invitation USING HASH (organization_id);
+CREATE INDEX idx_tender_invitation_user_id_hash ON tender_invitation USING HASH (user_id);
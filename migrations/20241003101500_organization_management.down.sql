@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_organization_name;
+
+ALTER TABLE organization_responsible
+    DROP CONSTRAINT IF EXISTS organization_responsible_organization_user_key;
+
+ALTER TABLE organization
+    ALTER COLUMN description DROP NOT NULL,
+    ALTER COLUMN description DROP DEFAULT;
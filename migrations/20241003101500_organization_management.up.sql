@@ -0,0 +1,16 @@
+UPDATE organization SET description = '' WHERE description IS NULL;
+
+ALTER TABLE organization
+    ALTER COLUMN description SET DEFAULT '',
+    ALTER COLUMN description SET NOT NULL;
+
+DELETE FROM organization_responsible a
+USING organization_responsible b
+WHERE a.organization_id = b.organization_id
+AND a.user_id = b.user_id
+AND a.id > b.id;
+
+ALTER TABLE organization_responsible
+    ADD CONSTRAINT organization_responsible_organization_user_key UNIQUE (organization_id, user_id);
+
+CREATE INDEX idx_organization_name ON organization (name);
@@ -0,0 +1,7 @@
+ALTER TABLE employee
+    DROP COLUMN IF EXISTS is_admin,
+    DROP COLUMN IF EXISTS is_active,
+    ALTER COLUMN last_name DROP NOT NULL,
+    ALTER COLUMN last_name DROP DEFAULT,
+    ALTER COLUMN first_name DROP NOT NULL,
+    ALTER COLUMN first_name DROP DEFAULT;
@@ -0,0 +1,14 @@
+UPDATE employee SET first_name = '' WHERE first_name IS NULL;
+UPDATE employee SET last_name = '' WHERE last_name IS NULL;
+
+ALTER TABLE employee
+    ALTER COLUMN first_name SET DEFAULT '',
+    ALTER COLUMN first_name SET NOT NULL,
+    ALTER COLUMN last_name SET DEFAULT '',
+    ALTER COLUMN last_name SET NOT NULL,
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- First administrator, grants admin to others. Sets first password with
+-- bootstrap token
+UPDATE employee SET is_admin = TRUE WHERE username = 'user1';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS login_attempt;
+
+DROP TABLE IF EXISTS password_reset_token;
+
+DROP TABLE IF EXISTS employee_credential;
@@ -0,0 +1,36 @@
+CREATE TABLE employee_credential (
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    password_hash TEXT NOT NULL,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    password_changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (employee_id)
+);
+
+CREATE TABLE password_reset_token (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_by UUID NULL REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT password_reset_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE TABLE login_attempt (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    employee_id UUID NULL REFERENCES employee(id) ON DELETE SET NULL,
+    username VARCHAR(50) NOT NULL,
+    client_ip VARCHAR(45) NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_login_attempt_created_at ON login_attempt (created_at);
+CREATE INDEX idx_login_attempt_username_hash ON login_attempt USING HASH (username);
@@ -0,0 +1,18 @@
+CREATE TABLE api_key (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by UUID NULL REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT api_key_prefix_key UNIQUE (prefix),
+    CONSTRAINT api_key_secret_hash_key UNIQUE (secret_hash)
+);
+
+CREATE INDEX idx_api_key_organization_id_hash ON api_key USING HASH (organization_id);
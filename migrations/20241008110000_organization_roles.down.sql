@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS organization_role;
+
+DROP TYPE IF EXISTS organization_role_type;
@@ -0,0 +1,25 @@
+CREATE TYPE organization_role_type AS ENUM (
+    'viewer',
+    'editor',
+    'publisher',
+    'approver',
+    'admin'
+);
+
+CREATE TABLE organization_role (
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role organization_role_type NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id, role),
+    CONSTRAINT organization_role_responsible_fkey FOREIGN KEY (organization_id, user_id)
+        REFERENCES organization_responsible (organization_id, user_id) ON DELETE CASCADE
+);
+
+-- Existing responsibles keep everything they could do before roles
+INSERT INTO organization_role (organization_id, user_id, role)
+SELECT organization_responsible.organization_id, organization_responsible.user_id, roles.role
+FROM organization_responsible
+CROSS JOIN unnest(enum_range(NULL::organization_role_type)) AS roles(role)
+WHERE organization_responsible.organization_id IS NOT NULL
+AND organization_responsible.user_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bid_organization_id_hash;
+
+ALTER TABLE bid DROP CONSTRAINT IF EXISTS bid_user_without_organization;
+
+ALTER TABLE bid DROP COLUMN IF EXISTS organization_id;
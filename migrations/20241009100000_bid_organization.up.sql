@@ -0,0 +1,20 @@
+ALTER TABLE bid ADD COLUMN organization_id UUID REFERENCES organization(id) ON DELETE CASCADE;
+
+-- Backfill organization bids whose author is responsible for exactly one organization,
+-- ambiguous ones stay without organization and can't be managed until recreated
+UPDATE bid
+SET organization_id = single.organization_id
+FROM (
+    SELECT user_id, (array_agg(organization_id))[1] AS organization_id
+    FROM organization_responsible
+    WHERE organization_id IS NOT NULL
+    GROUP BY user_id
+    HAVING COUNT(*) = 1
+) AS single
+WHERE bid.author = 'Organization'
+AND bid.author_id = single.user_id;
+
+ALTER TABLE bid ADD CONSTRAINT bid_user_without_organization
+    CHECK (author = 'Organization' OR organization_id IS NULL);
+
+CREATE INDEX idx_bid_organization_id_hash ON bid USING HASH (organization_id);
@@ -0,0 +1,20 @@
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID NOT NULL,
+    actor_name VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    entity_version INT NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    client_ip VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Entries outlive organizations and entities they describe, so no foreign keys
+CREATE INDEX idx_audit_log_organization_created ON audit_log (organization_id, created_at DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id);
@@ -0,0 +1,20 @@
+ALTER TABLE audit_log
+    ADD COLUMN seq BIGINT,
+    ADD COLUMN prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Existing entries are numbered in creation order and get hashed by the next write
+-- or by audit export, empty hash means entry is not sealed yet
+UPDATE audit_log
+SET seq = numbered.seq
+FROM (
+    SELECT id, row_number() OVER (ORDER BY created_at, id) AS seq
+    FROM audit_log
+) AS numbered
+WHERE audit_log.id = numbered.id;
+
+ALTER TABLE audit_log ALTER COLUMN seq SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_audit_log_seq ON audit_log (seq);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+CREATE INDEX idx_audit_log_unsealed ON audit_log (seq) WHERE hash = '';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS outbox_delivery;
+
+DROP TABLE IF EXISTS outbox_event;
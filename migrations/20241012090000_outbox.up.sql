@@ -0,0 +1,25 @@
+CREATE TABLE outbox_event (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    organization_id UUID,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_event_pending ON outbox_event (next_attempt_at, created_at) WHERE processed_at IS NULL;
+
+-- Delivery state per sink, event is processed once every sink has it
+CREATE TABLE outbox_delivery (
+    event_id UUID NOT NULL REFERENCES outbox_event(id) ON DELETE CASCADE,
+    sink VARCHAR(100) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, sink)
+);
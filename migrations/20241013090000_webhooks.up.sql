@@ -0,0 +1,41 @@
+CREATE TABLE webhook_subscription (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    url VARCHAR(2048) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    created_by UUID NULL REFERENCES employee(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_webhook_subscription_organization_id_hash ON webhook_subscription USING HASH (organization_id);
+
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'Pending',
+    'Delivered',
+    'DeadLetter'
+);
+
+-- Payload is full request body, so redelivery sends exactly the same bytes.
+-- Event id isn't a foreign key, outbox may be cleaned independently
+CREATE TABLE webhook_delivery (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status webhook_delivery_status NOT NULL DEFAULT 'Pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_subscription_event_key UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (next_attempt_at) WHERE status = 'Pending';
+CREATE INDEX idx_webhook_delivery_subscription ON webhook_delivery (subscription_id, created_at);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS email_message;
+
+DROP TYPE IF EXISTS email_message_status;
+
+DROP TABLE IF EXISTS tender_deadline_reminder;
+
+DROP TABLE IF EXISTS email_preference;
+
+DROP TYPE IF EXISTS email_notification_kind;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS deadline;
+
+DROP INDEX IF EXISTS employee_email_key;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS email;
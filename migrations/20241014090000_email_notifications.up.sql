@@ -0,0 +1,56 @@
+ALTER TABLE employee ADD COLUMN email VARCHAR(254) NULL;
+
+CREATE UNIQUE INDEX employee_email_key ON employee (lower(email)) WHERE email IS NOT NULL;
+
+ALTER TABLE tender ADD COLUMN deadline TIMESTAMP NULL;
+
+CREATE TYPE email_notification_kind AS ENUM (
+    'bid_received',
+    'bid_decided',
+    'tender_amended',
+    'deadline_approaching'
+);
+
+-- Employees receive only kinds they opted in to
+CREATE TABLE email_preference (
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    kind email_notification_kind NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (employee_id, kind)
+);
+
+-- Reminder is sent once per deadline, moving deadline sends it again
+CREATE TABLE tender_deadline_reminder (
+    tender_id UUID NOT NULL,
+    deadline TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tender_id, deadline)
+);
+
+CREATE TYPE email_message_status AS ENUM (
+    'Pending',
+    'Sent',
+    'Failed'
+);
+
+-- Messages are rendered when queued, so retries send the same content
+CREATE TABLE email_message (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    kind email_notification_kind NOT NULL,
+    to_address VARCHAR(254) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    status email_message_status NOT NULL DEFAULT 'Pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT email_message_event_employee_key UNIQUE (event_id, employee_id)
+);
+
+CREATE INDEX idx_email_message_pending ON email_message (next_attempt_at) WHERE status = 'Pending';
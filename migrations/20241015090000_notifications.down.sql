@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification;
+
+DROP TYPE IF EXISTS notification_kind;
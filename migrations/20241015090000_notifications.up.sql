@@ -0,0 +1,28 @@
+CREATE TYPE notification_kind AS ENUM (
+    'bid_received',
+    'bid_decided',
+    'tender_amended'
+);
+
+-- organization_id is organization recipient is notified for, NULL means
+-- recipient is notified personally
+CREATE TABLE notification (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    kind notification_kind NOT NULL,
+    organization_id UUID NULL REFERENCES organization(id) ON DELETE CASCADE,
+    tender_id UUID NOT NULL,
+    bid_id UUID NULL,
+    title VARCHAR(255) NOT NULL,
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT notification_event_employee_key UNIQUE (event_id, employee_id)
+);
+
+CREATE INDEX idx_notification_employee ON notification (employee_id, created_at DESC);
+
+CREATE INDEX idx_notification_unread ON notification (employee_id) WHERE read_at IS NULL;
+
+CREATE INDEX idx_notification_created_at ON notification (created_at);
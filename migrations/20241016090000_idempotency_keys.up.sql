@@ -0,0 +1,16 @@
+-- caller is "employee:<id>" or "api-key:<id>". Row without response_status is
+-- claimed by request in progress until locked_until
+CREATE TABLE idempotency_key (
+    caller VARCHAR(100) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash BYTEA NOT NULL,
+    response_status INTEGER NULL,
+    response_content_type VARCHAR(255) NULL,
+    response_body BYTEA NULL,
+    locked_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    PRIMARY KEY (caller, key)
+);
+
+CREATE INDEX idx_idempotency_key_created_at ON idempotency_key (created_at);
@@ -0,0 +1,10 @@
+-- Token buckets of shared rate limiting, allowed is decision on last request
+CREATE TABLE rate_limit_bucket (
+    key VARCHAR(255) NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (key)
+);
+
+CREATE INDEX idx_rate_limit_bucket_updated_at ON rate_limit_bucket (updated_at);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS outbox_event_notify ON outbox_event;
+DROP FUNCTION IF EXISTS notify_outbox_event;
@@ -0,0 +1,12 @@
+-- Every instance listens to outbox_event channel to feed its event stream,
+-- notification is sent on commit, so listeners get events in commit order
+CREATE FUNCTION notify_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('outbox_event', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_event_notify
+    AFTER INSERT ON outbox_event
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
@@ -0,0 +1,275 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Document is OpenAPI 3 document reduced to what request validation needs
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Delete     *Operation   `json:"delete"`
+	Patch      *Operation   `json:"patch"`
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+		http.MethodPatch:  p.Patch,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+type Operation struct {
+	OperationId string       `json:"operationId"`
+	Parameters  []*Parameter `json:"parameters"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route is operation address, path is template as written in document
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Load parses document and resolves references. Every reference must point
+// into document, so broken document fails here and not on first request
+func Load(data []byte) (*Document, error) {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("openapi - Load - json.Unmarshal: %w", err)
+	}
+	if err := checkRefs(raw, raw); err != nil {
+		return nil, fmt.Errorf("openapi - Load: %w", err)
+	}
+
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("openapi - Load - json.Unmarshal: %w", err)
+	}
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi - Load: unsupported version %q", d.OpenAPI)
+	}
+	if err := d.resolve(); err != nil {
+		return nil, fmt.Errorf("openapi - Load: %w", err)
+	}
+
+	return &d, nil
+}
+
+// Operation returns operation for method and path template, nil if document
+// doesn't have it
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+	return item.operations()[method]
+}
+
+// Routes lists all operations sorted by path and method
+func (d *Document) Routes() []Route {
+	var routes []Route
+	for path, item := range d.Paths {
+		for method := range item.operations() {
+			routes = append(routes, Route{Method: method, Path: path})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// resolve replaces parameter references with components, merges path level
+// parameters into operations and links schema references
+func (d *Document) resolve() error {
+	for name, s := range d.Components.Schemas {
+		if err := d.resolveSchema(s); err != nil {
+			return fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+	for name, p := range d.Components.Parameters {
+		if err := d.resolveSchema(p.Schema); err != nil {
+			return fmt.Errorf("parameter %s: %w", name, err)
+		}
+	}
+
+	for path, item := range d.Paths {
+		shared, err := d.resolveParameters(item.Parameters)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for method, op := range item.operations() {
+			own, err := d.resolveParameters(op.Parameters)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			op.Parameters = mergeParameters(shared, own)
+
+			if op.RequestBody == nil {
+				continue
+			}
+			for contentType, media := range op.RequestBody.Content {
+				if err := d.resolveSchema(media.Schema); err != nil {
+					return fmt.Errorf("%s %s %s: %w", method, path, contentType, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Document) resolveParameters(params []*Parameter) ([]*Parameter, error) {
+	resolved := make([]*Parameter, 0, len(params))
+	for _, p := range params {
+		if p.Ref != "" {
+			name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/")
+			if !ok || d.Components.Parameters[name] == nil {
+				return nil, fmt.Errorf("unresolved parameter %s", p.Ref)
+			}
+			p = d.Components.Parameters[name]
+		}
+		if p.Name == "" || (p.In != "path" && p.In != "query" && p.In != "header") {
+			return nil, fmt.Errorf("parameter %q in %q is not supported", p.Name, p.In)
+		}
+		if err := d.resolveSchema(p.Schema); err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		resolved = append(resolved, p)
+	}
+	return resolved, nil
+}
+
+// mergeParameters lets operation override path level parameter with same name and location
+func mergeParameters(shared, own []*Parameter) []*Parameter {
+	merged := append([]*Parameter{}, own...)
+	for _, s := range shared {
+		overridden := false
+		for _, o := range own {
+			if o.Name == s.Name && o.In == s.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+func (d *Document) resolveSchema(s *Schema) error {
+	if s == nil || s.target != nil {
+		return nil
+	}
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok || d.Components.Schemas[name] == nil {
+			return fmt.Errorf("unresolved schema %s", s.Ref)
+		}
+		s.target = d.Components.Schemas[name]
+		return nil
+	}
+
+	for _, p := range s.Properties {
+		if err := d.resolveSchema(p); err != nil {
+			return err
+		}
+	}
+	for _, sub := range s.AllOf {
+		if err := d.resolveSchema(sub); err != nil {
+			return err
+		}
+	}
+	return d.resolveSchema(s.Items)
+}
+
+// checkRefs follows every $ref in document, including parts validation
+// doesn't use, such as responses
+func checkRefs(root, node any) error {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if _, err := pointer(root, ref); err != nil {
+				return err
+			}
+		}
+		for _, child := range v {
+			if err := checkRefs(root, child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := checkRefs(root, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func pointer(root any, ref string) (any, error) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("only local references are supported, got %s", ref)
+	}
+	node := root
+	for _, token := range strings.Split(path, "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+		if node, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+	}
+	return node, nil
+}
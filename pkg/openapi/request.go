@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+var ErrRequestBodyRequired = errors.New("request body is required")
+
+// ValidateRequest checks parameters and JSON body against operation. Path
+// parameters come from router. Body is read and put back for handler
+func (o *Operation) ValidateRequest(r *http.Request, pathParams map[string]string) error {
+	query := r.URL.Query()
+	for _, p := range o.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+			// Binding treats ?limit= as missing parameter
+			if len(values) == 1 && values[0] == "" {
+				values = nil
+			}
+		case "header":
+			values = r.Header.Values(p.Name)
+		}
+
+		path := p.In + "." + p.Name
+		if len(values) == 0 {
+			if p.Required {
+				return fmt.Errorf("%s is required", path)
+			}
+			continue
+		}
+		if err := validateParameter(p.Schema, path, values); err != nil {
+			return err
+		}
+	}
+
+	if o.RequestBody == nil {
+		return nil
+	}
+	return o.validateBody(r)
+}
+
+// validateParameter parses values the way they are bound, repeated query
+// parameter is array and only first value counts otherwise
+func validateParameter(s *Schema, path string, values []string) error {
+	for s != nil && s.target != nil {
+		s = s.target
+	}
+	if s == nil {
+		return nil
+	}
+
+	if s.Type != "array" {
+		v, err := parseScalar(s, path, values[0])
+		if err != nil {
+			return err
+		}
+		return s.validate(path, v)
+	}
+
+	arr := make([]any, 0, len(values))
+	for _, raw := range values {
+		v, err := parseScalar(s.Items, path, raw)
+		if err != nil {
+			return err
+		}
+		arr = append(arr, v)
+	}
+	return s.validate(path, arr)
+}
+
+func parseScalar(s *Schema, path, raw string) (any, error) {
+	for s != nil && s.target != nil {
+		s = s.target
+	}
+	if s == nil {
+		return raw, nil
+	}
+
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, fmt.Errorf("%s must be integer", path)
+		}
+		return json.Number(raw), nil
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, fmt.Errorf("%s must be number", path)
+		}
+		return json.Number(raw), nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be boolean", path)
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+func (o *Operation) validateBody(r *http.Request) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("openapi - Operation.validateBody - io.ReadAll: %w", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(body) == 0 {
+		if o.RequestBody.Required {
+			return ErrRequestBodyRequired
+		}
+		return nil
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("content type %q is not supported", r.Header.Get("Content-Type"))
+	}
+	media, ok := o.RequestBody.Content[contentType]
+	if !ok {
+		return fmt.Errorf("content type %q is not supported", contentType)
+	}
+	if contentType != "application/json" {
+		return nil
+	}
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return errors.New("body must be JSON")
+	}
+	if dec.More() {
+		return errors.New("body must be single JSON value")
+	}
+	return media.Schema.validate("body", v)
+}
@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Schema is subset of OpenAPI schema object. Keywords it doesn't know are
+// ignored, so document may carry descriptions and examples
+type Schema struct {
+	Ref           string             `json:"$ref"`
+	Type          string             `json:"type"`
+	Format        string             `json:"format"`
+	Enum          []any              `json:"enum"`
+	Nullable      bool               `json:"nullable"`
+	Required      []string           `json:"required"`
+	Properties    map[string]*Schema `json:"properties"`
+	MinProperties *int               `json:"minProperties"`
+	Items         *Schema            `json:"items"`
+	MinItems      *int               `json:"minItems"`
+	MaxItems      *int               `json:"maxItems"`
+	MinLength     *int               `json:"minLength"`
+	MaxLength     *int               `json:"maxLength"`
+	Minimum       *float64           `json:"minimum"`
+	Maximum       *float64           `json:"maximum"`
+	AllOf         []*Schema          `json:"allOf"`
+
+	target *Schema
+}
+
+// validate checks decoded JSON value, numbers must be json.Number
+func (s *Schema) validate(path string, v any) error {
+	if s == nil {
+		return nil
+	}
+	if s.target != nil {
+		return s.target.validate(path, v)
+	}
+
+	for _, sub := range s.AllOf {
+		if err := sub.validate(path, v); err != nil {
+			return err
+		}
+	}
+
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", path)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be object", path)
+		}
+		return s.validateObject(path, obj)
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s must be array", path)
+		}
+		return s.validateArray(path, arr)
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be string", path)
+		}
+		return s.validateString(path, str)
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s must be integer", path)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return fmt.Errorf("%s must be integer", path)
+		}
+		return s.validateNumber(path, float64(i))
+	case "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s must be number", path)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("%s must be number", path)
+		}
+		return s.validateNumber(path, f)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be boolean", path)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(path string, obj map[string]any) error {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s.%s is required", path, name)
+		}
+	}
+	if s.MinProperties != nil && len(obj) < *s.MinProperties {
+		return fmt.Errorf("%s must have at least %d properties", path, *s.MinProperties)
+	}
+	for name, value := range obj {
+		if err := s.Properties[name].validate(path+"."+name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(path string, arr []any) error {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		return fmt.Errorf("%s must have at least %d items", path, *s.MinItems)
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		return fmt.Errorf("%s must have at most %d items", path, *s.MaxItems)
+	}
+	for i, item := range arr {
+		if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateString(path, str string) error {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		return fmt.Errorf("%s must be at least %d characters", path, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return fmt.Errorf("%s must be at most %d characters", path, *s.MaxLength)
+	}
+	if len(s.Enum) > 0 && !s.allowed(str) {
+		return fmt.Errorf("%s must be one of %v", path, s.Enum)
+	}
+	if !validFormat(s.Format, str) {
+		return fmt.Errorf("%s must be %s", path, s.Format)
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(path string, f float64) error {
+	if s.Minimum != nil && f < *s.Minimum {
+		return fmt.Errorf("%s must be at least %v", path, *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		return fmt.Errorf("%s must be at most %v", path, *s.Maximum)
+	}
+	return nil
+}
+
+func (s *Schema) allowed(str string) bool {
+	for _, e := range s.Enum {
+		if e == str {
+			return true
+		}
+	}
+	return false
+}
+
+// validFormat knows formats API uses, others are not checked
+func validFormat(format, s string) bool {
+	switch format {
+	case "uuid":
+		_, err := uuid.Parse(s)
+		return err == nil && len(s) == 36
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}
+	return true
+}
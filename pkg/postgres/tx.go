@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// Querier runs queries on pool or on transaction bound to context
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// txState is shared by transaction and its savepoints. Callers often replace
+// query errors with their own, so whether transaction may be retried is
+// recorded here as queries fail
+type txState struct {
+	retryable bool
+}
+
+func (s *txState) observe(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected) {
+		s.retryable = true
+	}
+}
+
+// txConn is transaction or savepoint bound to context
+type txConn struct {
+	tx    pgx.Tx
+	state *txState
+}
+
+func (c *txConn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tag, err := c.tx.Exec(ctx, sql, arguments...)
+	c.state.observe(err)
+	return tag, err
+}
+
+func (c *txConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := c.tx.Query(ctx, sql, args...)
+	if err != nil {
+		c.state.observe(err)
+		return nil, err
+	}
+	return &txRows{Rows: rows, state: c.state}, nil
+}
+
+func (c *txConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := c.Query(ctx, sql, args...)
+	return &txRow{rows: rows, err: err}
+}
+
+// txRows records error met while reading rows, most query errors arrive here
+type txRows struct {
+	pgx.Rows
+	state *txState
+}
+
+func (r *txRows) Err() error {
+	err := r.Rows.Err()
+	r.state.observe(err)
+	return err
+}
+
+// txRow reads single row like pgx does, but through txRows
+type txRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *txRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	rows := r.rows
+	defer rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
+// Conn returns transaction started by InTx if context carries one, otherwise pool
+func (p *Postgres) Conn(ctx context.Context) Querier {
+	if c, ok := ctx.Value(txKey{}).(*txConn); ok {
+		return c
+	}
+	return p.Pool
+}
+
+// InTx runs fn in transaction, queries made through Conn with fn context join it.
+// Nested call runs fn in savepoint of outer transaction, so its failure is
+// undone without aborting outer one. Error returned by fn is passed as is
+func (p *Postgres) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := p.runTx(ctx, pgx.TxOptions{}, fn)
+	return err
+}
+
+// runTx reports if failed transaction may succeed when run again. Savepoint
+// is never reported retryable, only whole transaction can be repeated
+func (p *Postgres) runTx(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context) error) (bool, error) {
+	if outer, ok := ctx.Value(txKey{}).(*txConn); ok {
+		return false, p.runSavepoint(ctx, outer, fn)
+	}
+
+	tx, err := p.Pool.BeginTx(ctx, opts)
+	if err != nil {
+		return false, fmt.Errorf("postgres - InTx - Pool.BeginTx: %w", err)
+	}
+	// No-op after successful commit
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	c := &txConn{tx: tx, state: &txState{}}
+	if err := fn(context.WithValue(ctx, txKey{}, c)); err != nil {
+		return c.state.retryable, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		c.state.observe(err)
+		return c.state.retryable, fmt.Errorf("postgres - InTx - tx.Commit: %w", err)
+	}
+
+	return false, nil
+}
+
+func (p *Postgres) runSavepoint(ctx context.Context, outer *txConn, fn func(ctx context.Context) error) error {
+	sp, err := outer.tx.Begin(ctx)
+	if err != nil {
+		outer.state.observe(err)
+		return fmt.Errorf("postgres - InTx - tx.Begin: %w", err)
+	}
+	// Rolls back to savepoint, no-op after release
+	defer func() { _ = sp.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(context.WithValue(ctx, txKey{}, &txConn{tx: sp, state: outer.state})); err != nil {
+		return err
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		outer.state.observe(err)
+		return fmt.Errorf("postgres - InTx - sp.Commit: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	errFn            = errors.New("fn failed")
+	errSerialization = &pgconn.PgError{Code: codeSerializationFailure}
+	errDeadlock      = &pgconn.PgError{Code: codeDeadlockDetected}
+	errUnique        = &pgconn.PgError{Code: "23505"}
+)
+
+// fakePool logs statements of transactions it begins. Statement fails with
+// errors queued for it in fail, one error per run, commit fails same way
+type fakePool struct {
+	PgxPool
+	log  []string
+	opts []pgx.TxOptions
+	fail map[string][]error
+}
+
+func (p *fakePool) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	p.opts = append(p.opts, opts)
+	p.log = append(p.log, "begin")
+	return &fakeTx{pool: p}, nil
+}
+
+func (p *fakePool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	p.log = append(p.log, "pool "+sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (p *fakePool) next(statement string) error {
+	errs := p.fail[statement]
+	if len(errs) == 0 {
+		return nil
+	}
+	p.fail[statement] = errs[1:]
+	return errs[0]
+}
+
+// fakeTx is transaction or, with savepoint set, savepoint of one
+type fakeTx struct {
+	pgx.Tx
+	pool      *fakePool
+	savepoint bool
+	closed    bool
+}
+
+func (tx *fakeTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx.pool.log = append(tx.pool.log, "savepoint")
+	return &fakeTx{pool: tx.pool, savepoint: true}, nil
+}
+
+func (tx *fakeTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tx.pool.log = append(tx.pool.log, sql)
+	return pgconn.CommandTag{}, tx.pool.next(sql)
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	tx.closed = true
+	if tx.savepoint {
+		tx.pool.log = append(tx.pool.log, "release")
+		return nil
+	}
+	tx.pool.log = append(tx.pool.log, "commit")
+	return tx.pool.next("commit")
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	if tx.closed {
+		return pgx.ErrTxClosed
+	}
+	tx.closed = true
+	if tx.savepoint {
+		tx.pool.log = append(tx.pool.log, "rollback to savepoint")
+	} else {
+		tx.pool.log = append(tx.pool.log, "rollback")
+	}
+	return nil
+}
+
+func newFakePostgres(fail map[string][]error) (*Postgres, *fakePool) {
+	pool := &fakePool{fail: fail}
+	return &Postgres{Pool: pool}, pool
+}
+
+// exec runs statements through Conn, first failure is returned
+func exec(ctx context.Context, p *Postgres, statements ...string) error {
+	for _, s := range statements {
+		if _, err := p.Conn(ctx).Exec(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkLog(t *testing.T, pool *fakePool, want ...string) {
+	t.Helper()
+	if !slices.Equal(pool.log, want) {
+		t.Errorf("log\n%q\nwant\n%q", pool.log, want)
+	}
+}
+
+func TestInTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    map[string][]error
+		fn      func(p *Postgres) func(ctx context.Context) error
+		wantErr error
+		want    []string
+	}{
+		{
+			name: "commit",
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return exec(ctx, p, "a", "b") }
+			},
+			want: []string{"begin", "a", "b", "commit"},
+		},
+		{
+			name: "rollback",
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := exec(ctx, p, "a"); err != nil {
+						return err
+					}
+					return errFn
+				}
+			},
+			wantErr: errFn,
+			want:    []string{"begin", "a", "rollback"},
+		},
+		{
+			name: "failed statement",
+			fail: map[string][]error{"b": {errUnique}},
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return exec(ctx, p, "a", "b", "c") }
+			},
+			wantErr: errUnique,
+			want:    []string{"begin", "a", "b", "rollback"},
+		},
+		{
+			name: "failed commit",
+			fail: map[string][]error{"commit": {errSerialization}},
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error { return exec(ctx, p, "a") }
+			},
+			wantErr: errSerialization,
+			want:    []string{"begin", "a", "commit"},
+		},
+		{
+			name: "savepoint released",
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := exec(ctx, p, "a"); err != nil {
+						return err
+					}
+					if err := p.InTx(ctx, func(ctx context.Context) error { return exec(ctx, p, "b") }); err != nil {
+						return err
+					}
+					return exec(ctx, p, "c")
+				}
+			},
+			want: []string{"begin", "a", "savepoint", "b", "release", "c", "commit"},
+		},
+		{
+			name: "savepoint rolled back",
+			fail: map[string][]error{"b": {errUnique}},
+			fn: func(p *Postgres) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err := exec(ctx, p, "a"); err != nil {
+						return err
+					}
+					// Outer transaction goes on after failure of nested one
+					err := p.InTx(ctx, func(ctx context.Context) error { return exec(ctx, p, "b", "skipped") })
+					if !errors.Is(err, errUnique) {
+						return fmt.Errorf("nested InTx = %v, want %v", err, errUnique)
+					}
+					return exec(ctx, p, "c")
+				}
+			},
+			want: []string{"begin", "a", "savepoint", "b", "rollback to savepoint", "c", "commit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, pool := newFakePostgres(tt.fail)
+			err := p.InTx(context.Background(), tt.fn(p))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InTx = %v, want %v", err, tt.wantErr)
+			}
+			checkLog(t, pool, tt.want...)
+		})
+	}
+}
+
+func TestConnOutsideOfTx(t *testing.T) {
+	p, pool := newFakePostgres(nil)
+	if err := exec(context.Background(), p, "a"); err != nil {
+		t.Fatal(err)
+	}
+	checkLog(t, pool, "pool a")
+}
+
+func TestInTxRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     map[string][]error
+		nested   bool
+		wrap     bool
+		wantErr  error
+		attempts int
+	}{
+		{name: "no failure", attempts: 1},
+		{name: "serialization failure", fail: map[string][]error{"a": {errSerialization}}, attempts: 2},
+		{name: "deadlock", fail: map[string][]error{"a": {errDeadlock, errDeadlock}}, attempts: 3},
+		{name: "failed commit", fail: map[string][]error{"commit": {errSerialization}}, attempts: 2},
+		{
+			name: "error replaced by caller",
+			fail: map[string][]error{"a": {errSerialization}},
+			// Caller returns own error, failure is known from query
+			wrap:     true,
+			attempts: 2,
+		},
+		{
+			name: "failure in savepoint",
+			fail: map[string][]error{"a": {errSerialization}},
+			// Savepoint isn't repeated, whole transaction is
+			nested:   true,
+			attempts: 2,
+		},
+		{
+			name:     "attempts exhausted",
+			fail:     map[string][]error{"a": {errSerialization, errSerialization, errSerialization}},
+			wantErr:  errSerialization,
+			attempts: 3,
+		},
+		{name: "other failure", fail: map[string][]error{"a": {errUnique}}, wantErr: errUnique, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, pool := newFakePostgres(tt.fail)
+			m, err := NewTxManager(p, TxBackoff(0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			attempts := 0
+			err = m.InTxRetry(context.Background(), func(ctx context.Context) error {
+				attempts++
+				run := func(ctx context.Context) error { return exec(ctx, p, "a") }
+				if tt.nested {
+					inner := run
+					run = func(ctx context.Context) error { return m.InTxRetry(ctx, inner) }
+				}
+				if err := run(ctx); err != nil {
+					if tt.wrap {
+						return errFn
+					}
+					return err
+				}
+				return nil
+			})
+
+			wantErr := tt.wantErr
+			if tt.wrap && wantErr != nil {
+				wantErr = errFn
+			}
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("InTxRetry = %v, want %v", err, wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("fn run %d times, want %d", attempts, tt.attempts)
+			}
+			if begun := len(pool.opts); begun != tt.attempts {
+				t.Errorf("%d transactions begun, want %d", begun, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestTxManagerIsolation(t *testing.T) {
+	p, pool := newFakePostgres(nil)
+	m, err := NewTxManager(p, TxIsolation("serializable"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.InTx(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.opts) != 1 || pool.opts[0].IsoLevel != pgx.Serializable {
+		t.Errorf("transactions begun with %+v, want serializable", pool.opts)
+	}
+
+	if _, err := NewTxManager(p, TxIsolation("snapshot")); err == nil {
+		t.Errorf("NewTxManager with unknown isolation level succeeded")
+	}
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTxAttempts = 3
+	defaultTxBackoff  = 20 * time.Millisecond
+)
+
+type TxOption func(*TxManager)
+
+func TxAttempts(attempts int) TxOption {
+	return func(m *TxManager) {
+		if attempts > 0 {
+			m.attempts = attempts
+		}
+	}
+}
+
+func TxBackoff(backoff time.Duration) TxOption {
+	return func(m *TxManager) {
+		m.backoff = backoff
+	}
+}
+
+// TxIsolation sets isolation level of transactions, e.g. "repeatable read"
+// or "serializable". Empty level keeps server default
+func TxIsolation(level string) TxOption {
+	return func(m *TxManager) {
+		m.opts.IsoLevel = pgx.TxIsoLevel(level)
+	}
+}
+
+// TxManager starts transactions with configured isolation level. Transaction
+// failed on serialization conflict or deadlock may be run again
+type TxManager struct {
+	pg       *Postgres
+	opts     pgx.TxOptions
+	attempts int
+	backoff  time.Duration
+}
+
+func NewTxManager(pg *Postgres, opts ...TxOption) (*TxManager, error) {
+	m := &TxManager{
+		pg:       pg,
+		attempts: defaultTxAttempts,
+		backoff:  defaultTxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	switch m.opts.IsoLevel {
+	case "", pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable:
+	default:
+		return nil, fmt.Errorf("postgres - NewTxManager: unknown isolation level %q", m.opts.IsoLevel)
+	}
+
+	return m, nil
+}
+
+// InTx runs fn once, see Postgres.InTx
+func (m *TxManager) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := m.pg.runTx(ctx, m.opts, fn)
+	return err
+}
+
+// InTxRetry runs fn in new transaction again while it fails on serialization
+// conflict or deadlock. fn must not have effects outside database. Nested call
+// joins outer transaction, it is repeated together with it
+func (m *TxManager) InTxRetry(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		retryable, err := m.pg.runTx(ctx, m.opts, fn)
+		if !retryable || attempt == m.attempts {
+			return err
+		}
+		log.Infof("postgres - TxManager.InTxRetry - attempt %d failed, retrying: %v", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.backoff * time.Duration(attempt)):
+		}
+	}
+}